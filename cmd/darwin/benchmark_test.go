@@ -2,12 +2,14 @@ package main
 
 import (
 	"fmt"
+	"os"
 	"runtime"
 	"testing"
 	"time"
 
 	"github.com/bxrne/darwin/internal/cfg"
 	"github.com/bxrne/darwin/internal/individual"
+	"github.com/bxrne/darwin/internal/mockserver"
 	"go.uber.org/zap"
 )
 
@@ -123,11 +125,13 @@ func newBenchmarkConfig(popSize, sizeParam, generations int, individualType stri
 		}
 
 	case "action_tree":
+		// The Generals action heads: pass, row, column, direction and split
 		actions := []individual.ActionTuple{
-			{Name: "move_north", Value: 1},
-			{Name: "move_south", Value: 2},
-			{Name: "move_east", Value: 3},
-			{Name: "move_west", Value: 4},
+			{Name: "pass", Value: 2},
+			{Name: "cell_i", Value: 8},
+			{Name: "cell_j", Value: 8},
+			{Name: "direction", Value: 4},
+			{Name: "split", Value: 2},
 		}
 		// Ensure SwitchTrainingTargetStep is at least 1 to avoid divide by zero
 		switchStep := max(generations/2, 1)
@@ -151,7 +155,7 @@ func newBenchmarkConfig(popSize, sizeParam, generations int, individualType stri
 				Enabled:                  true,
 				Actions:                  actions,
 				WeightsCount:             weightsCount,
-				WeightsColumnCount:       4, // Fixed matrix dimensions for 4 actions
+				WeightsColumnCount:       7, // Fixed matrix dimensions for the Generals features
 				ServerAddr:               "localhost:5000",
 				OpponentType:             "random",
 				MaxSteps:                 100,
@@ -192,10 +196,24 @@ func newBenchmarkConfig(popSize, sizeParam, generations int, individualType stri
 }
 
 func runBenchmark(b *testing.B, config *cfg.Config) {
-	// Check ActionTree prerequisites
+	// Benchmark configs skip LoadConfig, so fill the operator defaults it would have set
+	config.Operators.Mutation.Type = "native"
+	config.Operators.Crossover.Type = "native"
+
+	// ActionTree plays against the mock game server unless DARWIN_GAME_SERVER names a real one
 	if config.ActionTree.Enabled {
-		b.Logf("ActionTree benchmark requires game server at %s", config.ActionTree.ServerAddr)
-		b.Logf("To start game server: cd game && uv venv && uv sync && uv run main.py")
+		if addr := os.Getenv("DARWIN_GAME_SERVER"); addr != "" {
+			config.ActionTree.ServerAddr = addr
+			b.Logf("ActionTree benchmark requires game server at %s", addr)
+			b.Logf("To start game server: cd game && uv venv && uv sync && uv run main.py")
+		} else {
+			server, err := mockserver.Start(mockserver.Scenario{Rewards: []float64{1, 0.5, 2}, TruncateAt: 20})
+			if err != nil {
+				b.Fatalf("Failed to start mock game server: %v", err)
+			}
+			defer server.Close()
+			config.ActionTree.ServerAddr = server.Addr()
+		}
 	}
 
 	// Report config
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/bxrne/darwin/internal/analysis"
+)
+
+// groupFlags collects repeated -group name=glob flags
+type groupFlags []string
+
+func (g *groupFlags) String() string {
+	return strings.Join(*g, ",")
+}
+
+func (g *groupFlags) Set(value string) error {
+	if !strings.Contains(value, "=") {
+		return fmt.Errorf("group must be name=glob, got %q", value)
+	}
+	*g = append(*g, value)
+	return nil
+}
+
+// runCompareCommand handles `darwin compare`, writing a statistical report across groups of runs
+func runCompareCommand(args []string) error {
+	fs := flag.NewFlagSet("compare", flag.ExitOnError)
+	var groupArgs groupFlags
+	fs.Var(&groupArgs, "group", "Group of runs as name=glob (repeatable), e.g. base=runs/base/*.csv")
+	sweepDir := fs.String("sweep", "", "Sweep output directory; each point becomes a group")
+	metric := fs.String("metric", "max_fit", "Metric column to compare")
+	level := fs.Float64("ci", 0.95, "Confidence level for median intervals")
+	outDir := fs.String("out", "comparison", "Directory for report.md, curves.csv and tests.csv")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	groups := make([]analysis.Group, 0)
+	if *sweepDir != "" {
+		sweepGroups, err := analysis.LoadSweepGroups(*sweepDir)
+		if err != nil {
+			return err
+		}
+		groups = append(groups, sweepGroups...)
+	}
+	for _, arg := range groupArgs {
+		name, pattern, _ := strings.Cut(arg, "=")
+		group, err := analysis.LoadGroup(name, pattern)
+		if err != nil {
+			return err
+		}
+		groups = append(groups, group)
+	}
+
+	report, err := analysis.Compare(groups, *metric, *level)
+	if err != nil {
+		return err
+	}
+	if err := report.WriteAll(*outDir); err != nil {
+		return err
+	}
+
+	fmt.Printf("Compared %d groups on %s, report written to %s\n", len(groups), *metric, *outDir)
+	return nil
+}
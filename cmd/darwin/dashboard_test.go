@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/bxrne/darwin/internal/cfg"
+	"github.com/bxrne/darwin/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestStartDashboard_GIVEN_config_WHEN_started_THEN_serves_resolved_config(t *testing.T) {
+	config, err := cfg.LoadConfig("../../config/default.toml")
+	require.NoError(t, err)
+	config.Evolution.PopulationSize = 4
+	config.Dashboard.Listen = "127.0.0.1:0"
+
+	dash, err := startDashboard(config)
+	require.NoError(t, err)
+	defer dash.Close()
+
+	resp, err := http.Get("http://" + dash.Addr() + "/api/config")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "[dashboard]")
+	assert.Contains(t, string(body), "population_size = 4")
+}
+
+func TestRunEvolution_GIVEN_dashboard_and_prometheus_enabled_WHEN_run_THEN_completes(t *testing.T) {
+	config, err := cfg.LoadConfig("../../config/default.toml")
+	require.NoError(t, err)
+	config.ActionTree.Backend = "native"
+	config.ActionTree.MaxSteps = 20
+	config.Evolution.PopulationSize = 4
+	config.Evolution.Generations = 2
+	config.Fitness.TestCaseCount = 1
+	config.Metrics.CSVEnabled = false
+	config.Dashboard.Enabled = true
+	config.Dashboard.Listen = freeAddr(t)
+	config.Prometheus.Enabled = true
+	config.Prometheus.Listen = freeAddr(t)
+
+	generations := 0
+	handler := func(m metrics.GenerationMetrics) { generations++ }
+	finalPop, metricsComplete, err := RunEvolution(context.Background(), config, handler, zap.NewNop())
+	require.NoError(t, err)
+	<-metricsComplete
+
+	assert.NotEmpty(t, finalPop)
+	assert.Equal(t, 2, generations)
+}
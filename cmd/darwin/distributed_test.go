@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bxrne/darwin/internal/cfg"
+	"github.com/bxrne/darwin/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// freeAddr returns a localhost address nothing is listening on
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+	return addr
+}
+
+// startWorkers runs count workers that join the coordinator once it is listening on addr
+func startWorkers(t *testing.T, ctx context.Context, config *cfg.Config, addr string, count int) *sync.WaitGroup {
+	t.Helper()
+	var wg sync.WaitGroup
+	for i := range count {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				if conn, err := net.Dial("tcp", addr); err == nil {
+					_ = conn.Close()
+					break
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+			assert.NoError(t, RunWorker(ctx, config, addr, fmt.Sprintf("worker-%d", i), 1))
+		}()
+	}
+	return &wg
+}
+
+func TestRunEvolution_GIVEN_distributed_workers_WHEN_run_THEN_workers_score_every_generation(t *testing.T) {
+	config, err := cfg.LoadConfig("../../config/default.toml")
+	require.NoError(t, err)
+	config.ActionTree.Backend = "native"
+	config.ActionTree.MaxSteps = 40
+	config.ActionTree.WeightsCount = 3
+	config.Evolution.PopulationSize = 4
+	config.Evolution.Generations = 2
+	config.Fitness.TestCaseCount = 1
+	config.Metrics.CSVEnabled = false
+	config.Distributed.Enabled = true
+	config.Distributed.Listen = freeAddr(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	workers := startWorkers(t, ctx, config, config.Distributed.Listen, 2)
+
+	generations := 0
+	handler := func(m metrics.GenerationMetrics) { generations++ }
+	finalPop, metricsComplete, err := RunEvolution(ctx, config, handler, zap.NewNop())
+	require.NoError(t, err)
+	<-metricsComplete
+
+	assert.NotEmpty(t, finalPop)
+	assert.Equal(t, 2, generations)
+	// Workers stop once the run closes the coordinator
+	workers.Wait()
+}
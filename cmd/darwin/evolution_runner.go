@@ -1,91 +1,138 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"time"
 
+	"github.com/BurntSushi/toml"
 	"github.com/bxrne/darwin/internal/cfg"
+	"github.com/bxrne/darwin/internal/control"
+	"github.com/bxrne/darwin/internal/dashboard"
+	"github.com/bxrne/darwin/internal/distributed"
 	"github.com/bxrne/darwin/internal/evolution"
 	"github.com/bxrne/darwin/internal/fitness"
 	"github.com/bxrne/darwin/internal/individual"
+	"github.com/bxrne/darwin/internal/lineage"
 	"github.com/bxrne/darwin/internal/metrics"
-	"github.com/bxrne/darwin/internal/population"
+	"github.com/bxrne/darwin/internal/plugin"
 	"github.com/bxrne/darwin/internal/rng"
-	"github.com/bxrne/darwin/internal/selection"
 	"go.uber.org/zap"
 )
 
 type MetricsHandler func(metrics.GenerationMetrics)
 type MetricsComplete chan struct{}
 
-func getGenomeType(config *cfg.Config) individual.GenomeType {
-	if config.BitString.Enabled {
-		return individual.BitStringGenome
-	} else if config.Tree.Enabled {
-		return individual.TreeGenome
-	} else if config.GrammarTree.Enabled {
-		return individual.GrammarTreeGenome
-	} else if config.ActionTree.Enabled {
-		return individual.ActionTreeGenome
-	}
-	return -1 // or panic/error
+// Control steers a run from outside, e.g. the terminal UI
+type Control interface {
+	// Commands are forwarded to the engine between generations
+	Commands() <-chan evolution.EvolutionCommand
+	// Attach is called with the fitness calculator before the first generation
+	Attach(calculator fitness.FitnessCalculator)
 }
 
 // RunEvolution encapsulates the shared evolution logic.
 // It takes a context, config, optional metrics handler, and logger.
 // Returns the final population, a completion channel, and an error.
 func RunEvolution(ctx context.Context, config *cfg.Config, handler MetricsHandler, logger *zap.Logger) ([]individual.Evolvable, MetricsComplete, error) {
-	// pre evolution srv heartbeat
-	if config.ActionTree.Enabled {
+	return RunEvolutionControlled(ctx, config, handler, logger)
+}
+
+// RunEvolutionControlled is RunEvolution steered by controls, along with the control API if
+// [control] is enabled. A CmdStop from any of them ends the run early with the population as it
+// stands.
+func RunEvolutionControlled(ctx context.Context, config *cfg.Config, handler MetricsHandler, logger *zap.Logger, controls ...Control) ([]individual.Evolvable, MetricsComplete, error) {
+	// pre evolution srv heartbeat; distributed workers play against their own servers
+	if config.GenomeName() == "action_tree" && config.ActionTree.Backend == "tcp" && !config.Distributed.Enabled {
 		timeout := 5 * time.Second
 		if parsedTimeout, err := time.ParseDuration(config.ActionTree.ConnectionTimeout); err == nil {
 			timeout = parsedTimeout
 		}
 
 		healthChecker := fitness.NewServerHealthChecker(config.ActionTree.ServerAddr, timeout)
+		healthChecker.SetLogger(logger)
 		if err := healthChecker.CheckServerHealthWithRetry(); err != nil {
 			return nil, nil, fmt.Errorf("server health check failed: %w", err)
 		}
 	}
 
-	rng.Seed(config.Evolution.Seed)
+	// The run draws only from its own generator, so runs sharing the process cannot disturb it
+	r := rng.New(config.Evolution.Seed)
 
 	metricsChan := make(chan metrics.GenerationMetrics, config.Evolution.Generations)
-	cmdChan := make(chan evolution.EvolutionCommand, config.Evolution.Generations)
+	// Unbuffered, so commands from control reach the engine at the next generation
+	cmdChan := make(chan evolution.EvolutionCommand)
 	metricsComplete := make(chan struct{})
 
-	populationType := getGenomeType(config)
-	grammar := individual.CreateGrammar(config.Tree.TerminalSet, config.Tree.VariableSet, config.Tree.OperandSet)
-
-	popBuilder := population.NewPopulationBuilder()
-	popinfo := population.NewPopulationInfo(config, populationType)
-
-	individualFactory := population.NewIndividualFactory(config)
-
-	population := popBuilder.BuildPopulation(&popinfo, func() individual.Evolvable {
-		return individualFactory.CreateIndividual(populationType)
-	})
-
-	fitnessInfo := fitness.GenerateFitnessInfoFromConfig(config, populationType, grammar, population.GetPopulations())
-	fitnessCalculator := fitness.FitnessCalculatorFactoryWithConfig(fitnessInfo, config)
-
-	var selector selection.Selector
-	switch config.Evolution.SelectionType {
-	case "tournament":
-		selector = selection.NewTournamentSelector(config.Evolution.SelectionSize)
-	case "roulette":
-		selector = selection.NewRouletteSelector(config.Evolution.SelectionSize)
-
+	components, err := plugin.Build(config, r, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	fitnessCalculator := components.Fitness
+	if config.Distributed.Enabled {
+		taskTimeout, _ := time.ParseDuration(config.Distributed.TaskTimeout)
+		workerTimeout, _ := time.ParseDuration(config.Distributed.WorkerTimeout)
+		coordinator, err := distributed.Listen(config.Distributed.Listen, distributed.Options{TaskTimeout: taskTimeout, WorkerTimeout: workerTimeout})
+		if err != nil {
+			return nil, nil, err
+		}
+		// The local calculator only owns the populations' connections now; workers build their own
+		if cleanupCalc, ok := fitnessCalculator.(interface{ Close() error }); ok {
+			_ = cleanupCalc.Close()
+		}
+		fitnessCalculator = distributed.NewCalculator(coordinator, config.Distributed.InFlight)
 	}
+
 	metricsStreamer := metrics.NewMetricsStreamer(metricsChan)
 	var metricsSubscriber <-chan metrics.GenerationMetrics
 	if handler != nil {
 		metricsSubscriber = metricsStreamer.Subscribe()
 	}
-	crossoverInformation := individual.CrossoverInformation{CrossoverPoints: config.Evolution.CrossoverPointCount, MaxDepth: config.Tree.MaxDepth}
-	mutateInformation := individual.MutateInformation{OperandSet: config.Tree.OperandSet, TerminalSet: config.Tree.TerminalSet, VariableSet: config.Tree.VariableSet, MaxDepth: config.Tree.MaxDepth}
-	evolutionEngine := evolution.NewEvolutionEngine(population, selector, metricsChan, cmdChan, fitnessCalculator, crossoverInformation, mutateInformation, logger)
+	if config.Dashboard.Enabled {
+		dash, err := startDashboard(config)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer dash.Close()
+		go dash.Follow(metricsStreamer.Subscribe())
+	}
+	if config.Prometheus.Enabled {
+		var poolStats func() map[string]interface{}
+		if reporter, ok := fitnessCalculator.(fitness.PoolStatsReporter); ok {
+			poolStats = reporter.PoolStats
+		}
+		exporter := metrics.NewPrometheusExporter(poolStats)
+		if err := exporter.Listen(config.Prometheus.Listen); err != nil {
+			return nil, nil, err
+		}
+		defer exporter.Close()
+		go exporter.Follow(metricsStreamer.Subscribe())
+	}
+	if config.Control.Enabled {
+		api, err := control.Listen(config.Control.Listen, config.Control.CheckpointPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer api.Close()
+		controls = append(controls, api)
+	}
+	evolutionEngine := evolution.NewEvolutionEngine(components.Population, components.Selector, metricsChan, cmdChan, fitnessCalculator, components.Genome.CrossoverInformation, components.Genome.MutateInformation, logger)
+	evolutionEngine.SetOperators(components.Mutation, components.Crossover)
+	evolutionEngine.SetAdaptation(components.Adaptation)
+	evolutionEngine.SetRand(r)
+	if config.Lineage.Enabled {
+		tracker, err := lineage.Create(config.Lineage.Path)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer func() {
+			if err := tracker.Close(); err != nil {
+				logger.Error("Failed to close lineage file", zap.Error(err))
+			}
+		}()
+		evolutionEngine.SetLineage(tracker)
+	}
 
 	metricsStreamer.Start(ctx)
 	evolutionEngine.Start(ctx)
@@ -114,23 +161,10 @@ func RunEvolution(ctx context.Context, config *cfg.Config, handler MetricsHandle
 		close(metricsComplete) // Close immediately if no handler
 	}
 
-	// Send evolution commands
-	for gen := 1; gen <= config.Evolution.Generations; gen++ {
-		cmd := evolution.EvolutionCommand{
-			Type:            evolution.CmdStartGeneration,
-			Generation:      gen,
-			CrossoverPoints: config.Evolution.CrossoverPointCount,
-			CrossoverRate:   config.Evolution.CrossoverRate,
-			MutationRate:    config.Evolution.MutationRate,
-			ElitismPct:      config.Evolution.ElitismPercentage,
-		}
-
-		select {
-		case cmdChan <- cmd:
-		case <-time.After(5 * time.Second):
-			return nil, metricsComplete, fmt.Errorf("timeout sending evolution command for generation %d", gen)
-		}
+	for _, c := range controls {
+		c.Attach(fitnessCalculator)
 	}
+	feedCommands(ctx, config, cmdChan, controls, evolutionEngine.Done())
 
 	close(cmdChan)
 	evolutionEngine.Wait()
@@ -143,6 +177,109 @@ func RunEvolution(ctx context.Context, config *cfg.Config, handler MetricsHandle
 		}
 	}
 
+	if err := evolutionEngine.Err(); err != nil {
+		return nil, metricsComplete, err
+	}
+
 	finalPop := evolutionEngine.GetPopulation()
 	return finalPop, metricsComplete, nil
 }
+
+// startDashboard serves the dashboard, showing config as resolved after validation
+func startDashboard(config *cfg.Config) (*dashboard.Server, error) {
+	var resolved bytes.Buffer
+	if err := toml.NewEncoder(&resolved).Encode(config); err != nil {
+		return nil, fmt.Errorf("failed to encode config for the dashboard: %w", err)
+	}
+	return dashboard.Listen(config.Dashboard.Listen, resolved.String())
+}
+
+// feedCommands sends each generation to the engine, forwarding the controls' commands in
+// between, until every generation is sent and the engine is not paused, a control stops the run,
+// or the engine is done
+func feedCommands(ctx context.Context, config *cfg.Config, cmdChan chan<- evolution.EvolutionCommand, controls []Control, engineDone <-chan struct{}) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	controlCommands := mergeCommands(ctx, controls)
+	send := func(cmd evolution.EvolutionCommand) bool {
+		select {
+		case cmdChan <- cmd:
+			return true
+		case <-engineDone:
+		case <-ctx.Done():
+		}
+		return false
+	}
+
+	paused := false
+	for gen := 1; gen <= config.Evolution.Generations || paused; {
+		next := cmdChan
+		if gen > config.Evolution.Generations {
+			next = nil // every generation is sent, wait for a resume or stop
+		}
+		cmd := evolution.EvolutionCommand{
+			Type:            evolution.CmdStartGeneration,
+			Generation:      gen,
+			CrossoverPoints: config.Evolution.CrossoverPointCount,
+			CrossoverRate:   config.Evolution.CrossoverRate,
+			MutationRate:    config.Evolution.MutationRate,
+			ElitismPct:      config.Evolution.ElitismPercentage,
+		}
+
+		select {
+		case next <- cmd:
+			gen++
+		case controlCmd, ok := <-controlCommands:
+			if !ok {
+				controlCommands = nil
+				continue
+			}
+			if !send(controlCmd) {
+				return
+			}
+			switch controlCmd.Type {
+			case evolution.CmdPause:
+				paused = true
+			case evolution.CmdResume:
+				paused = false
+			case evolution.CmdStop:
+				return
+			}
+		case <-engineDone:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// mergeCommands returns one channel carrying the commands of every control until ctx is done
+func mergeCommands(ctx context.Context, controls []Control) <-chan evolution.EvolutionCommand {
+	switch len(controls) {
+	case 0:
+		return nil
+	case 1:
+		return controls[0].Commands()
+	}
+	merged := make(chan evolution.EvolutionCommand)
+	for _, c := range controls {
+		go func(commands <-chan evolution.EvolutionCommand) {
+			for {
+				select {
+				case cmd, ok := <-commands:
+					if !ok {
+						return
+					}
+					select {
+					case merged <- cmd:
+					case <-ctx.Done():
+						return
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(c.Commands())
+	}
+	return merged
+}
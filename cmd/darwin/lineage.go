@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/bxrne/darwin/internal/lineage"
+)
+
+// runLineageCommand handles `darwin lineage`, printing per-operator success rates of a recorded
+// run and writing the ancestry of its champion as Graphviz DOT
+func runLineageCommand(args []string) error {
+	fs := flag.NewFlagSet("lineage", flag.ExitOnError)
+	dotPath := fs.String("dot", "ancestry.dot", "Where the ancestry graph is written, - for stdout")
+	id := fs.Uint64("id", 0, "Individual whose ancestry is drawn instead of the champion's")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: darwin lineage [-dot file] [-id n] <lineage.jsonl>")
+	}
+	return analyseLineage(fs.Arg(0), *dotPath, *id, os.Stdout)
+}
+
+// analyseLineage writes the success rates to out and the ancestry of individual id, or the
+// champion if id is 0, to dotPath
+func analyseLineage(path, dotPath string, id uint64, out io.Writer) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open lineage: %w", err)
+	}
+	defer file.Close()
+	records, err := lineage.Read(file)
+	if err != nil {
+		return err
+	}
+
+	if id == 0 {
+		champion, ok := lineage.Champion(records)
+		if !ok {
+			return fmt.Errorf("lineage %s has no scored individuals", path)
+		}
+		id = champion.ID
+		fmt.Fprintf(out, "Champion #%d of generation %d, fitness %.4g\n\n", champion.ID, champion.Generation, *champion.Fitness)
+	}
+	if err := lineage.WriteStats(out, lineage.OperatorStats(records)); err != nil {
+		return err
+	}
+
+	if dotPath == "-" {
+		return lineage.WriteDOT(out, records, id)
+	}
+	dot, err := os.Create(dotPath)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", dotPath, err)
+	}
+	if err := lineage.WriteDOT(dot, records, id); err != nil {
+		dot.Close()
+		return err
+	}
+	if err := dot.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", dotPath, err)
+	}
+	fmt.Fprintf(out, "\nAncestry of #%d written to %s\n", id, dotPath)
+	return nil
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bxrne/darwin/internal/cfg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRunEvolution_GIVEN_lineage_enabled_WHEN_analysed_THEN_champion_ancestry_and_rates(t *testing.T) {
+	dir := t.TempDir()
+	config, err := cfg.LoadConfig("../../config/default.toml")
+	require.NoError(t, err)
+	config.ActionTree.Backend = "native"
+	config.ActionTree.MaxSteps = 20
+	config.Evolution.PopulationSize = 4
+	config.Evolution.Generations = 2
+	config.Fitness.TestCaseCount = 1
+	config.Metrics.CSVEnabled = false
+	config.Lineage.Enabled = true
+	config.Lineage.Path = filepath.Join(dir, "lineage.jsonl")
+
+	_, metricsComplete, err := RunEvolution(context.Background(), config, nil, zap.NewNop())
+	require.NoError(t, err)
+	<-metricsComplete
+
+	var out strings.Builder
+	dotPath := filepath.Join(dir, "ancestry.dot")
+	require.NoError(t, analyseLineage(config.Lineage.Path, dotPath, 0, &out))
+	assert.Contains(t, out.String(), "Champion #")
+	assert.Contains(t, out.String(), "elitism")
+	dot, err := os.ReadFile(dotPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(dot), "style=bold")
+}
+
+func TestAnalyseLineage_GIVEN_missing_file_WHEN_analysed_THEN_returns_error(t *testing.T) {
+	err := analyseLineage(filepath.Join(t.TempDir(), "missing.jsonl"), "-", 0, &strings.Builder{})
+
+	assert.ErrorContains(t, err, "failed to open lineage")
+}
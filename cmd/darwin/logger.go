@@ -9,31 +9,36 @@ import (
 
 // InitializeLogger creates and configures a zap logger based on the config's log level
 func InitializeLogger(config *cfg.Config) (*zap.Logger, error) {
-	var logger *zap.Logger
-	var buildErr error
+	return buildLogger(config, nil)
+}
+
+// InitializeFileLogger is InitializeLogger writing to path instead of stderr, e.g. while the
+// terminal UI owns the screen
+func InitializeFileLogger(config *cfg.Config, path string) (*zap.Logger, error) {
+	return buildLogger(config, []string{path})
+}
 
+func buildLogger(config *cfg.Config, outputPaths []string) (*zap.Logger, error) {
+	zapConfig := zap.NewDevelopmentConfig()
 	switch config.Logging.Level {
 	case "debug":
-		logger, buildErr = zap.NewDevelopment()
+		zapConfig.Level = zap.NewAtomicLevelAt(zap.DebugLevel)
 	case "info":
-		zapConfig := zap.NewDevelopmentConfig()
 		zapConfig.Level = zap.NewAtomicLevelAt(zap.InfoLevel)
-		logger, buildErr = zapConfig.Build()
 	case "warn":
-		zapConfig := zap.NewDevelopmentConfig()
 		zapConfig.Level = zap.NewAtomicLevelAt(zap.WarnLevel)
-		logger, buildErr = zapConfig.Build()
 	case "error":
-		zapConfig := zap.NewDevelopmentConfig()
 		zapConfig.Level = zap.NewAtomicLevelAt(zap.ErrorLevel)
-		logger, buildErr = zapConfig.Build()
 	default:
 		// Default to info if invalid level
-		zapConfig := zap.NewDevelopmentConfig()
 		zapConfig.Level = zap.NewAtomicLevelAt(zap.InfoLevel)
-		logger, buildErr = zapConfig.Build()
+	}
+	if outputPaths != nil {
+		zapConfig.OutputPaths = outputPaths
+		zapConfig.ErrorOutputPaths = outputPaths
 	}
 
+	logger, buildErr := zapConfig.Build()
 	if buildErr != nil {
 		return nil, fmt.Errorf("failed to initialize logger: %w", buildErr)
 	}
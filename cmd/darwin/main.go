@@ -12,8 +12,52 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "sweep":
+			if err := runSweepCommand(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Sweep failed: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "meta":
+			if err := runMetaCommand(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Meta evolution failed: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "worker":
+			if err := runWorkerCommand(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Worker failed: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "replay":
+			if err := runReplayCommand(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Replay failed: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "lineage":
+			if err := runLineageCommand(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Lineage analysis failed: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "compare":
+			if err := runCompareCommand(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Compare failed: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	configPath := flag.String("config", "config/default.toml", "Path to config file")
 	csvOutput := flag.String("csv-output", "", "Path to CSV file for metrics output")
+	tuiMode := flag.Bool("tui", false, "Watch and steer the run from a terminal dashboard")
+	savePath := flag.String("save", "population.jsonl", "Where the terminal dashboard's stop and save writes the population")
+	logFile := flag.String("log-file", "darwin.log", "Where logs go while the terminal dashboard owns the screen")
 	flag.Parse()
 
 	// Load config first (needed for logger level)
@@ -25,7 +69,12 @@ func main() {
 	}
 
 	// Initialize zap logger based on config
-	logger, err := InitializeLogger(cfg)
+	var logger *zap.Logger
+	if *tuiMode {
+		logger, err = InitializeFileLogger(cfg, *logFile)
+	} else {
+		logger, err = InitializeLogger(cfg)
+	}
 	if err != nil {
 		panic(fmt.Sprintf("failed to initialize logger: %v", err))
 	}
@@ -76,24 +125,36 @@ func main() {
 		csvFile = cfg.Metrics.CSVFile
 	}
 
-	// Add CSV handler if CSV output is enabled
-	if csvFile != "" {
-		csvHandler, err := metrics.CreateCSVHandler(csvFile)
-		if err != nil {
-			sugar.Fatalw("Failed to create CSV handler", "error", err)
+	// Add the metrics sinks: the CSV file and any [[metrics.sinks]]
+	sinks, err := openSinks(cfg, csvFile, *configPath)
+	if err != nil {
+		sugar.Fatalw("Failed to create metrics sinks", "error", err)
+	}
+	defer func() {
+		if err := sinks.Close(); err != nil {
+			sugar.Warnw("Failed to close metrics sinks", "error", err)
 		}
+	}()
+	if len(sinks) > 0 {
+		sinkHandler := metrics.CreateSinkHandler(sinks)
 
 		// Combine both handlers
 		handler = func(m metrics.GenerationMetrics) {
 			logHandler(m)
-			csvHandler(m)
+			sinkHandler(m)
 		}
-
-		sugar.Infow("CSV output enabled", "file", csvFile)
 	} else {
 		handler = logHandler
 	}
 
+	if *tuiMode {
+		if err := runTUI(ctx, cfg, handler, logger, *savePath); err != nil {
+			sugar.Fatalw("Evolution failed", "error", err.Error())
+		}
+		sugar.Info("Evolution finished successfully")
+		return
+	}
+
 	_, metricsComplete, err := RunEvolution(ctx, cfg, handler, logger)
 	if err != nil {
 		sugar.Fatalw("Evolution failed", "error", err.Error())
@@ -104,3 +165,32 @@ func main() {
 
 	sugar.Info("Evolution finished successfully")
 }
+
+// openSinks opens the CSV sink for csvFile, if set, and every sink configured in [metrics],
+// recording the run's settings in those that keep them
+func openSinks(config *cfg.Config, csvFile string, configPath string) (metrics.MultiSink, error) {
+	run := metrics.RunInfo{
+		"config":          configPath,
+		"seed":            config.Evolution.Seed,
+		"genome":          config.GenomeName(),
+		"fitness":         config.FitnessName(),
+		"population_size": config.Evolution.PopulationSize,
+		"generations":     config.Evolution.Generations,
+	}
+	sinkConfigs := config.Metrics.Sinks
+	if csvFile != "" {
+		sinkConfigs = append([]cfg.SinkConfig{{Type: "csv", Path: csvFile}}, sinkConfigs...)
+	}
+
+	var sinks metrics.MultiSink
+	for _, sc := range sinkConfigs {
+		sink, err := metrics.NewSink(sc.Type, sc.Path, run)
+		if err != nil {
+			_ = sinks.Close()
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+		zap.L().Info("Metrics sink enabled", zap.String("type", sc.Type), zap.String("file", sc.Path))
+	}
+	return sinks, nil
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bxrne/darwin/internal/cfg"
+	"github.com/bxrne/darwin/internal/evolution"
+	"github.com/bxrne/darwin/internal/experiment"
+	"github.com/bxrne/darwin/internal/individual"
+	"github.com/bxrne/darwin/internal/metrics"
+	"github.com/bxrne/darwin/internal/population"
+	"github.com/bxrne/darwin/internal/rng"
+	"github.com/bxrne/darwin/internal/selection"
+	"go.uber.org/zap"
+)
+
+// MetaFitnessCalculator scores a hyperparameter vector by the mean best fitness of short inner runs
+type MetaFitnessCalculator struct {
+	spec   *experiment.MetaSpec
+	ctx    context.Context
+	logger *zap.Logger
+}
+
+// NewMetaFitnessCalculator creates a calculator running inner evolutions for the spec
+func NewMetaFitnessCalculator(ctx context.Context, spec *experiment.MetaSpec, logger *zap.Logger) *MetaFitnessCalculator {
+	return &MetaFitnessCalculator{spec: spec, ctx: ctx, logger: logger}
+}
+
+// CalculateFitness runs one inner evolution per seed and averages their best fitness.
+// Inner seeds are fixed (1..inner_seeds) so every candidate faces the same random streams.
+func (mfc *MetaFitnessCalculator) CalculateFitness(evolvable individual.Evolvable) {
+	candidate, ok := evolvable.(*individual.RealVectorIndividual)
+	if !ok {
+		panic("Meta fitness needs RealVectorIndividual")
+	}
+
+	total := 0.0
+	for i := range mfc.spec.InnerSeeds {
+		seed := int64(i + 1)
+		config, err := mfc.spec.InnerConfig(candidate.Values(), seed)
+		if err != nil {
+			mfc.logger.Warn("Invalid inner config", zap.String("candidate", candidate.Describe()), zap.Error(err))
+			candidate.SetFitness(0)
+			return
+		}
+		best, err := mfc.innerBest(config)
+		if err != nil {
+			mfc.logger.Warn("Inner run failed", zap.String("candidate", candidate.Describe()), zap.Int64("seed", seed), zap.Error(err))
+			candidate.SetFitness(0)
+			return
+		}
+		total += best
+	}
+	candidate.SetFitness(total / float64(mfc.spec.InnerSeeds))
+}
+
+// innerBest runs one inner evolution of config and returns the fitness of its champion. The inner
+// run logs to a discarding logger of its own and draws from a generator seeded by config, so it
+// touches neither the process-wide logger nor the outer run's generator.
+func (mfc *MetaFitnessCalculator) innerBest(config *cfg.Config) (float64, error) {
+	finalPop, metricsComplete, err := RunEvolution(mfc.ctx, config, nil, zap.NewNop())
+	if err != nil {
+		return 0, err
+	}
+	<-metricsComplete
+	best := bestIndividual(finalPop)
+	if best == nil {
+		return 0, fmt.Errorf("inner run returned an empty population")
+	}
+	return best.GetFitness(), nil
+}
+
+// runMetaCommand handles `darwin meta`, evolving the hyperparameters of a base config
+func runMetaCommand(args []string) error {
+	fs := flag.NewFlagSet("meta", flag.ExitOnError)
+	specPath := fs.String("spec", "config/meta.toml", "Path to meta spec file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	spec, err := experiment.LoadMetaSpec(*specPath)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(spec.OutputDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create output dir: %w", err)
+	}
+
+	logger, err := zap.NewDevelopment()
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	defer func() {
+		_ = logger.Sync()
+	}()
+	zap.ReplaceGlobals(logger)
+
+	csvHandler, err := metrics.CreateCSVHandler(filepath.Join(spec.OutputDir, "meta_metrics.csv"))
+	if err != nil {
+		return err
+	}
+	handler := func(m metrics.GenerationMetrics) {
+		csvHandler(m)
+		logger.Info("Meta generation", zap.Int("gen", m.Generation), zap.Float64("best", m.Metrics["max_fit"]), zap.String("best_desc", m.BestDescription))
+	}
+
+	best, err := RunMetaEvolution(context.Background(), spec, handler, logger)
+	if err != nil {
+		return err
+	}
+
+	tuned, err := spec.TunedConfig(best.Values())
+	if err != nil {
+		return err
+	}
+	bestPath := filepath.Join(spec.OutputDir, "best.toml")
+	if err := experiment.WriteResolvedConfig(bestPath, tuned); err != nil {
+		return err
+	}
+
+	logger.Info("Meta evolution finished", zap.String("best", best.Describe()), zap.Float64("fitness", best.GetFitness()), zap.String("config", bestPath))
+	return nil
+}
+
+// RunMetaEvolution drives the outer evolution over hyperparameter vectors and returns the best one
+func RunMetaEvolution(ctx context.Context, spec *experiment.MetaSpec, handler MetricsHandler, logger *zap.Logger) (*individual.RealVectorIndividual, error) {
+	outer := spec.Outer
+	r := rng.New(outer.Seed)
+
+	bounds := spec.Bounds()
+	popInfo := population.PopulationInfo{Size: outer.PopulationSize, GenomeType: individual.RealVectorGenome}
+	pop := population.NewPopulationBuilder().BuildPopulation(&popInfo, func(r *rng.Rand) individual.Evolvable {
+		return individual.NewRealVectorIndividual(r, bounds)
+	}, r)
+
+	metricsChan := make(chan metrics.GenerationMetrics, outer.Generations)
+	cmdChan := make(chan evolution.EvolutionCommand, outer.Generations)
+	streamer := metrics.NewMetricsStreamer(metricsChan)
+	subscriber := streamer.Subscribe()
+
+	engine := evolution.NewEvolutionEngine(
+		pop,
+		selection.NewTournamentSelector(outer.SelectionSize),
+		metricsChan,
+		cmdChan,
+		NewMetaFitnessCalculator(ctx, spec, logger),
+		individual.CrossoverInformation{CrossoverPoints: outer.CrossoverPointCount},
+		individual.MutateInformation{},
+		logger,
+	)
+	engine.SetRand(r)
+
+	streamer.Start(ctx)
+	engine.Start(ctx)
+
+	handled := make(chan struct{})
+	go func() {
+		defer close(handled)
+		for m := range subscriber {
+			if handler != nil {
+				handler(m)
+			}
+		}
+	}()
+
+	for gen := 1; gen <= outer.Generations; gen++ {
+		cmd := evolution.EvolutionCommand{
+			Type:            evolution.CmdStartGeneration,
+			Generation:      gen,
+			CrossoverPoints: outer.CrossoverPointCount,
+			CrossoverRate:   outer.CrossoverRate,
+			MutationRate:    outer.MutationRate,
+			ElitismPct:      outer.ElitismPercentage,
+		}
+		select {
+		case cmdChan <- cmd:
+		case <-time.After(5 * time.Second):
+			return nil, fmt.Errorf("timeout sending meta evolution command for generation %d", gen)
+		}
+	}
+
+	close(cmdChan)
+	engine.Wait()
+	streamer.Stop()
+	<-handled
+
+	best, ok := bestIndividual(engine.GetPopulation()).(*individual.RealVectorIndividual)
+	if !ok {
+		return nil, fmt.Errorf("meta evolution produced no individuals")
+	}
+	return best, nil
+}
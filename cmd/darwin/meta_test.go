@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bxrne/darwin/internal/cfg"
+	"github.com/bxrne/darwin/internal/experiment"
+	"github.com/bxrne/darwin/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRunMetaEvolution_GIVEN_small_spec_WHEN_run_THEN_best_within_bounds(t *testing.T) {
+	spec, err := experiment.LoadMetaSpec("../../config/meta.toml")
+	assert.NoError(t, err)
+	spec.InnerGenerations = 2
+	spec.InnerSeeds = 2
+	spec.Outer.PopulationSize = 4
+	spec.Outer.Generations = 2
+
+	generations := 0
+	first, err := RunMetaEvolution(context.Background(), spec, func(metrics.GenerationMetrics) { generations++ }, zap.NewNop())
+	assert.NoError(t, err)
+
+	assert.Greater(t, generations, 0)
+	for i, b := range first.Bounds {
+		assert.GreaterOrEqual(t, first.Genes[i], b.Min)
+		assert.LessOrEqual(t, first.Genes[i], b.Max)
+	}
+	assert.Greater(t, first.GetFitness(), 0.0)
+}
+
+func TestRunEvolution_GIVEN_run_logger_WHEN_fitness_setup_logs_THEN_logged_to_run_logger_not_global(t *testing.T) {
+	globalCore, globalLogs := observer.New(zap.InfoLevel)
+	defer zap.ReplaceGlobals(zap.New(globalCore))()
+	runCore, runLogs := observer.New(zap.InfoLevel)
+	config, err := cfg.LoadConfig("../../config/default.toml")
+	assert.NoError(t, err)
+	config.ActionTree.Backend = "native"
+	config.ActionTree.Environment = "no-such-environment"
+	config.Metrics.CSVEnabled = false
+
+	_, _, err = RunEvolution(context.Background(), config, nil, zap.New(runCore))
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, runLogs.FilterMessage("Failed to create environment fitness calculator").Len())
+	assert.Zero(t, globalLogs.FilterMessage("Failed to create environment fitness calculator").Len())
+}
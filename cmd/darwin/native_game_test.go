@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bxrne/darwin/internal/cfg"
+	"github.com/bxrne/darwin/internal/individual"
+	"github.com/bxrne/darwin/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestRunEvolution_GIVEN_native_backend_WHEN_run_THEN_plays_without_game_server(t *testing.T) {
+	config, err := cfg.LoadConfig("../../config/default.toml")
+	assert.NoError(t, err)
+	config.ActionTree.Backend = "native"
+	config.ActionTree.MaxSteps = 60
+	config.ActionTree.WeightsCount = 3
+	config.Evolution.PopulationSize = 4
+	config.Evolution.Generations = 2
+	config.Fitness.TestCaseCount = 2
+	config.Metrics.CSVEnabled = false
+
+	generations := 0
+	handler := func(m metrics.GenerationMetrics) { generations++ }
+	finalPop, metricsComplete, err := RunEvolution(context.Background(), config, handler, zap.NewNop())
+	assert.NoError(t, err)
+	<-metricsComplete
+
+	assert.NotEmpty(t, finalPop)
+	assert.Equal(t, 2, generations)
+}
+
+func TestRunEvolution_GIVEN_cartpole_environment_WHEN_run_THEN_evolves_single_head_policy(t *testing.T) {
+	config, err := cfg.LoadConfig("../../config/default.toml")
+	assert.NoError(t, err)
+	config.ActionTree.Backend = "native"
+	config.ActionTree.Environment = "cartpole"
+	config.ActionTree.Actions = []individual.ActionTuple{{Name: "push", Value: 2}}
+	config.ActionTree.MaxSteps = 100
+	config.ActionTree.WeightsCount = 3
+	config.Tree.VariableSet = []string{"cart_position", "cart_velocity", "pole_angle", "pole_angular_velocity"}
+	config.Evolution.PopulationSize = 4
+	config.Evolution.Generations = 2
+	config.Fitness.TestCaseCount = 2
+	config.Metrics.CSVEnabled = false
+
+	var best float64
+	handler := func(m metrics.GenerationMetrics) { best = max(best, m.Metrics["max_fit"]) }
+	finalPop, metricsComplete, err := RunEvolution(context.Background(), config, handler, zap.NewNop())
+	assert.NoError(t, err)
+	<-metricsComplete
+
+	assert.NotEmpty(t, finalPop)
+	assert.Greater(t, best, 0.0) // every CartPole step survived pays 1
+}
+
+func TestRunEvolution_GIVEN_league_WHEN_run_THEN_rating_is_the_fitness(t *testing.T) {
+	config, err := cfg.LoadConfig("../../config/default.toml")
+	assert.NoError(t, err)
+	config.ActionTree.Backend = "native"
+	config.ActionTree.MaxSteps = 40
+	config.ActionTree.WeightsCount = 2
+	config.ActionTree.League.Enabled = true
+	config.ActionTree.League.Sampling = "prioritized"
+	config.ActionTree.League.Fitness = "rating"
+	config.Evolution.PopulationSize = 4
+	config.Evolution.Generations = 3
+	config.Fitness.TestCaseCount = 2
+	config.Metrics.CSVEnabled = false
+
+	var ratings []float64
+	handler := func(m metrics.GenerationMetrics) { ratings = append(ratings, m.Metrics["avg_fit"]) }
+	finalPop, metricsComplete, err := RunEvolution(context.Background(), config, handler, zap.NewNop())
+	assert.NoError(t, err)
+	<-metricsComplete
+
+	assert.NotEmpty(t, finalPop)
+	assert.Len(t, ratings, 3)
+	// Nobody is rated until the pool has a champion after the first generation
+	assert.Equal(t, 1200.0, ratings[0])
+	assert.NotEqual(t, 1200.0, ratings[2])
+}
+
+func TestRunEvolution_GIVEN_simultaneous_coevolution_WHEN_run_THEN_both_populations_evolve(t *testing.T) {
+	config, err := cfg.LoadConfig("../../config/default.toml")
+	assert.NoError(t, err)
+	config.ActionTree.Backend = "native"
+	config.ActionTree.MaxSteps = 40
+	config.ActionTree.WeightsCount = 3
+	config.ActionTree.Coevolution.Mode = "simultaneous"
+	config.ActionTree.Coevolution.Collaborators = "random"
+	config.ActionTree.Coevolution.CollaboratorCount = 2
+	config.ActionTree.Coevolution.Credit = "mean"
+	config.Evolution.PopulationSize = 4
+	config.Evolution.Generations = 2
+	config.Fitness.TestCaseCount = 1
+	config.Metrics.CSVEnabled = false
+
+	generations := 0
+	handler := func(m metrics.GenerationMetrics) { generations++ }
+	finalPop, metricsComplete, err := RunEvolution(context.Background(), config, handler, zap.NewNop())
+	assert.NoError(t, err)
+	<-metricsComplete
+
+	assert.NotEmpty(t, finalPop)
+	assert.Equal(t, 2, generations)
+}
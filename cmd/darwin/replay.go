@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bxrne/darwin/internal/replay"
+)
+
+// clearScreen moves the cursor home and clears the terminal between frames
+const clearScreen = "\033[H\033[2J"
+
+// runReplayCommand handles `darwin replay`, stepping through a recorded game in the terminal
+func runReplayCommand(args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	step := fs.Int("step", 0, "Print only this step (1-based) and exit")
+	play := fs.Duration("play", 0, "Advance automatically every interval, e.g. 200ms, instead of waiting for input")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: darwin replay [-step n | -play interval] <replay.jsonl[.gz]>")
+	}
+
+	r, err := replay.Read(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if len(r.Steps) == 0 {
+		return fmt.Errorf("replay %s has no steps", fs.Arg(0))
+	}
+	if *step > 0 {
+		return replay.Render(os.Stdout, r, *step-1)
+	}
+	if *play > 0 {
+		for i := range r.Steps {
+			fmt.Print(clearScreen)
+			if err := replay.Render(os.Stdout, r, i); err != nil {
+				return err
+			}
+			time.Sleep(*play)
+		}
+		return nil
+	}
+	return browseReplay(os.Stdin, os.Stdout, r)
+}
+
+// browseReplay shows one step at a time, reading commands from in: enter or n for the next
+// step, b for the previous, a step number to jump, t for the trees and q to quit
+func browseReplay(in io.Reader, out io.Writer, r *replay.Replay) error {
+	input := bufio.NewScanner(in)
+	index := 0
+	for {
+		fmt.Fprint(out, clearScreen)
+		if err := replay.Render(out, r, index); err != nil {
+			return err
+		}
+		fmt.Fprint(out, "\n[enter] next  [b] back  [n] step n  [t] trees  [q] quit > ")
+		if !input.Scan() {
+			fmt.Fprintln(out)
+			return input.Err()
+		}
+
+		command := strings.TrimSpace(input.Text())
+		switch command {
+		case "", "n":
+			index = min(index+1, len(r.Steps)-1)
+		case "b":
+			index = max(index-1, 0)
+		case "q":
+			return nil
+		case "t":
+			printTrees(out, r)
+			fmt.Fprint(out, "\n[enter] back to the game > ")
+			input.Scan()
+		default:
+			if n, err := strconv.Atoi(command); err == nil {
+				index = min(max(n-1, 0), len(r.Steps)-1)
+			}
+		}
+	}
+}
+
+// printTrees lists the action trees that played the game
+func printTrees(out io.Writer, r *replay.Replay) {
+	fmt.Fprint(out, clearScreen)
+	fmt.Fprintf(out, "%s  total reward %.2f\n\n", r.Game, r.TotalReward)
+	names := make([]string, 0, len(r.Trees))
+	for name := range r.Trees {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(out, "%s:\n  %s\n", name, r.Trees[name])
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bxrne/darwin/internal/replay"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBrowseReplay_GIVEN_commands_WHEN_browsing_THEN_moves_between_steps(t *testing.T) {
+	r := replay.New("client_7", []string{"direction"}, map[string]string{"direction": "(goal_dx + 1)"})
+	for step := range 4 {
+		r.Add(replay.Step{Step: step, Features: map[string]float64{"timestep": float64(step)}})
+	}
+	var out strings.Builder
+
+	// next, next, back, jump to 4, trees, quit
+	require.NoError(t, browseReplay(strings.NewReader("\nn\nb\n4\nt\n\nq\n"), &out, r))
+
+	frames := strings.Split(out.String(), clearScreen)[1:]
+	steps := []string{}
+	for _, frame := range frames {
+		if strings.HasPrefix(frame, "client_7  step") {
+			steps = append(steps, strings.Fields(frame)[2])
+		}
+	}
+	assert.Equal(t, []string{"1/4", "2/4", "3/4", "2/4", "4/4", "4/4"}, steps)
+	assert.Contains(t, out.String(), "direction:\n  (goal_dx + 1)")
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"github.com/bxrne/darwin/internal/experiment"
+	"github.com/bxrne/darwin/internal/individual"
+	"github.com/bxrne/darwin/internal/metrics"
+	"go.uber.org/zap"
+)
+
+// runSweepCommand handles `darwin sweep`, running every point of a sweep spec over several seeds
+func runSweepCommand(args []string) error {
+	fs := flag.NewFlagSet("sweep", flag.ExitOnError)
+	specPath := fs.String("spec", "config/sweep.toml", "Path to sweep spec file")
+	parallel := fs.Int("parallel", 0, "Number of runs to execute at once (overrides spec)")
+	verbose := fs.Bool("verbose", false, "Log each run's generations")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	spec, err := experiment.LoadSweepSpec(*specPath)
+	if err != nil {
+		return err
+	}
+	if *parallel > 0 {
+		spec.Parallel = *parallel
+	}
+
+	logger, err := zap.NewDevelopment()
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	defer func() {
+		_ = logger.Sync()
+	}()
+	zap.ReplaceGlobals(logger)
+
+	runs, err := spec.Runs()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(spec.OutputDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create output dir: %w", err)
+	}
+
+	runLogger := zap.NewNop()
+	if *verbose {
+		runLogger = logger
+	}
+
+	logger.Info("Starting sweep",
+		zap.Int("points", len(spec.Points())),
+		zap.Int("seeds", spec.Seeds),
+		zap.Int("runs", len(runs)),
+		zap.String("output_dir", spec.OutputDir))
+
+	results := RunSweep(context.Background(), runs, spec.Parallel, runLogger, func(r experiment.RunResult) {
+		if r.Err != nil {
+			logger.Error("Run failed", zap.String("point", r.Point.ID), zap.Int64("seed", r.Seed), zap.Error(r.Err))
+			return
+		}
+		logger.Info("Run finished", zap.String("point", r.Point.ID), zap.Int64("seed", r.Seed), zap.Float64("best", r.FinalBest))
+	})
+
+	summaries := experiment.Summarise(results)
+	if err := experiment.WriteSummaryCSV(filepath.Join(spec.OutputDir, "summary.csv"), summaries); err != nil {
+		return err
+	}
+	if err := experiment.WriteSummaryMarkdown(filepath.Join(spec.OutputDir, "summary.md"), summaries); err != nil {
+		return err
+	}
+
+	logger.Info("Sweep finished", zap.String("summary", filepath.Join(spec.OutputDir, "summary.csv")))
+	return nil
+}
+
+// RunSweep executes runs with up to `parallel` concurrent evolutions.
+// onResult is called as each run completes; results are returned in run order.
+func RunSweep(ctx context.Context, runs []experiment.Run, parallel int, logger *zap.Logger, onResult func(experiment.RunResult)) []experiment.RunResult {
+	if parallel <= 0 {
+		parallel = runtime.NumCPU()
+	}
+
+	results := make([]experiment.RunResult, len(runs))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+
+	for range parallel {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				result := executeRun(ctx, runs[i], logger)
+				results[i] = result
+				if onResult != nil {
+					mu.Lock()
+					onResult(result)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for i := range runs {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// executeRun runs one evolution and writes its metrics CSV, resolved config and champion to run.Dir
+func executeRun(ctx context.Context, run experiment.Run, logger *zap.Logger) experiment.RunResult {
+	result := experiment.RunResult{Point: run.Point, Seed: run.Seed}
+
+	if err := os.MkdirAll(run.Dir, 0o755); err != nil {
+		result.Err = fmt.Errorf("failed to create run dir: %w", err)
+		return result
+	}
+	if err := experiment.WriteResolvedConfig(filepath.Join(run.Dir, "config.toml"), run.Config); err != nil {
+		result.Err = err
+		return result
+	}
+
+	csvWriter, err := metrics.NewCSVWriter(filepath.Join(run.Dir, "metrics.csv"))
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	defer csvWriter.Close()
+
+	var last metrics.GenerationMetrics
+	handler := func(m metrics.GenerationMetrics) {
+		if err := csvWriter.WriteMetrics(m); err != nil {
+			logger.Warn("Failed to write metrics", zap.Error(err))
+		}
+		last = m
+	}
+
+	finalPop, metricsComplete, err := RunEvolution(ctx, run.Config, handler, logger)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	<-metricsComplete
+
+	result.FinalBest = last.Metrics["max_fit"]
+	if champion := bestIndividual(finalPop); champion != nil {
+		if err := os.WriteFile(filepath.Join(run.Dir, "champion.txt"), []byte(champion.Describe()+"\n"), 0o644); err != nil {
+			result.Err = fmt.Errorf("failed to write champion: %w", err)
+		}
+	}
+	return result
+}
+
+// bestIndividual returns the fittest individual in a population
+func bestIndividual(pop []individual.Evolvable) individual.Evolvable {
+	var best individual.Evolvable
+	for _, ind := range pop {
+		if best == nil || ind.GetFitness() > best.GetFitness() {
+			best = ind
+		}
+	}
+	return best
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bxrne/darwin/internal/experiment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRunSweep_GIVEN_same_runs_WHEN_run_in_parallel_THEN_same_results_as_sequential(t *testing.T) {
+	spec, err := experiment.LoadSweepSpec("../../config/sweep.toml")
+	require.NoError(t, err)
+	spec.Seeds = 4
+	spec.Grid = map[string][]any{"evolution.mutation_rate": {0.05}}
+	spec.Overrides["evolution.population_size"] = 20
+	spec.Overrides["evolution.generations"] = 5
+
+	spec.OutputDir = t.TempDir()
+	runs, err := spec.Runs()
+	require.NoError(t, err)
+	sequential := RunSweep(context.Background(), runs, 1, zap.NewNop(), nil)
+
+	spec.OutputDir = t.TempDir()
+	runs, err = spec.Runs()
+	require.NoError(t, err)
+	parallel := RunSweep(context.Background(), runs, len(runs), zap.NewNop(), nil)
+
+	require.Len(t, parallel, len(sequential))
+	for i := range sequential {
+		assert.NoError(t, sequential[i].Err)
+		assert.NoError(t, parallel[i].Err)
+		assert.Equal(t, sequential[i].Seed, parallel[i].Seed)
+		assert.Equal(t, sequential[i].FinalBest, parallel[i].FinalBest, "seed %d", sequential[i].Seed)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bxrne/darwin/internal/cfg"
+	"github.com/bxrne/darwin/internal/metrics"
+	"github.com/bxrne/darwin/internal/mockserver"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestRunEvolution_GIVEN_mock_game_server_WHEN_run_THEN_plays_over_tcp(t *testing.T) {
+	server, err := mockserver.Start(mockserver.Scenario{Rewards: []float64{4, 1, 1}, TerminateAt: 5})
+	assert.NoError(t, err)
+	defer server.Close()
+
+	config, err := cfg.LoadConfig("../../config/default.toml")
+	assert.NoError(t, err)
+	config.ActionTree.Backend = "tcp"
+	config.ActionTree.ServerAddr = server.Addr()
+	config.ActionTree.WeightsCount = 2
+	config.ActionTree.ConnectionPoolSize = 2
+	config.Evolution.PopulationSize = 3
+	config.Evolution.Generations = 1
+	config.Fitness.TestCaseCount = 1
+	config.Metrics.CSVEnabled = false
+
+	generations := 0
+	handler := func(m metrics.GenerationMetrics) { generations++ }
+	finalPop, metricsComplete, err := RunEvolution(context.Background(), config, handler, zap.NewNop())
+	assert.NoError(t, err)
+	<-metricsComplete
+
+	assert.NotEmpty(t, finalPop)
+	assert.Equal(t, 1, generations)
+	assert.Equal(t, int64(1), server.Stats().HealthChecks)
+	assert.Positive(t, server.Stats().Games)
+	// Every game runs until the scripted termination
+	assert.Equal(t, server.Stats().Games*5, server.Stats().Actions)
+}
+
+func TestRunEvolution_GIVEN_multiplexed_server_WHEN_run_THEN_games_share_one_connection(t *testing.T) {
+	server, err := mockserver.Start(mockserver.Scenario{Rewards: []float64{4, 1, 1}, TerminateAt: 5})
+	assert.NoError(t, err)
+	defer server.Close()
+
+	config, err := cfg.LoadConfig("../../config/default.toml")
+	assert.NoError(t, err)
+	config.ActionTree.Backend = "tcp"
+	config.ActionTree.ServerAddr = server.Addr()
+	config.ActionTree.Multiplex = true
+	config.ActionTree.WeightsCount = 2
+	config.Evolution.PopulationSize = 3
+	config.Evolution.Generations = 1
+	config.Fitness.TestCaseCount = 1
+	config.Metrics.CSVEnabled = false
+
+	finalPop, metricsComplete, err := RunEvolution(context.Background(), config, nil, zap.NewNop())
+	assert.NoError(t, err)
+	<-metricsComplete
+
+	assert.NotEmpty(t, finalPop)
+	// One connection for the health check and one carrying every game
+	assert.Equal(t, int64(2), server.Stats().Connections)
+	assert.Positive(t, server.Stats().Games)
+	assert.Equal(t, server.Stats().Games*5, server.Stats().Actions)
+}
+
+func TestRunEvolution_GIVEN_failing_server_WHEN_run_THEN_circuit_breaker_aborts(t *testing.T) {
+	server, err := mockserver.Start(mockserver.Scenario{DropAt: 1})
+	assert.NoError(t, err)
+	defer server.Close()
+
+	config, err := cfg.LoadConfig("../../config/default.toml")
+	assert.NoError(t, err)
+	config.ActionTree.Backend = "tcp"
+	config.ActionTree.ServerAddr = server.Addr()
+	config.ActionTree.WeightsCount = 2
+	config.ActionTree.ConnectionPoolSize = 2
+	config.ActionTree.GameRetries = 1
+	config.ActionTree.RetryBackoff = "1ms"
+	config.ActionTree.MaxGameFailures = 3
+	config.Evolution.PopulationSize = 3
+	config.Evolution.Generations = 5
+	config.Fitness.TestCaseCount = 1
+	config.Metrics.CSVEnabled = false
+
+	_, _, err = RunEvolution(context.Background(), config, nil, zap.NewNop())
+
+	assert.ErrorContains(t, err, "fitness evaluation aborted in generation 1")
+	assert.ErrorContains(t, err, "consecutive failed games")
+}
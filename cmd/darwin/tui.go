@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bxrne/darwin/internal/cfg"
+	"github.com/bxrne/darwin/internal/individual"
+	"github.com/bxrne/darwin/internal/metrics"
+	"github.com/bxrne/darwin/internal/tui"
+	"go.uber.org/zap"
+)
+
+// runTUI runs the evolution under the terminal UI, saving the population to savePath if the run
+// is stopped from the keyboard
+func runTUI(ctx context.Context, config *cfg.Config, handler MetricsHandler, logger *zap.Logger, savePath string) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	restore, err := cbreak(os.Stdin)
+	if err != nil {
+		logger.Warn("Terminal left in line mode, press Enter after each key", zap.Error(err))
+		restore = func() {}
+	}
+	defer restore()
+
+	monitor := tui.New(config.Evolution.Generations, config.Evolution.MutationRate)
+	uiCtx, closeUI := context.WithCancel(ctx)
+	uiDone := make(chan struct{})
+	go func() {
+		defer close(uiDone)
+		monitor.Run(uiCtx, os.Stdin, os.Stdout, time.Second)
+	}()
+
+	observe := func(m metrics.GenerationMetrics) {
+		handler(m)
+		monitor.Observe(m)
+	}
+	finalPop, metricsComplete, err := RunEvolutionControlled(ctx, config, observe, logger, monitor)
+	if metricsComplete != nil {
+		<-metricsComplete
+	}
+	closeUI()
+	<-uiDone
+	_ = monitor.Render(os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	if monitor.StopRequested() {
+		if err := savePopulation(savePath, finalPop); err != nil {
+			return err
+		}
+		fmt.Printf("Saved %d individuals to %s\n", len(finalPop), savePath)
+	}
+	return nil
+}
+
+// savePopulation writes one encoded individual per line, best first
+func savePopulation(path string, population []individual.Evolvable) error {
+	ranked := append([]individual.Evolvable(nil), population...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].GetFitness() > ranked[j].GetFitness()
+	})
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer file.Close()
+	return individual.WritePopulation(file, ranked)
+}
+
+// cbreak hands keystrokes over as they are typed, without echo, returning a function restoring
+// the terminal. It shells out to stty to stay free of terminal dependencies.
+func cbreak(terminal *os.File) (func(), error) {
+	state, err := stty(terminal, "-g")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := stty(terminal, "-icanon", "-echo", "min", "1"); err != nil {
+		return nil, err
+	}
+	return func() {
+		_, _ = stty(terminal, strings.TrimSpace(state))
+	}, nil
+}
+
+func stty(terminal *os.File, args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = terminal
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("stty %s failed: %w", strings.Join(args, " "), err)
+	}
+	return string(out), nil
+}
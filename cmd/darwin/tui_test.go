@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bxrne/darwin/internal/cfg"
+	"github.com/bxrne/darwin/internal/evolution"
+	"github.com/bxrne/darwin/internal/fitness"
+	"github.com/bxrne/darwin/internal/individual"
+	"github.com/bxrne/darwin/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// scriptedControl sends commands queued by the test
+type scriptedControl struct {
+	commands chan evolution.EvolutionCommand
+	attached fitness.FitnessCalculator
+}
+
+func (sc *scriptedControl) Commands() <-chan evolution.EvolutionCommand {
+	return sc.commands
+}
+
+func (sc *scriptedControl) Attach(calculator fitness.FitnessCalculator) {
+	sc.attached = calculator
+}
+
+func smallNativeConfig(t *testing.T, generations int) *cfg.Config {
+	t.Helper()
+	config, err := cfg.LoadConfig("../../config/default.toml")
+	require.NoError(t, err)
+	config.ActionTree.Backend = "native"
+	config.ActionTree.MaxSteps = 20
+	config.Evolution.PopulationSize = 4
+	config.Evolution.Generations = generations
+	config.Fitness.TestCaseCount = 1
+	config.Metrics.CSVEnabled = false
+	return config
+}
+
+// countGenerations returns a handler counting generations and a function reading the count
+func countGenerations() (MetricsHandler, func() int) {
+	var mu sync.Mutex
+	count := 0
+	return func(m metrics.GenerationMetrics) {
+			mu.Lock()
+			defer mu.Unlock()
+			count++
+		}, func() int {
+			mu.Lock()
+			defer mu.Unlock()
+			return count
+		}
+}
+
+func TestRunEvolutionControlled_GIVEN_stop_command_WHEN_run_THEN_ends_early_with_population(t *testing.T) {
+	config := smallNativeConfig(t, 20)
+	control := &scriptedControl{commands: make(chan evolution.EvolutionCommand, 1)}
+	control.commands <- evolution.EvolutionCommand{Type: evolution.CmdStop}
+	handler, generations := countGenerations()
+
+	finalPop, metricsComplete, err := RunEvolutionControlled(context.Background(), config, handler, zap.NewNop(), control)
+	require.NoError(t, err)
+	<-metricsComplete
+
+	assert.NotNil(t, control.attached)
+	assert.Len(t, finalPop, 4)
+	assert.LessOrEqual(t, generations(), 1)
+}
+
+func TestRunEvolutionControlled_GIVEN_pause_WHEN_resumed_THEN_completes_every_generation(t *testing.T) {
+	config := smallNativeConfig(t, 3)
+	control := &scriptedControl{commands: make(chan evolution.EvolutionCommand, 1)}
+	control.commands <- evolution.EvolutionCommand{Type: evolution.CmdPause}
+	handler, generations := countGenerations()
+
+	type outcome struct {
+		complete MetricsComplete
+		err      error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		_, metricsComplete, err := RunEvolutionControlled(context.Background(), config, handler, zap.NewNop(), control)
+		done <- outcome{metricsComplete, err}
+	}()
+
+	time.Sleep(200 * time.Millisecond)
+	assert.LessOrEqual(t, generations(), 1, "at most the generation sent before the pause runs")
+	select {
+	case <-done:
+		t.Fatal("run finished while paused")
+	default:
+	}
+	control.commands <- evolution.EvolutionCommand{Type: evolution.CmdResume}
+
+	result := <-done
+	require.NoError(t, result.err)
+	<-result.complete
+	assert.Equal(t, 3, generations())
+}
+
+func TestSavePopulation_GIVEN_population_WHEN_saved_THEN_best_first_and_decodable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "population.jsonl")
+	worse, better := individual.NewBinaryIndividual(nil, 8), individual.NewBinaryIndividual(nil, 8)
+	worse.SetFitness(0.25)
+	better.SetFitness(0.75)
+
+	require.NoError(t, savePopulation(path, []individual.Evolvable{worse, better}))
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	saved, err := individual.ReadPopulation(file)
+	require.NoError(t, err)
+	require.Len(t, saved, 2)
+	assert.Equal(t, 0.75, saved[0].GetFitness())
+	assert.Equal(t, 0.25, saved[1].GetFitness())
+}
+
+func TestRunEvolutionControlled_GIVEN_control_api_and_pausing_control_WHEN_resumed_over_api_THEN_completes(t *testing.T) {
+	config := smallNativeConfig(t, 2)
+	config.Control.Enabled = true
+	config.Control.Listen = freeAddr(t)
+	pauser := &scriptedControl{commands: make(chan evolution.EvolutionCommand, 1)}
+	pauser.commands <- evolution.EvolutionCommand{Type: evolution.CmdPause}
+	handler, generations := countGenerations()
+
+	done := make(chan error, 1)
+	go func() {
+		_, metricsComplete, err := RunEvolutionControlled(context.Background(), config, handler, zap.NewNop(), pauser)
+		if err == nil {
+			<-metricsComplete
+		}
+		done <- err
+	}()
+
+	api := "http://" + config.Control.Listen
+	var status evolution.Status
+	require.Eventually(t, func() bool {
+		resp, err := http.Get(api + "/status")
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close()
+		return json.NewDecoder(resp.Body).Decode(&status) == nil && status.Paused
+	}, 5*time.Second, 20*time.Millisecond)
+	resp, err := http.Post(api+"/resume", "application/json", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	require.NoError(t, <-done)
+	assert.Equal(t, 2, generations())
+}
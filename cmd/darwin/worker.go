@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/bxrne/darwin/internal/cfg"
+	"github.com/bxrne/darwin/internal/distributed"
+	"github.com/bxrne/darwin/internal/plugin"
+	"github.com/bxrne/darwin/internal/rng"
+	"go.uber.org/zap"
+)
+
+// runWorkerCommand handles `darwin worker`, scoring individuals for a distributed coordinator
+func runWorkerCommand(args []string) error {
+	fs := flag.NewFlagSet("worker", flag.ExitOnError)
+	configPath := fs.String("config", "config/default.toml", "Path to the coordinator's config file")
+	coordinator := fs.String("coordinator", "", "Coordinator address, defaults to [distributed] listen")
+	parallel := fs.Int("parallel", 1, "Individuals to score at once")
+	hostname, _ := os.Hostname()
+	name := fs.String("name", fmt.Sprintf("%s-%d", hostname, os.Getpid()), "Worker name shown in the coordinator's logs")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	config, err := cfg.LoadConfig(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	logger, err := InitializeLogger(config)
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	defer func() {
+		_ = logger.Sync()
+	}()
+	zap.ReplaceGlobals(logger)
+
+	addr := *coordinator
+	if addr == "" {
+		addr = config.Distributed.Listen
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	return RunWorker(ctx, config, addr, *name, *parallel)
+}
+
+// RunWorker builds the config's fitness calculator and scores tasks from the coordinator at addr
+// until ctx is cancelled or the coordinator shuts down
+func RunWorker(ctx context.Context, config *cfg.Config, addr, name string, parallel int) error {
+	// Calculators drawing test cases from the rng must draw the coordinator's
+	components, err := plugin.Build(config, rng.New(config.Evolution.Seed), zap.L())
+	if err != nil {
+		return err
+	}
+	if components.Fitness == nil {
+		return fmt.Errorf("failed to build fitness calculator %s", config.FitnessName())
+	}
+	if cleanupCalc, ok := components.Fitness.(interface{ Close() error }); ok {
+		defer func() {
+			if err := cleanupCalc.Close(); err != nil {
+				zap.L().Error("Failed to cleanup fitness calculator", zap.Error(err))
+			}
+		}()
+	}
+	return distributed.RunWorker(ctx, addr, name, components.Fitness, parallel)
+}
@@ -257,6 +257,12 @@ def worker_process(
                         # This prevents the "disconnected (reader)" log message
                         break
 
+                    elif msg_type == MessageType.PING:
+                        # Liveness check for pooled connections
+                        client_socket.sendall(
+                            (json.dumps({"type": MessageType.PONG}) + "\n").encode("utf-8")
+                        )
+
                     elif msg_type == MessageType.RESET:
                         if not game:
                             err = ErrorResponse(
@@ -232,7 +232,7 @@ def extract_features(state, my_id):
     N = len(armies)
     M = len(armies[0])
     owned_cells = state[my_id]["owned_cells"]
-    opponent_cells = state[my_id]["owned_cells"]
+    opponent_cells = state[my_id]["opponent_cells"]
     mountain_cells = state[my_id]["mountains"]
     cities_cells = state[my_id]["cities"]
     generals_cells = state[my_id]["generals"]
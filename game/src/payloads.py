@@ -32,6 +32,7 @@ class MessageType:
     RESET = "reset"
     SAVE_REPLAY = "save_replay"
     HEALTH = "health"
+    PING = "ping"
 
     # Server -> Client
     CONNECTED = "connected"
@@ -39,6 +40,7 @@ class MessageType:
     ERROR = "error"
     GAME_OVER = "game_over"
     HEALTH_RESPONSE = "health_response"
+    PONG = "pong"
 
 
 @dataclass
@@ -0,0 +1,80 @@
+// Package adaptive changes variation rates and operator choices while a run goes, from what the
+// previous generations achieved. The engine asks a Controller for the rates of each generation
+// and reports the generation's outcome back to it; optional interfaces let a controller also
+// choose each child's tree mutation type or have each individual carry its own rate.
+package adaptive
+
+import (
+	"math"
+
+	"github.com/bxrne/darwin/internal/rng"
+)
+
+// Rates are the mutation and crossover rates of one generation
+type Rates struct {
+	Mutation  float64
+	Crossover float64
+}
+
+// Child is a bred individual compared with the best of its parents
+type Child struct {
+	Operator     string // lineage.OpCrossover or lineage.OpMutation
+	MutationType string // set if a MutationSelector chose it
+	Improved     bool   // scored higher than every parent
+}
+
+// Outcome is what a generation achieved
+type Outcome struct {
+	Generation int
+	Rates      Rates
+	Children   []Child            // offspring bred, elites left out
+	Metrics    map[string]float64 // the generation's metrics, diversity included
+}
+
+// SuccessRate is the fraction of children that improved on their parents
+func (o Outcome) SuccessRate() float64 {
+	if len(o.Children) == 0 {
+		return 0
+	}
+	improved := 0
+	for _, c := range o.Children {
+		if c.Improved {
+			improved++
+		}
+	}
+	return float64(improved) / float64(len(o.Children))
+}
+
+// Controller sets the rates of each generation
+type Controller interface {
+	// Rates returns the rates to breed the next generation with, given those configured or set
+	// through the control API
+	Rates(base Rates) Rates
+	// Observe learns from a bred and scored generation
+	Observe(outcome Outcome)
+}
+
+// MutationSelector is a Controller that picks the tree mutation type of each child; see
+// individual.TreeMutationTypes. It is called once per child, in breeding order, before the
+// children are bred; any draw comes from r, the run's generator.
+type MutationSelector interface {
+	MutationType(r *rng.Rand) string
+}
+
+// RateInheritor is a Controller whose rates individuals carry in their genome (see
+// individual.SelfAdaptive). It is called from concurrent breeding goroutines.
+type RateInheritor interface {
+	// ChildRate varies the rates parents carry into their child's, drawing from r; parents
+	// without one count as carrying base
+	ChildRate(parents []float64, base float64, r *rng.Rand) float64
+}
+
+// Reporter is a Controller with state worth logging each generation
+type Reporter interface {
+	Report() map[string]float64
+}
+
+// clamp keeps rate within [lo, hi]
+func clamp(rate, lo, hi float64) float64 {
+	return math.Min(math.Max(rate, lo), hi)
+}
@@ -0,0 +1,160 @@
+package adaptive_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bxrne/darwin/internal/adaptive"
+	"github.com/bxrne/darwin/internal/rng"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// outcome is a generation in which improved of n children beat their parents, all given
+// mutationType
+func outcome(n, improved int, mutationType string) adaptive.Outcome {
+	children := make([]adaptive.Child, n)
+	for i := range children {
+		children[i] = adaptive.Child{Operator: "mutation", MutationType: mutationType, Improved: i < improved}
+	}
+	return adaptive.Outcome{Children: children}
+}
+
+func TestOneFifth_GIVEN_success_rates_WHEN_observed_THEN_mutation_rate_follows_the_rule(t *testing.T) {
+	controller, err := adaptive.NewOneFifth(2, 0.01, 0.8)
+	require.NoError(t, err)
+	base := adaptive.Rates{Mutation: 0.1, Crossover: 0.9}
+
+	assert.Equal(t, base, controller.Rates(base))
+	controller.Observe(outcome(10, 5, ""))
+	assert.Equal(t, adaptive.Rates{Mutation: 0.2, Crossover: 0.9}, controller.Rates(base))
+	controller.Observe(outcome(10, 0, ""))
+	controller.Observe(outcome(10, 0, ""))
+	assert.InDelta(t, 0.05, controller.Rates(base).Mutation, 1e-12)
+	controller.Observe(outcome(10, 2, ""))
+	assert.InDelta(t, 0.05, controller.Rates(base).Mutation, 1e-12, "exactly a fifth leaves the rate")
+	assert.Equal(t, 0.2, controller.Report()["success_rate"])
+}
+
+func TestOneFifth_GIVEN_rate_at_bound_WHEN_more_successes_THEN_recovers_at_once(t *testing.T) {
+	controller, err := adaptive.NewOneFifth(2, 0.01, 0.4)
+	require.NoError(t, err)
+	base := adaptive.Rates{Mutation: 0.1}
+
+	for range 10 {
+		controller.Rates(base)
+		controller.Observe(outcome(10, 10, ""))
+	}
+	assert.Equal(t, 0.4, controller.Rates(base).Mutation)
+	controller.Observe(outcome(10, 0, ""))
+
+	assert.Equal(t, 0.2, controller.Rates(base).Mutation)
+}
+
+func TestNewOneFifth_GIVEN_bad_options_WHEN_created_THEN_returns_error(t *testing.T) {
+	_, err := adaptive.NewOneFifth(1, 0, 1)
+	assert.Error(t, err)
+	_, err = adaptive.NewOneFifth(2, 0.5, 0.1)
+	assert.Error(t, err)
+}
+
+func TestDiversityTarget_GIVEN_diversity_WHEN_observed_THEN_rate_moves_towards_target(t *testing.T) {
+	controller, err := adaptive.NewDiversityTarget("diversity_hamming", 0.2, 2, 0, 1)
+	require.NoError(t, err)
+	base := adaptive.Rates{Mutation: 0.1}
+
+	controller.Rates(base)
+	controller.Observe(adaptive.Outcome{Metrics: map[string]float64{"diversity_hamming": 0.05}})
+	assert.Equal(t, 0.2, controller.Rates(base).Mutation)
+	controller.Observe(adaptive.Outcome{Metrics: map[string]float64{"diversity_hamming": 0.5}})
+	controller.Observe(adaptive.Outcome{Metrics: map[string]float64{"diversity_hamming": 0.5}})
+	assert.Equal(t, 0.05, controller.Rates(base).Mutation)
+	controller.Observe(adaptive.Outcome{Metrics: map[string]float64{"max_fit": 1}})
+	assert.Equal(t, 0.05, controller.Rates(base).Mutation, "a missing metric leaves the rate")
+
+	_, err = adaptive.NewDiversityTarget("diversity_hamming", 0, 2, 0, 1)
+	assert.Error(t, err)
+}
+
+func TestProbabilityMatching_GIVEN_rewarded_type_WHEN_observed_THEN_its_probability_grows_above_floor(t *testing.T) {
+	types := []string{"point", "shrink", "grow"}
+	controller, err := adaptive.NewProbabilityMatching(types, 0.1, 0.5)
+	require.NoError(t, err)
+	assert.InDelta(t, 1.0/3, controller.Report()["mutation_p_point"], 1e-12)
+
+	for range 10 {
+		controller.Observe(outcome(10, 8, "grow"))
+		controller.Observe(outcome(10, 0, "point"))
+		controller.Observe(outcome(10, 0, "shrink"))
+	}
+	report := controller.Report()
+
+	assert.Greater(t, report["mutation_p_grow"], 0.75)
+	assert.GreaterOrEqual(t, report["mutation_p_point"], 0.1)
+	assert.InDelta(t, 1, report["mutation_p_point"]+report["mutation_p_shrink"]+report["mutation_p_grow"], 1e-12)
+	rng.Seed(1)
+	picks := map[string]int{}
+	for range 1000 {
+		picks[controller.MutationType(nil)]++
+	}
+	assert.Greater(t, picks["grow"], 700)
+}
+
+func TestNewProbabilityMatching_GIVEN_floor_too_high_WHEN_created_THEN_returns_error(t *testing.T) {
+	_, err := adaptive.NewProbabilityMatching([]string{"a", "b"}, 0.5, 0.3)
+
+	assert.Error(t, err)
+}
+
+func TestBandit_GIVEN_untried_types_WHEN_picking_THEN_tries_each_first(t *testing.T) {
+	controller, err := adaptive.NewBandit([]string{"a", "b", "c"}, math.Sqrt2)
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{"a", "b", "c"}, []string{controller.MutationType(nil), controller.MutationType(nil), controller.MutationType(nil)})
+}
+
+func TestBandit_GIVEN_one_type_succeeding_WHEN_picking_THEN_mostly_exploits_it(t *testing.T) {
+	controller, err := adaptive.NewBandit([]string{"a", "b"}, 0.5)
+	require.NoError(t, err)
+
+	for range 20 {
+		var children []adaptive.Child
+		for range 10 {
+			t := controller.MutationType(nil)
+			children = append(children, adaptive.Child{MutationType: t, Improved: t == "b"})
+		}
+		controller.Observe(adaptive.Outcome{Children: children})
+	}
+	for range 10 {
+		controller.MutationType(nil)
+	}
+
+	assert.Greater(t, controller.Report()["mutation_p_b"], 0.8)
+}
+
+func TestSelfAdaptive_GIVEN_parents_WHEN_child_rate_THEN_varies_their_mean_within_bounds(t *testing.T) {
+	controller, err := adaptive.NewSelfAdaptive(0.2, 0.01, 0.5)
+	require.NoError(t, err)
+	rng.Seed(3)
+
+	rates := make([]float64, 200)
+	for i := range rates {
+		rates[i] = controller.ChildRate([]float64{0.1, 0.3}, 0.05, nil)
+	}
+
+	mean := 0.0
+	for _, r := range rates {
+		assert.GreaterOrEqual(t, r, 0.01)
+		assert.LessOrEqual(t, r, 0.5)
+		mean += r / float64(len(rates))
+	}
+	assert.InDelta(t, 0.2, mean, 0.02)
+	assert.NotEqual(t, rates[0], rates[1])
+	assert.Equal(t, 0.5, controller.ChildRate([]float64{0.9}, 0.1, nil), "clamped to max_rate")
+	assert.InDelta(t, 0.05, controller.ChildRate([]float64{0}, 0.05, nil), 0.05, "unset parents carry the base rate")
+}
+
+func TestOutcome_SuccessRate_GIVEN_no_children_WHEN_computed_THEN_zero(t *testing.T) {
+	assert.Equal(t, 0.0, adaptive.Outcome{}.SuccessRate())
+	assert.Equal(t, 0.3, outcome(10, 3, "").SuccessRate())
+}
@@ -0,0 +1,381 @@
+package adaptive
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/bxrne/darwin/internal/rng"
+)
+
+// scaledRate is a rate kept as a multiple of the base rate, so a rate set through the control API
+// moves the adapted rate with it
+type scaledRate struct {
+	factor  float64
+	minRate float64
+	maxRate float64
+	scale   float64
+	base    float64
+}
+
+func newScaledRate(factor, minRate, maxRate float64) (scaledRate, error) {
+	switch {
+	case factor <= 1:
+		return scaledRate{}, fmt.Errorf("factor must be above 1")
+	case minRate < 0 || maxRate > 1 || minRate > maxRate:
+		return scaledRate{}, fmt.Errorf("need 0 <= min_rate <= max_rate <= 1")
+	}
+	return scaledRate{factor: factor, minRate: minRate, maxRate: maxRate, scale: 1}, nil
+}
+
+func (s *scaledRate) apply(base float64) float64 {
+	s.base = base
+	return clamp(base*s.scale, s.minRate, s.maxRate)
+}
+
+// grow and shrink stop the scale at the rate bounds, so it cannot run away while clamped
+func (s *scaledRate) grow() {
+	s.scale *= s.factor
+	if s.base > 0 {
+		s.scale = math.Min(s.scale, s.maxRate/s.base)
+	}
+}
+
+func (s *scaledRate) shrink() {
+	s.scale /= s.factor
+	if s.base > 0 {
+		s.scale = math.Max(s.scale, s.minRate/s.base)
+	}
+}
+
+// OneFifth applies Rechenberg's 1/5th success rule to the mutation rate: it grows by the factor
+// after a generation in which more than a fifth of the children beat their parents, and shrinks
+// by it after one in which fewer did
+type OneFifth struct {
+	rate    scaledRate
+	success float64
+}
+
+// NewOneFifth creates a 1/5th rule controller keeping the mutation rate within [minRate, maxRate]
+func NewOneFifth(factor, minRate, maxRate float64) (*OneFifth, error) {
+	rate, err := newScaledRate(factor, minRate, maxRate)
+	if err != nil {
+		return nil, err
+	}
+	return &OneFifth{rate: rate}, nil
+}
+
+// Rates implements Controller
+func (o *OneFifth) Rates(base Rates) Rates {
+	base.Mutation = o.rate.apply(base.Mutation)
+	return base
+}
+
+// Observe implements Controller
+func (o *OneFifth) Observe(outcome Outcome) {
+	if len(outcome.Children) == 0 {
+		return
+	}
+	o.success = outcome.SuccessRate()
+	switch {
+	case o.success > 0.2:
+		o.rate.grow()
+	case o.success < 0.2:
+		o.rate.shrink()
+	}
+}
+
+// Report implements Reporter
+func (o *OneFifth) Report() map[string]float64 {
+	return map[string]float64{"success_rate": o.success}
+}
+
+// DiversityTarget steers the mutation rate to hold a diversity metric at a target: the rate grows
+// by the factor while diversity is below the target and shrinks while it is above
+type DiversityTarget struct {
+	metric string
+	target float64
+	rate   scaledRate
+}
+
+// NewDiversityTarget creates a controller holding metric, one of the diversity_ metrics, at target
+func NewDiversityTarget(metric string, target, factor, minRate, maxRate float64) (*DiversityTarget, error) {
+	if target <= 0 {
+		return nil, fmt.Errorf("target must be positive")
+	}
+	rate, err := newScaledRate(factor, minRate, maxRate)
+	if err != nil {
+		return nil, err
+	}
+	return &DiversityTarget{metric: metric, target: target, rate: rate}, nil
+}
+
+// Rates implements Controller
+func (d *DiversityTarget) Rates(base Rates) Rates {
+	base.Mutation = d.rate.apply(base.Mutation)
+	return base
+}
+
+// Observe implements Controller; generations without the metric leave the rate alone
+func (d *DiversityTarget) Observe(outcome Outcome) {
+	value, ok := outcome.Metrics[d.metric]
+	if !ok || math.IsNaN(value) {
+		return
+	}
+	switch {
+	case value < d.target:
+		d.rate.grow()
+	case value > d.target:
+		d.rate.shrink()
+	}
+}
+
+// ProbabilityMatching picks each child's mutation type with a probability proportional to how
+// often that type has produced improvements lately, never below a floor so no type starves
+type ProbabilityMatching struct {
+	mu      sync.Mutex
+	types   []string
+	floor   float64
+	alpha   float64
+	quality map[string]float64
+}
+
+// NewProbabilityMatching creates a probability matching selector over types. Each type keeps at
+// least floor probability; alpha is how fast the quality estimates follow new rewards.
+func NewProbabilityMatching(types []string, floor, alpha float64) (*ProbabilityMatching, error) {
+	switch {
+	case len(types) < 2:
+		return nil, fmt.Errorf("need at least two mutation types")
+	case floor < 0 || floor*float64(len(types)) >= 1:
+		return nil, fmt.Errorf("min_probability must be at least 0 and below 1/%d", len(types))
+	case alpha <= 0 || alpha > 1:
+		return nil, fmt.Errorf("adaptation_rate must be in (0, 1]")
+	}
+	quality := make(map[string]float64, len(types))
+	for _, t := range types {
+		quality[t] = 1
+	}
+	return &ProbabilityMatching{types: types, floor: floor, alpha: alpha, quality: quality}, nil
+}
+
+// Rates implements Controller, leaving the rates alone
+func (pm *ProbabilityMatching) Rates(base Rates) Rates {
+	return base
+}
+
+// probabilities must be called with mu held
+func (pm *ProbabilityMatching) probabilities() map[string]float64 {
+	total := 0.0
+	for _, t := range pm.types {
+		total += pm.quality[t]
+	}
+	probabilities := make(map[string]float64, len(pm.types))
+	for _, t := range pm.types {
+		share := 1 / float64(len(pm.types))
+		if total > 0 {
+			share = pm.quality[t] / total
+		}
+		probabilities[t] = pm.floor + (1-float64(len(pm.types))*pm.floor)*share
+	}
+	return probabilities
+}
+
+// MutationType implements MutationSelector
+func (pm *ProbabilityMatching) MutationType(r *rng.Rand) string {
+	pm.mu.Lock()
+	probabilities := pm.probabilities()
+	pm.mu.Unlock()
+	draw := r.Float64()
+	for _, t := range pm.types {
+		draw -= probabilities[t]
+		if draw < 0 {
+			return t
+		}
+	}
+	return pm.types[len(pm.types)-1]
+}
+
+// Observe implements Controller, moving each used type's quality towards its success rate
+func (pm *ProbabilityMatching) Observe(outcome Outcome) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	for t, reward := range rewards(outcome) {
+		if _, ok := pm.quality[t]; ok {
+			pm.quality[t] += pm.alpha * (reward - pm.quality[t])
+		}
+	}
+}
+
+// Report implements Reporter
+func (pm *ProbabilityMatching) Report() map[string]float64 {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	report := make(map[string]float64, len(pm.types))
+	for t, p := range pm.probabilities() {
+		report["mutation_p_"+t] = p
+	}
+	return report
+}
+
+// Bandit picks each child's mutation type with the UCB1 multi-armed bandit: the type with the
+// best success rate so far plus an exploration bonus shrinking as the type is tried more
+type Bandit struct {
+	mu          sync.Mutex
+	types       []string
+	exploration float64
+	pulls       map[string]int // children given the type
+	rewarded    map[string]int // of which were scored
+	improved    map[string]int // of which improved
+	generation  map[string]int // pulls since the last Observe
+}
+
+// NewBandit creates a UCB1 selector over types weighting the exploration bonus by exploration
+func NewBandit(types []string, exploration float64) (*Bandit, error) {
+	switch {
+	case len(types) < 2:
+		return nil, fmt.Errorf("need at least two mutation types")
+	case exploration < 0:
+		return nil, fmt.Errorf("exploration must not be negative")
+	}
+	return &Bandit{
+		types:       types,
+		exploration: exploration,
+		pulls:       make(map[string]int),
+		rewarded:    make(map[string]int),
+		improved:    make(map[string]int),
+		generation:  make(map[string]int),
+	}, nil
+}
+
+// Rates implements Controller, leaving the rates alone
+func (b *Bandit) Rates(base Rates) Rates {
+	return base
+}
+
+// MutationType implements MutationSelector. Counting the pull straight away spreads a
+// generation's children over the types instead of giving them all the current best.
+func (b *Bandit) MutationType(*rng.Rand) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	total := 0
+	for _, t := range b.types {
+		total += b.pulls[t]
+	}
+	best, bestScore := b.types[0], math.Inf(-1)
+	for _, t := range b.types {
+		if b.pulls[t] == 0 {
+			best = t
+			break
+		}
+		mean := 0.0
+		if b.rewarded[t] > 0 {
+			mean = float64(b.improved[t]) / float64(b.rewarded[t])
+		}
+		score := mean + b.exploration*math.Sqrt(math.Log(float64(total))/float64(b.pulls[t]))
+		if score > bestScore {
+			best, bestScore = t, score
+		}
+	}
+	b.pulls[best]++
+	b.generation[best]++
+	return best
+}
+
+// Observe implements Controller
+func (b *Bandit) Observe(outcome Outcome) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, c := range outcome.Children {
+		if c.MutationType == "" {
+			continue
+		}
+		b.rewarded[c.MutationType]++
+		if c.Improved {
+			b.improved[c.MutationType]++
+		}
+	}
+	clear(b.generation)
+}
+
+// Report implements Reporter with the share of the last generation's children given each type
+func (b *Bandit) Report() map[string]float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	total := 0
+	for _, n := range b.generation {
+		total += n
+	}
+	report := make(map[string]float64, len(b.types))
+	for _, t := range b.types {
+		share := 0.0
+		if total > 0 {
+			share = float64(b.generation[t]) / float64(total)
+		}
+		report["mutation_p_"+t] = share
+	}
+	return report
+}
+
+// rewards is the success rate of the children of each mutation type
+func rewards(outcome Outcome) map[string]float64 {
+	children := make(map[string]int)
+	improved := make(map[string]int)
+	for _, c := range outcome.Children {
+		if c.MutationType == "" {
+			continue
+		}
+		children[c.MutationType]++
+		if c.Improved {
+			improved[c.MutationType]++
+		}
+	}
+	rates := make(map[string]float64, len(children))
+	for t, n := range children {
+		rates[t] = float64(improved[t]) / float64(n)
+	}
+	return rates
+}
+
+// SelfAdaptive has every individual carry its own mutation rate, varied log-normally whenever it
+// is inherited, rate·exp(τ·N(0,1)), so selection favours the rates that breed well
+type SelfAdaptive struct {
+	tau     float64
+	minRate float64
+	maxRate float64
+}
+
+// NewSelfAdaptive creates a self-adaptive controller with learning rate tau keeping rates within
+// [minRate, maxRate]
+func NewSelfAdaptive(tau, minRate, maxRate float64) (*SelfAdaptive, error) {
+	switch {
+	case tau <= 0:
+		return nil, fmt.Errorf("tau must be positive")
+	case minRate < 0 || maxRate > 1 || minRate > maxRate:
+		return nil, fmt.Errorf("need 0 <= min_rate <= max_rate <= 1")
+	}
+	return &SelfAdaptive{tau: tau, minRate: minRate, maxRate: maxRate}, nil
+}
+
+// Rates implements Controller, leaving the rates alone; the individuals carry theirs
+func (sa *SelfAdaptive) Rates(base Rates) Rates {
+	return base
+}
+
+// Observe implements Controller; selection does the adapting
+func (sa *SelfAdaptive) Observe(outcome Outcome) {}
+
+// ChildRate implements RateInheritor, varying the mean of the parents' rates
+func (sa *SelfAdaptive) ChildRate(parents []float64, base float64, r *rng.Rand) float64 {
+	rate := base
+	if len(parents) > 0 {
+		sum := 0.0
+		for _, carried := range parents {
+			if carried <= 0 {
+				carried = base
+			}
+			sum += carried
+		}
+		rate = sum / float64(len(parents))
+	}
+	return clamp(rate*math.Exp(sa.tau*r.NormFloat64()), sa.minRate, sa.maxRate)
+}
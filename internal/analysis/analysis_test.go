@@ -0,0 +1,169 @@
+package analysis_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bxrne/darwin/internal/analysis"
+	"github.com/bxrne/darwin/internal/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMannWhitneyU_GIVEN_separated_samples_WHEN_test_THEN_matches_normal_approximation(t *testing.T) {
+	result, err := analysis.MannWhitneyU([]float64{1, 2, 3, 4, 5}, []float64{6, 7, 8, 9, 10})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, result.Statistic)
+	assert.InDelta(t, 0.01219, result.PValue, 1e-4)
+}
+
+func TestMannWhitneyU_GIVEN_identical_samples_WHEN_test_THEN_p_is_one(t *testing.T) {
+	result, err := analysis.MannWhitneyU([]float64{1, 1, 1}, []float64{1, 1, 1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, result.PValue)
+}
+
+func TestWilcoxonSignedRank_GIVEN_consistent_improvement_WHEN_test_THEN_matches_normal_approximation(t *testing.T) {
+	result, err := analysis.WilcoxonSignedRank([]float64{2, 4, 6, 8, 10}, []float64{1, 2, 3, 4, 5})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 15.0, result.Statistic)
+	assert.InDelta(t, 0.0590, result.PValue, 1e-3)
+}
+
+func TestWilcoxonSignedRank_GIVEN_unpaired_lengths_WHEN_test_THEN_error(t *testing.T) {
+	_, err := analysis.WilcoxonSignedRank([]float64{1, 2}, []float64{1})
+
+	assert.Error(t, err)
+}
+
+func TestVarghaDelaneyA12_GIVEN_samples_WHEN_compute_THEN_probability_of_superiority(t *testing.T) {
+	assert.Equal(t, 1.0, analysis.VarghaDelaneyA12([]float64{5, 6}, []float64{1, 2}))
+	assert.Equal(t, 0.5, analysis.VarghaDelaneyA12([]float64{1, 2}, []float64{1, 2}))
+	assert.Equal(t, "large", analysis.EffectMagnitude(1.0))
+	assert.Equal(t, "negligible", analysis.EffectMagnitude(0.52))
+}
+
+func TestMedianCI_GIVEN_ten_values_WHEN_compute_THEN_uses_order_statistics(t *testing.T) {
+	values := []float64{10, 9, 8, 7, 6, 5, 4, 3, 2, 1}
+
+	lower, upper := analysis.MedianCI(values, 0.95)
+
+	assert.Equal(t, 5.5, analysis.Median(values))
+	assert.Equal(t, 2.0, lower)
+	assert.Equal(t, 9.0, upper)
+}
+
+func writeRun(t *testing.T, path string, maxFit []float64) {
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	content := "generation,duration_ns,population_size,timestamp,max_fit\n"
+	for i, v := range maxFit {
+		content += fmt.Sprintf("%d,1,10,2025-01-01T00:00:00.000Z,%f\n", i+1, v)
+	}
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestCompare_GIVEN_sweep_dir_WHEN_compare_THEN_report_written(t *testing.T) {
+	dir := t.TempDir()
+	for seed := 1; seed <= 3; seed++ {
+		writeRun(t, filepath.Join(dir, "p000_low", fmt.Sprintf("seed-%d", seed), "metrics.csv"), []float64{0.1, 0.2, 0.3})
+		writeRun(t, filepath.Join(dir, "p001_high", fmt.Sprintf("seed-%d", seed), "metrics.csv"), []float64{0.5, 0.7, 0.9 + float64(seed)/100})
+	}
+
+	groups, err := analysis.LoadSweepGroups(dir)
+	assert.NoError(t, err)
+	assert.Len(t, groups, 2)
+
+	report, err := analysis.Compare(groups, "max_fit", 0.95)
+	assert.NoError(t, err)
+	assert.Len(t, report.Comparisons, 1)
+	assert.Equal(t, 0.0, report.Comparisons[0].A12)
+	assert.NotNil(t, report.Comparisons[0].Wilcoxon)
+	assert.Len(t, report.Curves["p001_high"], 3)
+
+	out := filepath.Join(dir, "report")
+	assert.NoError(t, report.WriteAll(out))
+	for _, name := range []string{"report.md", "curves.csv", "tests.csv"} {
+		_, err := os.Stat(filepath.Join(out, name))
+		assert.NoError(t, err)
+	}
+}
+
+func TestReadRun_GIVEN_csv_whose_schema_grew_WHEN_read_THEN_uses_sidecar_header(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.csv")
+	writer, err := metrics.NewCSVWriter(path)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.WriteMetrics(metrics.GenerationMetrics{Generation: 1, Metrics: map[string]float64{"max_fit": 1}}))
+	assert.NoError(t, writer.WriteMetrics(metrics.GenerationMetrics{Generation: 2, Metrics: map[string]float64{"max_fit": 2, "diversity": 0.5}}))
+	assert.NoError(t, writer.Close())
+
+	run, err := analysis.ReadRun(path)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 2}, run.Columns["max_fit"])
+	assert.Equal(t, []float64{0, 0.5}, run.Columns["diversity"])
+}
+
+func TestPaired_GIVEN_same_seeds_in_other_path_order_WHEN_paired_THEN_matched_by_seed(t *testing.T) {
+	dir := t.TempDir()
+	for _, seed := range []int{1, 2, 10} {
+		writeRun(t, filepath.Join(dir, "a", fmt.Sprintf("seed-%d", seed), "metrics.csv"), []float64{float64(seed)})
+		writeRun(t, filepath.Join(dir, "b", fmt.Sprintf("seed-%d", seed), "metrics.csv"), []float64{float64(seed) + 0.5})
+	}
+	a, err := analysis.LoadGroup("a", filepath.Join(dir, "a", "seed-*", "metrics.csv"))
+	assert.NoError(t, err)
+	b, err := analysis.LoadGroup("b", filepath.Join(dir, "b", "seed-*", "metrics.csv"))
+	assert.NoError(t, err)
+
+	x, y, ok := analysis.Paired(a, b, "max_fit")
+
+	assert.True(t, ok)
+	assert.Equal(t, []float64{1, 2, 10}, x)
+	assert.Equal(t, []float64{1.5, 2.5, 10.5}, y)
+}
+
+func TestReadRun_GIVEN_resolved_config_WHEN_read_THEN_seed_from_config(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "run-a")
+	writeRun(t, filepath.Join(dir, "metrics.csv"), []float64{0.1})
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "config.toml"), []byte("[evolution]\nseed = 7\n"), 0o644))
+
+	run, err := analysis.ReadRun(filepath.Join(dir, "metrics.csv"))
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, run.Seed) {
+		assert.Equal(t, int64(7), *run.Seed)
+	}
+}
+
+func TestCompare_GIVEN_different_seeds_WHEN_compare_THEN_no_wilcoxon(t *testing.T) {
+	dir := t.TempDir()
+	for i := 1; i <= 3; i++ {
+		writeRun(t, filepath.Join(dir, "p000_low", fmt.Sprintf("seed-%d", i), "metrics.csv"), []float64{0.1 * float64(i)})
+		writeRun(t, filepath.Join(dir, "p001_high", fmt.Sprintf("seed-%d", i+3), "metrics.csv"), []float64{0.5 * float64(i)})
+	}
+	groups, err := analysis.LoadSweepGroups(dir)
+	assert.NoError(t, err)
+
+	report, err := analysis.Compare(groups, "max_fit", 0.95)
+
+	assert.NoError(t, err)
+	assert.Nil(t, report.Comparisons[0].Wilcoxon)
+}
+
+func TestCompare_GIVEN_run_without_metric_WHEN_compare_THEN_returns_error(t *testing.T) {
+	dir := t.TempDir()
+	for seed := 1; seed <= 3; seed++ {
+		writeRun(t, filepath.Join(dir, "p000_low", fmt.Sprintf("seed-%d", seed), "metrics.csv"), []float64{0.1})
+		writeRun(t, filepath.Join(dir, "p001_high", fmt.Sprintf("seed-%d", seed), "metrics.csv"), []float64{0.5})
+	}
+	writeRun(t, filepath.Join(dir, "p001_high", "seed-4", "metrics.csv"), nil)
+	groups, err := analysis.LoadSweepGroups(dir)
+	assert.NoError(t, err)
+
+	_, err = analysis.Compare(groups, "max_fit", 0.95)
+
+	assert.ErrorContains(t, err, "has no values for metric max_fit")
+}
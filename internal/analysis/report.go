@@ -0,0 +1,186 @@
+package analysis
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Comparison holds the pairwise tests between two groups on final metric values
+type Comparison struct {
+	A           string
+	B           string
+	MannWhitney TestResult
+	Wilcoxon    *TestResult
+	A12         float64
+}
+
+// GroupSummary describes the final metric values of one group
+type GroupSummary struct {
+	Name   string
+	Runs   int
+	Median float64
+	Lower  float64
+	Upper  float64
+	Best   float64
+}
+
+// Report is the full comparison of several groups on one metric
+type Report struct {
+	Metric      string
+	Level       float64
+	Groups      []GroupSummary
+	Curves      map[string][]CurvePoint
+	Comparisons []Comparison
+	order       []string
+}
+
+// Compare builds a report for the metric across all groups, running every pairwise test.
+// Every run must have values for the metric. Wilcoxon pairs runs by seed and is only run when
+// both groups ran the same seeds.
+func Compare(groups []Group, metric string, level float64) (*Report, error) {
+	if len(groups) < 2 {
+		return nil, fmt.Errorf("need at least two groups to compare, got %d", len(groups))
+	}
+
+	report := &Report{Metric: metric, Level: level, Curves: make(map[string][]CurvePoint)}
+	finals := make(map[string][]float64, len(groups))
+	for _, g := range groups {
+		final, err := g.Final(metric)
+		if err != nil {
+			return nil, err
+		}
+		if len(final) == 0 {
+			return nil, fmt.Errorf("group %s has no runs", g.Name)
+		}
+		finals[g.Name] = final
+		report.order = append(report.order, g.Name)
+
+		lower, upper := MedianCI(final, level)
+		best := final[0]
+		for _, v := range final {
+			best = max(best, v)
+		}
+		report.Groups = append(report.Groups, GroupSummary{
+			Name: g.Name, Runs: len(final), Median: Median(final), Lower: lower, Upper: upper, Best: best,
+		})
+		report.Curves[g.Name] = g.Curve(metric, level)
+	}
+
+	for i := range groups {
+		for j := i + 1; j < len(groups); j++ {
+			a, b := finals[groups[i].Name], finals[groups[j].Name]
+			mw, err := MannWhitneyU(a, b)
+			if err != nil {
+				return nil, err
+			}
+			comparison := Comparison{A: groups[i].Name, B: groups[j].Name, MannWhitney: mw, A12: VarghaDelaneyA12(a, b)}
+			if x, y, ok := Paired(groups[i], groups[j], metric); ok {
+				if w, err := WilcoxonSignedRank(x, y); err == nil {
+					comparison.Wilcoxon = &w
+				}
+			}
+			report.Comparisons = append(report.Comparisons, comparison)
+		}
+	}
+	return report, nil
+}
+
+// WriteMarkdown renders the report as Markdown
+func (r *Report) WriteMarkdown(path string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Comparison of `%s`\n\n", r.Metric)
+	fmt.Fprintf(&b, "## Final values (median with %.0f%% CI)\n\n", r.Level*100)
+	b.WriteString("| Group | Runs | Median | CI low | CI high | Best |\n")
+	b.WriteString("|-------|------|--------|--------|---------|------|\n")
+	for _, g := range r.Groups {
+		fmt.Fprintf(&b, "| %s | %d | %.4f | %.4f | %.4f | %.4f |\n", g.Name, g.Runs, g.Median, g.Lower, g.Upper, g.Best)
+	}
+
+	b.WriteString("\n## Pairwise tests\n\n")
+	b.WriteString("| A | B | Mann–Whitney U | p | Wilcoxon W+ | p | A12 | Effect |\n")
+	b.WriteString("|---|---|----------------|---|-------------|---|-----|--------|\n")
+	for _, c := range r.Comparisons {
+		wStat, wP := "n/a", "n/a"
+		if c.Wilcoxon != nil {
+			wStat = fmt.Sprintf("%.1f", c.Wilcoxon.Statistic)
+			wP = fmt.Sprintf("%.4f", c.Wilcoxon.PValue)
+		}
+		fmt.Fprintf(&b, "| %s | %s | %.1f | %.4f | %s | %s | %.3f | %s |\n",
+			c.A, c.B, c.MannWhitney.Statistic, c.MannWhitney.PValue, wStat, wP, c.A12, EffectMagnitude(c.A12))
+	}
+	b.WriteString("\nA12 is the probability that a run of A ends with a higher value than a run of B.\n")
+	b.WriteString("Wilcoxon pairs the runs of A and B by seed and is n/a unless both ran the same seeds.\n")
+
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		return fmt.Errorf("failed to write report %s: %w", path, err)
+	}
+	return nil
+}
+
+// WriteCurvesCSV writes the median curves of every group in long format
+func (r *Report) WriteCurvesCSV(path string) error {
+	rows := [][]string{{"group", "generation", "median", "ci_low", "ci_high"}}
+	for _, name := range r.order {
+		for _, p := range r.Curves[name] {
+			rows = append(rows, []string{
+				name,
+				fmt.Sprintf("%d", p.Generation),
+				fmt.Sprintf("%f", p.Median),
+				fmt.Sprintf("%f", p.Lower),
+				fmt.Sprintf("%f", p.Upper),
+			})
+		}
+	}
+	return writeCSV(path, rows)
+}
+
+// WriteTestsCSV writes the pairwise test results
+func (r *Report) WriteTestsCSV(path string) error {
+	rows := [][]string{{"a", "b", "mann_whitney_u", "mann_whitney_p", "wilcoxon_w", "wilcoxon_p", "a12", "effect"}}
+	for _, c := range r.Comparisons {
+		wStat, wP := "", ""
+		if c.Wilcoxon != nil {
+			wStat = fmt.Sprintf("%f", c.Wilcoxon.Statistic)
+			wP = fmt.Sprintf("%f", c.Wilcoxon.PValue)
+		}
+		rows = append(rows, []string{
+			c.A, c.B,
+			fmt.Sprintf("%f", c.MannWhitney.Statistic),
+			fmt.Sprintf("%f", c.MannWhitney.PValue),
+			wStat, wP,
+			fmt.Sprintf("%f", c.A12),
+			EffectMagnitude(c.A12),
+		})
+	}
+	return writeCSV(path, rows)
+}
+
+// WriteAll writes report.md, curves.csv and tests.csv into dir
+func (r *Report) WriteAll(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create report dir: %w", err)
+	}
+	if err := r.WriteMarkdown(filepath.Join(dir, "report.md")); err != nil {
+		return err
+	}
+	if err := r.WriteCurvesCSV(filepath.Join(dir, "curves.csv")); err != nil {
+		return err
+	}
+	return r.WriteTestsCSV(filepath.Join(dir, "tests.csv"))
+}
+
+func writeCSV(path string, rows [][]string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer f.Close()
+	writer := csv.NewWriter(f)
+	if err := writer.WriteAll(rows); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,234 @@
+package analysis
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/bxrne/darwin/internal/metrics"
+)
+
+// Run is one metrics CSV: a column of values per metric, indexed by generation order
+type Run struct {
+	Path    string
+	Columns map[string][]float64
+	// Seed is the run's seed, nil if neither its resolved config nor its directory names one
+	Seed *int64
+}
+
+// Group is a set of runs of the same configuration (usually differing only by seed)
+type Group struct {
+	Name string
+	Runs []Run
+}
+
+// ReadRun loads a metrics CSV as written by metrics.CSVWriter, taking the header from its
+// sidecar file if the schema grew during the run. Non-numeric columns such as the timestamp are skipped.
+func ReadRun(path string) (Run, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Run{}, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	// Rows written after the schema grew are longer than the header line
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return Run{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if len(records) == 0 {
+		return Run{}, fmt.Errorf("%s is empty", path)
+	}
+
+	header := records[0]
+	if sidecar, err := os.ReadFile(metrics.HeaderPath(path)); err == nil {
+		header = strings.Split(strings.TrimSpace(string(sidecar)), ",")
+	}
+	columns := make(map[string][]float64, len(header))
+	for col, name := range header {
+		values := make([]float64, 0, len(records)-1)
+		numeric := true
+		for _, row := range records[1:] {
+			if col >= len(row) {
+				values = append(values, 0)
+				continue
+			}
+			v, err := strconv.ParseFloat(row[col], 64)
+			if err != nil {
+				numeric = false
+				break
+			}
+			values = append(values, v)
+		}
+		if numeric {
+			columns[name] = values
+		}
+	}
+	return Run{Path: path, Columns: columns, Seed: readSeed(path)}, nil
+}
+
+// readSeed finds the seed of the run whose metrics CSV is at path: the evolution seed of the
+// config.toml a sweep resolves next to it, or else the n of a seed-<n> run directory
+func readSeed(path string) *int64 {
+	var resolved struct {
+		Evolution struct {
+			Seed *int64 `toml:"seed"`
+		} `toml:"evolution"`
+	}
+	if _, err := toml.DecodeFile(filepath.Join(filepath.Dir(path), "config.toml"), &resolved); err == nil && resolved.Evolution.Seed != nil {
+		return resolved.Evolution.Seed
+	}
+	if n, ok := strings.CutPrefix(filepath.Base(filepath.Dir(path)), "seed-"); ok {
+		if seed, err := strconv.ParseInt(n, 10, 64); err == nil {
+			return &seed
+		}
+	}
+	return nil
+}
+
+// LoadGroup reads every CSV matched by the glob pattern into one group
+func LoadGroup(name string, pattern string) (Group, error) {
+	paths, err := filepath.Glob(pattern)
+	if err != nil {
+		return Group{}, fmt.Errorf("invalid pattern %s: %w", pattern, err)
+	}
+	if len(paths) == 0 {
+		return Group{}, fmt.Errorf("no files match %s", pattern)
+	}
+	sort.Strings(paths)
+
+	group := Group{Name: name}
+	for _, path := range paths {
+		run, err := ReadRun(path)
+		if err != nil {
+			return Group{}, err
+		}
+		group.Runs = append(group.Runs, run)
+	}
+	return group, nil
+}
+
+// LoadSweepGroups treats each point directory of a sweep output as a group,
+// reading <dir>/<point>/seed-*/metrics.csv
+func LoadSweepGroups(dir string) ([]Group, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sweep dir %s: %w", dir, err)
+	}
+	groups := make([]Group, 0)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		pattern := filepath.Join(dir, entry.Name(), "seed-*", "metrics.csv")
+		if matches, _ := filepath.Glob(pattern); len(matches) == 0 {
+			continue
+		}
+		group, err := LoadGroup(entry.Name(), pattern)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("no runs found under %s", dir)
+	}
+	return groups, nil
+}
+
+// Final returns the last value of the metric for every run in the group, failing if a run
+// has no values for it
+func (g Group) Final(metric string) ([]float64, error) {
+	values := make([]float64, 0, len(g.Runs))
+	for _, run := range g.Runs {
+		column := run.Columns[metric]
+		if len(column) == 0 {
+			return nil, fmt.Errorf("run %s of group %s has no values for metric %s", run.Path, g.Name, metric)
+		}
+		values = append(values, column[len(column)-1])
+	}
+	return values, nil
+}
+
+// Paired returns the final values of the metric in a and b paired by seed, in seed order.
+// ok is false unless every run names its seed and both groups ran the same seeds, each once.
+func Paired(a, b Group, metric string) (x, y []float64, ok bool) {
+	finalsA, okA := finalsBySeed(a, metric)
+	finalsB, okB := finalsBySeed(b, metric)
+	if !okA || !okB || len(finalsA) != len(finalsB) {
+		return nil, nil, false
+	}
+	seeds := make([]int64, 0, len(finalsA))
+	for seed := range finalsA {
+		if _, found := finalsB[seed]; !found {
+			return nil, nil, false
+		}
+		seeds = append(seeds, seed)
+	}
+	sort.Slice(seeds, func(i, j int) bool { return seeds[i] < seeds[j] })
+	for _, seed := range seeds {
+		x = append(x, finalsA[seed])
+		y = append(y, finalsB[seed])
+	}
+	return x, y, true
+}
+
+// finalsBySeed maps each run's seed to its final value of the metric; ok is false if a run has
+// no seed or no values, or two runs share a seed
+func finalsBySeed(g Group, metric string) (map[int64]float64, bool) {
+	finals := make(map[int64]float64, len(g.Runs))
+	for _, run := range g.Runs {
+		column := run.Columns[metric]
+		if run.Seed == nil || len(column) == 0 {
+			return nil, false
+		}
+		if _, dup := finals[*run.Seed]; dup {
+			return nil, false
+		}
+		finals[*run.Seed] = column[len(column)-1]
+	}
+	return finals, true
+}
+
+// CurvePoint is the across-run median of a metric at one generation, with its CI
+type CurvePoint struct {
+	Generation int
+	Median     float64
+	Lower      float64
+	Upper      float64
+}
+
+// Curve computes the median curve of a metric over generations. Generations are
+// truncated to the shortest run so every point uses the same number of samples.
+func (g Group) Curve(metric string, level float64) []CurvePoint {
+	length := -1
+	for _, run := range g.Runs {
+		if n := len(run.Columns[metric]); length < 0 || n < length {
+			length = n
+		}
+	}
+	if length <= 0 {
+		return nil
+	}
+
+	curve := make([]CurvePoint, length)
+	for gen := range length {
+		values := make([]float64, len(g.Runs))
+		for i, run := range g.Runs {
+			values[i] = run.Columns[metric][gen]
+		}
+		lower, upper := MedianCI(values, level)
+		curve[gen] = CurvePoint{Generation: gen + 1, Median: Median(values), Lower: lower, Upper: upper}
+		if generations, ok := g.Runs[0].Columns["generation"]; ok && gen < len(generations) {
+			curve[gen].Generation = int(generations[gen])
+		}
+	}
+	return curve
+}
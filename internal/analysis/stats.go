@@ -0,0 +1,205 @@
+// Package analysis compares groups of evolution runs with non-parametric statistics
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// TestResult holds the outcome of a two-sample hypothesis test
+type TestResult struct {
+	Statistic float64
+	Z         float64
+	PValue    float64
+	N1        int
+	N2        int
+}
+
+// Median returns the median of values without modifying them
+func Median(values []float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// MedianCI returns a distribution-free confidence interval for the median built from
+// order statistics: the widest symmetric pair whose binomial coverage is at least `level`
+func MedianCI(values []float64, level float64) (float64, float64) {
+	n := len(values)
+	if n == 0 {
+		return math.NaN(), math.NaN()
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	binom := distuv.Binomial{N: float64(n), P: 0.5}
+	alpha := 1 - level
+	// Largest j with P(B < j) <= alpha/2; interval is [x_(j), x_(n-j+1)] in 1-based order statistics
+	j := 0
+	for k := 1; k <= n/2; k++ {
+		if binom.CDF(float64(k-1)) <= alpha/2 {
+			j = k
+		}
+	}
+	if j == 0 {
+		// Too few samples for the requested coverage, fall back to the full range
+		return sorted[0], sorted[n-1]
+	}
+	return sorted[j-1], sorted[n-j]
+}
+
+// ranks assigns average ranks (1-based) to values, handling ties
+func ranks(values []float64) ([]float64, float64) {
+	idx := make([]int, len(values))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool { return values[idx[a]] < values[idx[b]] })
+
+	result := make([]float64, len(values))
+	tieCorrection := 0.0
+	for i := 0; i < len(idx); {
+		j := i
+		for j+1 < len(idx) && values[idx[j+1]] == values[idx[i]] {
+			j++
+		}
+		avg := float64(i+j)/2 + 1
+		for k := i; k <= j; k++ {
+			result[idx[k]] = avg
+		}
+		t := float64(j - i + 1)
+		tieCorrection += t*t*t - t
+		i = j + 1
+	}
+	return result, tieCorrection
+}
+
+// twoSidedP converts a z score to a two-sided p-value
+func twoSidedP(z float64) float64 {
+	return 2 * distuv.UnitNormal.CDF(-math.Abs(z))
+}
+
+// MannWhitneyU performs a two-sided Mann–Whitney U test using the normal approximation
+// with tie and continuity corrections. Statistic is U for the first sample.
+func MannWhitneyU(x, y []float64) (TestResult, error) {
+	n1, n2 := len(x), len(y)
+	if n1 == 0 || n2 == 0 {
+		return TestResult{}, fmt.Errorf("mann-whitney needs two non-empty samples")
+	}
+
+	combined := append(append([]float64(nil), x...), y...)
+	r, tieCorrection := ranks(combined)
+	r1 := 0.0
+	for i := range n1 {
+		r1 += r[i]
+	}
+
+	fn1, fn2 := float64(n1), float64(n2)
+	n := fn1 + fn2
+	u1 := r1 - fn1*(fn1+1)/2
+	mean := fn1 * fn2 / 2
+	variance := fn1 * fn2 / 12 * ((n + 1) - tieCorrection/(n*(n-1)))
+
+	result := TestResult{Statistic: u1, N1: n1, N2: n2, PValue: 1}
+	if variance <= 0 {
+		return result, nil
+	}
+	diff := u1 - mean
+	// Continuity correction towards the mean
+	diff -= math.Copysign(math.Min(0.5, math.Abs(diff)), diff)
+	result.Z = diff / math.Sqrt(variance)
+	result.PValue = twoSidedP(result.Z)
+	return result, nil
+}
+
+// WilcoxonSignedRank performs a two-sided Wilcoxon signed-rank test on paired samples
+// (e.g. runs sharing a seed) using the normal approximation. Zero differences are dropped.
+// Statistic is W+, the sum of ranks of positive differences.
+func WilcoxonSignedRank(x, y []float64) (TestResult, error) {
+	if len(x) != len(y) {
+		return TestResult{}, fmt.Errorf("wilcoxon needs paired samples of equal length, got %d and %d", len(x), len(y))
+	}
+
+	diffs := make([]float64, 0, len(x))
+	for i := range x {
+		if d := x[i] - y[i]; d != 0 {
+			diffs = append(diffs, d)
+		}
+	}
+	result := TestResult{N1: len(x), N2: len(y), PValue: 1}
+	if len(diffs) == 0 {
+		return result, nil
+	}
+
+	abs := make([]float64, len(diffs))
+	for i, d := range diffs {
+		abs[i] = math.Abs(d)
+	}
+	r, tieCorrection := ranks(abs)
+	wPlus := 0.0
+	for i, d := range diffs {
+		if d > 0 {
+			wPlus += r[i]
+		}
+	}
+
+	n := float64(len(diffs))
+	mean := n * (n + 1) / 4
+	variance := n*(n+1)*(2*n+1)/24 - tieCorrection/48
+	result.Statistic = wPlus
+	if variance <= 0 {
+		return result, nil
+	}
+	diff := wPlus - mean
+	diff -= math.Copysign(math.Min(0.5, math.Abs(diff)), diff)
+	result.Z = diff / math.Sqrt(variance)
+	result.PValue = twoSidedP(result.Z)
+	return result, nil
+}
+
+// VarghaDelaneyA12 returns the probability that a value drawn from x is larger than one
+// drawn from y, counting ties as half. 0.5 means no effect.
+func VarghaDelaneyA12(x, y []float64) float64 {
+	if len(x) == 0 || len(y) == 0 {
+		return math.NaN()
+	}
+	wins := 0.0
+	for _, a := range x {
+		for _, b := range y {
+			switch {
+			case a > b:
+				wins++
+			case a == b:
+				wins += 0.5
+			}
+		}
+	}
+	return wins / float64(len(x)*len(y))
+}
+
+// EffectMagnitude labels an A12 value using the Vargha–Delaney thresholds
+func EffectMagnitude(a12 float64) string {
+	d := math.Abs(a12 - 0.5)
+	switch {
+	case math.IsNaN(d):
+		return "n/a"
+	case d < 0.06:
+		return "negligible"
+	case d < 0.14:
+		return "small"
+	case d < 0.21:
+		return "medium"
+	default:
+		return "large"
+	}
+}
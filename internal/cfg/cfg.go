@@ -2,7 +2,9 @@ package cfg
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/BurntSushi/toml"
@@ -31,6 +33,15 @@ type TreeIndividualConfig struct {
 	VariableSet []string `toml:"variable_set"`
 	OperandSet  []string `toml:"operand_set"`
 	TerminalSet []string `toml:"terminal_set"`
+
+	// MutationWeights are the relative chances of each tree mutation type; empty keeps the
+	// default mix of point, shrink and grow
+	MutationWeights map[string]float64 `toml:"mutation_weights"`
+	MutationMode    string             `toml:"mutation_mode"`  // per_node or per_individual
+	ConstantSigma   float64            `toml:"constant_sigma"` // std dev of constant mutation
+
+	CrossoverType string `toml:"crossover_type"` // random_path, koza, size_fair, one_point or uniform
+	MaxSize       int    `toml:"max_size"`       // nodes a bred tree may have, 0 for no limit
 }
 
 // validate validates the TreeIndividualConfig.
@@ -61,13 +72,71 @@ func (tic *TreeIndividualConfig) validate() error {
 		return fmt.Errorf("operand set validation failed: %w", err)
 	}
 
+	if err := tic.validateMutation(); err != nil {
+		return err
+	}
+	return tic.validateCrossover()
+}
+
+// validateCrossover checks the tree crossover type and size limit, filling the default type
+func (tic *TreeIndividualConfig) validateCrossover() error {
+	if tic.CrossoverType == "" {
+		tic.CrossoverType = individual.TreeCrossoverRandomPath
+	}
+	if !slices.Contains(individual.TreeCrossoverTypes, tic.CrossoverType) {
+		return fmt.Errorf("unknown crossover_type %q, valid: %s", tic.CrossoverType, strings.Join(individual.TreeCrossoverTypes, ", "))
+	}
+	if tic.MaxSize < 0 {
+		return fmt.Errorf("max_size must not be negative")
+	}
+	return nil
+}
+
+// validateMutation checks the tree mutation mix and fills the default mode and sigma
+func (tic *TreeIndividualConfig) validateMutation() error {
+	if tic.MutationMode == "" {
+		tic.MutationMode = individual.TreeMutationPerNode
+	}
+	if tic.MutationMode != individual.TreeMutationPerNode && tic.MutationMode != individual.TreeMutationPerIndividual {
+		return fmt.Errorf("mutation_mode must be %s or %s", individual.TreeMutationPerNode, individual.TreeMutationPerIndividual)
+	}
+	if tic.ConstantSigma < 0 {
+		return fmt.Errorf("constant_sigma must not be negative")
+	}
+	if tic.ConstantSigma == 0 {
+		tic.ConstantSigma = individual.DefaultConstantSigma
+	}
+	if len(tic.MutationWeights) == 0 {
+		tic.MutationWeights = nil
+		return nil
+	}
+	total := 0.0
+	for name, weight := range tic.MutationWeights {
+		if !slices.Contains(individual.TreeMutationTypes, name) {
+			return fmt.Errorf("unknown mutation type %q in mutation_weights, valid: %s", name, strings.Join(individual.TreeMutationTypes, ", "))
+		}
+		if weight < 0 {
+			return fmt.Errorf("mutation weight of %s must not be negative", name)
+		}
+		total += weight
+	}
+	if total == 0 {
+		return fmt.Errorf("mutation_weights must give some mutation type a positive weight")
+	}
 	return nil
 }
 
 // MetricsConfig holds configuration for metrics output.
 type MetricsConfig struct {
-	CSVEnabled bool   `toml:"csv_enabled"`
-	CSVFile    string `toml:"csv_file"`
+	CSVEnabled bool         `toml:"csv_enabled"`
+	CSVFile    string       `toml:"csv_file"`
+	Sinks      []SinkConfig `toml:"sinks"`
+}
+
+// SinkConfig picks a registered metrics sink and the file it writes
+type SinkConfig struct {
+	Type string `toml:"type"` // csv, jsonl or a registered sink
+	Path string `toml:"path"`
 }
 
 // validate validates the MetricsConfig.
@@ -75,6 +144,11 @@ func (mc *MetricsConfig) validate() error {
 	if mc.CSVEnabled && mc.CSVFile == "" {
 		return fmt.Errorf("csv_file must be specified when csv_enabled is true")
 	}
+	for i, sink := range mc.Sinks {
+		if sink.Type == "" || sink.Path == "" {
+			return fmt.Errorf("sink %d needs a type and a path", i+1)
+		}
+	}
 	return nil
 }
 
@@ -101,8 +175,10 @@ func (lc *LoggingConfig) validate() error {
 }
 
 type FitnessConfig struct {
-	TestCaseCount  int    `toml:"test_case_count"`
-	TargetFunction string `toml:"target_function"`
+	TestCaseCount  int            `toml:"test_case_count"`
+	TargetFunction string         `toml:"target_function"`
+	Type           string         `toml:"type"`
+	Options        map[string]any `toml:"options"`
 }
 
 func (fc *FitnessConfig) validate() error {
@@ -139,6 +215,95 @@ type ActionTreeConfig struct {
 	ConnectionPoolSize         int                      `toml:"connection_pool_size"`
 	ConnectionTimeout          string                   `toml:"connection_timeout"`
 	HealthCheckTimeout         string                   `toml:"health_check_timeout"`
+	Multiplex                  bool                     `toml:"multiplex"`
+	BatchWindow                string                   `toml:"batch_window"`
+	GameRetries                int                      `toml:"game_retries"`
+	RetryBackoff               string                   `toml:"retry_backoff"`
+	MaxGameFailures            int                      `toml:"max_game_failures"`
+	Backend                    string                   `toml:"backend"`
+	GameSeed                   int64                    `toml:"game_seed"`
+	Environment                string                   `toml:"environment"`
+	ReplayDir                  string                   `toml:"replay_dir"`
+	ReplayMinReward            *float64                 `toml:"replay_min_reward"` // nil records every game
+	ReplayGzip                 bool                     `toml:"replay_gzip"`
+	League                     LeagueConfig             `toml:"league"`
+	Coevolution                CoevolutionConfig        `toml:"coevolution"`
+}
+
+// CoevolutionConfig picks how the action tree and weights populations are bred and paired.
+// The top collaborators are the best fitness_selection_percentage of the other population.
+type CoevolutionConfig struct {
+	Mode              string `toml:"mode"`          // alternate or simultaneous
+	Collaborators     string `toml:"collaborators"` // best, random or top
+	CollaboratorCount int    `toml:"collaborator_count"`
+	Credit            string `toml:"credit"` // max or mean
+}
+
+// validate fills the co-evolution defaults
+func (cc *CoevolutionConfig) validate() error {
+	if cc.Mode == "" {
+		cc.Mode = "alternate"
+	}
+	if cc.Mode != "alternate" && cc.Mode != "simultaneous" {
+		return fmt.Errorf("mode must be either alternate or simultaneous")
+	}
+	if cc.Collaborators == "" {
+		cc.Collaborators = "top"
+	}
+	if cc.Collaborators != "best" && cc.Collaborators != "random" && cc.Collaborators != "top" {
+		return fmt.Errorf("collaborators must be best, random or top")
+	}
+	if cc.CollaboratorCount <= 0 {
+		cc.CollaboratorCount = 1
+	}
+	if cc.Credit == "" {
+		cc.Credit = "max"
+	}
+	if cc.Credit != "max" && cc.Credit != "mean" {
+		return fmt.Errorf("credit must be either max or mean")
+	}
+	return nil
+}
+
+// LeagueConfig enables self-play: individuals play evolved opponents drawn from a pool of
+// hall-of-fame champions and the latest elites instead of the built-in bot
+type LeagueConfig struct {
+	Enabled        bool    `toml:"enabled"`
+	Sampling       string  `toml:"sampling"` // latest, uniform or prioritized
+	HallOfFameSize int     `toml:"hall_of_fame_size"`
+	EliteCount     int     `toml:"elite_count"`
+	KFactor        float64 `toml:"k_factor"`
+	InitialRating  float64 `toml:"initial_rating"`
+	Fitness        string  `toml:"fitness"` // reward or rating
+}
+
+// validate fills the league defaults
+func (lc *LeagueConfig) validate() error {
+	if lc.Sampling == "" {
+		lc.Sampling = "uniform"
+	}
+	if lc.Sampling != "latest" && lc.Sampling != "uniform" && lc.Sampling != "prioritized" {
+		return fmt.Errorf("sampling must be latest, uniform or prioritized")
+	}
+	if lc.HallOfFameSize <= 0 {
+		lc.HallOfFameSize = 10
+	}
+	if lc.EliteCount <= 0 {
+		lc.EliteCount = 3
+	}
+	if lc.KFactor <= 0 {
+		lc.KFactor = 32
+	}
+	if lc.InitialRating == 0 {
+		lc.InitialRating = 1200
+	}
+	if lc.Fitness == "" {
+		lc.Fitness = "reward"
+	}
+	if lc.Fitness != "reward" && lc.Fitness != "rating" {
+		return fmt.Errorf("fitness must be either reward or rating")
+	}
+	return nil
 }
 
 // validate validates the ActionTreeConfig.
@@ -158,6 +323,15 @@ func (atc *ActionTreeConfig) validate() error {
 	if atc.WeightsCount <= 0 {
 		return fmt.Errorf("Weights population must be more than 0")
 	}
+	if atc.FitnessSelectionPercentage == 0 {
+		atc.FitnessSelectionPercentage = 0.1
+	}
+	if atc.FitnessSelectionPercentage < 0 || atc.FitnessSelectionPercentage > 1 {
+		return fmt.Errorf("fitness_selection_percentage must be between 0 and 1")
+	}
+	if atc.SwitchTrainingTargetStep <= 0 {
+		atc.SwitchTrainingTargetStep = 5
+	}
 	if atc.ConnectionPoolSize <= 0 {
 		return fmt.Errorf("connection_pool_size must be greater than 0")
 	}
@@ -167,6 +341,77 @@ func (atc *ActionTreeConfig) validate() error {
 	if atc.HealthCheckTimeout == "" {
 		return fmt.Errorf("health_check_timeout must be specified, e.g., '5s'")
 	}
+	if atc.BatchWindow == "" {
+		atc.BatchWindow = "1ms"
+	}
+	if _, err := time.ParseDuration(atc.BatchWindow); err != nil {
+		return fmt.Errorf("batch_window must be a duration, e.g. '1ms': %w", err)
+	}
+	if atc.GameRetries < 0 {
+		return fmt.Errorf("game_retries must not be negative")
+	}
+	if atc.RetryBackoff == "" {
+		atc.RetryBackoff = "200ms"
+	}
+	if _, err := time.ParseDuration(atc.RetryBackoff); err != nil {
+		return fmt.Errorf("retry_backoff must be a duration, e.g. '200ms': %w", err)
+	}
+	if atc.MaxGameFailures <= 0 {
+		atc.MaxGameFailures = 20
+	}
+	if atc.Backend == "" {
+		atc.Backend = "tcp" // Default to the Python game server
+	}
+	if atc.Backend != "tcp" && atc.Backend != "native" {
+		return fmt.Errorf("backend must be either tcp or native")
+	}
+	if atc.GameSeed == 0 {
+		atc.GameSeed = 44 // Same map seed as the Python server
+	}
+	if atc.Environment == "" {
+		atc.Environment = "generals"
+	}
+	if atc.Backend == "tcp" && atc.Environment != "generals" {
+		return fmt.Errorf("the tcp backend only serves the generals environment")
+	}
+	if err := atc.League.validate(); err != nil {
+		return fmt.Errorf("league: %w", err)
+	}
+	if err := atc.Coevolution.validate(); err != nil {
+		return fmt.Errorf("coevolution: %w", err)
+	}
+	if atc.League.Enabled && (atc.Backend != "native" || atc.Environment != "generals") {
+		return fmt.Errorf("league play needs the native backend with the generals environment")
+	}
+	return nil
+}
+
+// ComponentConfig picks a registered component by name. Options is the component's own
+// sub-section, left undecoded here and decoded by the component when it is built.
+type ComponentConfig struct {
+	Type    string         `toml:"type"`
+	Options map[string]any `toml:"options"`
+}
+
+// OperatorsConfig picks the variation operators applied by the engine and how their rates adapt
+type OperatorsConfig struct {
+	Mutation   ComponentConfig `toml:"mutation"`
+	Crossover  ComponentConfig `toml:"crossover"`
+	Adaptation ComponentConfig `toml:"adaptation"`
+}
+
+// validate fills the default operators, which delegate to the genome's own methods, and keeps
+// the configured rates fixed unless an adaptation is chosen
+func (oc *OperatorsConfig) validate() error {
+	if oc.Mutation.Type == "" {
+		oc.Mutation.Type = "native"
+	}
+	if oc.Crossover.Type == "" {
+		oc.Crossover.Type = "native"
+	}
+	if oc.Adaptation.Type == "" {
+		oc.Adaptation.Type = "fixed"
+	}
 	return nil
 }
 
@@ -181,6 +426,8 @@ type EvolutionConfig struct {
 	SelectionSize       int     `toml:"selection_size"`
 	SelectionType       string  `toml:"selection_type"`
 	Seed                int64   `toml:"seed"`
+
+	SelectionOptions map[string]any `toml:"selection_options"`
 }
 
 // validate validates the EvolutionConfig.
@@ -196,8 +443,9 @@ func (ec *EvolutionConfig) validate() error {
 	if ec.SelectionSize <= 0 {
 		return fmt.Errorf("selection_size must be above 0")
 	}
-	if ec.SelectionType != "tournament" && ec.SelectionType != "roulette" {
-		return fmt.Errorf("selection_type must be either tournament or roulette")
+	// The name itself is checked against the selector registry when the run is built
+	if ec.SelectionType == "" {
+		return fmt.Errorf("selection_type must be specified, e.g. tournament or roulette")
 	}
 	if ec.CrossoverRate < 0 || ec.CrossoverRate > 1 {
 		return fmt.Errorf("crossover_rate must be above 0")
@@ -224,6 +472,103 @@ func (ec *EvolutionConfig) validate() error {
 	return nil
 }
 
+// DistributedConfig moves fitness evaluation onto `darwin worker` processes that pull
+// individuals from a coordinator run alongside the engine
+type DistributedConfig struct {
+	Enabled       bool   `toml:"enabled"`
+	Listen        string `toml:"listen"`
+	TaskTimeout   string `toml:"task_timeout"`
+	WorkerTimeout string `toml:"worker_timeout"`
+	InFlight      int    `toml:"in_flight"`
+}
+
+// validate fills the distributed defaults
+func (dc *DistributedConfig) validate() error {
+	if dc.Listen == "" {
+		dc.Listen = "127.0.0.1:7070"
+	}
+	if dc.TaskTimeout == "" {
+		dc.TaskTimeout = "60s"
+	}
+	timeout, err := time.ParseDuration(dc.TaskTimeout)
+	if err != nil || timeout <= 0 {
+		return fmt.Errorf("task_timeout must be a positive duration")
+	}
+	if dc.WorkerTimeout == "" {
+		dc.WorkerTimeout = (2 * timeout).String()
+	}
+	if timeout, err := time.ParseDuration(dc.WorkerTimeout); err != nil || timeout <= 0 {
+		return fmt.Errorf("worker_timeout must be a positive duration")
+	}
+	if dc.InFlight < 0 {
+		return fmt.Errorf("in_flight must not be negative")
+	}
+	if dc.InFlight == 0 {
+		dc.InFlight = 64
+	}
+	return nil
+}
+
+// DashboardConfig serves a live view of the run over HTTP
+type DashboardConfig struct {
+	Enabled bool   `toml:"enabled"`
+	Listen  string `toml:"listen"`
+}
+
+// validate fills the dashboard defaults
+func (dc *DashboardConfig) validate() error {
+	if dc.Listen == "" {
+		dc.Listen = "127.0.0.1:8080"
+	}
+	return nil
+}
+
+// PrometheusConfig exposes run telemetry at /metrics for Prometheus to scrape
+type PrometheusConfig struct {
+	Enabled bool   `toml:"enabled"`
+	Listen  string `toml:"listen"`
+}
+
+// validate fills the Prometheus defaults
+func (pc *PrometheusConfig) validate() error {
+	if pc.Listen == "" {
+		pc.Listen = "127.0.0.1:9464"
+	}
+	return nil
+}
+
+// ControlConfig serves a local HTTP/JSON API steering the run
+type ControlConfig struct {
+	Enabled        bool   `toml:"enabled"`
+	Listen         string `toml:"listen"`
+	CheckpointPath string `toml:"checkpoint_path"`
+}
+
+// validate fills the control API defaults
+func (cc *ControlConfig) validate() error {
+	if cc.Listen == "" {
+		cc.Listen = "127.0.0.1:7071"
+	}
+	if cc.CheckpointPath == "" {
+		cc.CheckpointPath = "checkpoint.jsonl"
+	}
+	return nil
+}
+
+// LineageConfig records every individual's parents and operator to a JSONL file
+type LineageConfig struct {
+	Enabled bool   `toml:"enabled"`
+	Path    string `toml:"path"`
+}
+
+// validate fills the lineage defaults
+func (lc *LineageConfig) validate() error {
+	if lc.Path == "" {
+		lc.Path = "lineage.jsonl"
+	}
+	return nil
+}
+
 // Config holds the entire configuration for the evolutionary algorithm.
 type Config struct {
 	Evolution   EvolutionConfig           `toml:"evolution"`
@@ -234,6 +579,39 @@ type Config struct {
 	GrammarTree GrammarTreeConfig         `toml:"grammar_tree"`
 	ActionTree  ActionTreeConfig          `toml:"action_tree"`
 	Logging     LoggingConfig             `toml:"logging"`
+	Genome      ComponentConfig           `toml:"genome"`
+	Operators   OperatorsConfig           `toml:"operators"`
+	Distributed DistributedConfig         `toml:"distributed"`
+	Dashboard   DashboardConfig           `toml:"dashboard"`
+	Prometheus  PrometheusConfig          `toml:"prometheus"`
+	Control     ControlConfig             `toml:"control"`
+	Lineage     LineageConfig             `toml:"lineage"`
+}
+
+// GenomeName returns the registered genome type to evolve. An explicit [genome] type wins;
+// otherwise the legacy per-individual enabled flags are consulted.
+func (c *Config) GenomeName() string {
+	switch {
+	case c.Genome.Type != "":
+		return c.Genome.Type
+	case c.BitString.Enabled:
+		return "bitstring"
+	case c.Tree.Enabled:
+		return "tree"
+	case c.GrammarTree.Enabled:
+		return "grammar_tree"
+	case c.ActionTree.Enabled:
+		return "action_tree"
+	}
+	return ""
+}
+
+// FitnessName returns the registered fitness calculator, defaulting to the one named after the genome
+func (c *Config) FitnessName() string {
+	if c.Fitness.Type != "" {
+		return c.Fitness.Type
+	}
+	return c.GenomeName()
 }
 
 // validate validates the entire Config.
@@ -267,6 +645,28 @@ func (c *Config) validate() error {
 	if err := c.Logging.validate(); err != nil {
 		return fmt.Errorf("logging config validation failed: %w", err)
 	}
+	if err := c.Operators.validate(); err != nil {
+		return fmt.Errorf("operators config validation failed: %w", err)
+	}
+	if err := c.Distributed.validate(); err != nil {
+		return fmt.Errorf("distributed config validation failed: %w", err)
+	}
+	if err := c.Dashboard.validate(); err != nil {
+		return fmt.Errorf("dashboard config validation failed: %w", err)
+	}
+	if err := c.Prometheus.validate(); err != nil {
+		return fmt.Errorf("prometheus config validation failed: %w", err)
+	}
+	if err := c.Control.validate(); err != nil {
+		return fmt.Errorf("control config validation failed: %w", err)
+	}
+	if err := c.Lineage.validate(); err != nil {
+		return fmt.Errorf("lineage config validation failed: %w", err)
+	}
+	// The league's opponent pool lives in the coordinator, out of the workers' reach
+	if c.Distributed.Enabled && c.ActionTree.League.Enabled {
+		return fmt.Errorf("distributed evaluation cannot be combined with a league")
+	}
 	// Mutual exclusivity
 	if c.Tree.Enabled && c.BitString.Enabled && c.GrammarTree.Enabled && c.ActionTree.Enabled {
 		return fmt.Errorf("only one individual type can be enabled at a time")
@@ -332,3 +732,17 @@ func LoadConfig(path string) (*Config, error) {
 
 	return &config, nil
 }
+
+// DecodeConfig parses and validates a config from TOML text rather than a file
+func DecodeConfig(data string) (*Config, error) {
+	var config Config
+	if _, err := toml.Decode(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to decode config: %w", err)
+	}
+
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("config validation failed: %w", err)
+	}
+
+	return &config, nil
+}
@@ -1,10 +1,14 @@
 package cfg_test
 
 import (
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/bxrne/darwin/internal/cfg"
+	"github.com/bxrne/darwin/internal/individual"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestLoadConfigFiles(t *testing.T) {
@@ -38,3 +42,80 @@ func TestLoadConfigFiles(t *testing.T) {
 		})
 	}
 }
+
+// loadWithKeys loads the default config with extra keys at the top of a section
+func loadWithKeys(t *testing.T, section, keys string) (*cfg.Config, error) {
+	data, err := os.ReadFile("../../config/default.toml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "config.toml")
+	header := "[" + section + "]\n"
+	text := strings.Replace(string(data), header, header+keys+"\n", 1)
+	if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return cfg.LoadConfig(path)
+}
+
+func TestLoadConfig_GIVEN_no_tree_mutation_keys_WHEN_loaded_THEN_defaults_filled(t *testing.T) {
+	config, err := loadWithKeys(t, "tree_individual", "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, individual.TreeMutationPerNode, config.Tree.MutationMode)
+	assert.Equal(t, individual.DefaultConstantSigma, config.Tree.ConstantSigma)
+	assert.Nil(t, config.Tree.MutationWeights)
+	assert.Equal(t, individual.TreeCrossoverRandomPath, config.Tree.CrossoverType)
+	assert.Zero(t, config.Tree.MaxSize)
+}
+
+func TestLoadConfig_GIVEN_tree_mutation_mix_WHEN_loaded_THEN_kept(t *testing.T) {
+	config, err := loadWithKeys(t, "tree_individual", `mutation_mode = "per_individual"
+constant_sigma = 0.1
+mutation_weights = { subtree = 0.5, hoist = 0.25, constant = 0.25 }`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, individual.TreeMutationPerIndividual, config.Tree.MutationMode)
+	assert.Equal(t, 0.1, config.Tree.ConstantSigma)
+	assert.Equal(t, map[string]float64{"subtree": 0.5, "hoist": 0.25, "constant": 0.25}, config.Tree.MutationWeights)
+}
+
+func TestLoadConfig_GIVEN_bad_tree_mutation_WHEN_loaded_THEN_returns_error(t *testing.T) {
+	cases := []struct {
+		name string
+		keys string
+		want string
+	}{
+		{"unknown_mode", `mutation_mode = "per_tree"`, "mutation_mode"},
+		{"unknown_type", `mutation_weights = { crossover = 1.0 }`, `unknown mutation type "crossover"`},
+		{"negative_weight", `mutation_weights = { point = -1.0, grow = 1.0 }`, "must not be negative"},
+		{"all_zero", `mutation_weights = { point = 0.0 }`, "positive weight"},
+		{"negative_sigma", `constant_sigma = -0.5`, "constant_sigma"},
+		{"unknown_crossover", `crossover_type = "two_point"`, `unknown crossover_type "two_point"`},
+		{"negative_max_size", `max_size = -1`, "max_size"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := loadWithKeys(t, "tree_individual", c.keys)
+
+			assert.ErrorContains(t, err, c.want)
+		})
+	}
+}
+
+func TestLoadConfig_GIVEN_no_replay_min_reward_WHEN_loaded_THEN_records_every_game(t *testing.T) {
+	config, err := loadWithKeys(t, "action_tree", "")
+
+	assert.NoError(t, err)
+	assert.Nil(t, config.ActionTree.ReplayMinReward)
+}
+
+func TestLoadConfig_GIVEN_zero_replay_min_reward_WHEN_loaded_THEN_kept(t *testing.T) {
+	config, err := loadWithKeys(t, "action_tree", "replay_min_reward = 0.0")
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, config.ActionTree.ReplayMinReward) {
+		assert.Equal(t, 0.0, *config.ActionTree.ReplayMinReward)
+	}
+}
@@ -0,0 +1,251 @@
+// Package control serves a local HTTP/JSON API steering a running evolution. Requests become
+// engine commands, which the engine handles between generations, so a request may wait for the
+// running generation to finish.
+package control
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/bxrne/darwin/internal/evolution"
+	"github.com/bxrne/darwin/internal/fitness"
+	"github.com/bxrne/darwin/internal/individual"
+	"go.uber.org/zap"
+)
+
+// errRunOver is returned once the run no longer takes commands
+var errRunOver = errors.New("the run is over")
+
+// Server is the control API of one run
+type Server struct {
+	listener       net.Listener
+	server         *http.Server
+	checkpointPath string
+	commands       chan evolution.EvolutionCommand
+	done           chan struct{}
+	closeOnce      sync.Once
+}
+
+// Parameters are the settings POST /parameters changes; absent ones are left alone
+type Parameters struct {
+	MutationRate  *float64 `json:"mutation_rate,omitempty"`
+	CrossoverRate *float64 `json:"crossover_rate,omitempty"`
+	SelectionSize *int     `json:"selection_size,omitempty"`
+}
+
+// Checkpoint describes a population written by POST /checkpoint
+type Checkpoint struct {
+	Path        string `json:"path"`
+	Individuals int    `json:"individuals"`
+	Generation  int    `json:"generation"`
+}
+
+// Listen serves the control API on addr, writing checkpoints to checkpointPath
+func Listen(addr string, checkpointPath string) (*Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	s := &Server{
+		listener:       listener,
+		checkpointPath: checkpointPath,
+		commands:       make(chan evolution.EvolutionCommand),
+		done:           make(chan struct{}),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /status", s.handleStatus)
+	mux.HandleFunc("POST /pause", s.handleSimple(evolution.CmdPause))
+	mux.HandleFunc("POST /resume", s.handleSimple(evolution.CmdResume))
+	mux.HandleFunc("POST /parameters", s.handleParameters)
+	mux.HandleFunc("POST /inject", s.handleInject)
+	mux.HandleFunc("GET /population", s.handlePopulation)
+	mux.HandleFunc("POST /checkpoint", s.handleCheckpoint)
+	s.server = &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			zap.L().Error("Control API stopped", zap.Error(err))
+		}
+	}()
+	zap.L().Info("Control API listening", zap.String("url", "http://"+s.Addr()))
+	return s, nil
+}
+
+// Addr is the address the API is served on
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Commands returns the engine commands requested over the API
+func (s *Server) Commands() <-chan evolution.EvolutionCommand {
+	return s.commands
+}
+
+// Attach implements the runner's control hook; the API needs nothing from the calculator
+func (s *Server) Attach(calculator fitness.FitnessCalculator) {}
+
+// Close stops serving; requests still waiting on the engine fail
+func (s *Server) Close() error {
+	s.closeOnce.Do(func() { close(s.done) })
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return s.server.Shutdown(ctx)
+}
+
+// execute sends cmd to the engine and waits for its reply
+func (s *Server) execute(ctx context.Context, cmd evolution.EvolutionCommand) (evolution.CommandReply, error) {
+	reply := make(chan evolution.CommandReply, 1)
+	cmd.Reply = reply
+	select {
+	case s.commands <- cmd:
+	case <-s.done:
+		return evolution.CommandReply{}, errRunOver
+	case <-ctx.Done():
+		return evolution.CommandReply{}, ctx.Err()
+	}
+	select {
+	case r := <-reply:
+		return r, nil
+	case <-s.done:
+		return evolution.CommandReply{}, errRunOver
+	case <-ctx.Done():
+		return evolution.CommandReply{}, ctx.Err()
+	}
+}
+
+// run executes cmd for a request, writing the error response and returning false if it failed
+func (s *Server) run(w http.ResponseWriter, r *http.Request, cmd evolution.EvolutionCommand) (evolution.CommandReply, bool) {
+	reply, err := s.execute(r.Context(), cmd)
+	switch {
+	case err != nil:
+		writeError(w, http.StatusServiceUnavailable, err)
+		return reply, false
+	case reply.Err != nil:
+		writeError(w, http.StatusBadRequest, reply.Err)
+		return reply, false
+	}
+	return reply, true
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	if reply, ok := s.run(w, r, evolution.EvolutionCommand{Type: evolution.CmdStatus}); ok {
+		writeJSON(w, http.StatusOK, reply.Status)
+	}
+}
+
+// handleSimple sends a command that takes no arguments, answering with the status after it
+func (s *Server) handleSimple(commandType evolution.CommandType) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if reply, ok := s.run(w, r, evolution.EvolutionCommand{Type: commandType}); ok {
+			writeJSON(w, http.StatusOK, reply.Status)
+		}
+	}
+}
+
+func (s *Server) handleParameters(w http.ResponseWriter, r *http.Request) {
+	var params Parameters
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid parameters: %w", err))
+		return
+	}
+	var cmds []evolution.EvolutionCommand
+	if params.MutationRate != nil {
+		cmds = append(cmds, evolution.EvolutionCommand{Type: evolution.CmdSetMutationRate, MutationRate: *params.MutationRate})
+	}
+	if params.CrossoverRate != nil {
+		cmds = append(cmds, evolution.EvolutionCommand{Type: evolution.CmdSetCrossoverRate, CrossoverRate: *params.CrossoverRate})
+	}
+	if params.SelectionSize != nil {
+		cmds = append(cmds, evolution.EvolutionCommand{Type: evolution.CmdSetSelectionSize, SelectionSize: *params.SelectionSize})
+	}
+	if len(cmds) == 0 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("set at least one of mutation_rate, crossover_rate and selection_size"))
+		return
+	}
+
+	var reply evolution.CommandReply
+	for _, cmd := range cmds {
+		var ok bool
+		if reply, ok = s.run(w, r, cmd); !ok {
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, reply.Status)
+}
+
+// handleInject takes individuals in the format of GET /population, one per line
+func (s *Server) handleInject(w http.ResponseWriter, r *http.Request) {
+	individuals, err := individual.ReadPopulation(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if reply, ok := s.run(w, r, evolution.EvolutionCommand{Type: evolution.CmdInject, Individuals: individuals}); ok {
+		writeJSON(w, http.StatusOK, reply.Status)
+	}
+}
+
+// handlePopulation returns the population best first, one encoded individual per line
+func (s *Server) handlePopulation(w http.ResponseWriter, r *http.Request) {
+	reply, ok := s.run(w, r, evolution.EvolutionCommand{Type: evolution.CmdSnapshot})
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	if err := individual.WritePopulation(w, reply.Population); err != nil {
+		zap.L().Warn("Failed to send population", zap.Error(err))
+	}
+}
+
+func (s *Server) handleCheckpoint(w http.ResponseWriter, r *http.Request) {
+	reply, ok := s.run(w, r, evolution.EvolutionCommand{Type: evolution.CmdSnapshot})
+	if !ok {
+		return
+	}
+	if err := writeCheckpoint(s.checkpointPath, reply.Population); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	zap.L().Info("Checkpoint written", zap.String("path", s.checkpointPath), zap.Int("generation", reply.Status.Generation))
+	writeJSON(w, http.StatusOK, Checkpoint{Path: s.checkpointPath, Individuals: len(reply.Population), Generation: reply.Status.Generation})
+}
+
+// writeCheckpoint replaces path with the population in one step, so a crash mid-write leaves the
+// previous checkpoint intact
+func writeCheckpoint(path string, population []individual.Evolvable) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create checkpoint: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := individual.WritePopulation(tmp, population); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace checkpoint %s: %w", path, err)
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,167 @@
+package control_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bxrne/darwin/internal/control"
+	"github.com/bxrne/darwin/internal/evolution"
+	"github.com/bxrne/darwin/internal/fitness"
+	"github.com/bxrne/darwin/internal/individual"
+	"github.com/bxrne/darwin/internal/metrics"
+	"github.com/bxrne/darwin/internal/population"
+	"github.com/bxrne/darwin/internal/rng"
+	"github.com/bxrne/darwin/internal/selection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// serve starts the API in front of an engine evolving ten scored bit strings
+func serve(t *testing.T) (*control.Server, string) {
+	t.Helper()
+	checkpoint := filepath.Join(t.TempDir(), "checkpoint.jsonl")
+	s, err := control.Listen("127.0.0.1:0", checkpoint)
+	require.NoError(t, err)
+
+	calc := fitness.FitnessCalculatorFactory(fitness.FitnessSetupInformation{GenomeType: individual.BitStringGenome})
+	pop := population.NewPopulationBuilder().BuildPopulation(&population.PopulationInfo{Size: 10, GenomeType: individual.BitStringGenome},
+		func(r *rng.Rand) individual.Evolvable { return individual.NewBinaryIndividual(r, 8) }, nil)
+	pop.CalculateFitnesses(calc)
+	engine := evolution.NewEvolutionEngine(pop, selection.NewTournamentSelector(3), make(chan metrics.GenerationMetrics, 10),
+		s.Commands(), calc, individual.CrossoverInformation{CrossoverPoints: 1}, individual.MutateInformation{}, zap.NewNop())
+	ctx, cancel := context.WithCancel(context.Background())
+	engine.Start(ctx)
+	t.Cleanup(func() {
+		cancel()
+		engine.Wait()
+		_ = s.Close()
+	})
+	return s, checkpoint
+}
+
+func call(t *testing.T, s *control.Server, method, path, body string) (int, []byte) {
+	t.Helper()
+	req, err := http.NewRequest(method, "http://"+s.Addr()+path, strings.NewReader(body))
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, data
+}
+
+func status(t *testing.T, data []byte) evolution.Status {
+	t.Helper()
+	var st evolution.Status
+	require.NoError(t, json.Unmarshal(data, &st))
+	return st
+}
+
+func TestServer_GIVEN_pause_and_resume_WHEN_posted_THEN_status_follows(t *testing.T) {
+	s, _ := serve(t)
+
+	code, body := call(t, s, http.MethodPost, "/pause", "")
+	require.Equal(t, http.StatusOK, code)
+	assert.True(t, status(t, body).Paused)
+
+	_, body = call(t, s, http.MethodPost, "/resume", "")
+	assert.False(t, status(t, body).Paused)
+	_, body = call(t, s, http.MethodGet, "/status", "")
+	assert.Equal(t, 10, status(t, body).PopulationSize)
+}
+
+func TestServer_GIVEN_parameters_WHEN_posted_THEN_applied(t *testing.T) {
+	s, _ := serve(t)
+
+	code, body := call(t, s, http.MethodPost, "/parameters", `{"mutation_rate": 0.2, "crossover_rate": 0.8, "selection_size": 4}`)
+
+	require.Equal(t, http.StatusOK, code, string(body))
+	st := status(t, body)
+	assert.Equal(t, 0.2, st.MutationRate)
+	assert.Equal(t, 0.8, st.CrossoverRate)
+	assert.Equal(t, 4, st.SelectionSize)
+}
+
+func TestServer_GIVEN_bad_parameters_WHEN_posted_THEN_bad_request(t *testing.T) {
+	s, _ := serve(t)
+
+	code, body := call(t, s, http.MethodPost, "/parameters", `{}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, string(body), "set at least one")
+
+	code, body = call(t, s, http.MethodPost, "/parameters", `{"crossover_rate": 2}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, string(body), "crossover rate must be between 0 and 1")
+}
+
+func TestServer_GIVEN_injected_individual_WHEN_population_requested_THEN_leads_it(t *testing.T) {
+	s, _ := serve(t)
+	var encoded bytes.Buffer
+	require.NoError(t, individual.WritePopulation(&encoded, []individual.Evolvable{&individual.BinaryIndividual{Genome: []byte("11111111")}}))
+
+	code, body := call(t, s, http.MethodPost, "/inject", encoded.String())
+	require.Equal(t, http.StatusOK, code, string(body))
+	code, body = call(t, s, http.MethodGet, "/population", "")
+
+	require.Equal(t, http.StatusOK, code)
+	pop, err := individual.ReadPopulation(bytes.NewReader(body))
+	require.NoError(t, err)
+	require.Len(t, pop, 10)
+	assert.Equal(t, 1.0, pop[0].GetFitness())
+}
+
+func TestServer_GIVEN_malformed_individuals_WHEN_injected_THEN_bad_request(t *testing.T) {
+	s, _ := serve(t)
+
+	code, body := call(t, s, http.MethodPost, "/inject", "not json\n")
+
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, string(body), "line 1")
+}
+
+func TestServer_GIVEN_checkpoint_WHEN_posted_THEN_population_written(t *testing.T) {
+	s, path := serve(t)
+
+	code, body := call(t, s, http.MethodPost, "/checkpoint", "")
+
+	require.Equal(t, http.StatusOK, code, string(body))
+	var checkpoint control.Checkpoint
+	require.NoError(t, json.Unmarshal(body, &checkpoint))
+	assert.Equal(t, control.Checkpoint{Path: path, Individuals: 10}, checkpoint)
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	pop, err := individual.ReadPopulation(file)
+	require.NoError(t, err)
+	assert.Len(t, pop, 10)
+}
+
+func TestServer_GIVEN_run_ends_WHEN_reply_awaited_THEN_unavailable(t *testing.T) {
+	s, err := control.Listen("127.0.0.1:0", "checkpoint.jsonl")
+	require.NoError(t, err)
+	done := make(chan int, 1)
+	go func() {
+		resp, err := http.Get("http://" + s.Addr() + "/status")
+		if !assert.NoError(t, err) {
+			done <- 0
+			return
+		}
+		resp.Body.Close()
+		done <- resp.StatusCode
+	}()
+
+	// Take the command like an engine that stops before replying
+	<-s.Commands()
+	require.NoError(t, s.Close())
+
+	assert.Equal(t, http.StatusServiceUnavailable, <-done)
+}
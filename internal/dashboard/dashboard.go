@@ -0,0 +1,201 @@
+// Package dashboard serves a live view of a running evolution: fitness, depth and node-count
+// charts fed over Server-Sent Events, the current best individual and the resolved config. The
+// page and its scripts are embedded, so the dashboard works without network access.
+package dashboard
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bxrne/darwin/internal/metrics"
+	"go.uber.org/zap"
+)
+
+//go:embed static
+var static embed.FS
+
+// Server is the dashboard of one run
+type Server struct {
+	listener net.Listener
+	server   *http.Server
+	config   string
+
+	mu      sync.Mutex
+	history [][]byte
+	clients map[chan []byte]struct{}
+	done    bool
+}
+
+// event is a Server-Sent Event: a name and its JSON data
+type event struct {
+	name string
+	data []byte
+}
+
+// Listen starts serving the dashboard on addr, e.g. "127.0.0.1:8080" or "127.0.0.1:0". config is
+// the run's resolved config, shown as is.
+func Listen(addr string, config string) (*Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	s := &Server{
+		listener: listener,
+		config:   config,
+		clients:  make(map[chan []byte]struct{}),
+	}
+
+	assets, _ := fs.Sub(static, "static")
+	mux := http.NewServeMux()
+	mux.Handle("GET /", http.FileServerFS(assets))
+	mux.HandleFunc("GET /events", s.handleEvents)
+	mux.HandleFunc("GET /api/history", s.handleHistory)
+	mux.HandleFunc("GET /api/config", s.handleConfig)
+	s.server = &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			zap.L().Error("Dashboard stopped", zap.Error(err))
+		}
+	}()
+	zap.L().Info("Dashboard listening", zap.String("url", "http://"+s.Addr()))
+	return s, nil
+}
+
+// Addr is the address the dashboard is served on
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Follow publishes every generation received from a MetricsStreamer subscription until the
+// channel closes, then tells the browsers the run is over
+func (s *Server) Follow(subscription <-chan metrics.GenerationMetrics) {
+	for m := range subscription {
+		s.Publish(m)
+	}
+	s.Finish()
+}
+
+// Publish records a generation and streams it to every open dashboard
+func (s *Server) Publish(m metrics.GenerationMetrics) {
+	data, err := json.Marshal(metrics.NewRecord(m))
+	if err != nil {
+		zap.L().Warn("Failed to encode generation for the dashboard", zap.Error(err))
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.history = append(s.history, data)
+	s.broadcast(data)
+}
+
+// Finish marks the run as over; open dashboards stop waiting for generations
+func (s *Server) Finish() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done {
+		return
+	}
+	s.done = true
+	for client := range s.clients {
+		close(client)
+		delete(s.clients, client)
+	}
+}
+
+// broadcast queues data for every client, dropping it for clients too slow to keep up; the
+// caller holds s.mu
+func (s *Server) broadcast(data []byte) {
+	for client := range s.clients {
+		select {
+		case client <- data:
+		default:
+		}
+	}
+}
+
+// Close stops serving
+func (s *Server) Close() error {
+	s.Finish()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return s.server.Shutdown(ctx)
+}
+
+// handleEvents streams the generations so far followed by each new one, then a done event
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	s.mu.Lock()
+	backlog := append([][]byte(nil), s.history...)
+	var updates chan []byte
+	if !s.done {
+		updates = make(chan []byte, 64)
+		s.clients[updates] = struct{}{}
+	}
+	s.mu.Unlock()
+	client := updates
+	defer func() {
+		s.mu.Lock()
+		delete(s.clients, client)
+		s.mu.Unlock()
+	}()
+
+	for _, data := range backlog {
+		writeEvent(w, event{name: "generation", data: data})
+	}
+	flusher.Flush()
+	for updates != nil {
+		select {
+		case <-r.Context().Done():
+			return
+		case data, ok := <-updates:
+			if !ok {
+				updates = nil
+				continue
+			}
+			writeEvent(w, event{name: "generation", data: data})
+			flusher.Flush()
+		}
+	}
+	writeEvent(w, event{name: "done", data: []byte("{}")})
+	flusher.Flush()
+}
+
+func writeEvent(w http.ResponseWriter, e event) {
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.name, e.data)
+}
+
+// handleHistory returns every generation so far as a JSON array
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	history := append([][]byte(nil), s.history...)
+	s.mu.Unlock()
+
+	records := make([]json.RawMessage, len(history))
+	for i, data := range history {
+		records[i] = data
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(records)
+}
+
+// handleConfig returns the resolved config
+func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = fmt.Fprint(w, s.config)
+}
@@ -0,0 +1,150 @@
+package dashboard_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bxrne/darwin/internal/dashboard"
+	"github.com/bxrne/darwin/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func generation(n int, best float64) metrics.GenerationMetrics {
+	return metrics.GenerationMetrics{
+		Generation:      n,
+		Duration:        time.Millisecond,
+		PopulationSize:  10,
+		Timestamp:       time.Now(),
+		BestDescription: "(x + 1)",
+		Metrics:         map[string]float64{"max_fit": best, "avg_depth": 3},
+	}
+}
+
+func listen(t *testing.T) *dashboard.Server {
+	t.Helper()
+	s, err := dashboard.Listen("127.0.0.1:0", "[evolution]\npopulation_size = 10\n")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+func get(t *testing.T, s *dashboard.Server, path string) (string, string) {
+	t.Helper()
+	resp, err := http.Get("http://" + s.Addr() + path)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body), resp.Header.Get("Content-Type")
+}
+
+// readEvents reads Server-Sent Events until a done event, returning each event's name and data
+func readEvents(t *testing.T, body io.Reader) [][2]string {
+	t.Helper()
+	var events [][2]string
+	var name string
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			events = append(events, [2]string{name, strings.TrimPrefix(line, "data: ")})
+			if name == "done" {
+				return events
+			}
+		}
+	}
+	return events
+}
+
+func TestServer_GIVEN_embedded_assets_WHEN_requested_THEN_served(t *testing.T) {
+	s := listen(t)
+
+	page, contentType := get(t, s, "/")
+	assert.Contains(t, contentType, "text/html")
+	assert.Contains(t, page, `<script src="app.js">`)
+	assert.NotContains(t, page, "http", "the dashboard must not load anything from the network")
+	script, _ := get(t, s, "/app.js")
+	assert.Contains(t, script, "EventSource")
+	_, contentType = get(t, s, "/style.css")
+	assert.Contains(t, contentType, "text/css")
+}
+
+func TestServer_GIVEN_config_WHEN_requested_THEN_returned_as_is(t *testing.T) {
+	s := listen(t)
+
+	config, _ := get(t, s, "/api/config")
+
+	assert.Equal(t, "[evolution]\npopulation_size = 10\n", config)
+}
+
+func TestServer_GIVEN_published_generations_WHEN_history_requested_THEN_returns_records(t *testing.T) {
+	s := listen(t)
+	s.Publish(generation(1, 0.5))
+	s.Publish(generation(2, 0.75))
+
+	body, _ := get(t, s, "/api/history")
+
+	var records []metrics.Record
+	require.NoError(t, json.Unmarshal([]byte(body), &records))
+	require.Len(t, records, 2)
+	assert.Equal(t, 2, records[1].Generation)
+	assert.Equal(t, 0.75, *records[1].Metrics["max_fit"])
+	assert.Equal(t, "(x + 1)", records[1].BestDescription)
+}
+
+func TestServer_GIVEN_streamed_run_WHEN_subscribed_THEN_replays_history_then_streams_until_done(t *testing.T) {
+	s := listen(t)
+	subscription := make(chan metrics.GenerationMetrics)
+	go s.Follow(subscription)
+	subscription <- generation(1, 0.5)
+
+	resp, err := http.Get("http://" + s.Addr() + "/events")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	// Headers are flushed only once the stream is registered for updates
+	subscription <- generation(2, 0.75)
+	close(subscription)
+
+	events := readEvents(t, resp.Body)
+
+	require.Len(t, events, 3)
+	assert.Equal(t, "generation", events[0][0])
+	assert.Contains(t, events[0][1], `"generation":1`)
+	assert.Equal(t, "generation", events[1][0])
+	assert.Contains(t, events[1][1], `"generation":2`)
+	assert.Equal(t, "done", events[2][0])
+}
+
+func TestServer_GIVEN_finished_run_WHEN_subscribed_THEN_replays_history_and_done(t *testing.T) {
+	s := listen(t)
+	s.Publish(generation(1, 0.5))
+	s.Finish()
+
+	resp, err := http.Get("http://" + s.Addr() + "/events")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	events := readEvents(t, resp.Body)
+
+	require.Len(t, events, 2)
+	assert.Equal(t, "done", events[1][0])
+}
+
+func TestListen_GIVEN_address_in_use_WHEN_listen_THEN_returns_error(t *testing.T) {
+	s := listen(t)
+
+	_, err := dashboard.Listen(s.Addr(), "")
+
+	assert.ErrorContains(t, err, "failed to listen")
+}
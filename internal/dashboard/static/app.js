@@ -0,0 +1,119 @@
+// Live dashboard: charts min/avg/max of each population metric as generations stream in over SSE.
+"use strict";
+
+const series = ["min", "avg", "max"];
+const colours = { min: "#c0392b", avg: "#2471a3", max: "#229954" };
+const charts = ["fit", "depth", "nodes"];
+const history = [];
+
+function value(record, key) {
+  const v = record.metrics[key];
+  return v === null || v === undefined ? null : v;
+}
+
+function draw(metric) {
+  const canvas = document.getElementById("chart-" + metric);
+  const ctx = canvas.getContext("2d");
+  const pad = { left: 56, right: 12, top: 12, bottom: 24 };
+  const width = canvas.width - pad.left - pad.right;
+  const height = canvas.height - pad.top - pad.bottom;
+  ctx.clearRect(0, 0, canvas.width, canvas.height);
+
+  let lo = Infinity;
+  let hi = -Infinity;
+  for (const record of history) {
+    for (const s of series) {
+      const v = value(record, s + "_" + metric);
+      if (v !== null) {
+        lo = Math.min(lo, v);
+        hi = Math.max(hi, v);
+      }
+    }
+  }
+  ctx.fillStyle = "#6b7385";
+  ctx.font = "11px system-ui, sans-serif";
+  if (lo === Infinity) {
+    ctx.fillText("no data", pad.left, pad.top + height / 2);
+    return;
+  }
+  if (lo === hi) {
+    lo -= 1;
+    hi += 1;
+  }
+
+  const first = history[0].generation;
+  const last = history[history.length - 1].generation;
+  const span = Math.max(last - first, 1);
+  const x = (g) => pad.left + ((g - first) / span) * width;
+  const y = (v) => pad.top + (1 - (v - lo) / (hi - lo)) * height;
+
+  ctx.strokeStyle = "#dde1e8";
+  ctx.beginPath();
+  ctx.moveTo(pad.left, pad.top);
+  ctx.lineTo(pad.left, pad.top + height);
+  ctx.lineTo(pad.left + width, pad.top + height);
+  ctx.stroke();
+  ctx.fillText(hi.toPrecision(4), 4, pad.top + 8);
+  ctx.fillText(lo.toPrecision(4), 4, pad.top + height);
+  ctx.fillText(String(first), pad.left, canvas.height - 6);
+  ctx.fillText(String(last), pad.left + width - 24, canvas.height - 6);
+
+  for (const s of series) {
+    ctx.strokeStyle = colours[s];
+    ctx.beginPath();
+    let drawing = false;
+    for (const record of history) {
+      const v = value(record, s + "_" + metric);
+      if (v === null) {
+        drawing = false;
+        continue;
+      }
+      if (drawing) {
+        ctx.lineTo(x(record.generation), y(v));
+      } else {
+        ctx.moveTo(x(record.generation), y(v));
+        drawing = true;
+      }
+    }
+    ctx.stroke();
+  }
+  series.forEach((s, i) => {
+    ctx.fillStyle = colours[s];
+    ctx.fillText(s, pad.left + width - 90 + i * 30, pad.top + 10);
+  });
+}
+
+function update(record) {
+  history.push(record);
+  document.getElementById("generation").textContent = record.generation;
+  document.getElementById("population").textContent = record.population_size;
+  document.getElementById("duration").textContent = (record.duration_ns / 1e6).toFixed(1) + " ms";
+  document.getElementById("best").textContent = record.best_description || "-";
+  charts.forEach(draw);
+}
+
+function connect() {
+  const status = document.getElementById("status");
+  const events = new EventSource("events");
+  events.onopen = () => {
+    // Every connection replays the run from the start, including reconnects
+    history.length = 0;
+    status.textContent = "live";
+  };
+  events.addEventListener("generation", (e) => update(JSON.parse(e.data)));
+  events.addEventListener("done", () => {
+    status.textContent = "run finished";
+    events.close();
+  });
+  events.onerror = () => {
+    status.textContent = "disconnected";
+  };
+}
+
+fetch("api/config")
+  .then((response) => response.text())
+  .then((text) => {
+    document.getElementById("config").textContent = text;
+  });
+charts.forEach(draw);
+connect();
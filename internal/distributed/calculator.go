@@ -0,0 +1,64 @@
+package distributed
+
+import (
+	"github.com/bxrne/darwin/internal/individual"
+	"go.uber.org/zap"
+)
+
+// Calculator is a fitness calculator that evaluates individuals on remote workers through a
+// coordinator. It scores single individuals and co-evolved teams alike, and asks populations to
+// keep inFlight evaluations outstanding so every worker has something to do.
+type Calculator struct {
+	coordinator *Coordinator
+	inFlight    int
+}
+
+// NewCalculator creates a calculator evaluating through coordinator with up to inFlight
+// concurrent evaluations
+func NewCalculator(coordinator *Coordinator, inFlight int) *Calculator {
+	return &Calculator{coordinator: coordinator, inFlight: inFlight}
+}
+
+// CalculateFitness scores the individual remotely; a failed evaluation scores 0
+func (c *Calculator) CalculateFitness(evolvable individual.Evolvable) {
+	evolvable.SetFitness(c.evaluate([]individual.Evolvable{evolvable}, 0))
+}
+
+// CalculateTeamFitness scores team[subject] remotely; a failed evaluation scores 0
+func (c *Calculator) CalculateTeamFitness(team []individual.Evolvable, subject int) float64 {
+	return c.evaluate(team, subject)
+}
+
+// Parallelism is the number of evaluations to keep outstanding
+func (c *Calculator) Parallelism() int {
+	return c.inFlight
+}
+
+// Err implements fitness.Aborter, reporting a coordinator that gave up for want of workers
+func (c *Calculator) Err() error {
+	return c.coordinator.Err()
+}
+
+// Close shuts the coordinator down
+func (c *Calculator) Close() error {
+	return c.coordinator.Close()
+}
+
+func (c *Calculator) evaluate(team []individual.Evolvable, subject int) float64 {
+	encoded := make([][]byte, len(team))
+	for i, member := range team {
+		data, err := individual.Marshal(member)
+		if err != nil {
+			zap.L().Error("Failed to encode individual for a worker", zap.Error(err))
+			return 0
+		}
+		encoded[i] = data
+	}
+
+	result, err := c.coordinator.Evaluate(encoded, subject)
+	if err != nil {
+		zap.L().Error("Remote fitness evaluation failed", zap.Error(err))
+		return 0
+	}
+	return result.Fitness
+}
@@ -0,0 +1,354 @@
+// Package distributed spreads fitness evaluation over worker processes. The coordinator queues
+// serialized individuals and workers pull them over net/rpc, run the configured fitness
+// calculator and send back the scores. Pulling balances the load: a fast worker simply takes
+// more tasks. A task whose worker goes quiet for longer than the task timeout is handed to another
+// worker, and an idle worker steals a copy of a task that has run for half the timeout; the first
+// result wins. Queued tasks fail once no worker has been seen for the worker timeout.
+package distributed
+
+import (
+	"fmt"
+	"net"
+	"net/rpc"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Task is one evaluation: a single individual, or a team of co-evolved individuals scored for
+// the member at Subject. Individuals are encoded with individual.Marshal.
+type Task struct {
+	ID      uint64
+	Team    [][]byte
+	Subject int
+}
+
+// Result is a worker's score for a task; Err is set if the evaluation failed
+type Result struct {
+	ID      uint64
+	Worker  string
+	Fitness float64
+	Err     string
+}
+
+// NextArgs identifies the worker asking for a task
+type NextArgs struct {
+	Worker string
+}
+
+// Options tune the coordinator
+type Options struct {
+	// TaskTimeout is how long a worker may hold a task before it is reassigned
+	TaskTimeout time.Duration
+	// MaxAttempts bounds how often a task is handed out before it fails
+	MaxAttempts int
+	// PollWait is how long a Next call waits for work before returning an empty task
+	PollWait time.Duration
+	// WorkerTimeout is how long queued tasks wait while no worker is seen before the coordinator
+	// gives up on them; it defaults to twice the task timeout
+	WorkerTimeout time.Duration
+}
+
+// task is a queued or running evaluation
+type task struct {
+	Task
+	done     chan Result
+	leasedAt time.Time
+	worker   string
+	attempts int
+	stolen   bool
+}
+
+// Coordinator queues tasks for workers and collects their results
+type Coordinator struct {
+	listener net.Listener
+	options  Options
+
+	mu      sync.Mutex
+	changed chan struct{}
+	pending []*task
+	running map[uint64]*task
+	workers map[string]time.Time
+	seen    time.Time
+	err     error
+	conns   map[net.Conn]struct{}
+	nextID  uint64
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+// Listen starts a coordinator serving workers on addr, e.g. ":7070" or "127.0.0.1:0"
+func Listen(addr string, options Options) (*Coordinator, error) {
+	if options.TaskTimeout <= 0 {
+		return nil, fmt.Errorf("task timeout must be positive")
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = 3
+	}
+	if options.PollWait <= 0 {
+		options.PollWait = time.Second
+	}
+	if options.WorkerTimeout <= 0 {
+		options.WorkerTimeout = 2 * options.TaskTimeout
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	c := &Coordinator{
+		listener: listener,
+		options:  options,
+		changed:  make(chan struct{}),
+		running:  make(map[uint64]*task),
+		workers:  make(map[string]time.Time),
+		seen:     time.Now(),
+		conns:    make(map[net.Conn]struct{}),
+	}
+	server := rpc.NewServer()
+	if err := server.RegisterName("Coordinator", &service{c}); err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+
+	c.wg.Add(2)
+	go c.accept(server)
+	go c.reap()
+	zap.L().Info("Distributed fitness coordinator listening", zap.String("address", c.Addr()))
+	return c, nil
+}
+
+// Addr is the address workers should dial
+func (c *Coordinator) Addr() string {
+	return c.listener.Addr().String()
+}
+
+// Workers returns the names of the workers seen within the last task timeout
+func (c *Coordinator) Workers() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var names []string
+	for name, seen := range c.workers {
+		if time.Since(seen) < c.options.TaskTimeout {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Err reports why the coordinator gave up on its queued tasks, nil while workers are being seen.
+// Once set it stays set and further evaluations fail at once.
+func (c *Coordinator) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// Evaluate queues a task and blocks until a worker returns its result, or until the coordinator
+// gives up after seeing no worker for the worker timeout
+func (c *Coordinator) Evaluate(team [][]byte, subject int) (Result, error) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return Result{}, fmt.Errorf("coordinator is closed")
+	}
+	if c.err != nil {
+		c.mu.Unlock()
+		return Result{}, c.err
+	}
+	c.nextID++
+	t := &task{Task: Task{ID: c.nextID, Team: team, Subject: subject}, done: make(chan Result, 1)}
+	c.pending = append(c.pending, t)
+	c.notify()
+	c.mu.Unlock()
+
+	result := <-t.done
+	if result.Err != "" {
+		return result, fmt.Errorf("task %d failed: %s", result.ID, result.Err)
+	}
+	return result, nil
+}
+
+// Close stops serving workers and fails the tasks still queued or running
+func (c *Coordinator) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	for _, t := range c.pending {
+		t.done <- Result{ID: t.ID, Err: "coordinator is closed"}
+	}
+	for _, t := range c.running {
+		t.done <- Result{ID: t.ID, Err: "coordinator is closed"}
+	}
+	c.pending, c.running = nil, make(map[uint64]*task)
+	c.notify()
+	for conn := range c.conns {
+		_ = conn.Close()
+	}
+	c.mu.Unlock()
+
+	err := c.listener.Close()
+	c.wg.Wait()
+	return err
+}
+
+// accept serves each worker connection until the coordinator closes
+func (c *Coordinator) accept(server *rpc.Server) {
+	defer c.wg.Done()
+	for {
+		conn, err := c.listener.Accept()
+		if err != nil {
+			return
+		}
+		c.mu.Lock()
+		if c.closed {
+			c.mu.Unlock()
+			_ = conn.Close()
+			return
+		}
+		c.conns[conn] = struct{}{}
+		c.mu.Unlock()
+
+		go func() {
+			server.ServeConn(conn)
+			c.mu.Lock()
+			delete(c.conns, conn)
+			c.mu.Unlock()
+		}()
+	}
+}
+
+// notify wakes workers waiting for a task; the caller holds c.mu
+func (c *Coordinator) notify() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// next hands a worker the oldest queued task, or steals a copy of a task running for half the
+// timeout, waiting up to PollWait for one. ok is false if there is no work.
+func (c *Coordinator) next(worker string) (Task, bool) {
+	deadline := time.After(c.options.PollWait)
+	for {
+		c.mu.Lock()
+		c.workers[worker] = time.Now()
+		c.seen = time.Now()
+		if c.closed {
+			c.mu.Unlock()
+			return Task{}, false
+		}
+		if len(c.pending) > 0 {
+			t := c.pending[0]
+			c.pending = c.pending[1:]
+			t.attempts++
+			t.leasedAt, t.worker = time.Now(), worker
+			c.running[t.ID] = t
+			c.mu.Unlock()
+			return t.Task, true
+		}
+		for _, t := range c.running {
+			if !t.stolen && t.worker != worker && time.Since(t.leasedAt) > c.options.TaskTimeout/2 {
+				// The stealer gets a full timeout before the task counts as lost
+				t.stolen, t.leasedAt = true, time.Now()
+				zap.L().Debug("Worker stealing a slow task", zap.Uint64("task", t.ID), zap.String("from", t.worker), zap.String("to", worker))
+				c.mu.Unlock()
+				return t.Task, true
+			}
+		}
+		changed := c.changed
+		c.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-deadline:
+			return Task{}, false
+		}
+	}
+}
+
+// complete records a result; results for finished tasks, e.g. the slower copy of a stolen task,
+// are dropped
+func (c *Coordinator) complete(result Result) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.workers[result.Worker] = time.Now()
+	c.seen = time.Now()
+	t, ok := c.running[result.ID]
+	if !ok {
+		return
+	}
+	delete(c.running, result.ID)
+	if result.Err != "" && t.attempts < c.options.MaxAttempts {
+		zap.L().Warn("Task failed on worker, requeueing", zap.Uint64("task", t.ID), zap.String("worker", result.Worker), zap.String("error", result.Err))
+		c.requeue(t)
+		return
+	}
+	t.done <- result
+}
+
+// requeue puts a task back at the front of the queue; the caller holds c.mu
+func (c *Coordinator) requeue(t *task) {
+	t.stolen = false
+	c.pending = append([]*task{t}, c.pending...)
+	c.notify()
+}
+
+// reap reassigns tasks whose worker has held them past the timeout, failing tasks out of attempts,
+// and fails the queued tasks once no worker has been seen for the worker timeout
+func (c *Coordinator) reap() {
+	defer c.wg.Done()
+	ticker := time.NewTicker(min(c.options.TaskTimeout, c.options.WorkerTimeout) / 4)
+	defer ticker.Stop()
+	for range ticker.C {
+		c.mu.Lock()
+		if c.closed {
+			c.mu.Unlock()
+			return
+		}
+		for id, t := range c.running {
+			if time.Since(t.leasedAt) <= c.options.TaskTimeout {
+				continue
+			}
+			delete(c.running, id)
+			if t.attempts >= c.options.MaxAttempts {
+				t.done <- Result{ID: id, Err: fmt.Sprintf("timed out %d times, last on worker %s", t.attempts, t.worker)}
+				continue
+			}
+			zap.L().Warn("Worker timed out, reassigning task", zap.Uint64("task", id), zap.String("worker", t.worker))
+			c.requeue(t)
+		}
+		if len(c.pending) > 0 && time.Since(c.seen) > c.options.WorkerTimeout {
+			c.err = fmt.Errorf("no worker seen for %s", c.options.WorkerTimeout)
+			zap.L().Error("No live workers, failing queued tasks", zap.Int("tasks", len(c.pending)), zap.Error(c.err))
+			for _, t := range c.pending {
+				t.done <- Result{ID: t.ID, Err: c.err.Error()}
+			}
+			c.pending = nil
+		}
+		c.mu.Unlock()
+	}
+}
+
+// service exposes the coordinator over net/rpc
+type service struct {
+	c *Coordinator
+}
+
+// Next returns a task for the worker, or a task with ID 0 when there is none yet
+func (s *service) Next(args NextArgs, reply *Task) error {
+	t, ok := s.c.next(args.Worker)
+	if ok {
+		*reply = t
+	}
+	return nil
+}
+
+// Complete accepts a worker's result
+func (s *service) Complete(result Result, reply *bool) error {
+	s.c.complete(result)
+	*reply = true
+	return nil
+}
@@ -0,0 +1,245 @@
+package distributed_test
+
+import (
+	"context"
+	"fmt"
+	"net/rpc"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bxrne/darwin/internal/distributed"
+	"github.com/bxrne/darwin/internal/fitness"
+	"github.com/bxrne/darwin/internal/individual"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingCalculator scores bitstrings and records how many it scored, optionally slowly
+type countingCalculator struct {
+	delay  time.Duration
+	scored atomic.Int64
+}
+
+func (c *countingCalculator) CalculateFitness(evolvable individual.Evolvable) {
+	time.Sleep(c.delay)
+	(&fitness.BinaryFitnessCalculator{}).CalculateFitness(evolvable)
+	c.scored.Add(1)
+}
+
+// sumCalculator scores a team as the sum of the member fitnesses
+type sumCalculator struct{}
+
+func (sumCalculator) CalculateFitness(evolvable individual.Evolvable) {}
+
+func (sumCalculator) CalculateTeamFitness(team []individual.Evolvable, subject int) float64 {
+	total := 0.0
+	for _, member := range team {
+		total += member.GetFitness()
+	}
+	return total
+}
+
+func startCoordinator(t *testing.T, timeout time.Duration) *distributed.Coordinator {
+	t.Helper()
+	coordinator, err := distributed.Listen("127.0.0.1:0", distributed.Options{TaskTimeout: timeout, PollWait: 50 * time.Millisecond})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = coordinator.Close() })
+	return coordinator
+}
+
+func startWorker(t *testing.T, ctx context.Context, addr, name string, calc fitness.FitnessCalculator) *sync.WaitGroup {
+	t.Helper()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		assert.NoError(t, distributed.RunWorker(ctx, addr, name, calc, 2))
+	}()
+	return &wg
+}
+
+// evaluateAll scores the population through the calculator concurrently
+func evaluateAll(calc *distributed.Calculator, population []individual.Evolvable) {
+	var wg sync.WaitGroup
+	for _, ind := range population {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			calc.CalculateFitness(ind)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestCalculator_GIVEN_several_workers_WHEN_evaluating_THEN_matches_local_scores(t *testing.T) {
+	coordinator := startCoordinator(t, 5*time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	workers := make([]*countingCalculator, 3)
+	for i := range workers {
+		workers[i] = &countingCalculator{delay: time.Millisecond}
+		startWorker(t, ctx, coordinator.Addr(), fmt.Sprintf("worker-%d", i), workers[i])
+	}
+	calc := distributed.NewCalculator(coordinator, 16)
+
+	population := make([]individual.Evolvable, 60)
+	for i := range population {
+		population[i] = individual.NewBinaryIndividual(nil, 32)
+	}
+	evaluateAll(calc, population)
+
+	total := int64(0)
+	for _, worker := range workers {
+		assert.Positive(t, worker.scored.Load(), "every worker takes a share")
+		total += worker.scored.Load()
+	}
+	assert.GreaterOrEqual(t, total, int64(len(population)))
+	for _, ind := range population {
+		expected := ind.Clone()
+		(&fitness.BinaryFitnessCalculator{}).CalculateFitness(expected)
+		assert.Equal(t, expected.GetFitness(), ind.GetFitness())
+	}
+	assert.Len(t, coordinator.Workers(), 3)
+}
+
+func TestCalculator_GIVEN_team_WHEN_evaluated_THEN_worker_scores_team(t *testing.T) {
+	coordinator := startCoordinator(t, 5*time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	startWorker(t, ctx, coordinator.Addr(), "worker", sumCalculator{})
+	calc := distributed.NewCalculator(coordinator, 1)
+
+	first, second := individual.NewBinaryIndividual(nil, 4), individual.NewBinaryIndividual(nil, 4)
+	first.SetFitness(2)
+	second.SetFitness(3)
+
+	assert.Equal(t, 5.0, calc.CalculateTeamFitness([]individual.Evolvable{first, second}, 1))
+}
+
+func TestCoordinator_GIVEN_dead_worker_WHEN_task_times_out_THEN_reassigns_it(t *testing.T) {
+	coordinator := startCoordinator(t, 200*time.Millisecond)
+
+	// A worker that takes a task and never answers
+	client, err := rpc.Dial("tcp", coordinator.Addr())
+	require.NoError(t, err)
+	defer client.Close()
+	calc := distributed.NewCalculator(coordinator, 1)
+	ind := individual.NewBinaryIndividual(nil, 16)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		calc.CalculateFitness(ind)
+	}()
+	var lost distributed.Task
+	require.NoError(t, client.Call("Coordinator.Next", distributed.NextArgs{Worker: "dead"}, &lost))
+	require.NotZero(t, lost.ID)
+	_ = client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	healthy := &countingCalculator{}
+	startWorker(t, ctx, coordinator.Addr(), "healthy", healthy)
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("task was never reassigned")
+	}
+	expected := ind.Clone()
+	(&fitness.BinaryFitnessCalculator{}).CalculateFitness(expected)
+	assert.Equal(t, expected.GetFitness(), ind.GetFitness())
+	assert.Equal(t, int64(1), healthy.scored.Load())
+}
+
+func TestCoordinator_GIVEN_slow_worker_WHEN_another_is_idle_THEN_steals_the_task(t *testing.T) {
+	coordinator := startCoordinator(t, 400*time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	slow := &countingCalculator{delay: 2 * time.Second}
+	startWorker(t, ctx, coordinator.Addr(), "slow", slow)
+	calc := distributed.NewCalculator(coordinator, 1)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		calc.CalculateFitness(individual.NewBinaryIndividual(nil, 16))
+	}()
+	// Let the slow worker take the task before the fast one joins
+	time.Sleep(50 * time.Millisecond)
+	fast := &countingCalculator{}
+	startWorker(t, ctx, coordinator.Addr(), "fast", fast)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("idle worker did not steal the slow task")
+	}
+	assert.Equal(t, int64(1), fast.scored.Load())
+}
+
+func TestCoordinator_GIVEN_no_workers_WHEN_closed_THEN_fails_pending_tasks(t *testing.T) {
+	coordinator, err := distributed.Listen("127.0.0.1:0", distributed.Options{TaskTimeout: time.Second})
+	require.NoError(t, err)
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := coordinator.Evaluate([][]byte{[]byte("{}")}, 0)
+		errs <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, coordinator.Close())
+
+	assert.ErrorContains(t, <-errs, "coordinator is closed")
+	_, err = coordinator.Evaluate(nil, 0)
+	assert.ErrorContains(t, err, "coordinator is closed")
+}
+
+func TestRunWorker_GIVEN_no_coordinator_WHEN_started_THEN_returns_error(t *testing.T) {
+	err := distributed.RunWorker(context.Background(), "127.0.0.1:1", "worker", &countingCalculator{}, 1)
+
+	assert.ErrorContains(t, err, "failed to reach coordinator")
+}
+
+func TestRunWorker_GIVEN_coordinator_closes_WHEN_running_THEN_returns(t *testing.T) {
+	coordinator := startCoordinator(t, time.Second)
+	wg := startWorker(t, context.Background(), coordinator.Addr(), "worker", &countingCalculator{})
+
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, coordinator.Close())
+
+	finished := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+	case <-time.After(3 * time.Second):
+		t.Fatal("worker kept running after the coordinator closed")
+	}
+}
+
+func TestCoordinator_GIVEN_no_workers_WHEN_worker_timeout_passes_THEN_fails_pending_tasks(t *testing.T) {
+	coordinator, err := distributed.Listen("127.0.0.1:0", distributed.Options{TaskTimeout: time.Second, WorkerTimeout: 200 * time.Millisecond})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = coordinator.Close() })
+	calc := distributed.NewCalculator(coordinator, 1)
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := coordinator.Evaluate([][]byte{[]byte("{}")}, 0)
+		errs <- err
+	}()
+
+	select {
+	case err := <-errs:
+		assert.ErrorContains(t, err, "no worker seen")
+	case <-time.After(5 * time.Second):
+		t.Fatal("evaluation waited forever with no workers")
+	}
+	assert.ErrorContains(t, calc.Err(), "no worker seen")
+	_, err = coordinator.Evaluate(nil, 0)
+	assert.ErrorContains(t, err, "no worker seen")
+}
@@ -0,0 +1,104 @@
+package distributed
+
+import (
+	"context"
+	"fmt"
+	"net/rpc"
+	"sync"
+	"time"
+
+	"github.com/bxrne/darwin/internal/fitness"
+	"github.com/bxrne/darwin/internal/individual"
+	"go.uber.org/zap"
+)
+
+// RunWorker pulls tasks from the coordinator at addr and scores them with calc, running parallel
+// tasks at once. It returns nil when ctx is cancelled or the coordinator shuts down, and an error
+// if the coordinator cannot be reached at all.
+func RunWorker(ctx context.Context, addr, name string, calc fitness.FitnessCalculator, parallel int) error {
+	client, err := rpc.Dial("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to reach coordinator at %s: %w", addr, err)
+	}
+	go func() {
+		<-ctx.Done()
+		_ = client.Close()
+	}()
+	if parallel <= 0 {
+		parallel = 1
+	}
+	zap.L().Info("Worker connected to coordinator", zap.String("worker", name), zap.String("coordinator", addr), zap.Int("parallel", parallel))
+
+	var wg sync.WaitGroup
+	for range parallel {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				var t Task
+				if err := client.Call("Coordinator.Next", NextArgs{Worker: name}, &t); err != nil {
+					if ctx.Err() == nil {
+						zap.L().Info("Coordinator went away, stopping worker", zap.String("worker", name), zap.Error(err))
+					}
+					_ = client.Close()
+					return
+				}
+				if t.ID == 0 {
+					continue
+				}
+
+				result := evaluate(calc, t)
+				result.Worker = name
+				var ok bool
+				if err := client.Call("Coordinator.Complete", result, &ok); err != nil {
+					_ = client.Close()
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return nil
+}
+
+// evaluate decodes a task and scores it, recovering from calculators that panic on bad input
+func evaluate(calc fitness.FitnessCalculator, t Task) (result Result) {
+	result.ID = t.ID
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			result.Err = fmt.Sprintf("fitness calculator panicked: %v", r)
+		}
+		zap.L().Debug("Evaluated task", zap.Uint64("task", t.ID), zap.Duration("took", time.Since(start)), zap.String("error", result.Err))
+	}()
+
+	team := make([]individual.Evolvable, len(t.Team))
+	for i, data := range t.Team {
+		member, err := individual.Unmarshal(data)
+		if err != nil {
+			result.Err = err.Error()
+			return result
+		}
+		team[i] = member
+	}
+	if t.Subject < 0 || t.Subject >= len(team) {
+		result.Err = fmt.Sprintf("subject %d outside team of %d", t.Subject, len(team))
+		return result
+	}
+
+	if len(team) == 1 {
+		calc.CalculateFitness(team[0])
+		result.Fitness = team[0].GetFitness()
+	} else if teamCalc, ok := calc.(fitness.TeamFitnessCalculator); ok {
+		result.Fitness = teamCalc.CalculateTeamFitness(team, t.Subject)
+	} else {
+		result.Err = fmt.Sprintf("fitness calculator %T cannot score teams", calc)
+		return result
+	}
+	if aborter, ok := calc.(fitness.Aborter); ok {
+		if err := aborter.Err(); err != nil {
+			result.Err = err.Error()
+		}
+	}
+	return result
+}
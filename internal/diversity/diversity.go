@@ -0,0 +1,324 @@
+// Package diversity measures how varied a population is, so premature convergence shows up in the
+// metrics next to fitness. Every measure is computed from the population alone; sampled measures
+// draw from their own fixed-seed source and leave the run's random stream untouched.
+package diversity
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/bxrne/darwin/internal/individual"
+)
+
+const (
+	// maxPairs bounds the individual pairs a sampled distance compares
+	maxPairs = 200
+	// fitnessBins is how many equal-width bins fitness values are counted in
+	fitnessBins = 10
+)
+
+// Measure returns the diversity metrics of a population of one genome type:
+//
+//	diversity_hamming             mean pairwise Hamming distance per gene (bitstrings)
+//	diversity_codon_entropy       mean Shannon entropy, in bits, of the codons at each locus (grammar genomes)
+//	diversity_unique_phenotypes   share of distinct expressions (trees, grammar genomes, action trees)
+//	diversity_tree_distance       mean structural distance between sampled pairs of trees
+//	diversity_weights_distance    mean Frobenius distance to the mean weights matrix
+//	diversity_vector_distance     mean Euclidean distance to the mean real vector
+//	diversity_fitness_entropy     Shannon entropy, in bits, of fitness values in equal-width bins (all genomes)
+func Measure(population []individual.Evolvable) map[string]float64 {
+	values := make(map[string]float64)
+	if len(population) == 0 {
+		return values
+	}
+	values["diversity_fitness_entropy"] = FitnessEntropy(population)
+
+	switch population[0].(type) {
+	case *individual.BinaryIndividual:
+		values["diversity_hamming"] = Hamming(genomes(population, func(b *individual.BinaryIndividual) []byte { return b.Genome }))
+	case *individual.GrammarTree:
+		values["diversity_codon_entropy"] = CodonEntropy(genomes(population, func(g *individual.GrammarTree) []int { return g.Genome }))
+		values["diversity_unique_phenotypes"] = UniquePhenotypes(population)
+		values["diversity_tree_distance"] = sampledTreeDistance(population, func(g *individual.GrammarTree) []*individual.TreeNode {
+			return []*individual.TreeNode{g.Root}
+		})
+	case *individual.Tree:
+		values["diversity_unique_phenotypes"] = UniquePhenotypes(population)
+		values["diversity_tree_distance"] = sampledTreeDistance(population, func(t *individual.Tree) []*individual.TreeNode {
+			return []*individual.TreeNode{t.Root}
+		})
+	case *individual.ActionTreeIndividual:
+		values["diversity_unique_phenotypes"] = UniquePhenotypes(population)
+		values["diversity_tree_distance"] = sampledTreeDistance(population, actionRoots)
+	case *individual.WeightsIndividual:
+		values["diversity_weights_distance"] = CentroidDistance(genomes(population, func(w *individual.WeightsIndividual) []float64 {
+			if w.Weights == nil {
+				return nil
+			}
+			return w.Weights.RawMatrix().Data
+		}))
+	case *individual.RealVectorIndividual:
+		values["diversity_vector_distance"] = CentroidDistance(genomes(population, func(rv *individual.RealVectorIndividual) []float64 {
+			return rv.Genes
+		}))
+	}
+	return values
+}
+
+// genomes extracts a genome from each individual of type T, skipping any other type
+func genomes[T individual.Evolvable, G any](population []individual.Evolvable, genome func(T) G) []G {
+	out := make([]G, 0, len(population))
+	for _, ind := range population {
+		if typed, ok := ind.(T); ok {
+			out = append(out, genome(typed))
+		}
+	}
+	return out
+}
+
+// Hamming is the mean Hamming distance between every pair of genomes divided by the genome
+// length: 0 when all are equal, 0.5 for random bits. It counts the differing pairs at each locus
+// instead of comparing every pair, so it is linear in the population. Genomes of different
+// lengths differ at every locus only one of them has, on top of the shared loci that differ.
+func Hamming(genomes [][]byte) float64 {
+	n := len(genomes)
+	if n < 2 {
+		return 0
+	}
+	length := 0
+	for _, g := range genomes {
+		length = max(length, len(g))
+	}
+	if length == 0 {
+		return 0
+	}
+	total := 0.0
+	counts := make(map[int]int)
+	for locus := range length {
+		clear(counts)
+		for _, g := range genomes {
+			gene := -1 // outside the byte range, so a missing gene differs from any present one
+			if locus < len(g) {
+				gene = int(g[locus])
+			}
+			counts[gene]++
+		}
+		// Pairs differing at this locus are all pairs less those agreeing
+		agreeing := 0
+		for _, c := range counts {
+			agreeing += c * (c - 1) / 2
+		}
+		total += float64(n*(n-1)/2 - agreeing)
+	}
+	return total / float64(n*(n-1)/2) / float64(length)
+}
+
+// CodonEntropy is the Shannon entropy of the codon values at each locus, averaged over the loci.
+// Shorter genomes only count at the loci they have.
+func CodonEntropy(genomes [][]int) float64 {
+	length := 0
+	for _, g := range genomes {
+		length = max(length, len(g))
+	}
+	if length == 0 {
+		return 0
+	}
+	total := 0.0
+	counts := make(map[int]int)
+	for locus := range length {
+		clear(counts)
+		present := 0
+		for _, g := range genomes {
+			if locus < len(g) {
+				counts[g[locus]]++
+				present++
+			}
+		}
+		total += entropy(counts, present)
+	}
+	return total / float64(length)
+}
+
+// UniquePhenotypes is the number of distinct descriptions over the population size: 1 when all
+// differ, 1/n when all are equal
+func UniquePhenotypes(population []individual.Evolvable) float64 {
+	if len(population) == 0 {
+		return 0
+	}
+	seen := make(map[string]struct{}, len(population))
+	for _, ind := range population {
+		seen[ind.Describe()] = struct{}{}
+	}
+	return float64(len(seen)) / float64(len(population))
+}
+
+// TreeDistance is the structural distance between two trees: overlaying them from the root, each
+// position where only one tree has a node, or the two nodes differ, counts one. It is 0 for equal
+// trees and bounds their tree-edit distance from above.
+func TreeDistance(a, b *individual.TreeNode) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return size(b)
+	case b == nil:
+		return size(a)
+	}
+	distance := TreeDistance(a.Left, b.Left) + TreeDistance(a.Right, b.Right)
+	if a.Value != b.Value {
+		distance++
+	}
+	return distance
+}
+
+func size(node *individual.TreeNode) int {
+	if node == nil {
+		return 0
+	}
+	return 1 + size(node.Left) + size(node.Right)
+}
+
+// actionRoots lists an action tree individual's trees in action order, so pairs compare like
+// with like
+func actionRoots(ati *individual.ActionTreeIndividual) []*individual.TreeNode {
+	names := make([]string, 0, len(ati.Trees))
+	for name := range ati.Trees {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	roots := make([]*individual.TreeNode, len(names))
+	for i, name := range names {
+		if tree := ati.Trees[name]; tree != nil {
+			roots[i] = tree.Root
+		}
+	}
+	return roots
+}
+
+// sampledTreeDistance is the mean TreeDistance, summed over each individual's trees, of every pair
+// in small populations and maxPairs random pairs in larger ones
+func sampledTreeDistance[T individual.Evolvable](population []individual.Evolvable, roots func(T) []*individual.TreeNode) float64 {
+	trees := genomes(population, roots)
+	pairs := samplePairs(len(trees))
+	if len(pairs) == 0 {
+		return 0
+	}
+	total := 0
+	for _, pair := range pairs {
+		a, b := trees[pair[0]], trees[pair[1]]
+		for i := range max(len(a), len(b)) {
+			var ra, rb *individual.TreeNode
+			if i < len(a) {
+				ra = a[i]
+			}
+			if i < len(b) {
+				rb = b[i]
+			}
+			total += TreeDistance(ra, rb)
+		}
+	}
+	return float64(total) / float64(len(pairs))
+}
+
+// samplePairs returns every pair of n items if there are at most maxPairs, else maxPairs random
+// distinct pairs drawn from a fixed seed
+func samplePairs(n int) [][2]int {
+	var pairs [][2]int
+	if n*(n-1)/2 <= maxPairs {
+		for i := range n {
+			for j := i + 1; j < n; j++ {
+				pairs = append(pairs, [2]int{i, j})
+			}
+		}
+		return pairs
+	}
+	source := rand.New(rand.NewPCG(uint64(n), 0x5eed))
+	for range maxPairs {
+		i := source.IntN(n)
+		j := source.IntN(n - 1)
+		if j >= i {
+			j++
+		}
+		pairs = append(pairs, [2]int{i, j})
+	}
+	return pairs
+}
+
+// CentroidDistance is the mean Euclidean distance of each vector to their mean. Vectors of a
+// different length from the first are skipped.
+func CentroidDistance(vectors [][]float64) float64 {
+	if len(vectors) == 0 {
+		return 0
+	}
+	dims := len(vectors[0])
+	centroid := make([]float64, dims)
+	used := 0
+	for _, v := range vectors {
+		if len(v) != dims {
+			continue
+		}
+		for i, x := range v {
+			centroid[i] += x
+		}
+		used++
+	}
+	for i := range centroid {
+		centroid[i] /= float64(used)
+	}
+
+	total := 0.0
+	for _, v := range vectors {
+		if len(v) != dims {
+			continue
+		}
+		sum := 0.0
+		for i, x := range v {
+			d := x - centroid[i]
+			sum += d * d
+		}
+		total += math.Sqrt(sum)
+	}
+	return total / float64(used)
+}
+
+// FitnessEntropy is the Shannon entropy of the population's fitness values counted in
+// fitnessBins equal-width bins between the lowest and highest: 0 when all are equal, at most
+// log2(fitnessBins). Non-finite fitness values are left out.
+func FitnessEntropy(population []individual.Evolvable) float64 {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	var values []float64
+	for _, ind := range population {
+		f := ind.GetFitness()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			continue
+		}
+		values = append(values, f)
+		lo, hi = math.Min(lo, f), math.Max(hi, f)
+	}
+	if len(values) == 0 || hi == lo {
+		return 0
+	}
+	counts := make(map[int]int, fitnessBins)
+	for _, f := range values {
+		bin := min(int((f-lo)/(hi-lo)*fitnessBins), fitnessBins-1)
+		counts[bin]++
+	}
+	return entropy(counts, len(values))
+}
+
+// entropy is the Shannon entropy in bits of counts totalling total
+func entropy[K comparable](counts map[K]int, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	h := 0.0
+	for _, c := range counts {
+		if c == 0 {
+			continue
+		}
+		p := float64(c) / float64(total)
+		h -= p * math.Log2(p)
+	}
+	return h
+}
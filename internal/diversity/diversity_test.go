@@ -0,0 +1,130 @@
+package diversity
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bxrne/darwin/internal/individual"
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/mat"
+)
+
+func leaf(value string) *individual.TreeNode {
+	return &individual.TreeNode{Value: value}
+}
+
+func node(value string, left, right *individual.TreeNode) *individual.TreeNode {
+	return &individual.TreeNode{Value: value, Left: left, Right: right}
+}
+
+func TestHamming_GIVEN_identical_genomes_WHEN_measured_THEN_zero(t *testing.T) {
+	assert.Equal(t, 0.0, Hamming([][]byte{[]byte("1010"), []byte("1010"), []byte("1010")}))
+}
+
+func TestHamming_GIVEN_genomes_WHEN_measured_THEN_mean_pairwise_distance_per_gene(t *testing.T) {
+	// Pairs differ at 4, 2 and 2 of 4 genes
+	genomes := [][]byte{[]byte("0000"), []byte("1111"), []byte("0011")}
+
+	assert.InDelta(t, (1.0+0.5+0.5)/3, Hamming(genomes), 1e-12)
+}
+
+func TestHamming_GIVEN_different_lengths_WHEN_measured_THEN_missing_genes_differ_even_from_zero(t *testing.T) {
+	// Pairs differ at 2, 1+2 and 1 of 4 genes; zero bytes must not match the missing loci
+	genomes := [][]byte{{0, 0, 0, 0}, {0, 0}, {0, 1}}
+
+	assert.InDelta(t, (2.0+3.0+1.0)/3/4, Hamming(genomes), 1e-12)
+}
+
+func TestCodonEntropy_GIVEN_codons_WHEN_measured_THEN_mean_entropy_per_locus(t *testing.T) {
+	// Locus 0 splits evenly (1 bit), locus 1 agrees (0 bits)
+	genomes := [][]int{{1, 7}, {2, 7}}
+
+	assert.InDelta(t, 0.5, CodonEntropy(genomes), 1e-12)
+}
+
+func TestUniquePhenotypes_GIVEN_duplicate_trees_WHEN_measured_THEN_distinct_share(t *testing.T) {
+	population := []individual.Evolvable{
+		&individual.Tree{Root: node("+", leaf("x"), leaf("1"))},
+		&individual.Tree{Root: node("+", leaf("x"), leaf("1"))},
+		&individual.Tree{Root: leaf("x")},
+		&individual.Tree{Root: leaf("y")},
+	}
+
+	assert.Equal(t, 0.75, UniquePhenotypes(population))
+}
+
+func TestTreeDistance_GIVEN_trees_WHEN_compared_THEN_counts_differing_and_missing_nodes(t *testing.T) {
+	a := node("+", leaf("x"), leaf("1"))
+	b := node("*", leaf("x"), node("-", leaf("y"), leaf("2")))
+
+	assert.Equal(t, 0, TreeDistance(a, a))
+	assert.Equal(t, 4, TreeDistance(a, b)) // root, right value, and its two extra children
+	assert.Equal(t, TreeDistance(a, b), TreeDistance(b, a))
+}
+
+func TestCentroidDistance_GIVEN_vectors_WHEN_measured_THEN_mean_distance_to_mean(t *testing.T) {
+	vectors := [][]float64{{0, 0}, {2, 0}}
+
+	assert.InDelta(t, 1.0, CentroidDistance(vectors), 1e-12)
+}
+
+func TestFitnessEntropy_GIVEN_fitness_values_WHEN_measured_THEN_binned_entropy(t *testing.T) {
+	equal := []individual.Evolvable{
+		&individual.BinaryIndividual{Fitness: 1},
+		&individual.BinaryIndividual{Fitness: 1},
+	}
+	split := []individual.Evolvable{
+		&individual.BinaryIndividual{Fitness: 0},
+		&individual.BinaryIndividual{Fitness: 1},
+		&individual.BinaryIndividual{Fitness: math.NaN()},
+	}
+
+	assert.Equal(t, 0.0, FitnessEntropy(equal))
+	assert.InDelta(t, 1.0, FitnessEntropy(split), 1e-12)
+}
+
+func TestMeasure_GIVEN_each_genome_type_WHEN_measured_THEN_reports_its_measures(t *testing.T) {
+	weights := func(values ...float64) individual.Evolvable {
+		return &individual.WeightsIndividual{Weights: mat.NewDense(1, len(values), values)}
+	}
+	tests := []struct {
+		name       string
+		population []individual.Evolvable
+		keys       []string
+	}{
+		{"bitstring", []individual.Evolvable{individual.NewBinaryIndividual(nil, 8), individual.NewBinaryIndividual(nil, 8)}, []string{"diversity_hamming"}},
+		{"grammar", []individual.Evolvable{individual.NewGrammarTree(nil, 8), individual.NewGrammarTree(nil, 8)}, []string{"diversity_codon_entropy", "diversity_unique_phenotypes", "diversity_tree_distance"}},
+		{"tree", []individual.Evolvable{&individual.Tree{Root: leaf("x")}, &individual.Tree{Root: leaf("y")}}, []string{"diversity_unique_phenotypes", "diversity_tree_distance"}},
+		{"weights", []individual.Evolvable{weights(0, 0), weights(0, 2)}, []string{"diversity_weights_distance"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := Measure(tt.population)
+
+			assert.Contains(t, values, "diversity_fitness_entropy")
+			for _, key := range tt.keys {
+				assert.Contains(t, values, key)
+				assert.False(t, math.IsNaN(values[key]), key)
+			}
+			assert.Len(t, values, len(tt.keys)+1)
+		})
+	}
+	assert.Empty(t, Measure(nil))
+}
+
+func TestMeasure_GIVEN_tree_pairs_WHEN_measured_THEN_tree_distance_is_their_distance(t *testing.T) {
+	population := []individual.Evolvable{&individual.Tree{Root: leaf("x")}, &individual.Tree{Root: node("+", leaf("x"), leaf("1"))}}
+
+	assert.Equal(t, 3.0, Measure(population)["diversity_tree_distance"])
+}
+
+func TestSamplePairs_GIVEN_large_population_WHEN_sampled_THEN_bounded_distinct_and_repeatable(t *testing.T) {
+	pairs := samplePairs(1000)
+
+	assert.Len(t, pairs, maxPairs)
+	for _, pair := range pairs {
+		assert.NotEqual(t, pair[0], pair[1])
+	}
+	assert.Equal(t, pairs, samplePairs(1000))
+	assert.Len(t, samplePairs(5), 10)
+}
@@ -0,0 +1,107 @@
+package environment
+
+import (
+	"math"
+	"math/rand/v2"
+)
+
+// Classic cart-pole constants (Barto, Sutton and Anderson 1983)
+const (
+	cartPoleGravity        = 9.8
+	cartPoleCartMass       = 1.0
+	cartPolePoleMass       = 0.1
+	cartPoleHalfLength     = 0.5
+	cartPoleForce          = 10.0
+	cartPoleTau            = 0.02
+	cartPoleAngleLimit     = 12 * 2 * math.Pi / 360
+	cartPolePositionLimit  = 2.4
+	cartPoleMaxSteps       = 500
+	cartPoleInitialSpread  = 0.05
+	cartPoleTotalMass      = cartPoleCartMass + cartPolePoleMass
+	cartPolePoleMassLength = cartPolePoleMass * cartPoleHalfLength
+)
+
+// CartPole balances a pole on a cart by pushing it left or right. Every step the
+// pole stays up pays 1; the episode ends when the pole falls or the cart leaves the track.
+type CartPole struct {
+	seed     int64
+	maxSteps int
+	x        float64
+	xDot     float64
+	theta    float64
+	thetaDot float64
+	steps    int
+}
+
+func newCartPole(seed int64, settings Settings) (GameEnvironment, error) {
+	maxSteps := cartPoleMaxSteps
+	if settings.MaxSteps > 0 {
+		maxSteps = settings.MaxSteps
+	}
+	return &CartPole{seed: seed, maxSteps: maxSteps}, nil
+}
+
+// Reset implements GameEnvironment; the initial state is drawn from the seed
+func (c *CartPole) Reset() (*Observation, error) {
+	r := rand.New(rand.NewPCG(uint64(c.seed), 2))
+	spread := func() float64 { return (r.Float64()*2 - 1) * cartPoleInitialSpread }
+	c.x, c.xDot, c.theta, c.thetaDot = spread(), spread(), spread(), spread()
+	c.steps = 0
+	return c.observe(0, false, false), nil
+}
+
+// Step implements GameEnvironment using Euler integration of the cart-pole dynamics
+func (c *CartPole) Step(action []int) (*Observation, error) {
+	force := -cartPoleForce
+	if clampAction(action, 0, 2) == 1 {
+		force = cartPoleForce
+	}
+
+	cosTheta, sinTheta := math.Cos(c.theta), math.Sin(c.theta)
+	temp := (force + cartPolePoleMassLength*c.thetaDot*c.thetaDot*sinTheta) / cartPoleTotalMass
+	thetaAcc := (cartPoleGravity*sinTheta - cosTheta*temp) /
+		(cartPoleHalfLength * (4.0/3.0 - cartPolePoleMass*cosTheta*cosTheta/cartPoleTotalMass))
+	xAcc := temp - cartPolePoleMassLength*thetaAcc*cosTheta/cartPoleTotalMass
+
+	c.x += cartPoleTau * c.xDot
+	c.xDot += cartPoleTau * xAcc
+	c.theta += cartPoleTau * c.thetaDot
+	c.thetaDot += cartPoleTau * thetaAcc
+	c.steps++
+
+	terminated := math.Abs(c.x) > cartPolePositionLimit || math.Abs(c.theta) > cartPoleAngleLimit
+	reward := 1.0
+	if terminated {
+		reward = 0
+	}
+	return c.observe(reward, terminated, !terminated && c.steps >= c.maxSteps), nil
+}
+
+// ObservationSchema implements GameEnvironment
+func (c *CartPole) ObservationSchema() []string {
+	return []string{"cart_position", "cart_velocity", "pole_angle", "pole_angular_velocity"}
+}
+
+// ActionSpace implements GameEnvironment: push left (0) or right (1)
+func (c *CartPole) ActionSpace() ActionSpace {
+	return ActionSpace{Heads: []ActionHead{{Name: "push", Size: 2}}}
+}
+
+// Close implements GameEnvironment
+func (c *CartPole) Close() error {
+	return nil
+}
+
+func (c *CartPole) observe(reward float64, terminated, truncated bool) *Observation {
+	return &Observation{
+		Features: map[string]float64{
+			"cart_position":         c.x,
+			"cart_velocity":         c.xDot,
+			"pole_angle":            c.theta,
+			"pole_angular_velocity": c.thetaDot,
+		},
+		Reward:     reward,
+		Terminated: terminated,
+		Truncated:  truncated,
+	}
+}
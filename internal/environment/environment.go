@@ -0,0 +1,106 @@
+// Package environment defines the game interface driven by action-tree fitness and
+// provides in-process environments: Generals, a grid world, CartPole and snake
+package environment
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/bxrne/darwin/internal/generals"
+)
+
+// ActionHead is one discrete component of an action, e.g. a direction with four choices.
+// The layout fields are the environment's defaults for heads the config leaves undeclared.
+type ActionHead struct {
+	Name string
+	Size int
+	// DependsOn names earlier heads whose chosen values condition this head's mask
+	DependsOn []string
+	// Mask names the mask provider restricting this head's valid values, empty for none
+	Mask string
+	// StopValue ends the action when chosen, leaving later heads at 0
+	StopValue *int
+	// PenalizeConstant penalises agents whose outputs for this head never vary
+	PenalizeConstant bool
+}
+
+// ActionSpace describes the action vector an environment accepts, one index per head
+type ActionSpace struct {
+	Heads []ActionHead
+}
+
+// Observation is what an environment reports after Reset and each Step
+type Observation struct {
+	Features   map[string]float64
+	Reward     float64
+	Terminated bool
+	Truncated  bool
+	// Grid carries spatial data for mask providers: the mountain grid on reset and the
+	// valid start cells after each step. Nil for environments without masks.
+	Grid [][]bool
+}
+
+// GameEnvironment is a single episode of a game played by an agent
+type GameEnvironment interface {
+	// Reset starts the episode and returns the first observation
+	Reset() (*Observation, error)
+	// Step applies an action with one index per action head
+	Step(action []int) (*Observation, error)
+	// ObservationSchema lists the feature keys reported in every observation
+	ObservationSchema() []string
+	// ActionSpace describes the accepted actions
+	ActionSpace() ActionSpace
+	// Close releases any resources held by the episode
+	Close() error
+}
+
+// Renderer is implemented by environments that can draw their current state as text, one string
+// per row, e.g. for replays
+type Renderer interface {
+	Render() []string
+}
+
+// Settings tune the in-process environments; fields unused by an environment are ignored
+type Settings struct {
+	// Opponent is the built-in Generals opponent, random or expander
+	Opponent string
+	// OpponentBot replaces the built-in Generals opponent, e.g. with an evolved policy
+	OpponentBot generals.Bot
+	// MaxSteps truncates an episode, 0 keeps the environment's own limit
+	MaxSteps int
+}
+
+// constructors maps environment names to their in-process implementations
+var constructors = map[string]func(seed int64, settings Settings) (GameEnvironment, error){
+	"generals":  newGeneralsEnvironment,
+	"gridworld": newGridWorld,
+	"cartpole":  newCartPole,
+	"snake":     newSnake,
+}
+
+// New creates an in-process environment by name, deterministic for a given seed
+func New(name string, seed int64, settings Settings) (GameEnvironment, error) {
+	constructor, ok := constructors[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown environment %q, available: %v", name, Names())
+	}
+	return constructor(seed, settings)
+}
+
+// Names returns the available in-process environments in sorted order
+func Names() []string {
+	names := make([]string, 0, len(constructors))
+	for name := range constructors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// clampAction reads head i of an action, treating missing or out of range indices as 0
+func clampAction(action []int, i, size int) int {
+	if i >= len(action) || action[i] < 0 || action[i] >= size {
+		return 0
+	}
+	return action[i]
+}
@@ -0,0 +1,158 @@
+package environment
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// play runs an episode with a fixed policy and returns the features seen at each step
+func play(t *testing.T, name string, seed int64, policy func(step int) []int) ([]map[string]float64, *Observation) {
+	env, err := New(name, seed, Settings{MaxSteps: 200})
+	assert.NoError(t, err)
+	defer env.Close()
+
+	obs, err := env.Reset()
+	assert.NoError(t, err)
+	trace := []map[string]float64{obs.Features}
+	for step := range 200 {
+		obs, err = env.Step(policy(step))
+		assert.NoError(t, err)
+		trace = append(trace, obs.Features)
+		if obs.Terminated || obs.Truncated {
+			break
+		}
+	}
+	return trace, obs
+}
+
+func TestNew_GIVEN_unknown_name_WHEN_created_THEN_returns_error(t *testing.T) {
+	_, err := New("chess", 1, Settings{})
+
+	assert.ErrorContains(t, err, "cartpole")
+}
+
+func TestEnvironments_GIVEN_schema_WHEN_observed_THEN_features_match_schema(t *testing.T) {
+	for _, name := range Names() {
+		env, err := New(name, 3, Settings{})
+		assert.NoError(t, err)
+
+		obs, err := env.Reset()
+		assert.NoError(t, err)
+
+		assert.Len(t, obs.Features, len(env.ObservationSchema()), name)
+		for _, key := range env.ObservationSchema() {
+			assert.Contains(t, obs.Features, key, name)
+		}
+		assert.NotEmpty(t, env.ActionSpace().Heads, name)
+	}
+}
+
+func TestEnvironments_GIVEN_same_seed_WHEN_played_twice_THEN_episodes_are_identical(t *testing.T) {
+	for _, name := range Names() {
+		env, err := New(name, 1, Settings{})
+		assert.NoError(t, err)
+		heads := env.ActionSpace().Heads
+		policy := func(step int) []int {
+			action := make([]int, len(heads))
+			for i, head := range heads {
+				action[i] = (step + i) % head.Size
+			}
+			return action
+		}
+
+		first, _ := play(t, name, 9, policy)
+		second, _ := play(t, name, 9, policy)
+
+		assert.Equal(t, first, second, name)
+	}
+}
+
+func TestCartPole_GIVEN_constant_push_WHEN_played_THEN_pole_falls(t *testing.T) {
+	trace, obs := play(t, "cartpole", 1, func(int) []int { return []int{1} })
+
+	assert.True(t, obs.Terminated)
+	assert.Equal(t, 0.0, obs.Reward)
+	assert.Less(t, len(trace), 100)
+}
+
+func TestGridWorld_GIVEN_open_grid_WHEN_walking_to_goal_THEN_rewarded_and_terminated(t *testing.T) {
+	env, err := New("gridworld", 1, Settings{})
+	assert.NoError(t, err)
+	_, err = env.Reset()
+	assert.NoError(t, err)
+	world := env.(*GridWorld)
+	world.walls = [gridWorldSize][gridWorldSize]bool{}
+
+	var obs *Observation
+	for range gridWorldSize - 1 {
+		obs, _ = env.Step([]int{1}) // down
+	}
+	for range gridWorldSize - 1 {
+		obs, _ = env.Step([]int{3}) // right
+	}
+
+	assert.True(t, obs.Terminated)
+	assert.Equal(t, gridWorldGoalReward, obs.Reward)
+}
+
+func TestSnake_GIVEN_straight_moves_WHEN_wall_hit_THEN_dies_with_penalty(t *testing.T) {
+	env, err := New("snake", 1, Settings{})
+	assert.NoError(t, err)
+	_, err = env.Reset()
+	assert.NoError(t, err)
+	snake := env.(*Snake)
+	snake.food = [2]int{0, 0}
+
+	var obs *Observation
+	for range snakeSize {
+		obs, _ = env.Step([]int{1})
+		if obs.Terminated {
+			break
+		}
+	}
+
+	assert.True(t, obs.Terminated)
+	assert.Equal(t, -1.0, obs.Reward)
+}
+
+func TestSnake_GIVEN_food_ahead_WHEN_eaten_THEN_grows_and_rewarded(t *testing.T) {
+	env, err := New("snake", 1, Settings{})
+	assert.NoError(t, err)
+	_, err = env.Reset()
+	assert.NoError(t, err)
+	snake := env.(*Snake)
+	head := snake.body[0]
+	snake.food = [2]int{head[0], head[1] + 1}
+
+	obs, err := env.Step([]int{1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, obs.Reward)
+	assert.Equal(t, 4.0, obs.Features["length"])
+}
+
+func TestRenderers_GIVEN_reset_game_WHEN_rendered_THEN_draw_every_row(t *testing.T) {
+	expected := map[string]struct {
+		rows   int
+		marker string
+	}{
+		"generals":  {8, "+G"},
+		"gridworld": {gridWorldSize, "A"},
+		"snake":     {snakeSize, "H"},
+	}
+	for name, want := range expected {
+		env, err := New(name, 1, Settings{})
+		assert.NoError(t, err)
+		_, err = env.Reset()
+		assert.NoError(t, err)
+
+		renderer, ok := env.(Renderer)
+		assert.True(t, ok, name)
+		rows := renderer.Render()
+
+		assert.Len(t, rows, want.rows, name)
+		assert.Contains(t, strings.Join(rows, "\n"), want.marker, name)
+	}
+}
@@ -0,0 +1,135 @@
+package environment
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bxrne/darwin/internal/generals"
+)
+
+// GeneralsEnvironment plays an in-process generals.Game, reporting the same observations as the Python server
+type GeneralsEnvironment struct {
+	game   *generals.Game
+	config generals.Config
+}
+
+func newGeneralsEnvironment(seed int64, settings Settings) (GameEnvironment, error) {
+	config := generals.DefaultConfig()
+	if settings.Opponent != "" {
+		config.Opponent = settings.Opponent
+	}
+	config.MaxTurns = settings.MaxSteps
+	var game *generals.Game
+	var err error
+	if settings.OpponentBot != nil {
+		game, err = generals.NewGameAgainst(config, seed, settings.OpponentBot)
+	} else {
+		game, err = generals.NewGame(config, seed)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &GeneralsEnvironment{game: game, config: config}, nil
+}
+
+// Reset implements GameEnvironment; Grid holds the visible mountains
+func (e *GeneralsEnvironment) Reset() (*Observation, error) {
+	obs := e.game.Reset()
+	return &Observation{Features: generals.Features(obs), Grid: obs.Mountains}, nil
+}
+
+// Step implements GameEnvironment; Grid holds the owned cells able to move
+func (e *GeneralsEnvironment) Step(action []int) (*Observation, error) {
+	result := e.game.Step(generals.ActionFromVector(action))
+	return &Observation{
+		Features:   generals.Features(result.Observation),
+		Reward:     result.Reward,
+		Terminated: result.Terminated,
+		Truncated:  result.Truncated,
+		Grid:       generals.ValidStartPoints(result.Observation),
+	}, nil
+}
+
+// ObservationSchema implements GameEnvironment
+func (e *GeneralsEnvironment) ObservationSchema() []string {
+	return generals.FeatureNames
+}
+
+// ActionSpace implements GameEnvironment
+func (e *GeneralsEnvironment) ActionSpace() ActionSpace {
+	return GeneralsActionSpace(e.config.Rows, e.config.Cols)
+}
+
+// Outcome scores the game for the agent: 1 for a win, 0 for a loss and 0.5 for a draw.
+// Games without a captured general are decided by land held.
+func (e *GeneralsEnvironment) Outcome() float64 {
+	switch e.game.Winner() {
+	case generals.Agent:
+		return 1
+	case generals.Opponent:
+		return 0
+	}
+	obs := e.game.Observe(generals.Agent)
+	switch {
+	case obs.OwnedLandCount > obs.OpponentLandCount:
+		return 1
+	case obs.OwnedLandCount < obs.OpponentLandCount:
+		return 0
+	}
+	return 0.5
+}
+
+// Render implements Renderer, drawing the agent's view: + marks its cells and - the opponent's,
+// G a general and C a city, followed by the army; ^^ is a mountain, .. fog and ## a structure in fog
+func (e *GeneralsEnvironment) Render() []string {
+	obs := e.game.Observe(generals.Agent)
+	rows := make([]string, obs.Rows)
+	for row := range obs.Rows {
+		var b strings.Builder
+		for col := range obs.Cols {
+			switch {
+			case obs.StructuresInFog[row][col]:
+				b.WriteString("   ##")
+			case obs.FogCells[row][col]:
+				b.WriteString("   ..")
+			case obs.Mountains[row][col]:
+				b.WriteString("   ^^")
+			default:
+				owner, structure := ' ', ' '
+				if obs.OwnedCells[row][col] {
+					owner = '+'
+				} else if obs.OpponentCells[row][col] {
+					owner = '-'
+				}
+				if obs.Generals[row][col] {
+					structure = 'G'
+				} else if obs.Cities[row][col] {
+					structure = 'C'
+				}
+				fmt.Fprintf(&b, " %c%c%3d", owner, structure, obs.Armies[row][col])
+			}
+		}
+		rows[row] = b.String()
+	}
+	return rows
+}
+
+// Close implements GameEnvironment
+func (e *GeneralsEnvironment) Close() error {
+	return nil
+}
+
+// GeneralsActionSpace is the five-head Generals action for a rows x cols map. Passing ends the
+// action; the row, column and direction are masked to moves from owned cells onto open land.
+func GeneralsActionSpace(rows, cols int) ActionSpace {
+	pass := 1
+	return ActionSpace{
+		Heads: []ActionHead{
+			{Name: "pass", Size: 2, StopValue: &pass},
+			{Name: "cell_i", Size: rows, Mask: "owned_rows", PenalizeConstant: true},
+			{Name: "cell_j", Size: cols, DependsOn: []string{"cell_i"}, Mask: "owned_cells_in_row", PenalizeConstant: true},
+			{Name: "direction", Size: 4, DependsOn: []string{"cell_i", "cell_j"}, Mask: "open_directions", PenalizeConstant: true},
+			{Name: "split", Size: 2},
+		},
+	}
+}
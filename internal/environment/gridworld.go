@@ -0,0 +1,155 @@
+package environment
+
+import (
+	"math/rand/v2"
+)
+
+const (
+	gridWorldSize         = 8
+	gridWorldWallDensity  = 0.15
+	gridWorldMaxSteps     = 100
+	gridWorldGoalReward   = 10.0
+	gridWorldStepPenalty  = -0.1
+	gridWorldWallAttempts = 100
+)
+
+// gridMoves are up, down, left, right, matching the Generals direction order
+var gridMoves = [4][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}}
+
+// GridWorld is a maze on a square grid: walk from the top-left corner to the goal in the
+// bottom-right corner. Each step costs 0.1 and reaching the goal pays 10.
+type GridWorld struct {
+	seed     int64
+	maxSteps int
+	walls    [gridWorldSize][gridWorldSize]bool
+	x, y     int
+	steps    int
+}
+
+func newGridWorld(seed int64, settings Settings) (GameEnvironment, error) {
+	maxSteps := gridWorldMaxSteps
+	if settings.MaxSteps > 0 {
+		maxSteps = settings.MaxSteps
+	}
+	return &GridWorld{seed: seed, maxSteps: maxSteps}, nil
+}
+
+// Reset implements GameEnvironment; the walls are regenerated from the seed until the goal is reachable
+func (g *GridWorld) Reset() (*Observation, error) {
+	r := rand.New(rand.NewPCG(uint64(g.seed), 1))
+	for attempt := 0; ; attempt++ {
+		for i := range gridWorldSize {
+			for j := range gridWorldSize {
+				g.walls[i][j] = attempt < gridWorldWallAttempts && r.Float64() < gridWorldWallDensity
+			}
+		}
+		g.walls[0][0] = false
+		g.walls[gridWorldSize-1][gridWorldSize-1] = false
+		if g.reachable() {
+			break
+		}
+	}
+	g.x, g.y, g.steps = 0, 0, 0
+	return g.observe(0, false, false), nil
+}
+
+// Step implements GameEnvironment; moving into a wall or off the grid leaves the agent in place
+func (g *GridWorld) Step(action []int) (*Observation, error) {
+	move := gridMoves[clampAction(action, 0, len(gridMoves))]
+	nx, ny := g.x+move[0], g.y+move[1]
+	if g.open(nx, ny) {
+		g.x, g.y = nx, ny
+	}
+	g.steps++
+
+	reward := gridWorldStepPenalty
+	terminated := g.x == gridWorldSize-1 && g.y == gridWorldSize-1
+	if terminated {
+		reward = gridWorldGoalReward
+	}
+	return g.observe(reward, terminated, !terminated && g.steps >= g.maxSteps), nil
+}
+
+// ObservationSchema implements GameEnvironment
+func (g *GridWorld) ObservationSchema() []string {
+	return []string{"agent_x", "agent_y", "goal_dx", "goal_dy", "wall_up", "wall_down", "wall_left", "wall_right", "timestep"}
+}
+
+// ActionSpace implements GameEnvironment
+func (g *GridWorld) ActionSpace() ActionSpace {
+	return ActionSpace{Heads: []ActionHead{{Name: "direction", Size: len(gridMoves)}}}
+}
+
+// Render implements Renderer: A is the agent, G the goal and # a wall
+func (g *GridWorld) Render() []string {
+	rows := make([]string, gridWorldSize)
+	for i := range gridWorldSize {
+		row := make([]byte, gridWorldSize)
+		for j := range gridWorldSize {
+			switch {
+			case i == g.x && j == g.y:
+				row[j] = 'A'
+			case i == gridWorldSize-1 && j == gridWorldSize-1:
+				row[j] = 'G'
+			case g.walls[i][j]:
+				row[j] = '#'
+			default:
+				row[j] = '.'
+			}
+		}
+		rows[i] = string(row)
+	}
+	return rows
+}
+
+// Close implements GameEnvironment
+func (g *GridWorld) Close() error {
+	return nil
+}
+
+func (g *GridWorld) observe(reward float64, terminated, truncated bool) *Observation {
+	features := map[string]float64{
+		"agent_x":  float64(g.x),
+		"agent_y":  float64(g.y),
+		"goal_dx":  float64(gridWorldSize - 1 - g.x),
+		"goal_dy":  float64(gridWorldSize - 1 - g.y),
+		"timestep": float64(g.steps),
+	}
+	for i, name := range []string{"wall_up", "wall_down", "wall_left", "wall_right"} {
+		features[name] = boolFeature(!g.open(g.x+gridMoves[i][0], g.y+gridMoves[i][1]))
+	}
+	return &Observation{Features: features, Reward: reward, Terminated: terminated, Truncated: truncated}
+}
+
+func (g *GridWorld) open(x, y int) bool {
+	return x >= 0 && x < gridWorldSize && y >= 0 && y < gridWorldSize && !g.walls[x][y]
+}
+
+// reachable checks for a path from the start to the goal
+func (g *GridWorld) reachable() bool {
+	var seen [gridWorldSize][gridWorldSize]bool
+	queue := [][2]int{{0, 0}}
+	seen[0][0] = true
+	for len(queue) > 0 {
+		cell := queue[0]
+		queue = queue[1:]
+		if cell[0] == gridWorldSize-1 && cell[1] == gridWorldSize-1 {
+			return true
+		}
+		for _, m := range gridMoves {
+			nx, ny := cell[0]+m[0], cell[1]+m[1]
+			if g.open(nx, ny) && !seen[nx][ny] {
+				seen[nx][ny] = true
+				queue = append(queue, [2]int{nx, ny})
+			}
+		}
+	}
+	return false
+}
+
+func boolFeature(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,185 @@
+package environment
+
+import (
+	"math/rand/v2"
+	"strings"
+)
+
+const (
+	snakeSize     = 10
+	snakeMaxSteps = 500
+	// snakeHunger ends an episode that goes this many steps without eating
+	snakeHunger = 100
+)
+
+// Snake is the classic game on a walled square grid. The snake turns left, keeps
+// straight or turns right; food pays 1 and hitting a wall or itself costs 1 and ends the episode.
+type Snake struct {
+	seed      int64
+	maxSteps  int
+	r         *rand.Rand
+	body      [][2]int // head first
+	direction int      // index into gridMoves
+	food      [2]int
+	steps     int
+	sinceFood int
+}
+
+// snakeTurns maps each direction (up, down, left, right) to its left and right turns
+var snakeTurns = [4][2]int{
+	{2, 3}, // up: left, right
+	{3, 2}, // down
+	{1, 0}, // left
+	{0, 1}, // right
+}
+
+func newSnake(seed int64, settings Settings) (GameEnvironment, error) {
+	maxSteps := snakeMaxSteps
+	if settings.MaxSteps > 0 {
+		maxSteps = settings.MaxSteps
+	}
+	return &Snake{seed: seed, maxSteps: maxSteps}, nil
+}
+
+// Reset implements GameEnvironment; food placement follows the seed
+func (s *Snake) Reset() (*Observation, error) {
+	s.r = rand.New(rand.NewPCG(uint64(s.seed), 3))
+	mid := snakeSize / 2
+	s.body = [][2]int{{mid, mid}, {mid, mid - 1}, {mid, mid - 2}}
+	s.direction = 3
+	s.steps, s.sinceFood = 0, 0
+	s.placeFood()
+	return s.observe(0, false, false), nil
+}
+
+// Step implements GameEnvironment: 0 turns left, 1 keeps straight, 2 turns right
+func (s *Snake) Step(action []int) (*Observation, error) {
+	s.direction = s.turn(clampAction(action, 0, 3))
+	head := s.next(s.direction)
+	s.steps++
+	s.sinceFood++
+
+	if s.deadly(head) {
+		return s.observe(-1, true, false), nil
+	}
+
+	reward := 0.0
+	s.body = append([][2]int{head}, s.body...)
+	if head == s.food {
+		reward = 1
+		s.sinceFood = 0
+		if len(s.body) == snakeSize*snakeSize {
+			return s.observe(reward, true, false), nil
+		}
+		s.placeFood()
+	} else {
+		s.body = s.body[:len(s.body)-1]
+	}
+	truncated := s.steps >= s.maxSteps || s.sinceFood >= snakeHunger
+	return s.observe(reward, false, truncated), nil
+}
+
+// ObservationSchema implements GameEnvironment
+func (s *Snake) ObservationSchema() []string {
+	return []string{"head_x", "head_y", "food_dx", "food_dy", "danger_ahead", "danger_left", "danger_right", "length", "direction", "timestep"}
+}
+
+// ActionSpace implements GameEnvironment
+func (s *Snake) ActionSpace() ActionSpace {
+	return ActionSpace{Heads: []ActionHead{{Name: "turn", Size: 3}}}
+}
+
+// Render implements Renderer: H is the head, o the body and * the food
+func (s *Snake) Render() []string {
+	grid := make([][]byte, snakeSize)
+	for i := range grid {
+		grid[i] = []byte(strings.Repeat(".", snakeSize))
+	}
+	grid[s.food[0]][s.food[1]] = '*'
+	for i, part := range s.body {
+		grid[part[0]][part[1]] = 'o'
+		if i == 0 {
+			grid[part[0]][part[1]] = 'H'
+		}
+	}
+	rows := make([]string, snakeSize)
+	for i, row := range grid {
+		rows[i] = string(row)
+	}
+	return rows
+}
+
+// Close implements GameEnvironment
+func (s *Snake) Close() error {
+	return nil
+}
+
+// turn returns the direction after a relative turn
+func (s *Snake) turn(action int) int {
+	switch action {
+	case 0:
+		return snakeTurns[s.direction][0]
+	case 2:
+		return snakeTurns[s.direction][1]
+	default:
+		return s.direction
+	}
+}
+
+// next returns the cell in front of the head when moving in a direction
+func (s *Snake) next(direction int) [2]int {
+	head := s.body[0]
+	return [2]int{head[0] + gridMoves[direction][0], head[1] + gridMoves[direction][1]}
+}
+
+// deadly reports whether moving the head onto a cell kills the snake. The tail cell
+// is safe because it moves away on the same step.
+func (s *Snake) deadly(cell [2]int) bool {
+	if cell[0] < 0 || cell[0] >= snakeSize || cell[1] < 0 || cell[1] >= snakeSize {
+		return true
+	}
+	for _, part := range s.body[:len(s.body)-1] {
+		if part == cell {
+			return true
+		}
+	}
+	return false
+}
+
+// placeFood puts food on a random free cell
+func (s *Snake) placeFood() {
+	free := make([][2]int, 0, snakeSize*snakeSize)
+	occupied := make(map[[2]int]bool, len(s.body))
+	for _, part := range s.body {
+		occupied[part] = true
+	}
+	for i := range snakeSize {
+		for j := range snakeSize {
+			if !occupied[[2]int{i, j}] {
+				free = append(free, [2]int{i, j})
+			}
+		}
+	}
+	s.food = free[s.r.IntN(len(free))]
+}
+
+func (s *Snake) observe(reward float64, terminated, truncated bool) *Observation {
+	head := s.body[0]
+	return &Observation{
+		Features: map[string]float64{
+			"head_x":       float64(head[0]),
+			"head_y":       float64(head[1]),
+			"food_dx":      float64(s.food[0] - head[0]),
+			"food_dy":      float64(s.food[1] - head[1]),
+			"danger_ahead": boolFeature(s.deadly(s.next(s.direction))),
+			"danger_left":  boolFeature(s.deadly(s.next(snakeTurns[s.direction][0]))),
+			"danger_right": boolFeature(s.deadly(s.next(snakeTurns[s.direction][1]))),
+			"length":       float64(len(s.body)),
+			"direction":    float64(s.direction),
+			"timestep":     float64(s.steps),
+		},
+		Reward:     reward,
+		Terminated: terminated,
+		Truncated:  truncated,
+	}
+}
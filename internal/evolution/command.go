@@ -1,5 +1,7 @@
 package evolution
 
+import "github.com/bxrne/darwin/internal/individual"
+
 // EvolutionCommand represents commands sent to the evolution engine
 type EvolutionCommand struct {
 	Type            CommandType
@@ -8,6 +10,11 @@ type EvolutionCommand struct {
 	CrossoverRate   float64
 	MutationRate    float64
 	ElitismPct      float64
+	SelectionSize   int                    // CmdSetSelectionSize
+	Individuals     []individual.Evolvable // CmdInject
+	// Reply, if set, receives the outcome once the engine has handled the command. It must have
+	// room for the reply, as the engine does not wait.
+	Reply chan<- CommandReply
 }
 
 // CommandType defines the type of evolution command
@@ -16,4 +23,37 @@ type CommandType int
 const (
 	CmdStartGeneration CommandType = iota
 	CmdStop
+	// CmdPause holds later generations until CmdResume; the running generation finishes
+	CmdPause
+	CmdResume
+	// CmdSetMutationRate replaces the MutationRate of every later generation with the command's
+	CmdSetMutationRate
+	// CmdSetCrossoverRate replaces the CrossoverRate of every later generation with the command's
+	CmdSetCrossoverRate
+	// CmdSetSelectionSize resizes the selector's tournament or sample, see selection.Resizable
+	CmdSetSelectionSize
+	// CmdInject scores the command's Individuals and swaps them in for the worst of the population
+	CmdInject
+	// CmdSnapshot replies with copies of the population, best first
+	CmdSnapshot
+	// CmdStatus replies with the engine's Status
+	CmdStatus
 )
+
+// CommandReply is the engine's answer to a command sent with a Reply channel
+type CommandReply struct {
+	Err        error
+	Status     Status
+	Population []individual.Evolvable // CmdSnapshot
+}
+
+// Status describes the engine between generations
+type Status struct {
+	Generation     int     `json:"generation"` // last completed
+	Paused         bool    `json:"paused"`
+	Pending        int     `json:"pending"` // generations held while paused
+	PopulationSize int     `json:"population_size"`
+	MutationRate   float64 `json:"mutation_rate"`
+	CrossoverRate  float64 `json:"crossover_rate"`
+	SelectionSize  int     `json:"selection_size,omitempty"` // 0 unless the selector is resizable
+}
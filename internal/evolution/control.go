@@ -0,0 +1,153 @@
+package evolution
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/bxrne/darwin/internal/fitness"
+	"github.com/bxrne/darwin/internal/individual"
+	"github.com/bxrne/darwin/internal/lineage"
+	"github.com/bxrne/darwin/internal/population"
+	"github.com/bxrne/darwin/internal/selection"
+	"go.uber.org/zap"
+)
+
+// handle applies a command received between generations, replying if asked to
+func (ee *EvolutionEngine) handle(cmd EvolutionCommand) {
+	var reply CommandReply
+	switch cmd.Type {
+	case CmdStartGeneration:
+		ee.pending = append(ee.pending, cmd)
+		ee.lastReceived = cmd
+	case CmdPause:
+		ee.paused = true
+		ee.logger.Info("Evolution paused", zap.Int("generation", ee.currentGen))
+	case CmdResume:
+		ee.paused = false
+		ee.logger.Info("Evolution resumed", zap.Int("generation", ee.currentGen))
+	case CmdSetMutationRate:
+		reply.Err = ee.setRate(&ee.mutationRate, "mutation", cmd.MutationRate)
+	case CmdSetCrossoverRate:
+		reply.Err = ee.setRate(&ee.crossoverRate, "crossover", cmd.CrossoverRate)
+	case CmdSetSelectionSize:
+		reply.Err = ee.setSelectionSize(cmd.SelectionSize)
+	case CmdInject:
+		reply.Err = ee.inject(cmd.Individuals)
+	case CmdSnapshot:
+		reply.Population = ee.snapshot()
+	case CmdStatus:
+	default:
+		reply.Err = fmt.Errorf("unknown command %d", cmd.Type)
+	}
+	reply.Status = ee.status()
+	ee.reply(cmd, reply)
+}
+
+// reply answers a command if it asked for an answer
+func (ee *EvolutionEngine) reply(cmd EvolutionCommand, reply CommandReply) {
+	if cmd.Reply == nil {
+		return
+	}
+	select {
+	case cmd.Reply <- reply:
+	default:
+		ee.logger.Warn("Dropped reply to a command without room for it", zap.Int("command", int(cmd.Type)))
+	}
+}
+
+// withOverrides applies the rates set by CmdSetMutationRate and CmdSetCrossoverRate to a generation
+func (ee *EvolutionEngine) withOverrides(cmd EvolutionCommand) EvolutionCommand {
+	if ee.mutationRate != nil {
+		cmd.MutationRate = *ee.mutationRate
+	}
+	if ee.crossoverRate != nil {
+		cmd.CrossoverRate = *ee.crossoverRate
+	}
+	return cmd
+}
+
+func (ee *EvolutionEngine) setRate(override **float64, name string, rate float64) error {
+	if rate < 0 || rate > 1 {
+		return fmt.Errorf("%s rate must be between 0 and 1", name)
+	}
+	*override = &rate
+	ee.logger.Info("Rate changed", zap.String("rate", name), zap.Float64("value", rate))
+	return nil
+}
+
+func (ee *EvolutionEngine) setSelectionSize(size int) error {
+	resizable, ok := ee.selector.(selection.Resizable)
+	if !ok {
+		return fmt.Errorf("selector %T has no size to change", ee.selector)
+	}
+	if size < 1 || size > ee.population.Count() {
+		return fmt.Errorf("selection size must be between 1 and the population size %d", ee.population.Count())
+	}
+	resizable.SetSize(size)
+	ee.logger.Info("Selection size changed", zap.Int("selection_size", size))
+	return nil
+}
+
+// inject scores individuals and replaces the worst of the population with them. Only a single
+// population scored one individual at a time can take them.
+func (ee *EvolutionEngine) inject(individuals []individual.Evolvable) error {
+	if _, ok := ee.population.(population.Coevolving); ok {
+		return fmt.Errorf("cannot inject into co-evolving species")
+	}
+	if _, ok := ee.fitnessCalculator.(fitness.TeamFitnessCalculator); ok {
+		return fmt.Errorf("cannot score injected individuals outside a team")
+	}
+	if len(individuals) == 0 || len(individuals) > ee.population.Count() {
+		return fmt.Errorf("can inject between 1 and %d individuals", ee.population.Count())
+	}
+	want := fmt.Sprintf("%T", ee.population.Get(0))
+	for _, ind := range individuals {
+		if got := fmt.Sprintf("%T", ind); got != want {
+			return fmt.Errorf("cannot inject %s into a population of %s", got, want)
+		}
+	}
+
+	for _, ind := range individuals {
+		ee.fitnessCalculator.CalculateFitness(ind)
+	}
+	ee.sortPopulation()
+	members := ee.population.GetPopulation()
+	if ee.lineage != nil {
+		replaced := append([]individual.Evolvable(nil), members[len(members)-len(individuals):]...)
+		ee.lineage.Replace(ee.currentGen, lineage.OpInject, replaced, individuals)
+		ee.flushLineage()
+	}
+	copy(members[len(members)-len(individuals):], individuals)
+	ee.sortPopulation()
+	ee.logger.Info("Injected individuals", zap.Int("count", len(individuals)), zap.Float64("best_fitness", ee.population.Get(0).GetFitness()))
+	return nil
+}
+
+// snapshot copies the population, best first
+func (ee *EvolutionEngine) snapshot() []individual.Evolvable {
+	copies := make([]individual.Evolvable, ee.population.Count())
+	for i := range copies {
+		copies[i] = ee.population.Get(i).Clone()
+		copies[i].SetFitness(ee.population.Get(i).GetFitness())
+	}
+	sort.SliceStable(copies, func(i, j int) bool {
+		return copies[i].GetFitness() > copies[j].GetFitness()
+	})
+	return copies
+}
+
+func (ee *EvolutionEngine) status() Status {
+	rates := ee.withOverrides(ee.lastReceived)
+	status := Status{
+		Generation:     ee.currentGen,
+		Paused:         ee.paused,
+		Pending:        len(ee.pending),
+		PopulationSize: ee.population.Count(),
+		MutationRate:   rates.MutationRate,
+		CrossoverRate:  rates.CrossoverRate,
+	}
+	if resizable, ok := ee.selector.(selection.Resizable); ok {
+		status.SelectionSize = resizable.Size()
+	}
+	return status
+}
@@ -0,0 +1,109 @@
+package evolution
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bxrne/darwin/internal/fitness"
+	"github.com/bxrne/darwin/internal/individual"
+	"github.com/bxrne/darwin/internal/metrics"
+	"github.com/bxrne/darwin/internal/population"
+	"github.com/bxrne/darwin/internal/rng"
+	"github.com/bxrne/darwin/internal/selection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// startControlledEngine runs an engine on ten scored bit strings with a tournament selector
+func startControlledEngine(t *testing.T) (chan<- EvolutionCommand, <-chan metrics.GenerationMetrics) {
+	t.Helper()
+	calc := fitness.FitnessCalculatorFactory(fitness.FitnessSetupInformation{GenomeType: individual.BitStringGenome})
+	pop := population.NewPopulationBuilder().BuildPopulation(&population.PopulationInfo{Size: 10, GenomeType: individual.BitStringGenome},
+		func(r *rng.Rand) individual.Evolvable { return individual.NewBinaryIndividual(r, 8) }, nil)
+	pop.CalculateFitnesses(calc)
+	metricsChan := make(chan metrics.GenerationMetrics, 10)
+	cmdChan := make(chan EvolutionCommand)
+	engine := NewEvolutionEngine(pop, selection.NewTournamentSelector(3), metricsChan, cmdChan, calc, individual.CrossoverInformation{CrossoverPoints: 1}, individual.MutateInformation{}, zap.NewNop())
+	engine.Start(context.Background())
+	t.Cleanup(func() {
+		close(cmdChan)
+		engine.Wait()
+	})
+	return cmdChan, metricsChan
+}
+
+// send sends a command and waits for the engine's reply
+func send(t *testing.T, cmdChan chan<- EvolutionCommand, cmd EvolutionCommand) CommandReply {
+	t.Helper()
+	reply := make(chan CommandReply, 1)
+	cmd.Reply = reply
+	cmdChan <- cmd
+	select {
+	case r := <-reply:
+		return r
+	case <-time.After(5 * time.Second):
+		t.Fatal("no reply")
+	}
+	return CommandReply{}
+}
+
+func TestEngineControl_GIVEN_parameter_changes_WHEN_status_requested_THEN_reports_them(t *testing.T) {
+	cmdChan, _ := startControlledEngine(t)
+
+	require.NoError(t, send(t, cmdChan, EvolutionCommand{Type: CmdSetMutationRate, MutationRate: 0.4}).Err)
+	require.NoError(t, send(t, cmdChan, EvolutionCommand{Type: CmdSetCrossoverRate, CrossoverRate: 0.6}).Err)
+	require.NoError(t, send(t, cmdChan, EvolutionCommand{Type: CmdSetSelectionSize, SelectionSize: 5}).Err)
+	send(t, cmdChan, EvolutionCommand{Type: CmdPause})
+	status := send(t, cmdChan, EvolutionCommand{Type: CmdStatus}).Status
+
+	assert.Equal(t, Status{Paused: true, PopulationSize: 10, MutationRate: 0.4, CrossoverRate: 0.6, SelectionSize: 5}, status)
+}
+
+func TestEngineControl_GIVEN_invalid_parameters_WHEN_set_THEN_replies_error(t *testing.T) {
+	cmdChan, _ := startControlledEngine(t)
+
+	assert.ErrorContains(t, send(t, cmdChan, EvolutionCommand{Type: CmdSetMutationRate, MutationRate: 1.5}).Err, "between 0 and 1")
+	assert.ErrorContains(t, send(t, cmdChan, EvolutionCommand{Type: CmdSetSelectionSize, SelectionSize: 11}).Err, "population size 10")
+	assert.ErrorContains(t, send(t, cmdChan, EvolutionCommand{Type: CommandType(99)}).Err, "unknown command")
+}
+
+func TestEngineControl_GIVEN_generation_held_WHEN_status_requested_THEN_counts_pending_with_its_rates(t *testing.T) {
+	cmdChan, metricsChan := startControlledEngine(t)
+	send(t, cmdChan, EvolutionCommand{Type: CmdPause})
+	cmdChan <- EvolutionCommand{Type: CmdStartGeneration, Generation: 1, MutationRate: 0.1, CrossoverRate: 0.9, ElitismPct: 0.1}
+
+	status := send(t, cmdChan, EvolutionCommand{Type: CmdStatus}).Status
+	assert.Equal(t, 1, status.Pending)
+	assert.Equal(t, 0.1, status.MutationRate)
+
+	send(t, cmdChan, EvolutionCommand{Type: CmdResume})
+	<-metricsChan
+	status = send(t, cmdChan, EvolutionCommand{Type: CmdStatus}).Status
+	assert.Equal(t, 1, status.Generation)
+	assert.Zero(t, status.Pending)
+}
+
+func TestEngineControl_GIVEN_fit_individual_WHEN_injected_THEN_replaces_worst_and_leads_snapshot(t *testing.T) {
+	cmdChan, _ := startControlledEngine(t)
+	perfect := &individual.BinaryIndividual{Genome: []byte("11111111")}
+
+	require.NoError(t, send(t, cmdChan, EvolutionCommand{Type: CmdInject, Individuals: []individual.Evolvable{perfect}}).Err)
+	snapshot := send(t, cmdChan, EvolutionCommand{Type: CmdSnapshot}).Population
+
+	require.Len(t, snapshot, 10)
+	assert.Equal(t, 1.0, snapshot[0].GetFitness())
+	assert.NotSame(t, perfect, snapshot[0], "snapshots are copies")
+	for i := 1; i < len(snapshot); i++ {
+		assert.GreaterOrEqual(t, snapshot[i-1].GetFitness(), snapshot[i].GetFitness())
+	}
+}
+
+func TestEngineControl_GIVEN_wrong_genome_WHEN_injected_THEN_replies_error(t *testing.T) {
+	cmdChan, _ := startControlledEngine(t)
+
+	reply := send(t, cmdChan, EvolutionCommand{Type: CmdInject, Individuals: []individual.Evolvable{individual.NewGrammarTree(nil, 4)}})
+
+	assert.ErrorContains(t, reply.Err, "cannot inject *individual.GrammarTree into a population of *individual.BinaryIndividual")
+}
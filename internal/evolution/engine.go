@@ -8,8 +8,11 @@ import (
 	"sync"
 	"time"
 
+	"github.com/bxrne/darwin/internal/adaptive"
+	"github.com/bxrne/darwin/internal/diversity"
 	"github.com/bxrne/darwin/internal/fitness"
 	"github.com/bxrne/darwin/internal/individual"
+	"github.com/bxrne/darwin/internal/lineage"
 	"github.com/bxrne/darwin/internal/metrics"
 	"github.com/bxrne/darwin/internal/population"
 	"github.com/bxrne/darwin/internal/rng"
@@ -28,7 +31,20 @@ type EvolutionEngine struct {
 	fitnessCalculator    fitness.FitnessCalculator
 	crossoverInformation individual.CrossoverInformation
 	mutateInformation    individual.MutateInformation
+	mutate               MutationOperator
+	crossover            CrossoverOperator
 	logger               *zap.Logger
+	err                  error
+	lineage              *lineage.Tracker    // nil unless SetLineage was called
+	adaptation           adaptive.Controller // nil keeps the rates of each command
+	bred                 []bred              // this generation's offspring, while adapting
+	rand                 *rng.Rand           // nil draws from the package generator
+
+	paused        bool
+	pending       []EvolutionCommand // generations received while paused
+	lastReceived  EvolutionCommand   // latest generation, for the rates Status reports
+	mutationRate  *float64           // set by CmdSetMutationRate
+	crossoverRate *float64           // set by CmdSetCrossoverRate
 }
 
 // NewEvolutionEngine creates a new evolution engine
@@ -52,16 +68,60 @@ func NewEvolutionEngine(
 		fitnessCalculator:    fitnessCalculator,
 		crossoverInformation: crossoverInformation,
 		mutateInformation:    mutateInformation,
+		mutate:               NativeMutation,
+		crossover:            NativeCrossover,
 		logger:               logger,
 	}
 }
 
-// Start begins processing evolution commands
+// SetOperators replaces the variation operators; nil keeps the current one.
+// Must be called before Start.
+func (ee *EvolutionEngine) SetOperators(mutate MutationOperator, crossover CrossoverOperator) {
+	if mutate != nil {
+		ee.mutate = mutate
+	}
+	if crossover != nil {
+		ee.crossover = crossover
+	}
+}
+
+// SetLineage records every individual bred from here on in tracker. Must be called before Start.
+func (ee *EvolutionEngine) SetLineage(tracker *lineage.Tracker) {
+	ee.lineage = tracker
+}
+
+// SetRand draws every random choice of the run from r, so runs in one process stay apart and a
+// seed always breeds the same generations. Must be called before Start.
+func (ee *EvolutionEngine) SetRand(r *rng.Rand) {
+	ee.rand = r
+}
+
+// SetAdaptation lets controller set the rates of every generation. Must be called before Start.
+func (ee *EvolutionEngine) SetAdaptation(controller adaptive.Controller) {
+	ee.adaptation = controller
+}
+
+// Start begins processing evolution commands. Generations received while paused run in order
+// once resumed; closing cmdChan ends the run even if some are still held.
 func (ee *EvolutionEngine) Start(ctx context.Context) {
 	go func() {
 		defer close(ee.done)
 
 		for {
+			if !ee.paused && len(ee.pending) > 0 {
+				if ctx.Err() != nil {
+					return
+				}
+				cmd := ee.pending[0]
+				ee.pending = ee.pending[1:]
+				ee.processGeneration(ee.withOverrides(cmd))
+				if ee.err != nil {
+					return
+				}
+				ee.currentGen = cmd.Generation
+				continue
+			}
+
 			select {
 			case <-ctx.Done():
 				return
@@ -69,14 +129,11 @@ func (ee *EvolutionEngine) Start(ctx context.Context) {
 				if !ok {
 					return
 				}
-
-				switch cmd.Type {
-				case CmdStartGeneration:
-					// Log immediately when command is received (before processing starts)
-					ee.processGeneration(cmd)
-				case CmdStop:
+				if cmd.Type == CmdStop {
+					ee.reply(cmd, CommandReply{})
 					return
 				}
+				ee.handle(cmd)
 			}
 		}
 	}()
@@ -92,92 +149,316 @@ func (ee *EvolutionEngine) Wait() {
 	<-ee.done
 }
 
-func (ee *EvolutionEngine) generateOffspring(cmd EvolutionCommand, out chan<- individual.Evolvable) {
-	parent1 := ee.selector.Select(ee.population.GetPopulation())
-	parent2 := ee.selector.Select(ee.population.GetPopulation())
+// Done is closed when the engine is done
+func (ee *EvolutionEngine) Done() <-chan struct{} {
+	return ee.done
+}
+
+// Err returns why the engine stopped early, nil if it was not aborted. Call after Wait.
+func (ee *EvolutionEngine) Err() error {
+	return ee.err
+}
+
+// aborted checks whether the fitness calculator has given up on the run, recording why
+func (ee *EvolutionEngine) aborted(generation int) bool {
+	aborter, ok := ee.fitnessCalculator.(fitness.Aborter)
+	if !ok {
+		return false
+	}
+	if err := aborter.Err(); err != nil {
+		ee.err = fmt.Errorf("fitness evaluation aborted in generation %d: %w", generation, err)
+		ee.logger.Error("Evolution aborted", zap.Int("generation", generation), zap.Error(err))
+		return true
+	}
+	return false
+}
+
+// litter is what breed settles for a pair of offspring before the pairs are bred concurrently:
+// the generator the pair draws from and any mutation types an adaptation picked for them
+type litter struct {
+	rand          *rng.Rand
+	mutationTypes [2]string
+}
+
+func (ee *EvolutionEngine) generateOffspring(cmd EvolutionCommand, l litter) [2]lineage.Birth {
+	parent1 := ee.selector.Select(ee.population.GetPopulation(), l.rand)
+	parent2 := ee.selector.Select(ee.population.GetPopulation(), l.rand)
 	// Perform crossover and mutation
 	// Create copies of parents to avoid mutating the original population
 	parentCopy1 := parent1.Clone()
 	parentCopy2 := parent2.Clone()
 	// Crossover with configured probability; otherwise mutate
-	if rng.Float64() < cmd.CrossoverRate {
-		child1, child2 := parentCopy1.MultiPointCrossover(parentCopy2, &ee.crossoverInformation)
+	if l.rand.Float64() < cmd.CrossoverRate {
+		crossoverInformation := ee.crossoverInformation
+		crossoverInformation.Rand = l.rand
+		child1, child2 := ee.crossover(parentCopy1, parentCopy2, &crossoverInformation)
 		// Mutate children post-crossover
-		child1.Mutate(cmd.MutationRate, &ee.mutateInformation)
-		child2.Mutate(cmd.MutationRate, &ee.mutateInformation)
-		out <- child1
-		out <- child2
-		return
+		parents := []individual.Evolvable{parent1, parent2}
+		return [2]lineage.Birth{
+			ee.mutateChild(child1, lineage.OpCrossover, cmd.MutationRate, parents, l.mutationTypes[0], l.rand),
+			ee.mutateChild(child2, lineage.OpCrossover, cmd.MutationRate, parents, l.mutationTypes[1], l.rand),
+		}
 	}
 
-	parentCopy1.Mutate(cmd.MutationRate, &ee.mutateInformation)
-	parentCopy2.Mutate(cmd.MutationRate, &ee.mutateInformation)
-	out <- parentCopy1
-	out <- parentCopy2
+	return [2]lineage.Birth{
+		ee.mutateChild(parentCopy1, lineage.OpMutation, cmd.MutationRate, []individual.Evolvable{parent1}, l.mutationTypes[0], l.rand),
+		ee.mutateChild(parentCopy2, lineage.OpMutation, cmd.MutationRate, []individual.Evolvable{parent2}, l.mutationTypes[1], l.rand),
+	}
+}
+
+// mutateChild mutates a child bred by operator from parents, drawing from r. A mutation type
+// picked by the adaptation replaces the configured mix, and an adaptation may have the child
+// inherit and vary its parents' rates instead of using rate.
+func (ee *EvolutionEngine) mutateChild(child individual.Evolvable, operator string, rate float64, parents []individual.Evolvable, mutationType string, r *rng.Rand) lineage.Birth {
+	birth := lineage.Birth{Child: child, Operator: operator, Parents: parents, MutationType: mutationType}
+	info := ee.mutateInformation
+	info.Rand = r
+	if mutationType != "" {
+		info.MutationWeights = map[string]float64{mutationType: 1}
+	}
+	if inheritor, ok := ee.adaptation.(adaptive.RateInheritor); ok {
+		if carrier, ok := child.(individual.SelfAdaptive); ok {
+			carried := make([]float64, 0, len(parents))
+			for _, parent := range parents {
+				if p, ok := parent.(individual.SelfAdaptive); ok {
+					carried = append(carried, p.MutationRate())
+				}
+			}
+			rate = inheritor.ChildRate(carried, rate, r)
+			carrier.SetMutationRate(rate)
+		}
+	}
+	ee.mutate(child, rate, &info)
+	return birth
 }
 
 // processGeneration performs one generation of evolution
 func (ee *EvolutionEngine) processGeneration(cmd EvolutionCommand) {
 	start := time.Now()
-	ee.logger.Info("Starting generation", zap.Int("generation", cmd.Generation))
+	cmd = ee.adapt(cmd)
+	ee.bred = ee.bred[:0]
+	ee.logger.Info("Starting generation", zap.Int("generation", cmd.Generation), zap.Float64("mutation_rate", cmd.MutationRate), zap.Float64("crossover_rate", cmd.CrossoverRate))
 
+	evaluations := 0
 	// For generation 1, calculate fitness for the initial population first
 	// (initial population doesn't have fitness calculated yet)
 	if cmd.Generation == 1 {
+		evaluations += ee.scored()
 		ee.logger.Info("Calculating fitness for initial population (generation 1)", zap.Int("population_size", ee.population.Count()))
 		ee.population.CalculateFitnesses(ee.fitnessCalculator)
+		if ee.aborted(cmd.Generation) {
+			return
+		}
 		ee.logger.Info("Initial population fitness calculation complete")
+		ee.recordInitial()
+	}
+	// Co-evolving populations breed each of their species in turn
+	if coevolving, ok := ee.population.(population.Coevolving); ok {
+		for _, species := range coevolving.Breeding() {
+			coevolving.Activate(species)
+			ee.breed(cmd)
+		}
+	} else {
+		ee.breed(cmd)
+	}
+	ee.population.Update(cmd.Generation)
+	evaluations += ee.scored()
+	ee.population.CalculateFitnesses(ee.fitnessCalculator)
+	if ee.aborted(cmd.Generation) {
+		return
+	}
+	ee.flushLineage()
+	if observer, ok := ee.fitnessCalculator.(fitness.GenerationObserver); ok {
+		ee.sortPopulation()
+		observer.EndGeneration(cmd.Generation, ee.population.GetPopulation())
+	}
+	duration := time.Since(start)
+	// Calculate and send metrics
+	genMetrics := ee.calculateMetrics(cmd.Generation, duration)
+	genMetrics.Evaluations = evaluations
+	ee.reportRates(cmd, genMetrics.Metrics)
+
+	// Send metrics before logging completion to ensure proper ordering
+	select {
+	case ee.metricsChan <- genMetrics:
+	default:
+		// Skip if metrics channel is full (non-blocking)
+	}
+
+	// Log completion after metrics are sent to ensure ordering
+	ee.logger.Info("Generation completed", zap.Int("generation", cmd.Generation), zap.Int64("duration_ms", duration.Milliseconds()))
+}
+
+// recordInitial tracks the scored initial population. A co-evolving population has both species
+// tracked, including one that alternate mode does not breed yet, so its children have parents.
+func (ee *EvolutionEngine) recordInitial() {
+	if ee.lineage == nil {
+		return
 	}
+	if _, ok := ee.population.(population.Coevolving); ok {
+		for _, species := range ee.population.GetPopulations() {
+			ee.lineage.Seed(0, lineage.OpInit, *species)
+		}
+	} else {
+		ee.lineage.Seed(0, lineage.OpInit, ee.population.GetPopulation())
+	}
+	ee.flushLineage()
+}
+
+// flushLineage writes the records of the individuals scored since the last flush
+func (ee *EvolutionEngine) flushLineage() {
+	if ee.lineage == nil {
+		return
+	}
+	if err := ee.lineage.Flush(); err != nil {
+		ee.logger.Warn("Failed to record lineage", zap.Error(err))
+	}
+}
+
+// bred is an offspring of this generation, kept to tell the adaptation whether it improved
+type bred struct {
+	lineage.Birth
+	parentFitness float64
+}
+
+func bestFitness(individuals []individual.Evolvable) float64 {
+	best := math.Inf(-1)
+	for _, ind := range individuals {
+		best = math.Max(best, ind.GetFitness())
+	}
+	return best
+}
+
+// adapt sets the rates of a generation from the adaptation, if any
+func (ee *EvolutionEngine) adapt(cmd EvolutionCommand) EvolutionCommand {
+	if ee.adaptation == nil {
+		return cmd
+	}
+	rates := ee.adaptation.Rates(adaptive.Rates{Mutation: cmd.MutationRate, Crossover: cmd.CrossoverRate})
+	cmd.MutationRate, cmd.CrossoverRate = rates.Mutation, rates.Crossover
+	return cmd
+}
+
+// reportRates adds the rates a generation was bred with to its metrics, then tells the
+// adaptation how the generation went and adds whatever it reports
+func (ee *EvolutionEngine) reportRates(cmd EvolutionCommand, values map[string]float64) {
+	if values == nil {
+		return
+	}
+	values["mutation_rate"] = cmd.MutationRate
+	values["crossover_rate"] = cmd.CrossoverRate
+	if ee.adaptation == nil {
+		return
+	}
+	if _, ok := ee.adaptation.(adaptive.RateInheritor); ok {
+		values["mutation_rate"] = ee.carriedRate(cmd.MutationRate)
+	}
+
+	children := make([]adaptive.Child, len(ee.bred))
+	for i, b := range ee.bred {
+		children[i] = adaptive.Child{Operator: b.Operator, MutationType: b.MutationType, Improved: b.Child.GetFitness() > b.parentFitness}
+	}
+	ee.adaptation.Observe(adaptive.Outcome{
+		Generation: cmd.Generation,
+		Rates:      adaptive.Rates{Mutation: cmd.MutationRate, Crossover: cmd.CrossoverRate},
+		Children:   children,
+		Metrics:    values,
+	})
+	fields := []zap.Field{zap.Int("generation", cmd.Generation), zap.Float64("mutation_rate", values["mutation_rate"]), zap.Float64("crossover_rate", cmd.CrossoverRate)}
+	if reporter, ok := ee.adaptation.(adaptive.Reporter); ok {
+		for key, value := range reporter.Report() {
+			values[key] = value
+			fields = append(fields, zap.Float64(key, value))
+		}
+	}
+	ee.logger.Info("Adapted rates", fields...)
+}
+
+// carriedRate is the mean mutation rate the population carries, counting individuals without
+// one as carrying base
+func (ee *EvolutionEngine) carriedRate(base float64) float64 {
+	sum, count := 0.0, 0
+	for _, ind := range ee.population.GetPopulation() {
+		rate := base
+		if carrier, ok := ind.(individual.SelfAdaptive); ok && carrier.MutationRate() > 0 {
+			rate = carrier.MutationRate()
+		}
+		sum += rate
+		count++
+	}
+	if count == 0 {
+		return base
+	}
+	return sum / float64(count)
+}
+
+// scored returns how many individuals CalculateFitnesses scores: the population, or every bred
+// species of a co-evolving one
+func (ee *EvolutionEngine) scored() int {
+	coevolving, ok := ee.population.(population.Coevolving)
+	if !ok {
+		return ee.population.Count()
+	}
+	species := ee.population.GetPopulations()
+	count := 0
+	for _, s := range coevolving.Breeding() {
+		count += len(*species[s])
+	}
+	return count
+}
+
+// breed replaces the population with its elites and their offspring
+func (ee *EvolutionEngine) breed(cmd EvolutionCommand) {
 	// Sort population by fitness (descending)
 	ee.sortPopulation()
 	// Create new population
 	newPop := make([]individual.Evolvable, 0, ee.population.Count())
 	// Elitism: keep best individuals
+	births := make([]lineage.Birth, 0, ee.population.Count())
 	elitismCount := max(int(float64(ee.population.Count())*cmd.ElitismPct), 1)
 	for i := 0; i < elitismCount && i < ee.population.Count(); i++ {
-		newPop = append(newPop, ee.population.Get(i))
+		elite := ee.population.Get(i)
+		newPop = append(newPop, elite)
+		births = append(births, lineage.Birth{Child: elite, Operator: lineage.OpElitism, Parents: []individual.Evolvable{elite}})
 	}
 	offspringNeeded := ee.population.Count() - len(newPop)
-	offspringChanCount := (offspringNeeded + 1) / 2
-	offspringChan := make(chan individual.Evolvable)
+	// Every pair gets its own generator, split from the run's in order, so the pairs can be bred
+	// concurrently and still come out the same for a seed
+	litters := make([]litter, (offspringNeeded+1)/2)
+	selector, selecting := ee.adaptation.(adaptive.MutationSelector)
+	for i := range litters {
+		litters[i].rand = ee.rand.Split()
+		if selecting {
+			litters[i].mutationTypes = [2]string{selector.MutationType(ee.rand), selector.MutationType(ee.rand)}
+		}
+	}
+	offspring := make([][2]lineage.Birth, len(litters))
 	var wg sync.WaitGroup
 	// Generate offspring
-	for i := 0; i < offspringChanCount; i++ {
+	for i := range litters {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			ee.generateOffspring(cmd, offspringChan)
+			offspring[i] = ee.generateOffspring(cmd, litters[i])
 		}()
 	}
-	go func() {
-		wg.Wait()
-		close(offspringChan)
-	}()
-	count := 0
-	for ind := range offspringChan {
-		if count < offspringNeeded {
-			newPop = append(newPop, ind)
-			count++
-		} else {
-			break
+	wg.Wait()
+	for _, pair := range offspring {
+		for _, birth := range pair {
+			if len(newPop) == ee.population.Count() {
+				break
+			}
+			newPop = append(newPop, birth.Child)
+			births = append(births, birth)
+			if ee.adaptation != nil {
+				ee.bred = append(ee.bred, bred{Birth: birth, parentFitness: bestFitness(birth.Parents)})
+			}
 		}
 	}
-	ee.population.SetPopulation(newPop)
-	ee.population.Update(cmd.Generation)
-	ee.population.CalculateFitnesses(ee.fitnessCalculator)
-	duration := time.Since(start)
-	// Calculate and send metrics
-	genMetrics := ee.calculateMetrics(cmd.Generation, duration)
-
-	// Send metrics before logging completion to ensure proper ordering
-	select {
-	case ee.metricsChan <- genMetrics:
-	default:
-		// Skip if metrics channel is full (non-blocking)
+	if ee.lineage != nil {
+		ee.lineage.Breed(cmd.Generation, ee.population.GetPopulation(), births)
 	}
-
-	// Log completion after metrics are sent to ensure ordering
-	ee.logger.Info("Generation completed", zap.Int("generation", cmd.Generation), zap.Int64("duration_ms", duration.Milliseconds()))
+	ee.population.SetPopulation(newPop)
 }
 
 // sortPopulation sorts the population by fitness (descending)
@@ -230,6 +511,9 @@ func (ee *EvolutionEngine) calculateMetrics(generation int, duration time.Durati
 		overallMetrics[minKey] = minMetricValues[key]
 		overallMetrics[maxKey] = maxMetricValues[key]
 	}
+	for key, value := range diversity.Measure(ee.population.GetPopulation()) {
+		overallMetrics[key] = value
+	}
 
 	ee.sortPopulation()
 	bestDescription := ee.population.Get(0).Describe()
@@ -2,15 +2,22 @@ package evolution
 
 import (
 	"context"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/bxrne/darwin/internal/adaptive"
 	"github.com/bxrne/darwin/internal/fitness"
 	"github.com/bxrne/darwin/internal/individual"
+	"github.com/bxrne/darwin/internal/lineage"
 	"github.com/bxrne/darwin/internal/metrics"
 	"github.com/bxrne/darwin/internal/population"
+	"github.com/bxrne/darwin/internal/rng"
+	"github.com/bxrne/darwin/internal/selection"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/zap"
 )
@@ -20,11 +27,29 @@ type MockSelector struct {
 	mock.Mock
 }
 
-func (m *MockSelector) Select(population []individual.Evolvable) individual.Evolvable {
+func (m *MockSelector) Select(population []individual.Evolvable, _ *rng.Rand) individual.Evolvable {
 	args := m.Called(population)
 	return args.Get(0).(individual.Evolvable)
 }
 
+// recordingController halves the mutation rate and records what it observes
+type recordingController struct {
+	outcomes []adaptive.Outcome
+}
+
+func (r *recordingController) Rates(base adaptive.Rates) adaptive.Rates {
+	base.Mutation /= 2
+	return base
+}
+
+func (r *recordingController) Observe(outcome adaptive.Outcome) {
+	r.outcomes = append(r.outcomes, outcome)
+}
+
+func (r *recordingController) Report() map[string]float64 {
+	return map[string]float64{"success_rate": 0.5}
+}
+
 type EvolutionEngineTestSuite struct {
 	suite.Suite
 	population        population.Population
@@ -40,7 +65,7 @@ func (suite *EvolutionEngineTestSuite) SetupTest() {
 	fitnessCalc := fitness.FitnessCalculatorFactory(fitness.FitnessSetupInformation{GenomeType: individual.BitStringGenome})
 	popInfo := &population.PopulationInfo{Size: 10, GenomeType: individual.BitStringGenome}
 	suite.population = popBuilder.BuildPopulation(popInfo,
-		func() individual.Evolvable { return individual.NewBinaryIndividual(5) })
+		func(r *rng.Rand) individual.Evolvable { return individual.NewBinaryIndividual(r, 5) }, nil)
 	suite.selector = &MockSelector{}
 	suite.metricsChan = make(chan metrics.GenerationMetrics, 10)
 	suite.cmdChan = make(chan EvolutionCommand, 10)
@@ -71,8 +96,8 @@ func (suite *EvolutionEngineTestSuite) TestEvolutionEngine_Start_GIVEN_running_e
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	parent1 := individual.NewBinaryIndividual(5)
-	parent2 := individual.NewBinaryIndividual(5)
+	parent1 := individual.NewBinaryIndividual(nil, 5)
+	parent2 := individual.NewBinaryIndividual(nil, 5)
 	// Set up multiple expectations since processGeneration may be called
 	suite.selector.On("Select", suite.population.GetPopulation()).Return(parent1).Maybe()
 	suite.selector.On("Select", suite.population.GetPopulation()).Return(parent2).Maybe()
@@ -135,9 +160,9 @@ func (suite *EvolutionEngineTestSuite) TestEvolutionEngine_GetPopulation_GIVEN_p
 func (suite *EvolutionEngineTestSuite) TestPopulationBuilder_BuildPopulation_GIVEN_size_genome_size_WHEN_build_THEN_population_created() {
 	builder := population.NewPopulationBuilder()
 	popInfo := &population.PopulationInfo{Size: 3, GenomeType: individual.BitStringGenome}
-	population := builder.BuildPopulation(popInfo, func() individual.Evolvable {
-		return individual.NewBinaryIndividual(5)
-	})
+	population := builder.BuildPopulation(popInfo, func(r *rng.Rand) individual.Evolvable {
+		return individual.NewBinaryIndividual(r, 5)
+	}, nil)
 
 	assert.Equal(suite.T(), population.Count(), 3)
 	for index := range population.Count() {
@@ -146,3 +171,228 @@ func (suite *EvolutionEngineTestSuite) TestPopulationBuilder_BuildPopulation_GIV
 		assert.Len(suite.T(), binInd.Genome, 5)
 	}
 }
+
+func (suite *EvolutionEngineTestSuite) TestEvolutionEngine_processGeneration_GIVEN_generations_WHEN_processed_THEN_reports_individuals_scored() {
+	suite.selector.On("Select", mock.Anything).Return(individual.NewBinaryIndividual(nil, 5))
+	cmd := EvolutionCommand{Type: CmdStartGeneration, Generation: 1, CrossoverPoints: 1, CrossoverRate: 0.9, MutationRate: 0.1, ElitismPct: 0.1}
+
+	suite.engine.processGeneration(cmd)
+	first := <-suite.metricsChan
+	cmd.Generation = 2
+	suite.engine.processGeneration(cmd)
+	second := <-suite.metricsChan
+
+	assert.Equal(suite.T(), 20, first.Evaluations, "the initial population is scored too")
+	assert.Equal(suite.T(), 10, second.Evaluations)
+}
+
+func (suite *EvolutionEngineTestSuite) TestEvolutionEngine_processGeneration_GIVEN_lineage_WHEN_processed_THEN_records_every_individual() {
+	suite.selector.On("Select", mock.Anything).Return(suite.population.Get(0))
+	var out strings.Builder
+	suite.engine.SetLineage(lineage.NewTracker(&out))
+	cmd := EvolutionCommand{Type: CmdStartGeneration, Generation: 1, CrossoverPoints: 1, CrossoverRate: 0.5, MutationRate: 0.1, ElitismPct: 0.1}
+
+	suite.engine.processGeneration(cmd)
+	<-suite.metricsChan
+
+	records, err := lineage.Read(strings.NewReader(out.String()))
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), records, 20, "the initial population and its offspring")
+	for _, r := range records[10:] {
+		assert.Equal(suite.T(), 1, r.Generation)
+		assert.NotEmpty(suite.T(), r.Parents, r.Operator)
+		assert.NotNil(suite.T(), r.Fitness)
+	}
+	assert.Equal(suite.T(), lineage.OpElitism, records[10].Operator)
+}
+
+func TestEvolutionEngine_processGeneration_GIVEN_coevolution_lineage_WHEN_species_alternate_THEN_both_species_traced(t *testing.T) {
+	pop, err := population.NewCoevolutionPopulation([2]int{4, 5}, [2]func(*rng.Rand) individual.Evolvable{
+		func(r *rng.Rand) individual.Evolvable { return individual.NewBinaryIndividual(r, 8) },
+		func(r *rng.Rand) individual.Evolvable { return individual.NewBinaryIndividual(r, 8) },
+	}, population.CoevolutionConfig{Mode: "alternate", SwitchStep: 1, Collaborators: "best", Credit: "max"}, rng.New(1))
+	require.NoError(t, err)
+	metricsChan := make(chan metrics.GenerationMetrics, 2)
+	calc := fitness.FitnessCalculatorFactory(fitness.FitnessSetupInformation{GenomeType: individual.BitStringGenome})
+	engine := NewEvolutionEngine(pop, selection.NewTournamentSelector(2), metricsChan, nil, calc, individual.CrossoverInformation{CrossoverPoints: 1}, individual.MutateInformation{}, zap.NewNop())
+	engine.SetRand(rng.New(1))
+	var out strings.Builder
+	engine.SetLineage(lineage.NewTracker(&out))
+
+	// Generation 1 breeds the first species and generation 2 the second
+	for generation := 1; generation <= 2; generation++ {
+		engine.processGeneration(EvolutionCommand{Type: CmdStartGeneration, Generation: generation, CrossoverRate: 0.5, MutationRate: 0.1, ElitismPct: 0.25})
+		<-metricsChan
+	}
+
+	records, err := lineage.Read(strings.NewReader(out.String()))
+	require.NoError(t, err)
+	initial := map[uint64]bool{}
+	for _, r := range records {
+		if r.Generation == 0 {
+			initial[r.ID] = true
+			continue
+		}
+		assert.NotEmpty(t, r.Parents, "generation %d %s child %d", r.Generation, r.Operator, r.ID)
+	}
+	assert.Len(t, initial, 9, "every individual of both species")
+	assert.Len(t, records, 9+4+5)
+}
+
+func (suite *EvolutionEngineTestSuite) TestEvolutionEngine_processGeneration_GIVEN_bitstrings_WHEN_processed_THEN_reports_diversity() {
+	suite.selector.On("Select", mock.Anything).Return(individual.NewBinaryIndividual(nil, 5))
+	cmd := EvolutionCommand{Type: CmdStartGeneration, Generation: 1, CrossoverPoints: 1, CrossoverRate: 0.9, MutationRate: 0.1, ElitismPct: 0.1}
+
+	suite.engine.processGeneration(cmd)
+	generation := <-suite.metricsChan
+
+	assert.Contains(suite.T(), generation.Metrics, "diversity_hamming")
+	assert.Contains(suite.T(), generation.Metrics, "diversity_fitness_entropy")
+}
+
+func (suite *EvolutionEngineTestSuite) TestEvolutionEngine_Start_GIVEN_paused_WHEN_generation_sent_THEN_held_until_resumed() {
+	suite.selector.On("Select", mock.Anything).Return(individual.NewBinaryIndividual(nil, 5))
+	suite.engine.Start(context.Background())
+
+	suite.cmdChan <- EvolutionCommand{Type: CmdPause}
+	suite.cmdChan <- EvolutionCommand{Type: CmdStartGeneration, Generation: 1, CrossoverRate: 0.9, MutationRate: 0.1, ElitismPct: 0.1}
+	select {
+	case <-suite.metricsChan:
+		suite.Fail("generation ran while paused")
+	case <-time.After(50 * time.Millisecond):
+	}
+	suite.cmdChan <- EvolutionCommand{Type: CmdResume}
+
+	select {
+	case m := <-suite.metricsChan:
+		assert.Equal(suite.T(), 1, m.Generation)
+	case <-time.After(time.Second):
+		suite.Fail("generation did not run after resuming")
+	}
+	suite.cmdChan <- EvolutionCommand{Type: CmdStop}
+	suite.engine.Wait()
+}
+
+func (suite *EvolutionEngineTestSuite) TestEvolutionEngine_Start_GIVEN_mutation_rate_set_WHEN_generation_runs_THEN_uses_new_rate() {
+	suite.selector.On("Select", mock.Anything).Return(individual.NewBinaryIndividual(nil, 5))
+	var mu sync.Mutex
+	var rates []float64
+	suite.engine.SetOperators(func(ind individual.Evolvable, rate float64, info *individual.MutateInformation) {
+		mu.Lock()
+		defer mu.Unlock()
+		rates = append(rates, rate)
+	}, nil)
+	suite.engine.Start(context.Background())
+
+	suite.cmdChan <- EvolutionCommand{Type: CmdSetMutationRate, MutationRate: 0.6}
+	suite.cmdChan <- EvolutionCommand{Type: CmdStartGeneration, Generation: 1, CrossoverRate: 0.9, MutationRate: 0.1, ElitismPct: 0.1}
+	<-suite.metricsChan
+	suite.cmdChan <- EvolutionCommand{Type: CmdStop}
+	suite.engine.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.NotEmpty(suite.T(), rates)
+	for _, rate := range rates {
+		assert.Equal(suite.T(), 0.6, rate)
+	}
+}
+
+func (suite *EvolutionEngineTestSuite) TestEvolutionEngine_processGeneration_GIVEN_no_adaptation_WHEN_processed_THEN_reports_command_rates() {
+	suite.selector.On("Select", mock.Anything).Return(individual.NewBinaryIndividual(nil, 5))
+	cmd := EvolutionCommand{Type: CmdStartGeneration, Generation: 1, CrossoverPoints: 1, CrossoverRate: 0.9, MutationRate: 0.1, ElitismPct: 0.1}
+
+	suite.engine.processGeneration(cmd)
+	generation := <-suite.metricsChan
+
+	assert.Equal(suite.T(), 0.1, generation.Metrics["mutation_rate"])
+	assert.Equal(suite.T(), 0.9, generation.Metrics["crossover_rate"])
+}
+
+func (suite *EvolutionEngineTestSuite) TestEvolutionEngine_processGeneration_GIVEN_adaptation_WHEN_processed_THEN_breeds_with_its_rates_and_reports_outcome() {
+	suite.selector.On("Select", mock.Anything).Return(suite.population.Get(0))
+	var mu sync.Mutex
+	var rates []float64
+	suite.engine.SetOperators(func(ind individual.Evolvable, rate float64, info *individual.MutateInformation) {
+		mu.Lock()
+		defer mu.Unlock()
+		rates = append(rates, rate)
+	}, nil)
+	controller := &recordingController{}
+	suite.engine.SetAdaptation(controller)
+	cmd := EvolutionCommand{Type: CmdStartGeneration, Generation: 1, CrossoverPoints: 1, CrossoverRate: 0.9, MutationRate: 0.4, ElitismPct: 0.1}
+
+	suite.engine.processGeneration(cmd)
+	generation := <-suite.metricsChan
+
+	require.NotEmpty(suite.T(), rates)
+	for _, rate := range rates {
+		assert.Equal(suite.T(), 0.2, rate)
+	}
+	assert.Equal(suite.T(), 0.2, generation.Metrics["mutation_rate"])
+	assert.Equal(suite.T(), 0.5, generation.Metrics["success_rate"])
+	require.Len(suite.T(), controller.outcomes, 1)
+	assert.Len(suite.T(), controller.outcomes[0].Children, 9, "offspring without the elite")
+	assert.Contains(suite.T(), controller.outcomes[0].Metrics, "diversity_hamming")
+}
+
+func (suite *EvolutionEngineTestSuite) TestEvolutionEngine_processGeneration_GIVEN_self_adaptive_WHEN_processed_THEN_children_carry_rates() {
+	suite.selector.On("Select", mock.Anything).Return(suite.population.Get(0))
+	controller, err := adaptive.NewSelfAdaptive(0.2, 0.01, 0.5)
+	require.NoError(suite.T(), err)
+	suite.engine.SetAdaptation(controller)
+	cmd := EvolutionCommand{Type: CmdStartGeneration, Generation: 1, CrossoverPoints: 1, CrossoverRate: 0.5, MutationRate: 0.1, ElitismPct: 0.1}
+
+	suite.engine.processGeneration(cmd)
+	generation := <-suite.metricsChan
+
+	carried := 0
+	for _, ind := range suite.engine.population.GetPopulation() {
+		if rate := ind.(individual.SelfAdaptive).MutationRate(); rate > 0 {
+			assert.GreaterOrEqual(suite.T(), rate, 0.01)
+			assert.LessOrEqual(suite.T(), rate, 0.5)
+			carried++
+		}
+	}
+	assert.Equal(suite.T(), 9, carried, "every offspring carries a rate")
+	assert.Greater(suite.T(), generation.Metrics["mutation_rate"], 0.0)
+}
+
+// runSeeded runs five generations of bit strings drawn from a generator seeded with seed,
+// returning the final population
+func runSeeded(seed int64) []string {
+	r := rng.New(seed)
+	calc := fitness.FitnessCalculatorFactory(fitness.FitnessSetupInformation{GenomeType: individual.BitStringGenome})
+	pop := population.NewPopulationBuilder().BuildPopulation(&population.PopulationInfo{Size: 30, GenomeType: individual.BitStringGenome},
+		func(r *rng.Rand) individual.Evolvable { return individual.NewBinaryIndividual(r, 16) }, r)
+	metricsChan := make(chan metrics.GenerationMetrics, 5)
+	cmdChan := make(chan EvolutionCommand, 5)
+	engine := NewEvolutionEngine(pop, selection.NewTournamentSelector(3), metricsChan, cmdChan, calc, individual.CrossoverInformation{CrossoverPoints: 2}, individual.MutateInformation{}, zap.NewNop())
+	engine.SetRand(r)
+	engine.Start(context.Background())
+	for gen := 1; gen <= 5; gen++ {
+		cmdChan <- EvolutionCommand{Type: CmdStartGeneration, Generation: gen, CrossoverRate: 0.7, MutationRate: 0.1, ElitismPct: 0.1}
+	}
+	close(cmdChan)
+	engine.Wait()
+
+	descriptions := make([]string, 0, pop.Count())
+	for _, ind := range engine.GetPopulation() {
+		descriptions = append(descriptions, ind.Describe())
+	}
+	return descriptions
+}
+
+func TestEvolutionEngine_GIVEN_same_seed_WHEN_run_alongside_another_THEN_same_population(t *testing.T) {
+	expected := runSeeded(5)
+
+	results := make(chan []string, 2)
+	for _, seed := range []int64{5, 6} {
+		go func() { results <- runSeeded(seed) }()
+	}
+	rng.Seed(99)
+	first, second := <-results, <-results
+
+	assert.Contains(t, [][]string{first, second}, expected)
+	assert.NotEqual(t, first, second)
+}
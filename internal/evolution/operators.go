@@ -0,0 +1,19 @@
+package evolution
+
+import "github.com/bxrne/darwin/internal/individual"
+
+// MutationOperator mutates an individual in place
+type MutationOperator func(ind individual.Evolvable, rate float64, info *individual.MutateInformation)
+
+// CrossoverOperator recombines two parents into two children
+type CrossoverOperator func(parent1, parent2 individual.Evolvable, info *individual.CrossoverInformation) (individual.Evolvable, individual.Evolvable)
+
+// NativeMutation delegates to the individual's own Mutate
+func NativeMutation(ind individual.Evolvable, rate float64, info *individual.MutateInformation) {
+	ind.Mutate(rate, info)
+}
+
+// NativeCrossover delegates to the first parent's own MultiPointCrossover
+func NativeCrossover(parent1, parent2 individual.Evolvable, info *individual.CrossoverInformation) (individual.Evolvable, individual.Evolvable) {
+	return parent1.MultiPointCrossover(parent2, info)
+}
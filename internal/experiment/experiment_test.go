@@ -0,0 +1,145 @@
+package experiment_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/bxrne/darwin/internal/experiment"
+	"github.com/stretchr/testify/assert"
+)
+
+func loadBase(t *testing.T) map[string]any {
+	base := make(map[string]any)
+	_, err := toml.DecodeFile("../../config/default.toml", &base)
+	assert.NoError(t, err)
+	return base
+}
+
+func TestSweepSpec_Points_GIVEN_grid_WHEN_expand_THEN_cartesian_product(t *testing.T) {
+	spec, err := experiment.NewSweepSpec(loadBase(t), map[string][]any{
+		"evolution.mutation_rate":  {0.1, 0.2},
+		"evolution.selection_size": {int64(3), int64(5), int64(7)},
+	}, 1)
+	assert.NoError(t, err)
+
+	points := spec.Points()
+
+	assert.Len(t, points, 6)
+	ids := make(map[string]bool)
+	for _, p := range points {
+		ids[p.ID] = true
+		assert.Len(t, p.Params, 2)
+	}
+	assert.Len(t, ids, 6, "point IDs should be unique")
+}
+
+func TestSweepSpec_Runs_GIVEN_seeds_WHEN_resolve_THEN_params_and_seed_applied(t *testing.T) {
+	spec, err := experiment.NewSweepSpec(loadBase(t), map[string][]any{
+		"evolution.mutation_rate": {0.25},
+	}, 3)
+	assert.NoError(t, err)
+
+	runs, err := spec.Runs()
+
+	assert.NoError(t, err)
+	assert.Len(t, runs, 3)
+	for i, run := range runs {
+		assert.Equal(t, 0.25, run.Config.Evolution.MutationRate)
+		assert.Equal(t, int64(i+1), run.Config.Evolution.Seed)
+		assert.False(t, run.Config.Metrics.CSVEnabled)
+		assert.Contains(t, run.Dir, run.Point.ID)
+	}
+}
+
+func TestSweepSpec_Runs_GIVEN_servers_and_lineage_in_base_WHEN_resolve_THEN_disabled(t *testing.T) {
+	base := loadBase(t)
+	base["dashboard"] = map[string]any{"enabled": true}
+	base["prometheus"] = map[string]any{"enabled": true}
+	base["control"] = map[string]any{"enabled": true}
+	base["lineage"] = map[string]any{"enabled": true}
+	spec, err := experiment.NewSweepSpec(base, map[string][]any{
+		"evolution.mutation_rate": {0.1, 0.2},
+	}, 1)
+	assert.NoError(t, err)
+
+	runs, err := spec.Runs()
+
+	assert.NoError(t, err)
+	for _, run := range runs {
+		assert.False(t, run.Config.Dashboard.Enabled)
+		assert.False(t, run.Config.Prometheus.Enabled)
+		assert.False(t, run.Config.Control.Enabled)
+		assert.False(t, run.Config.Lineage.Enabled)
+	}
+}
+
+func TestLoadSweepSpec_GIVEN_relative_base_WHEN_load_THEN_resolves_against_spec_dir(t *testing.T) {
+	spec, err := experiment.LoadSweepSpec("../../config/sweep.toml")
+
+	assert.NoError(t, err)
+	runs, err := spec.Runs()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, runs)
+	assert.True(t, runs[0].Config.BitString.Enabled)
+}
+
+func TestSummarise_GIVEN_results_WHEN_summarise_THEN_median_iqr_best(t *testing.T) {
+	p := experiment.Point{ID: "p000"}
+	q := experiment.Point{ID: "p001"}
+	results := []experiment.RunResult{
+		{Point: p, FinalBest: 1},
+		{Point: p, FinalBest: 2},
+		{Point: p, FinalBest: 3},
+		{Point: p, FinalBest: 4},
+		{Point: q, FinalBest: 9},
+		{Point: q, Err: errors.New("boom")},
+	}
+
+	summaries := experiment.Summarise(results)
+
+	assert.Len(t, summaries, 2)
+	assert.Equal(t, "p000", summaries[0].Point.ID)
+	assert.Equal(t, 2.5, summaries[0].Median)
+	assert.Equal(t, 1.75, summaries[0].Q1)
+	assert.Equal(t, 3.25, summaries[0].Q3)
+	assert.Equal(t, 1.5, summaries[0].IQR)
+	assert.Equal(t, 4.0, summaries[0].Best)
+	assert.Equal(t, 1, summaries[1].Runs)
+	assert.Equal(t, 1, summaries[1].Failures)
+}
+
+func TestWriteSummary_GIVEN_summaries_WHEN_write_THEN_files_created(t *testing.T) {
+	dir := t.TempDir()
+	summaries := experiment.Summarise([]experiment.RunResult{
+		{Point: experiment.Point{ID: "p000", Params: map[string]any{"evolution.mutation_rate": 0.1}}, FinalBest: 0.5},
+	})
+
+	assert.NoError(t, experiment.WriteSummaryCSV(filepath.Join(dir, "summary.csv"), summaries))
+	assert.NoError(t, experiment.WriteSummaryMarkdown(filepath.Join(dir, "summary.md"), summaries))
+
+	data, err := os.ReadFile(filepath.Join(dir, "summary.md"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "evolution.mutation_rate=0.1")
+}
+
+func TestLoadMetaSpec_GIVEN_example_WHEN_inner_config_THEN_values_and_generations_applied(t *testing.T) {
+	spec, err := experiment.LoadMetaSpec("../../config/meta.toml")
+	assert.NoError(t, err)
+	assert.Len(t, spec.Bounds(), 4)
+
+	config, err := spec.InnerConfig(map[string]float64{
+		"evolution.mutation_rate":      0.2,
+		"evolution.crossover_rate":     0.6,
+		"evolution.selection_size":     4,
+		"evolution.elitism_percentage": 0.1,
+	}, 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0.2, config.Evolution.MutationRate)
+	assert.Equal(t, 4, config.Evolution.SelectionSize)
+	assert.Equal(t, spec.InnerGenerations, config.Evolution.Generations)
+	assert.Equal(t, int64(3), config.Evolution.Seed)
+}
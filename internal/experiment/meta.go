@@ -0,0 +1,157 @@
+package experiment
+
+import (
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+	"github.com/bxrne/darwin/internal/cfg"
+	"github.com/bxrne/darwin/internal/individual"
+)
+
+// MetaParameter is one hyperparameter evolved by the outer run
+type MetaParameter struct {
+	Key     string  `toml:"key"`
+	Min     float64 `toml:"min"`
+	Max     float64 `toml:"max"`
+	Integer bool    `toml:"integer"`
+}
+
+// OuterConfig holds the evolution settings of the outer (meta) run
+type OuterConfig struct {
+	PopulationSize      int     `toml:"population_size"`
+	Generations         int     `toml:"generations"`
+	CrossoverPointCount int     `toml:"crossover_point_count"`
+	CrossoverRate       float64 `toml:"crossover_rate"`
+	MutationRate        float64 `toml:"mutation_rate"`
+	ElitismPercentage   float64 `toml:"elitism_percentage"`
+	SelectionSize       int     `toml:"selection_size"`
+	Seed                int64   `toml:"seed"`
+}
+
+// MetaSpec describes a meta-optimisation of a base config's hyperparameters
+type MetaSpec struct {
+	Base             string          `toml:"base"`
+	OutputDir        string          `toml:"output_dir"`
+	InnerGenerations int             `toml:"inner_generations"`
+	InnerSeeds       int             `toml:"inner_seeds"`
+	Overrides        map[string]any  `toml:"overrides"`
+	Parameters       []MetaParameter `toml:"parameters"`
+	Outer            OuterConfig     `toml:"outer"`
+
+	baseConfig map[string]any
+}
+
+// DefaultMetaParameters are the hyperparameters tuned when a spec lists none
+var DefaultMetaParameters = []MetaParameter{
+	{Key: "evolution.mutation_rate", Min: 0.0, Max: 1.0},
+	{Key: "evolution.crossover_rate", Min: 0.0, Max: 1.0},
+	{Key: "evolution.selection_size", Min: 1, Max: 20, Integer: true},
+	{Key: "evolution.elitism_percentage", Min: 0.01, Max: 0.5},
+}
+
+// validate validates the MetaSpec and fills defaults
+func (m *MetaSpec) validate() error {
+	if m.Base == "" {
+		return fmt.Errorf("base must point to a config file")
+	}
+	if m.OutputDir == "" {
+		m.OutputDir = "meta_output"
+	}
+	if m.InnerGenerations <= 0 {
+		return fmt.Errorf("inner_generations must be greater than 0")
+	}
+	if m.InnerSeeds <= 0 {
+		m.InnerSeeds = 1
+	}
+	if len(m.Parameters) == 0 {
+		m.Parameters = DefaultMetaParameters
+	}
+	for _, p := range m.Parameters {
+		if p.Key == "" {
+			return fmt.Errorf("parameter is missing a key")
+		}
+		if p.Min > p.Max {
+			return fmt.Errorf("parameter %s has min greater than max", p.Key)
+		}
+	}
+
+	o := &m.Outer
+	if o.PopulationSize <= 0 {
+		return fmt.Errorf("outer population_size must be greater than 0")
+	}
+	if o.Generations <= 0 {
+		return fmt.Errorf("outer generations must be greater than 0")
+	}
+	if o.CrossoverPointCount <= 0 {
+		o.CrossoverPointCount = 1
+	}
+	if o.CrossoverRate < 0 || o.CrossoverRate > 1 {
+		return fmt.Errorf("outer crossover_rate must be between 0 and 1")
+	}
+	if o.MutationRate < 0 || o.MutationRate > 1 {
+		return fmt.Errorf("outer mutation_rate must be between 0 and 1")
+	}
+	if o.ElitismPercentage <= 0 || o.ElitismPercentage > 1 {
+		return fmt.Errorf("outer elitism_percentage must be greater than 0 and less than 1")
+	}
+	if o.SelectionSize <= 0 {
+		return fmt.Errorf("outer selection_size must be above 0")
+	}
+	if o.Seed == 0 {
+		o.Seed = 1
+	}
+	return nil
+}
+
+// LoadMetaSpec reads a meta spec and the base config it refers to
+func LoadMetaSpec(path string) (*MetaSpec, error) {
+	var spec MetaSpec
+	if _, err := toml.DecodeFile(path, &spec); err != nil {
+		return nil, fmt.Errorf("failed to load meta spec: %w", err)
+	}
+	if err := spec.validate(); err != nil {
+		return nil, fmt.Errorf("meta spec validation failed: %w", err)
+	}
+
+	base, err := loadBaseConfig(path, spec.Base)
+	if err != nil {
+		return nil, err
+	}
+	spec.baseConfig = base
+	return &spec, nil
+}
+
+// Bounds converts the evolved parameters into genome bounds for individual.RealVectorIndividual
+func (m *MetaSpec) Bounds() []individual.Bound {
+	bounds := make([]individual.Bound, len(m.Parameters))
+	for i, p := range m.Parameters {
+		bounds[i] = individual.Bound{Name: p.Key, Min: p.Min, Max: p.Max, Integer: p.Integer}
+	}
+	return bounds
+}
+
+// InnerConfig resolves the config for one inner run from evolved values and a seed
+func (m *MetaSpec) InnerConfig(values map[string]float64, seed int64) (*cfg.Config, error) {
+	params := m.params(values)
+	params["evolution.generations"] = int64(m.InnerGenerations)
+	return resolveConfig(m.baseConfig, seed, m.Overrides, params)
+}
+
+// TunedConfig resolves the base config with evolved values applied, keeping its generation count
+func (m *MetaSpec) TunedConfig(values map[string]float64) (*cfg.Config, error) {
+	return resolveConfig(m.baseConfig, m.Outer.Seed, m.Overrides, m.params(values))
+}
+
+// params maps evolved values onto dotted config keys, truncating integer parameters
+func (m *MetaSpec) params(values map[string]float64) map[string]any {
+	params := make(map[string]any, len(values)+1)
+	for _, p := range m.Parameters {
+		v := values[p.Key]
+		if p.Integer {
+			params[p.Key] = int64(v)
+		} else {
+			params[p.Key] = v
+		}
+	}
+	return params
+}
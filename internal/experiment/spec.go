@@ -0,0 +1,343 @@
+// Package experiment expands parameter sweeps over a base config and aggregates the results of many runs
+package experiment
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/bxrne/darwin/internal/cfg"
+)
+
+// RandomRange describes a parameter sampled uniformly between Min and Max
+type RandomRange struct {
+	Key     string  `toml:"key"`
+	Min     float64 `toml:"min"`
+	Max     float64 `toml:"max"`
+	Integer bool    `toml:"integer"`
+}
+
+// SweepSpec holds the sweep definition loaded from a TOML file
+type SweepSpec struct {
+	Base          string           `toml:"base"`
+	OutputDir     string           `toml:"output_dir"`
+	Seeds         int              `toml:"seeds"`
+	BaseSeed      int64            `toml:"base_seed"`
+	Parallel      int              `toml:"parallel"`
+	Overrides     map[string]any   `toml:"overrides"`
+	Grid          map[string][]any `toml:"grid"`
+	Random        []RandomRange    `toml:"random"`
+	RandomSamples int              `toml:"random_samples"`
+	RandomSeed    uint64           `toml:"random_seed"`
+
+	baseConfig map[string]any
+}
+
+// Point is a single configuration in the sweep
+type Point struct {
+	ID     string
+	Params map[string]any
+}
+
+// Run is a single (point, seed) combination to execute
+type Run struct {
+	Point  Point
+	Seed   int64
+	Config *cfg.Config
+	Dir    string
+}
+
+// validate validates the SweepSpec and fills defaults
+func (s *SweepSpec) validate() error {
+	if s.Base == "" {
+		return fmt.Errorf("base must point to a config file")
+	}
+	if s.OutputDir == "" {
+		s.OutputDir = "sweep_output"
+	}
+	if s.Seeds <= 0 {
+		s.Seeds = 1
+	}
+	if s.BaseSeed == 0 {
+		s.BaseSeed = 1
+	}
+	if s.Parallel < 0 {
+		return fmt.Errorf("parallel must not be negative")
+	}
+	for _, r := range s.Random {
+		if r.Key == "" {
+			return fmt.Errorf("random range is missing a key")
+		}
+		if r.Min > r.Max {
+			return fmt.Errorf("random range %s has min greater than max", r.Key)
+		}
+	}
+	if len(s.Random) > 0 && s.RandomSamples <= 0 {
+		return fmt.Errorf("random_samples must be greater than 0 when random ranges are given")
+	}
+	for key, values := range s.Grid {
+		if len(values) == 0 {
+			return fmt.Errorf("grid %s must have at least one value", key)
+		}
+	}
+	return nil
+}
+
+// LoadSweepSpec reads a sweep spec and the base config it refers to.
+// A relative base path is resolved against the spec's directory.
+func LoadSweepSpec(path string) (*SweepSpec, error) {
+	var spec SweepSpec
+	if _, err := toml.DecodeFile(path, &spec); err != nil {
+		return nil, fmt.Errorf("failed to load sweep spec: %w", err)
+	}
+	if err := spec.validate(); err != nil {
+		return nil, fmt.Errorf("sweep spec validation failed: %w", err)
+	}
+
+	base, err := loadBaseConfig(path, spec.Base)
+	if err != nil {
+		return nil, err
+	}
+	spec.baseConfig = base
+
+	return &spec, nil
+}
+
+// NewSweepSpec creates a spec from an already decoded base config, mainly for tests and programmatic use
+func NewSweepSpec(base map[string]any, grid map[string][]any, seeds int) (*SweepSpec, error) {
+	spec := &SweepSpec{Base: "<memory>", Grid: grid, Seeds: seeds, baseConfig: base}
+	if err := spec.validate(); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// Points expands the grid (cartesian product) and random ranges into sweep points.
+// With both present every grid point is combined with every random sample.
+func (s *SweepSpec) Points() []Point {
+	points := []map[string]any{{}}
+
+	keys := make([]string, 0, len(s.Grid))
+	for key := range s.Grid {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		expanded := make([]map[string]any, 0, len(points)*len(s.Grid[key]))
+		for _, p := range points {
+			for _, v := range s.Grid[key] {
+				next := copyParams(p)
+				next[key] = v
+				expanded = append(expanded, next)
+			}
+		}
+		points = expanded
+	}
+
+	if len(s.Random) > 0 {
+		r := rand.New(rand.NewPCG(s.RandomSeed, s.RandomSeed))
+		expanded := make([]map[string]any, 0, len(points)*s.RandomSamples)
+		for _, p := range points {
+			for range s.RandomSamples {
+				next := copyParams(p)
+				for _, rr := range s.Random {
+					v := rr.Min + r.Float64()*(rr.Max-rr.Min)
+					if rr.Integer {
+						next[rr.Key] = int64(v + 0.5)
+					} else {
+						next[rr.Key] = v
+					}
+				}
+				expanded = append(expanded, next)
+			}
+		}
+		points = expanded
+	}
+
+	result := make([]Point, len(points))
+	for i, p := range points {
+		result[i] = Point{ID: pointID(i, p), Params: p}
+	}
+	return result
+}
+
+// Runs builds every (point, seed) run with its resolved config and output directory
+func (s *SweepSpec) Runs() ([]Run, error) {
+	runs := make([]Run, 0)
+	for _, point := range s.Points() {
+		for i := range s.Seeds {
+			seed := s.BaseSeed + int64(i)
+			config, err := s.resolve(point, seed)
+			if err != nil {
+				return nil, fmt.Errorf("point %s seed %d: %w", point.ID, seed, err)
+			}
+			runs = append(runs, Run{
+				Point:  point,
+				Seed:   seed,
+				Config: config,
+				Dir:    filepath.Join(s.OutputDir, point.ID, fmt.Sprintf("seed-%d", seed)),
+			})
+		}
+	}
+	return runs, nil
+}
+
+// resolve applies overrides, point params and seed to the base config and validates the result
+func (s *SweepSpec) resolve(point Point, seed int64) (*cfg.Config, error) {
+	return resolveConfig(s.baseConfig, seed, s.Overrides, point.Params)
+}
+
+// resolveConfig layers dotted-key params over a copy of the base config, sets the seed,
+// disables CSV output (owned by the runner, never the base config), the dashboard, the Prometheus
+// endpoint and the control API (whose ports concurrent runs would contend for) and lineage
+// recording (whose file they would) and validates the result
+func resolveConfig(base map[string]any, seed int64, layers ...map[string]any) (*cfg.Config, error) {
+	raw := deepCopy(base).(map[string]any)
+	for _, layer := range layers {
+		for key, v := range layer {
+			if err := setDotted(raw, key, v); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := setDotted(raw, "evolution.seed", seed); err != nil {
+		return nil, err
+	}
+	if err := setDotted(raw, "metrics.csv_enabled", false); err != nil {
+		return nil, err
+	}
+	if err := setDotted(raw, "dashboard.enabled", false); err != nil {
+		return nil, err
+	}
+	if err := setDotted(raw, "prometheus.enabled", false); err != nil {
+		return nil, err
+	}
+	if err := setDotted(raw, "control.enabled", false); err != nil {
+		return nil, err
+	}
+	if err := setDotted(raw, "lineage.enabled", false); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(raw); err != nil {
+		return nil, fmt.Errorf("failed to encode resolved config: %w", err)
+	}
+	return cfg.DecodeConfig(buf.String())
+}
+
+// loadBaseConfig decodes a base config into a raw table, resolving relative paths against specPath's directory
+func loadBaseConfig(specPath string, base string) (map[string]any, error) {
+	basePath := base
+	if !filepath.IsAbs(basePath) {
+		basePath = filepath.Join(filepath.Dir(specPath), basePath)
+	}
+	raw := make(map[string]any)
+	if _, err := toml.DecodeFile(basePath, &raw); err != nil {
+		return nil, fmt.Errorf("failed to load base config %s: %w", basePath, err)
+	}
+	return raw, nil
+}
+
+// WriteResolvedConfig stores the exact config used by a run next to its outputs
+func WriteResolvedConfig(path string, config *cfg.Config) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer f.Close()
+	return toml.NewEncoder(f).Encode(config)
+}
+
+// setDotted sets a value in nested TOML tables, e.g. "evolution.mutation_rate"
+func setDotted(raw map[string]any, key string, value any) error {
+	parts := strings.Split(key, ".")
+	table := raw
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := table[part]
+		if !ok {
+			child := make(map[string]any)
+			table[part] = child
+			table = child
+			continue
+		}
+		child, ok := next.(map[string]any)
+		if !ok {
+			return fmt.Errorf("cannot set %s: %s is not a table", key, part)
+		}
+		table = child
+	}
+	table[parts[len(parts)-1]] = value
+	return nil
+}
+
+// pointID produces a stable, filesystem safe directory name for a point
+func pointID(index int, params map[string]any) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	id := fmt.Sprintf("p%03d", index)
+	for _, key := range keys {
+		name := key[strings.LastIndex(key, ".")+1:]
+		id += "_" + name + "=" + formatValue(params[key])
+	}
+	return strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ' ' {
+			return '-'
+		}
+		return r
+	}, id)
+}
+
+// formatValue keeps directory names short for floats
+func formatValue(v any) string {
+	switch val := v.(type) {
+	case float64:
+		return strconv.FormatFloat(val, 'g', 4, 64)
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+func copyParams(p map[string]any) map[string]any {
+	c := make(map[string]any, len(p))
+	for k, v := range p {
+		c[k] = v
+	}
+	return c
+}
+
+// deepCopy copies decoded TOML values so each run gets an independent tree
+func deepCopy(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		c := make(map[string]any, len(val))
+		for k, inner := range val {
+			c[k] = deepCopy(inner)
+		}
+		return c
+	case []map[string]any:
+		c := make([]map[string]any, len(val))
+		for i, inner := range val {
+			c[i] = deepCopy(inner).(map[string]any)
+		}
+		return c
+	case []any:
+		c := make([]any, len(val))
+		for i, inner := range val {
+			c[i] = deepCopy(inner)
+		}
+		return c
+	default:
+		return val
+	}
+}
@@ -0,0 +1,143 @@
+package experiment
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// RunResult is the outcome of one run in the sweep
+type RunResult struct {
+	Point     Point
+	Seed      int64
+	FinalBest float64
+	Err       error
+}
+
+// Summary aggregates all seeds of one sweep point
+type Summary struct {
+	Point    Point
+	Runs     int
+	Failures int
+	Median   float64
+	Q1       float64
+	Q3       float64
+	IQR      float64
+	Best     float64
+}
+
+// Summarise groups results by point and computes median, IQR and best final fitness.
+// Points are returned in the order they first appear in results.
+func Summarise(results []RunResult) []Summary {
+	order := make([]string, 0)
+	byPoint := make(map[string][]RunResult)
+	for _, r := range results {
+		if _, ok := byPoint[r.Point.ID]; !ok {
+			order = append(order, r.Point.ID)
+		}
+		byPoint[r.Point.ID] = append(byPoint[r.Point.ID], r)
+	}
+
+	summaries := make([]Summary, 0, len(order))
+	for _, id := range order {
+		group := byPoint[id]
+		summary := Summary{Point: group[0].Point}
+		values := make([]float64, 0, len(group))
+		for _, r := range group {
+			if r.Err != nil {
+				summary.Failures++
+				continue
+			}
+			values = append(values, r.FinalBest)
+		}
+		summary.Runs = len(values)
+		if len(values) > 0 {
+			sort.Float64s(values)
+			summary.Median = Quantile(values, 0.5)
+			summary.Q1 = Quantile(values, 0.25)
+			summary.Q3 = Quantile(values, 0.75)
+			summary.IQR = summary.Q3 - summary.Q1
+			summary.Best = values[len(values)-1]
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+// Quantile returns the p-quantile of sorted values using linear interpolation between
+// closest ranks (the common "type 7" definition, so the median of two values is their mean)
+func Quantile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	h := float64(len(sorted)-1) * p
+	lower := int(h)
+	if lower >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[lower] + (h-float64(lower))*(sorted[lower+1]-sorted[lower])
+}
+
+// WriteSummaryCSV writes the aggregate table as CSV
+func WriteSummaryCSV(path string, summaries []Summary) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create summary file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	writer := csv.NewWriter(f)
+	header := []string{"point", "params", "runs", "failures", "median", "q1", "q3", "iqr", "best"}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+	for _, s := range summaries {
+		row := []string{
+			s.Point.ID,
+			describeParams(s.Point.Params),
+			fmt.Sprintf("%d", s.Runs),
+			fmt.Sprintf("%d", s.Failures),
+			fmt.Sprintf("%f", s.Median),
+			fmt.Sprintf("%f", s.Q1),
+			fmt.Sprintf("%f", s.Q3),
+			fmt.Sprintf("%f", s.IQR),
+			fmt.Sprintf("%f", s.Best),
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write row: %w", err)
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteSummaryMarkdown writes the aggregate table as a Markdown table for the report
+func WriteSummaryMarkdown(path string, summaries []Summary) error {
+	var b strings.Builder
+	b.WriteString("| Point | Params | Runs | Median | IQR | Best |\n")
+	b.WriteString("|-------|--------|------|--------|-----|------|\n")
+	for _, s := range summaries {
+		fmt.Fprintf(&b, "| %s | %s | %d | %.4f | %.4f | %.4f |\n",
+			s.Point.ID, describeParams(s.Point.Params), s.Runs, s.Median, s.IQR, s.Best)
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		return fmt.Errorf("failed to write summary file %s: %w", path, err)
+	}
+	return nil
+}
+
+// describeParams renders params as "key=value" pairs in key order
+func describeParams(params map[string]any) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, key+"="+formatValue(params[key]))
+	}
+	return strings.Join(parts, " ")
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/bxrne/darwin/internal/individual"
+	"github.com/bxrne/darwin/internal/replay"
 	"github.com/bxrne/darwin/internal/rng"
 	"math"
 )
@@ -14,48 +15,82 @@ type ActionExecutor struct {
 	validator *ActionValidator
 }
 
-// NewActionExecutor creates a new action executor
+// NewActionExecutor creates a new action executor for the given action heads
 func NewActionExecutor(actions []individual.ActionTuple) *ActionExecutor {
 	return &ActionExecutor{
 		actions:   actions,
-		validator: NewActionValidator(),
+		validator: NewActionValidator(actions),
 	}
 }
 
-// ExecuteActionTreesWithSoftmax evaluates all action trees with given inputs and returns selected action using softmax
+// ExecuteActionTreesWithSoftmax evaluates all action trees with given inputs and returns the selected action,
+// masked by each head's mask provider. checkConstantActions holds one flag per action head.
 func (ae *ActionExecutor) ExecuteActionTreesWithSoftmax(actionTreeIndividual *individual.ActionTreeIndividual, weights *individual.WeightsIndividual, inputs map[string]float64, owned_cells [][]bool, checkConstantActions *[]bool) ([]int, error) {
-	// Calculate outputs for each action tree
-	actionOutputs := make([][]float64, len(ae.actions))
-	r, c := weights.Weights.Dims()
-	for row := range r {
-		for column := range c {
-			key := fmt.Sprintf("w%d", column)
-			inputs[key] = weights.Weights.At(row, column)
-		}
-		for i, actionName := range ae.actions {
-			if actionName.Value <= row {
-				continue
-			}
-			tree, exists := actionTreeIndividual.Trees[actionName.Name]
-			if !exists {
-				return nil, fmt.Errorf("action tree not found: %s", actionName.Name)
-			}
-
-			// Execute tree with inputs
-			fitness, _ := tree.Root.EvaluateTree(&inputs)
-			actionOutputs[i] = append(actionOutputs[i], fitness)
-		}
+	actionOutputs, err := ae.evaluateTrees(actionTreeIndividual, weights, inputs)
+	if err != nil {
+		return nil, err
 	}
 
 	// Apply softmax to convert scores to probabilities
 	selectedActions, err := ae.validator.SelectValidAction(actionOutputs, *checkConstantActions, owned_cells)
 	if err != nil {
-		//Pass if no vlaid acitons(need more troops)
-		return []int{1, 0, 0, 0, 0}, nil
+		// No valid action, e.g. Generals needs more troops: send the no-op instead
+		return NoOp(ae.actions), nil
 	}
 	return selectedActions, nil
 }
 
+// ExecuteActionTreesTraced is ExecuteActionTreesWithSoftmax also reporting how each head was
+// decided, for replays. The trace is empty when no valid action exists and the no-op is sent.
+func (ae *ActionExecutor) ExecuteActionTreesTraced(actionTreeIndividual *individual.ActionTreeIndividual, weights *individual.WeightsIndividual, inputs map[string]float64, owned_cells [][]bool, checkConstantActions *[]bool) ([]int, []replay.Head, error) {
+	actionOutputs, err := ae.evaluateTrees(actionTreeIndividual, weights, inputs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	selectedActions, trace, err := ae.validator.SelectValidActionTraced(actionOutputs, *checkConstantActions, owned_cells)
+	if err != nil {
+		return NoOp(ae.actions), nil, nil
+	}
+	return selectedActions, trace, nil
+}
+
+// evaluateTrees calculates one output per value of each action head, feeding the head's tree
+// the matching row of weights as inputs. A head with more values than the matrix has rows is an
+// error rather than a narrower head.
+func (ae *ActionExecutor) evaluateTrees(actionTreeIndividual *individual.ActionTreeIndividual, weights *individual.WeightsIndividual, inputs map[string]float64) ([][]float64, error) {
+	trees := make([]*individual.Tree, len(ae.actions))
+	for i, action := range ae.actions {
+		tree, exists := actionTreeIndividual.Trees[action.Name]
+		if !exists {
+			return nil, fmt.Errorf("action tree not found: %s", action.Name)
+		}
+		trees[i] = tree
+	}
+
+	r, c := weights.Weights.Dims()
+	keys := make([]string, c)
+	for column := range c {
+		keys[column] = fmt.Sprintf("w%d", column)
+	}
+	actionOutputs := make([][]float64, len(ae.actions))
+	for i, action := range ae.actions {
+		if action.Value > r {
+			return nil, fmt.Errorf("action %s has %d values but the weights matrix has %d rows", action.Name, action.Value, r)
+		}
+		outputs := make([]float64, action.Value)
+		for row := range action.Value {
+			for column, key := range keys {
+				inputs[key] = weights.Weights.At(row, column)
+			}
+			// Execute tree with inputs
+			outputs[row], _ = trees[i].Root.EvaluateTree(&inputs)
+		}
+		actionOutputs[i] = outputs
+	}
+	return actionOutputs, nil
+}
+
 // calculateSoftmax converts scores to probabilities using numerically stable softmax
 func CalculateSoftmax(scores []float64) []float64 {
 	if len(scores) == 0 {
@@ -116,7 +151,7 @@ func ArgMax(values []float64) int {
 	return maxIndex
 }
 
-func SampleAction(probabilties []float64) int {
+func SampleAction(probabilties []float64, r *rng.Rand) int {
 	if len(probabilties) == 0 {
 		return -1 // or panic, depending on your use case
 	}
@@ -127,7 +162,7 @@ func SampleAction(probabilties []float64) int {
 	}
 
 	cum_prob := 0.0
-	randVal := rng.Float64() * sum
+	randVal := r.Float64() * sum
 	for i, prob := range probabilties {
 		cum_prob += prob
 		if randVal <= cum_prob {
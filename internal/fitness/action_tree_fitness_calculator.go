@@ -2,16 +2,30 @@ package fitness
 
 import (
 	"fmt"
+	"maps"
 	"math"
-	"sort"
+	"os"
+	"path/filepath"
+	"slices"
 	"strconv"
 	"sync/atomic"
 	"time"
 
+	"github.com/bxrne/darwin/internal/environment"
+	"github.com/bxrne/darwin/internal/generals"
 	"github.com/bxrne/darwin/internal/individual"
+	"github.com/bxrne/darwin/internal/league"
+	"github.com/bxrne/darwin/internal/replay"
 	"go.uber.org/zap"
 )
 
+// EnvironmentFactory creates the environment for one test case; clientId names the game
+type EnvironmentFactory func(testCase int, clientId string) (environment.GameEnvironment, error)
+
+// LeagueEnvironmentFactory creates the Generals environment for one test case against opponent,
+// or against the built-in opponent when opponent is nil
+type LeagueEnvironmentFactory func(testCase int, opponent generals.Bot) (environment.GameEnvironment, error)
+
 // ActionTreeFitnessCalculator implements fitness calculation for ActionTree individuals
 type ActionTreeFitnessCalculator struct {
 	serverAddr           string
@@ -20,15 +34,35 @@ type ActionTreeFitnessCalculator struct {
 	actions              []individual.ActionTuple
 	weightsPopulation    *[]individual.Evolvable
 	actionTreePopulation *[]individual.Evolvable
-	selectionPercentage  float64
 	testCaseCount        int
 	connectionPool       *TCPConnectionPool
+	multiplexClient      *MultiplexClient
 	clientId             uint64
+	newEnvironment       EnvironmentFactory
+
+	retries      int
+	retryBackoff time.Duration
+	breaker      *CircuitBreaker
+
+	league            *league.League
+	leagueEnvironment LeagueEnvironmentFactory
+	leagueActions     []individual.ActionTuple
+	ratingFitness     bool
+
+	replayDir       string
+	replayMinReward float64
+	replayExt       string
+
+	logger *zap.Logger
 }
 
-// NewActionTreeFitnessCalculator creates a new action tree fitness calculator
-func NewActionTreeFitnessCalculator(serverAddr string, opponentType string, actions []individual.ActionTuple, maxSteps int, populations []*[]individual.Evolvable, selectionPercentage float64, poolSize int, testCaseCount int, timeout time.Duration) *ActionTreeFitnessCalculator {
+// NewActionTreeFitnessCalculator creates a new action tree fitness calculator playing on the game server
+func NewActionTreeFitnessCalculator(serverAddr string, opponentType string, actions []individual.ActionTuple, maxSteps int, populations []*[]individual.Evolvable, poolSize int, testCaseCount int, timeout time.Duration) *ActionTreeFitnessCalculator {
 	pool := NewTCPConnectionPool(serverAddr, poolSize, timeout)
+	rows, cols := 8, 8
+	if len(actions) >= 3 {
+		rows, cols = actions[1].Value, actions[2].Value
+	}
 
 	return &ActionTreeFitnessCalculator{
 		serverAddr:           serverAddr,
@@ -38,10 +72,202 @@ func NewActionTreeFitnessCalculator(serverAddr string, opponentType string, acti
 		weightsPopulation:    populations[0],
 		actionTreePopulation: populations[1],
 		testCaseCount:        testCaseCount,
-		selectionPercentage:  selectionPercentage,
 		connectionPool:       pool,
 		clientId:             0,
+		newEnvironment: func(testCase int, clientId string) (environment.GameEnvironment, error) {
+			return NewTCPEnvironment(pool, clientId, opponentType, rows, cols), nil
+		},
+	}
+}
+
+// NewMultiplexActionTreeFitnessCalculator creates a calculator whose games all share client's
+// connection to a game server speaking the multiplexed protocol
+func NewMultiplexActionTreeFitnessCalculator(client *MultiplexClient, opponentType string, actions []individual.ActionTuple, maxSteps int, populations []*[]individual.Evolvable, testCaseCount int) *ActionTreeFitnessCalculator {
+	rows, cols := 8, 8
+	if len(actions) >= 3 {
+		rows, cols = actions[1].Value, actions[2].Value
+	}
+
+	return &ActionTreeFitnessCalculator{
+		serverAddr:           client.serverAddr,
+		opponentType:         opponentType,
+		maxSteps:             maxSteps,
+		actions:              actions,
+		weightsPopulation:    populations[0],
+		actionTreePopulation: populations[1],
+		testCaseCount:        testCaseCount,
+		multiplexClient:      client,
+		newEnvironment: func(testCase int, clientId string) (environment.GameEnvironment, error) {
+			return client.NewGame(clientId, opponentType, rows, cols), nil
+		},
+	}
+}
+
+// NewEnvironmentActionTreeFitnessCalculator creates a calculator playing in-process environments.
+// One environment is created up front to check that its action space matches the configured actions.
+func NewEnvironmentActionTreeFitnessCalculator(newEnvironment EnvironmentFactory, actions []individual.ActionTuple, maxSteps int, populations []*[]individual.Evolvable, testCaseCount int) (*ActionTreeFitnessCalculator, error) {
+	probe, err := newEnvironment(0, "probe")
+	if err != nil {
+		return nil, err
+	}
+	defer probe.Close()
+	if _, err := resolveActionLayout(probe.ActionSpace(), actions); err != nil {
+		return nil, err
+	}
+
+	return &ActionTreeFitnessCalculator{
+		maxSteps:             maxSteps,
+		actions:              actions,
+		weightsPopulation:    populations[0],
+		actionTreePopulation: populations[1],
+		testCaseCount:        testCaseCount,
+		newEnvironment:       newEnvironment,
+	}, nil
+}
+
+// checkActionSpace verifies that each configured action matches the environment's head of the same position
+func checkActionSpace(space environment.ActionSpace, actions []individual.ActionTuple) error {
+	if len(space.Heads) != len(actions) {
+		return fmt.Errorf("environment has %d action heads but %d actions are configured", len(space.Heads), len(actions))
+	}
+	for i, head := range space.Heads {
+		if head.Size != actions[i].Value {
+			return fmt.Errorf("action %s has %d values but environment head %s has %d", actions[i].Name, actions[i].Value, head.Name, head.Size)
+		}
+	}
+	return nil
+}
+
+// resolveActionLayout fills in the environment's default layout for actions the config declares
+// only by name and size, renaming the environment's head dependencies to the configured names
+func resolveActionLayout(space environment.ActionSpace, actions []individual.ActionTuple) ([]individual.ActionTuple, error) {
+	if err := checkActionSpace(space, actions); err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(actions))
+	for i, head := range space.Heads {
+		names[head.Name] = actions[i].Name
+	}
+
+	resolved := make([]individual.ActionTuple, len(actions))
+	for i, action := range actions {
+		if !action.HasLayout() {
+			head := space.Heads[i]
+			action.Mask = head.Mask
+			action.StopValue = head.StopValue
+			action.PenalizeConstant = head.PenalizeConstant
+			action.DependsOn = make([]string, len(head.DependsOn))
+			for j, dependency := range head.DependsOn {
+				action.DependsOn[j] = names[dependency]
+			}
+		}
+		resolved[i] = action
+	}
+	if err := ValidateActionLayout(resolved); err != nil {
+		return nil, err
+	}
+	return resolved, nil
+}
+
+// SetFailurePolicy retries a failed game up to retries times, doubling the wait from backoff
+// between attempts, and reports games failing every attempt to breaker
+func (atfc *ActionTreeFitnessCalculator) SetFailurePolicy(retries int, backoff time.Duration, breaker *CircuitBreaker) {
+	atfc.retries = retries
+	atfc.retryBackoff = backoff
+	atfc.breaker = breaker
+}
+
+// SetLogger logs the calculator's games to logger rather than the global logger
+func (atfc *ActionTreeFitnessCalculator) SetLogger(logger *zap.Logger) {
+	atfc.logger = logger
+}
+
+// log is the calculator's logger, or the global logger if none was set
+func (atfc *ActionTreeFitnessCalculator) log() *zap.Logger {
+	if atfc.logger == nil {
+		return zap.L()
+	}
+	return atfc.logger
+}
+
+// Err implements Aborter, reporting an open circuit breaker
+func (atfc *ActionTreeFitnessCalculator) Err() error {
+	if atfc.breaker == nil {
+		return nil
+	}
+	return atfc.breaker.Err()
+}
+
+// EnableReplays records every game scoring above minReward to dir as <client id>.jsonl, gzipped
+// to .jsonl.gz if compress is set
+func (atfc *ActionTreeFitnessCalculator) EnableReplays(dir string, minReward float64, compress bool) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create replay directory: %w", err)
+	}
+	atfc.replayDir = dir
+	atfc.replayMinReward = minReward
+	atfc.replayExt = ".jsonl"
+	if compress {
+		atfc.replayExt += ".gz"
+	}
+	return nil
+}
+
+// EnableLeague makes every game a self-play match against an opponent sampled from l, falling
+// back to the built-in opponent while the pool is empty. With ratingFitness the individual's Elo
+// rating replaces the game reward as its fitness.
+func (atfc *ActionTreeFitnessCalculator) EnableLeague(l *league.League, newEnvironment LeagueEnvironmentFactory, ratingFitness bool) error {
+	probe, err := newEnvironment(0, nil)
+	if err != nil {
+		return err
+	}
+	defer probe.Close()
+	actions, err := resolveActionLayout(probe.ActionSpace(), atfc.actions)
+	if err != nil {
+		return err
 	}
+	atfc.league = l
+	atfc.leagueEnvironment = newEnvironment
+	atfc.leagueActions = actions
+	atfc.ratingFitness = ratingFitness
+	return nil
+}
+
+// EndGeneration implements GenerationObserver, freezing the best individuals of the evaluated
+// population into the league, each paired with the best member of the other population
+func (atfc *ActionTreeFitnessCalculator) EndGeneration(generation int, population []individual.Evolvable) {
+	if atfc.league == nil || len(population) == 0 {
+		return
+	}
+	bestTrees := best(*atfc.actionTreePopulation)
+	bestWeights := best(*atfc.weightsPopulation)
+	elites := make([]league.Member, 0, len(population))
+	for _, evolvable := range population {
+		member := league.Member{Source: evolvable}
+		switch ind := evolvable.(type) {
+		case *individual.ActionTreeIndividual:
+			member.Trees, member.Weights = ind, bestWeights.(*individual.WeightsIndividual)
+		case *individual.WeightsIndividual:
+			member.Trees, member.Weights = bestTrees.(*individual.ActionTreeIndividual), ind
+		default:
+			continue
+		}
+		elites = append(elites, member)
+	}
+	alive := append(append([]individual.Evolvable(nil), *atfc.actionTreePopulation...), *atfc.weightsPopulation...)
+	atfc.league.EndGeneration(elites, alive)
+	atfc.log().Debug("League updated", zap.Int("generation", generation), zap.Int("pool_size", len(atfc.league.Pool())))
+}
+
+// best returns the fittest individual of a population
+func best(population []individual.Evolvable) individual.Evolvable {
+	fittest := population[0]
+	for _, evolvable := range population[1:] {
+		if evolvable.GetFitness() > fittest.GetFitness() {
+			fittest = evolvable
+		}
+	}
+	return fittest
 }
 
 func (atfc *ActionTreeFitnessCalculator) getClientId() string {
@@ -65,14 +291,14 @@ func Score(value float64, alpha float64) float64 {
 }
 
 // handleTestCase scenario more cleanly and share code
-func (atfc *ActionTreeFitnessCalculator) handleTestCases(wi *individual.WeightsIndividual, tree *individual.ActionTreeIndividual, index int, fitnesses []Client) {
+func (atfc *ActionTreeFitnessCalculator) handleTestCases(subject individual.Evolvable, wi *individual.WeightsIndividual, tree *individual.ActionTreeIndividual, index int, fitnesses []Client) {
 	sum := 0.0
 	clientId := ""
 	successCount := 0
-	for range atfc.testCaseCount {
-		fitness, currentClientId, err := atfc.SetupGameAndRun(wi, tree)
+	for testCase := range atfc.testCaseCount {
+		fitness, currentClientId, err := atfc.runWithRetries(subject, wi, tree, testCase)
 		if err != nil {
-			zap.L().Error("Failed to setup game and run", zap.Error(err))
+			atfc.log().Error("Failed to setup game and run", zap.Error(err))
 		} else {
 			sum += Score(fitness, 0.5)
 			clientId += currentClientId + " " + strconv.FormatFloat(fitness, 'f', -1, 64) + " : "
@@ -93,134 +319,171 @@ func (atfc *ActionTreeFitnessCalculator) handleTestCases(wi *individual.WeightsI
 	}
 }
 
-// CalculateFitness evaluates the fitness of an ActionTree individual
+// CalculateFitness scores an ActionTree or weights individual with every member of the other
+// population, keeping its best score. Co-evolving populations call CalculateTeamFitness instead
+// and pick the partners themselves.
 func (atfc *ActionTreeFitnessCalculator) CalculateFitness(evolvable individual.Evolvable) {
-
-	wi, wiok := evolvable.(*individual.WeightsIndividual)
-	at, atok := evolvable.(*individual.ActionTreeIndividual)
-	if !wiok && !atok {
-		zap.L().Error("Expected ActionTreeIndividual or weightsIndividual", zap.String("type", fmt.Sprintf("%T", evolvable)))
+	subject := 0
+	partners := *atfc.actionTreePopulation
+	if _, ok := evolvable.(*individual.ActionTreeIndividual); ok {
+		subject = 1
+		partners = *atfc.weightsPopulation
+	} else if _, ok := evolvable.(*individual.WeightsIndividual); !ok {
+		atfc.log().Error("Expected ActionTreeIndividual or weightsIndividual", zap.String("type", fmt.Sprintf("%T", evolvable)))
 		evolvable.SetFitness(0.0)
 		return
 	}
 
-	fitnesses := make([]Client, max(len(*atfc.weightsPopulation), len(*atfc.actionTreePopulation)))
-	if wiok {
-		for index, evolvable := range *atfc.actionTreePopulation {
-			tree, ok := (evolvable).(*individual.ActionTreeIndividual)
-			if !ok {
-				panic("Not action tree in action tree population")
-			}
-			atfc.handleTestCases(wi, tree, index, fitnesses)
-		}
-		return
+	fitness := math.Inf(-1)
+	for _, partner := range partners {
+		team := make([]individual.Evolvable, 2)
+		team[subject], team[1-subject] = evolvable, partner
+		fitness = max(fitness, atfc.CalculateTeamFitness(team, subject))
 	}
+	if len(partners) == 0 {
+		fitness = 0.0
+	}
+	evolvable.SetFitness(fitness)
+}
 
-	if atok {
-		actionTreeFitnesses := make([]Client, len(*atfc.actionTreePopulation))
-		for index, evolvable := range *atfc.weightsPopulation {
-			weights, ok := (evolvable).(*individual.WeightsIndividual)
-			if !ok {
-				panic("Not action tree in action tree population")
-			}
-
-			atfc.handleTestCases(weights, at, index, fitnesses)
-		}
-		at.SetFitness(actionTreeFitnesses[0].Fitness)
-		at.SetClient(actionTreeFitnesses[0].ID)
+// CalculateTeamFitness implements TeamFitnessCalculator for a team of a weights individual and
+// an ActionTree individual, in that order, playing every test case together
+func (atfc *ActionTreeFitnessCalculator) CalculateTeamFitness(team []individual.Evolvable, subject int) float64 {
+	wi, wiok := team[0].(*individual.WeightsIndividual)
+	tree, treeok := team[1].(*individual.ActionTreeIndividual)
+	if !wiok || !treeok {
+		atfc.log().Error("Expected a weights and ActionTree team",
+			zap.String("weights_type", fmt.Sprintf("%T", team[0])),
+			zap.String("action_tree_type", fmt.Sprintf("%T", team[1])))
+		return 0.0
 	}
 
-	sort.Slice(fitnesses, func(i, j int) bool {
-		return (fitnesses[i].Fitness > fitnesses[j].Fitness)
-	})
-	if wiok {
-		wi.SetFitness(fitnesses[0].Fitness)
-		wi.SetClient(fitnesses[0].ID)
-		return
+	fitnesses := make([]Client, 1)
+	atfc.handleTestCases(team[subject], wi, tree, 0, fitnesses)
+	if client, ok := team[subject].(interface{ SetClient(string) }); ok {
+		client.SetClient(fitnesses[0].ID)
+	}
+	if atfc.ratingFitness {
+		// The Elo rating after this team's games replaces the reward
+		return atfc.league.Rating(team[subject])
 	}
+	return fitnesses[0].Fitness
+}
 
-	at.SetFitness(fitnesses[0].Fitness)
-	at.SetClient(fitnesses[0].ID)
+// runWithRetries plays a test case, retrying with backoff after a failure. Once the circuit
+// breaker is open no game is played.
+func (atfc *ActionTreeFitnessCalculator) runWithRetries(subject individual.Evolvable, wi *individual.WeightsIndividual, tree *individual.ActionTreeIndividual, testCase int) (float64, string, error) {
+	if err := atfc.Err(); err != nil {
+		return 0.0, "", err
+	}
+	backoff := atfc.retryBackoff
+	for attempt := 0; ; attempt++ {
+		fitness, clientId, err := atfc.SetupGameAndRun(subject, wi, tree, testCase)
+		if err == nil {
+			if atfc.breaker != nil {
+				atfc.breaker.Success()
+			}
+			return fitness, clientId, nil
+		}
+		if attempt >= atfc.retries {
+			if atfc.breaker != nil {
+				atfc.breaker.Failure(err)
+			}
+			return 0.0, "", err
+		}
+		atfc.log().Warn("Game failed, retrying", zap.Int("attempt", attempt+1), zap.Duration("backoff", backoff), zap.Error(err))
+		time.Sleep(backoff)
+		backoff *= 2
+	}
 }
 
-func (atfc *ActionTreeFitnessCalculator) SetupGameAndRun(weightsInd *individual.WeightsIndividual, actionTreeInd *individual.ActionTreeIndividual) (float64, string, error) {
-	// Get connection from pool
-	client, err := atfc.connectionPool.GetConnection()
+// SetupGameAndRun creates the environment for a test case and plays one game in it. In a league
+// the game is against a sampled opponent and its outcome updates the rating of subject.
+func (atfc *ActionTreeFitnessCalculator) SetupGameAndRun(subject individual.Evolvable, weightsInd *individual.WeightsIndividual, actionTreeInd *individual.ActionTreeIndividual, testCase int) (float64, string, error) {
+	clientId := atfc.getClientId()
+	var env environment.GameEnvironment
+	var opponent *league.Player
+	var err error
+	if atfc.league != nil {
+		var bot generals.Bot
+		if opponent = atfc.league.Sample(); opponent != nil {
+			bot = NewPolicyBot(atfc.leagueActions, opponent.Trees, opponent.Weights)
+		}
+		env, err = atfc.leagueEnvironment(testCase, bot)
+	} else {
+		env, err = atfc.newEnvironment(testCase, clientId)
+	}
 	if err != nil {
-		zap.L().Error("Failed to get connection from pool", zap.Error(err))
-		return 0.0, "", fmt.Errorf("connection pool error: %w", err)
+		return 0.0, "", fmt.Errorf("environment error: %w", err)
 	}
-	clientId := atfc.getClientId()
-	zap.L().Debug("Got connection for game evaluation",
+	atfc.log().Debug("Created environment for game evaluation",
 		zap.String("client_id", clientId),
 		zap.String("weights_id", fmt.Sprintf("%p", weightsInd)),
 		zap.String("action_tree_id", fmt.Sprintf("%p", actionTreeInd)))
 
-	// Ensure connection is returned to pool
+	// Ensure the environment's resources (e.g. pooled connections) are released
 	defer func() {
-		if returnErr := atfc.connectionPool.ReturnConnection(client); returnErr != nil {
-			zap.L().Error("Failed to return connection to pool", zap.Error(returnErr))
-		} else {
-			zap.L().Debug("Connection returned to pool successfully",
-				zap.String("client_id", clientId))
+		if closeErr := env.Close(); closeErr != nil {
+			atfc.log().Error("Failed to close environment", zap.Error(closeErr))
 		}
 	}()
 
-	// Small delay to ensure connection is stable
-	time.Sleep(100 * time.Millisecond)
-
-	// Connect to game
-	connectedResp, err := client.ConnectToGame(clientId, atfc.opponentType)
+	fitness, err := atfc.playGame(env, clientId, weightsInd, actionTreeInd)
 	if err != nil {
-		zap.L().Error("Failed to connect to game", zap.Error(err))
-		return 0.0, "", fmt.Errorf("game connection error: %w", err)
+		return 0.0, "", err
+	}
+	if scorer, ok := env.(interface{ Outcome() float64 }); ok && opponent != nil {
+		atfc.league.Record(subject, opponent, scorer.Outcome())
 	}
 
-	zap.L().Debug("Connected to game",
-		zap.String("agent_id", connectedResp.AgentID),
-		zap.String("opponent_id", connectedResp.OpponentID))
-
-	// Play game
-	fitness := atfc.playGame(client, weightsInd, actionTreeInd)
-
-	zap.L().Debug("Fitness calculated",
+	atfc.log().Debug("Fitness calculated",
 		zap.Float64("fitness", fitness),
 		zap.String("agent_id", clientId))
 	return fitness, clientId, nil
 }
 
 // playGame plays a single game and returns the fitness score
-func (atfc *ActionTreeFitnessCalculator) playGame(client *TCPClient, weightsInd *individual.WeightsIndividual, actionTreeInd *individual.ActionTreeIndividual) float64 {
+func (atfc *ActionTreeFitnessCalculator) playGame(env environment.GameEnvironment, clientId string, weightsInd *individual.WeightsIndividual, actionTreeInd *individual.ActionTreeIndividual) (float64, error) {
 	totalReward := 0.0
-	zap.L().Debug("Starting game evaluation",
+	atfc.log().Debug("Starting game evaluation",
 		zap.Int("max_steps", atfc.maxSteps),
 		zap.String("weights_id", fmt.Sprintf("%p", weightsInd)),
 		zap.String("action_tree_id", fmt.Sprintf("%p", actionTreeInd)))
 
-	// Get observation from conncetion (Reset)
-	obs, err := client.ReceiveObservation()
+	obs, err := env.Reset()
 	if err != nil {
-		zap.L().Error("Failed to receive observation", zap.Error(err))
+		return 0.0, fmt.Errorf("failed to reset environment: %w", err)
 	}
-	actionExecutor := NewActionExecutor(atfc.actions)
-	actionExecutor.validator.SetMountains(obs.Info)
-	constantActionSelectionTracker := make([]bool, 3)
-
-	// Send action to server
-	err = client.SendAction([]int{1, 0, 0, 0, 0})
+	actions, err := resolveActionLayout(env.ActionSpace(), atfc.actions)
 	if err != nil {
-		zap.L().Error("Failed to send action", zap.Error(err))
+		return 0.0, err
+	}
+	actionExecutor := NewActionExecutor(actions)
+	if obs.Grid != nil {
+		actionExecutor.validator.SetMountains(obs.Grid)
 	}
+	// Heads marked penalize_constant cost a game in which their outputs never varied
+	constantActionSelectionTracker := make([]bool, len(actions))
+	// Start with the no-op, e.g. pass on the first Generals turn
+	action := NoOp(actions)
+	var recording *replay.Replay
+	if atfc.replayDir != "" {
+		recording = newReplay(clientId, actions, actionTreeInd)
+		first := replayStep(env, 0, obs)
+		first.Action = slices.Clone(action)
+		recording.Add(first)
+	}
+
 	for step := range atfc.maxSteps {
 		totalReward += obs.Reward
-		obs, err = client.ReceiveObservation()
+		obs, err = env.Step(action)
 		if err != nil {
-			zap.L().Error("Failed to receive observation", zap.Error(err))
-			break
+			// A game cut short by the server is failed rather than scored on its partial reward
+			return 0.0, fmt.Errorf("failed to step game: %w", err)
 		}
 		// Log game progress every 10 steps
 		if step%10 == 0 {
-			zap.L().Debug("Game progress",
+			atfc.log().Debug("Game progress",
 				zap.Int("step", step),
 				zap.Float64("reward", obs.Reward),
 				zap.Bool("terminated", obs.Terminated),
@@ -230,52 +493,106 @@ func (atfc *ActionTreeFitnessCalculator) playGame(client *TCPClient, weightsInd
 
 		// Check if game is over
 		if obs.Terminated || obs.Truncated {
-			zap.L().Debug("Game ended",
+			atfc.log().Debug("Game ended",
 				zap.Int("step", step),
 				zap.Float64("final_reward", obs.Reward),
 				zap.Float64("total_reward", totalReward+obs.Reward),
 				zap.Bool("terminated", obs.Terminated),
 				zap.Bool("truncated", obs.Truncated))
-			// Extract final game state
+			if recording != nil {
+				recording.Add(replayStep(env, step+1, obs))
+			}
 			break
 		}
 
 		// Execute action trees to get action
-		action, err := actionExecutor.ExecuteActionTreesWithSoftmax(actionTreeInd, weightsInd, obs.Observation, obs.Info, &constantActionSelectionTracker)
-		if err != nil {
-			zap.L().Error("Failed to execute action trees", zap.Error(err))
-			// Send a default action (pass) instead of panicking
+		var recorded replay.Step
+		if recording != nil {
+			recorded = replayStep(env, step+1, obs)
+			action, recorded.Heads, err = actionExecutor.ExecuteActionTreesTraced(actionTreeInd, weightsInd, obs.Features, obs.Grid, &constantActionSelectionTracker)
+		} else {
+			action, err = actionExecutor.ExecuteActionTreesWithSoftmax(actionTreeInd, weightsInd, obs.Features, obs.Grid, &constantActionSelectionTracker)
 		}
-
-		// Send action to server
-		err = client.SendAction(action)
 		if err != nil {
-			zap.L().Error("Failed to send action", zap.Error(err))
-			break
+			atfc.log().Error("Failed to execute action trees", zap.Error(err))
+			// Send the no-op instead of panicking
+			action = NoOp(actions)
+		}
+		if recording != nil {
+			recorded.Action = slices.Clone(action)
+			recording.Add(recorded)
 		}
 
 		totalReward += obs.Reward
 	}
-	for _, actionIsntConstant := range constantActionSelectionTracker {
-		if !actionIsntConstant {
+	for i, actionIsntConstant := range constantActionSelectionTracker {
+		if actions[i].PenalizeConstant && !actionIsntConstant {
 			totalReward -= 10 // Try to reduce them but not totally kill them as genome parts could still be good if one tree is bad
 		}
 	}
-	if totalReward > 5.0 {
-		err = client.RequestReplay()
+	if replayer, ok := env.(interface{ RequestReplay() error }); ok && totalReward > 5.0 {
+		err = replayer.RequestReplay()
 		if err != nil {
-			zap.L().Error("Failed to getReplay", zap.Error(err))
+			atfc.log().Error("Failed to getReplay", zap.Error(err))
+		}
+	}
+
+	if recording != nil && totalReward > atfc.replayMinReward {
+		recording.TotalReward = totalReward
+		path := filepath.Join(atfc.replayDir, clientId+atfc.replayExt)
+		if err := replay.Write(path, recording); err != nil {
+			atfc.log().Error("Failed to save replay", zap.String("path", path), zap.Error(err))
 		}
 	}
 
-	zap.L().Debug("Final fitness calculation",
+	atfc.log().Debug("Final fitness calculation",
 		zap.Float64("total_reward", totalReward))
 
-	return totalReward
+	return totalReward, nil
+}
+
+// newReplay starts the recording of a game played by the trees
+func newReplay(clientId string, actions []individual.ActionTuple, trees *individual.ActionTreeIndividual) *replay.Replay {
+	names := make([]string, len(actions))
+	descriptions := make(map[string]string, len(actions))
+	for i, action := range actions {
+		names[i] = action.Name
+		if tree, ok := trees.Trees[action.Name]; ok {
+			descriptions[action.Name] = tree.Describe()
+		}
+	}
+	return replay.New(clientId, names, descriptions)
+}
+
+// replayStep records an observation, drawing the board if the environment can. The features are
+// copied since evaluating the trees adds the weights to them.
+func replayStep(env environment.GameEnvironment, step int, obs *environment.Observation) replay.Step {
+	recorded := replay.Step{
+		Step:       step,
+		Features:   maps.Clone(obs.Features),
+		Reward:     obs.Reward,
+		Terminated: obs.Terminated,
+		Truncated:  obs.Truncated,
+	}
+	if renderer, ok := env.(environment.Renderer); ok {
+		recorded.Board = renderer.Render()
+	}
+	return recorded
+}
+
+// PoolStats implements PoolStatsReporter
+func (atfc *ActionTreeFitnessCalculator) PoolStats() map[string]interface{} {
+	if atfc.connectionPool == nil {
+		return nil
+	}
+	return atfc.connectionPool.GetStats()
 }
 
-// Close closes the connection pool and cleans up resources
+// Close closes the connection pool or multiplexed connection and cleans up resources
 func (atfc *ActionTreeFitnessCalculator) Close() error {
+	if atfc.multiplexClient != nil {
+		return atfc.multiplexClient.Close()
+	}
 	if atfc.connectionPool != nil {
 		return atfc.connectionPool.Close()
 	}
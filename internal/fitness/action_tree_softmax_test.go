@@ -156,6 +156,21 @@ func TestExecuteActionTreesWithSoftmax_ErrorCases(t *testing.T) {
 			expectedError: "action tree not found: action_3",
 			seed:          42,
 		},
+		{
+			name:        "HeadWiderThanWeights",
+			description: "INFO: Test Case: Head Wider Than Weights - action_2 has 4 values but the weights matrix only 3 rows",
+			inputs:      []float64{1.0, 2.0, 3.0, 4.0},
+			trees: map[string]*individual.Tree{
+				"action_0": {Root: &individual.TreeNode{Value: "0.0"}},
+				"action_1": {Root: &individual.TreeNode{Value: "0.0"}},
+				"action_2": {Root: &individual.TreeNode{Value: "0.0"}},
+				"action_3": {Root: &individual.TreeNode{Value: "0.0"}},
+				"action_4": {Root: &individual.TreeNode{Value: "0.0"}},
+			},
+			weights:       createAllOnesWeights(3, 4),
+			expectedError: "action action_2 has 4 values but the weights matrix has 3 rows",
+			seed:          42,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -190,5 +205,5 @@ func TestExecuteActionTreesWithSoftmax_ErrorCases(t *testing.T) {
 
 // createAllOnesWeights creates a weights matrix filled with 1.0
 func createAllOnesWeights(rows, cols int) *individual.WeightsIndividual {
-	return individual.NewWeightsIndividual(rows, cols)
+	return individual.NewWeightsIndividual(nil, rows, cols)
 }
@@ -2,18 +2,97 @@ package fitness
 
 import (
 	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/bxrne/darwin/internal/individual"
+	"github.com/bxrne/darwin/internal/replay"
 )
 
-// ActionValidator validates actions based on game observations
+// MaskProvider returns a 0/1 mask over a head's values. owned_cells is the grid of the latest
+// observation and dependencies holds the chosen values of the head's DependsOn heads, in order.
+type MaskProvider func(av *ActionValidator, owned_cells [][]bool, dependencies []int) ([]float64, error)
+
+// maskProviders maps the names usable in an action's mask field to their providers
+var maskProviders = map[string]MaskProvider{
+	"owned_rows": func(av *ActionValidator, owned_cells [][]bool, dependencies []int) ([]float64, error) {
+		return av.ValidXActionMask(owned_cells)
+	},
+	"owned_cells_in_row": func(av *ActionValidator, owned_cells [][]bool, dependencies []int) ([]float64, error) {
+		if len(dependencies) != 1 {
+			return nil, fmt.Errorf("owned_cells_in_row depends on exactly one row head")
+		}
+		return av.ValidYActionMask(dependencies[0], owned_cells)
+	},
+	"open_directions": func(av *ActionValidator, owned_cells [][]bool, dependencies []int) ([]float64, error) {
+		if len(dependencies) != 2 {
+			return nil, fmt.Errorf("open_directions depends on a row head and a column head")
+		}
+		return av.DirectionActionMask(dependencies[0], dependencies[1])
+	},
+}
+
+// RegisterMaskProvider makes a mask provider available to action configs under name
+func RegisterMaskProvider(name string, provider MaskProvider) {
+	maskProviders[name] = provider
+}
+
+// MaskProviderNames returns the registered mask providers in sorted order
+func MaskProviderNames() []string {
+	names := make([]string, 0, len(maskProviders))
+	for name := range maskProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ActionValidator chooses valid actions for a declared action layout
 type ActionValidator struct {
+	actions    []individual.ActionTuple
 	gridWidth  int
 	gridHeight int
 	mountains  [][]bool
 }
 
-// NewActionValidator creates a new action validator
-func NewActionValidator() *ActionValidator {
-	return &ActionValidator{}
+// NewActionValidator creates a new action validator for the given action heads
+func NewActionValidator(actions []individual.ActionTuple) *ActionValidator {
+	return &ActionValidator{actions: actions}
+}
+
+// ValidateActionLayout checks that dependencies name earlier heads and masks are registered
+func ValidateActionLayout(actions []individual.ActionTuple) error {
+	seen := make(map[string]bool, len(actions))
+	for _, action := range actions {
+		for _, dependency := range action.DependsOn {
+			if !seen[dependency] {
+				return fmt.Errorf("action %s depends on %s, which is not an earlier action", action.Name, dependency)
+			}
+		}
+		if action.Mask != "" {
+			if _, ok := maskProviders[action.Mask]; !ok {
+				return fmt.Errorf("action %s has unknown mask %q, available: %v", action.Name, action.Mask, MaskProviderNames())
+			}
+		}
+		if action.StopValue != nil && (*action.StopValue < 0 || *action.StopValue >= action.Value) {
+			return fmt.Errorf("action %s has stop value %d outside its %d values", action.Name, *action.StopValue, action.Value)
+		}
+		seen[action.Name] = true
+	}
+	return nil
+}
+
+// NoOp is the action sent when nothing valid can be chosen: the first head with a stop value
+// set to it, which ends the action, and every other head 0
+func NoOp(actions []individual.ActionTuple) []int {
+	selected := make([]int, len(actions))
+	for i, action := range actions {
+		if action.StopValue != nil {
+			selected[i] = *action.StopValue
+			break
+		}
+	}
+	return selected
 }
 
 // setGridDimensions extracts grid dimensions from mountain data
@@ -130,44 +209,63 @@ func CheckDistinctOnce(arr []float64, flag *bool) {
 		*flag = true
 	}
 }
-func (av *ActionValidator) SelectValidAction(actionOutputs [][]float64, checkedConstantValues []bool, owned_cells [][]bool) ([]int, error) {
 
-	selectedActions := make([]int, len(actionOutputs))
-	passProbabilities := CalculateSoftmax(actionOutputs[0])
-	selectedActions[0] = ArgMax(passProbabilities)
-	if selectedActions[0] == 1 {
-		return selectedActions, nil
-	}
+// SelectValidAction picks each head's most probable value in order, after masking it by the
+// head's mask provider. checkedConstantValues holds one flag per head, set once that head's
+// outputs vary. Choosing a head's stop value ends the action with later heads left at 0.
+func (av *ActionValidator) SelectValidAction(actionOutputs [][]float64, checkedConstantValues []bool, owned_cells [][]bool) ([]int, error) {
+	return av.selectValidAction(actionOutputs, checkedConstantValues, owned_cells, nil)
+}
 
-	CheckDistinctOnce(actionOutputs[1], &checkedConstantValues[1])
-	CheckDistinctOnce(actionOutputs[2], &checkedConstantValues[2])
-	CheckDistinctOnce(actionOutputs[3], &checkedConstantValues[0])
+// SelectValidActionTraced is SelectValidAction also reporting each decided head's outputs,
+// probabilities, mask and choice
+func (av *ActionValidator) SelectValidActionTraced(actionOutputs [][]float64, checkedConstantValues []bool, owned_cells [][]bool) ([]int, []replay.Head, error) {
+	trace := make([]replay.Head, 0, len(av.actions))
+	selectedActions, err := av.selectValidAction(actionOutputs, checkedConstantValues, owned_cells, &trace)
+	return selectedActions, trace, err
+}
 
-	Xprobabilities := CalculateSoftmax(actionOutputs[1])
-	validXActions, err := av.ValidXActionMask(owned_cells)
-	if err != nil {
-		return nil, fmt.Errorf("failed to find valid X value %s", err.Error())
-	}
-	applyMask(Xprobabilities, validXActions)
-	selectedActions[1] = ArgMax(Xprobabilities)
+// selectValidAction implements SelectValidAction, appending each decided head to trace if set
+func (av *ActionValidator) selectValidAction(actionOutputs [][]float64, checkedConstantValues []bool, owned_cells [][]bool, trace *[]replay.Head) ([]int, error) {
+	selectedActions := make([]int, len(av.actions))
+	chosen := make(map[string]int, len(av.actions))
+	for i, action := range av.actions {
+		if action.PenalizeConstant && i < len(checkedConstantValues) {
+			CheckDistinctOnce(actionOutputs[i], &checkedConstantValues[i])
+		}
 
-	Yprobabilities := CalculateSoftmax(actionOutputs[2])
-	validYActions, err := av.ValidYActionMask(selectedActions[1], owned_cells)
-	if err != nil {
-		return nil, fmt.Errorf("failed to find valid Y value %s", err.Error())
-	}
-	applyMask(Yprobabilities, validYActions)
-	selectedActions[2] = ArgMax(Yprobabilities)
+		probabilities := CalculateSoftmax(actionOutputs[i])
+		var head *replay.Head
+		if trace != nil {
+			*trace = append(*trace, replay.Head{Name: action.Name, Outputs: actionOutputs[i], Probabilities: slices.Clone(probabilities)})
+			head = &(*trace)[len(*trace)-1]
+		}
+		if action.Mask != "" {
+			dependencies := make([]int, len(action.DependsOn))
+			for j, dependency := range action.DependsOn {
+				dependencies[j] = chosen[dependency]
+			}
+			mask, err := maskProviders[action.Mask](av, owned_cells, dependencies)
+			if err != nil {
+				return nil, fmt.Errorf("failed to find valid %s value %s", action.Name, err.Error())
+			}
+			if len(mask) != len(probabilities) {
+				return nil, fmt.Errorf("mask %s has %d values but action %s has %d outputs", action.Mask, len(mask), action.Name, len(probabilities))
+			}
+			applyMask(probabilities, mask)
+			if head != nil {
+				head.Mask = mask
+			}
+		}
+		selectedActions[i] = max(ArgMax(probabilities), 0)
+		if head != nil {
+			head.Chosen = selectedActions[i]
+		}
+		chosen[action.Name] = selectedActions[i]
 
-	directionProbabilities := CalculateSoftmax(actionOutputs[3])
-	validDirections, err := av.DirectionActionMask(selectedActions[1], selectedActions[2])
-	if err != nil {
-		return nil, fmt.Errorf("failed to find valid Direction value %s", err.Error())
+		if action.StopValue != nil && selectedActions[i] == *action.StopValue {
+			return selectedActions, nil
+		}
 	}
-	applyMask(directionProbabilities, validDirections)
-	selectedActions[3] = ArgMax(directionProbabilities)
-
-	splitProbabilities := CalculateSoftmax(actionOutputs[4])
-	selectedActions[4] = ArgMax(splitProbabilities)
 	return selectedActions, nil
 }
@@ -1 +1,123 @@
 package fitness_test
+
+import (
+	"testing"
+
+	"github.com/bxrne/darwin/internal/fitness"
+	"github.com/bxrne/darwin/internal/individual"
+	"github.com/stretchr/testify/assert"
+)
+
+// generalsLayout declares the Generals heads on a 3x3 map the way a config would
+func generalsLayout() []individual.ActionTuple {
+	pass := 1
+	return []individual.ActionTuple{
+		{Name: "pass", Value: 2, StopValue: &pass},
+		{Name: "row", Value: 3, Mask: "owned_rows", PenalizeConstant: true},
+		{Name: "col", Value: 3, DependsOn: []string{"row"}, Mask: "owned_cells_in_row", PenalizeConstant: true},
+		{Name: "direction", Value: 4, DependsOn: []string{"row", "col"}, Mask: "open_directions", PenalizeConstant: true},
+		{Name: "split", Value: 2},
+	}
+}
+
+func TestSelectValidAction_GIVEN_stop_value_chosen_WHEN_selected_THEN_later_heads_stay_zero(t *testing.T) {
+	validator := fitness.NewActionValidator(generalsLayout())
+	outputs := [][]float64{{0, 5}, {9, 0, 0}, {9, 0, 0}, {9, 0, 0, 0}, {9, 0}}
+
+	action, err := validator.SelectValidAction(outputs, make([]bool, 5), nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 0, 0, 0, 0}, action)
+}
+
+func TestSelectValidAction_GIVEN_masks_WHEN_selected_THEN_only_valid_values_are_chosen(t *testing.T) {
+	validator := fitness.NewActionValidator(generalsLayout())
+	validator.SetMountains([][]bool{
+		{false, true, false},
+		{false, false, false},
+		{false, false, false},
+	})
+	owned := [][]bool{
+		{false, false, false},
+		{false, true, false},
+		{false, false, false},
+	}
+	// Row, column and direction prefer value 0, which the masks rule out: the only owned cell
+	// is (1, 1) and moving up from it hits a mountain
+	outputs := [][]float64{{5, 0}, {9, 0, 0}, {9, 0, 0}, {9, 0, 0, 8}, {0, 1}}
+
+	action, err := validator.SelectValidAction(outputs, make([]bool, 5), owned)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, action[0])
+	assert.Equal(t, 1, action[1])
+	assert.Equal(t, 1, action[2])
+	assert.Equal(t, 3, action[3])
+	assert.Equal(t, 1, action[4])
+}
+
+func TestSelectValidAction_GIVEN_varying_outputs_WHEN_selected_THEN_only_penalized_heads_are_tracked(t *testing.T) {
+	validator := fitness.NewActionValidator(generalsLayout())
+	validator.SetMountains([][]bool{{false, false, false}, {false, false, false}, {false, false, false}})
+	owned := [][]bool{{true, true, true}, {true, true, true}, {true, true, true}}
+	outputs := [][]float64{{1, 0}, {1, 2, 3}, {0, 0, 0}, {1, 2, 3, 4}, {1, 2}}
+	tracker := make([]bool, 5)
+
+	_, err := validator.SelectValidAction(outputs, tracker, owned)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{false, true, false, true, false}, tracker)
+}
+
+func TestSelectValidAction_GIVEN_no_valid_cells_WHEN_selected_THEN_returns_error(t *testing.T) {
+	validator := fitness.NewActionValidator(generalsLayout())
+	validator.SetMountains([][]bool{{false, false, false}, {false, false, false}, {false, false, false}})
+	owned := [][]bool{{false, false, false}, {false, false, false}, {false, false, false}}
+	outputs := [][]float64{{1, 0}, {0, 0, 0}, {0, 0, 0}, {0, 0, 0, 0}, {0, 0}}
+
+	_, err := validator.SelectValidAction(outputs, make([]bool, 5), owned)
+
+	assert.ErrorContains(t, err, "row")
+}
+
+func TestSelectValidAction_GIVEN_custom_mask_provider_WHEN_selected_THEN_dependency_values_are_passed(t *testing.T) {
+	fitness.RegisterMaskProvider("test_not_equal", func(av *fitness.ActionValidator, owned_cells [][]bool, dependencies []int) ([]float64, error) {
+		mask := []float64{1, 1, 1}
+		mask[dependencies[0]] = 0
+		return mask, nil
+	})
+	validator := fitness.NewActionValidator([]individual.ActionTuple{
+		{Name: "first", Value: 3},
+		{Name: "second", Value: 3, DependsOn: []string{"first"}, Mask: "test_not_equal"},
+	})
+
+	action, err := validator.SelectValidAction([][]float64{{0, 0, 9}, {0, 1, 9}}, make([]bool, 2), nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 1}, action)
+}
+
+func TestValidateActionLayout_GIVEN_bad_layouts_WHEN_validated_THEN_returns_errors(t *testing.T) {
+	stop := 2
+	testCases := []struct {
+		name    string
+		actions []individual.ActionTuple
+		err     string
+	}{
+		{"LaterDependency", []individual.ActionTuple{{Name: "a", Value: 2, DependsOn: []string{"b"}}, {Name: "b", Value: 2}}, "not an earlier action"},
+		{"UnknownMask", []individual.ActionTuple{{Name: "a", Value: 2, Mask: "nope"}}, "unknown mask"},
+		{"StopValueOutOfRange", []individual.ActionTuple{{Name: "a", Value: 2, StopValue: &stop}}, "stop value"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.ErrorContains(t, fitness.ValidateActionLayout(tc.actions), tc.err)
+		})
+	}
+	assert.NoError(t, fitness.ValidateActionLayout(generalsLayout()))
+}
+
+func TestNoOp_GIVEN_layout_WHEN_built_THEN_first_stop_value_is_set(t *testing.T) {
+	assert.Equal(t, []int{1, 0, 0, 0, 0}, fitness.NoOp(generalsLayout()))
+	assert.Equal(t, []int{0, 0}, fitness.NoOp([]individual.ActionTuple{{Name: "a", Value: 2}, {Name: "b", Value: 2}}))
+}
@@ -0,0 +1,55 @@
+package fitness
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Aborter is implemented by fitness calculators that can give up on a run, e.g. when the game
+// server stops answering; the engine stops once Err returns an error
+type Aborter interface {
+	Err() error
+}
+
+// CircuitBreaker counts consecutive failed games and opens after maxFailures of them, so a dead
+// server aborts the run instead of failing every remaining game; it is safe for concurrent use
+type CircuitBreaker struct {
+	mu          sync.Mutex
+	maxFailures int
+	failures    int
+	lastErr     error
+	open        bool
+}
+
+// NewCircuitBreaker creates a closed breaker; maxFailures <= 0 never opens
+func NewCircuitBreaker(maxFailures int) *CircuitBreaker {
+	return &CircuitBreaker{maxFailures: maxFailures}
+}
+
+// Success resets the count of consecutive failures
+func (cb *CircuitBreaker) Success() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.failures = 0
+}
+
+// Failure counts a failed game, opening the breaker at maxFailures
+func (cb *CircuitBreaker) Failure(err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.failures++
+	cb.lastErr = err
+	if cb.maxFailures > 0 && cb.failures >= cb.maxFailures {
+		cb.open = true
+	}
+}
+
+// Err describes why the breaker opened, nil while it is closed
+func (cb *CircuitBreaker) Err() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if !cb.open {
+		return nil
+	}
+	return fmt.Errorf("aborting after %d consecutive failed games, last error: %w", cb.failures, cb.lastErr)
+}
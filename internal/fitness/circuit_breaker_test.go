@@ -0,0 +1,57 @@
+package fitness_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bxrne/darwin/internal/fitness"
+	"github.com/bxrne/darwin/internal/individual"
+	"github.com/bxrne/darwin/internal/mockserver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker_GIVEN_consecutive_failures_WHEN_counted_THEN_opens_at_limit(t *testing.T) {
+	breaker := fitness.NewCircuitBreaker(2)
+
+	breaker.Failure(assert.AnError)
+	breaker.Success()
+	breaker.Failure(assert.AnError)
+	assert.NoError(t, breaker.Err())
+
+	breaker.Failure(assert.AnError)
+	assert.ErrorContains(t, breaker.Err(), "aborting after 2 consecutive failed games")
+	assert.ErrorIs(t, breaker.Err(), assert.AnError)
+}
+
+func TestCircuitBreaker_GIVEN_no_limit_WHEN_failing_THEN_never_opens(t *testing.T) {
+	breaker := fitness.NewCircuitBreaker(0)
+
+	for range 100 {
+		breaker.Failure(assert.AnError)
+	}
+
+	assert.NoError(t, breaker.Err())
+}
+
+func TestActionTreeFitnessCalculator_GIVEN_failing_server_WHEN_scored_THEN_retries_then_trips_breaker(t *testing.T) {
+	server := startServer(t, mockserver.Scenario{DropAt: 1})
+	actions := []individual.ActionTuple{{Name: "pass", Value: 2}, {Name: "row", Value: 8}, {Name: "col", Value: 8}, {Name: "direction", Value: 4}, {Name: "split", Value: 2}}
+	weights := []individual.Evolvable{individual.NewWeightsIndividual(nil, 8, 5)}
+	trees := []individual.Evolvable{individual.NewRandomActionTreeIndividual(nil, actions, 2, []string{"+"}, []string{"timestep"}, []string{"1"})}
+	calc := fitness.NewActionTreeFitnessCalculator(server.Addr(), "random", actions, 10, []*[]individual.Evolvable{&weights, &trees}, 2, 1, time.Second)
+	defer calc.Close()
+	calc.SetFailurePolicy(2, time.Millisecond, fitness.NewCircuitBreaker(2))
+
+	team := []individual.Evolvable{weights[0], trees[0]}
+	assert.Equal(t, 0.0, calc.CalculateTeamFitness(team, 1))
+	// The first attempt and two retries
+	assert.Equal(t, int64(3), server.Stats().Games)
+	assert.NoError(t, calc.Err())
+
+	calc.CalculateTeamFitness(team, 1)
+	assert.ErrorContains(t, calc.Err(), "failed to step game")
+
+	// An open breaker plays no more games
+	calc.CalculateTeamFitness(team, 1)
+	assert.Equal(t, int64(6), server.Stats().Games)
+}
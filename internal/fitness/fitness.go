@@ -1,28 +1,70 @@
 package fitness
 
 import (
+	"math"
 	"time"
 
 	"github.com/bxrne/darwin/internal/cfg"
+	"github.com/bxrne/darwin/internal/environment"
+	"github.com/bxrne/darwin/internal/generals"
 	"github.com/bxrne/darwin/internal/individual"
+	"github.com/bxrne/darwin/internal/league"
+	"github.com/bxrne/darwin/internal/rng"
+	"go.uber.org/zap"
 )
 
 type FitnessCalculator interface {
 	CalculateFitness(evolvable individual.Evolvable)
 }
 
+// GenerationObserver is implemented by fitness calculators that keep state across generations;
+// the engine calls EndGeneration once a generation is evaluated, with the population sorted best first
+type GenerationObserver interface {
+	EndGeneration(generation int, population []individual.Evolvable)
+}
+
+// TeamFitnessCalculator is implemented by fitness calculators for co-evolved species, which can
+// only score an individual as part of a team with one member of each species
+type TeamFitnessCalculator interface {
+	// CalculateTeamFitness returns the fitness of team[subject] when playing with the rest of team
+	CalculateTeamFitness(team []individual.Evolvable, subject int) float64
+}
+
+// ParallelFitnessCalculator is implemented by calculators that are safe for concurrent use and
+// want Parallelism evaluations in flight at once, e.g. one per remote worker slot
+type ParallelFitnessCalculator interface {
+	Parallelism() int
+}
+
+// PoolStatsReporter is implemented by calculators holding a TCPConnectionPool; PoolStats returns
+// its GetStats, or nil when the calculator plays without one
+type PoolStatsReporter interface {
+	PoolStats() map[string]interface{}
+}
+
 type FitnessSetupInformation struct {
-	EvalFunction                  string
-	VariableSet                   []string
-	GenomeType                    individual.GenomeType
-	TestCaseCount                 int
-	Grammar                       map[string]individual.Node
-	ServerAddr                    string
-	OpponentType                  string
-	MaxSteps                      int
-	Actions                       []individual.ActionTuple
-	Population                    []*[]individual.Evolvable
-	ActionTreeSelectionPercentage float64
+	EvalFunction  string
+	VariableSet   []string
+	GenomeType    individual.GenomeType
+	TestCaseCount int
+	Grammar       map[string]individual.Node
+	ServerAddr    string
+	OpponentType  string
+	MaxSteps      int
+	Actions       []individual.ActionTuple
+	Population    []*[]individual.Evolvable
+	// Rand is the generator of the run, drawing the test cases; nil is the package generator
+	Rand *rng.Rand
+	// Logger is the logger of the run; nil is the global logger
+	Logger *zap.Logger
+}
+
+// logger is info.Logger, or the global logger if none was set
+func (info FitnessSetupInformation) logger() *zap.Logger {
+	if info.Logger == nil {
+		return zap.L()
+	}
+	return info.Logger
 }
 
 func GenerateFitnessInfoFromConfig(config *cfg.Config, genomeType individual.GenomeType, grammar map[string]individual.Node, populations []*[]individual.Evolvable) FitnessSetupInformation {
@@ -40,7 +82,6 @@ func GenerateFitnessInfoFromConfig(config *cfg.Config, genomeType individual.Gen
 		fitnessInfo.MaxSteps = config.ActionTree.MaxSteps
 		fitnessInfo.Actions = config.ActionTree.Actions
 		fitnessInfo.Population = populations
-		fitnessInfo.ActionTreeSelectionPercentage = 0.3
 	}
 
 	return fitnessInfo
@@ -54,38 +95,125 @@ func FitnessCalculatorFactoryWithConfig(info FitnessSetupInformation, config *cf
 	switch info.GenomeType {
 	case individual.TreeGenome:
 		calc := &TreeFitnessCalculator{}
-		calc.SetupEvalFunction(info.EvalFunction, info.VariableSet, info.TestCaseCount)
+		calc.SetupEvalFunction(info.EvalFunction, info.VariableSet, info.TestCaseCount, info.Rand)
 		return calc
 	case individual.BitStringGenome:
 		return &BinaryFitnessCalculator{}
 	case individual.GrammarTreeGenome:
 		calc := &GrammarTreeFitnessCalculator{Grammar: info.Grammar}
-		calc.SetupEvalFunction(info.EvalFunction, info.VariableSet, info.TestCaseCount)
+		calc.SetupEvalFunction(info.EvalFunction, info.VariableSet, info.TestCaseCount, info.Rand)
 		return calc
 	case individual.ActionTreeGenome:
-		// Extract config values with defaults
-		poolSize := 10
-		timeout := 30 * time.Second
+		calc := newActionTreeFitnessCalculator(info, config)
+		if calc == nil {
+			// Keep the interface nil rather than holding a nil pointer
+			return nil
+		}
+		calc.SetLogger(info.logger())
 		if config != nil {
-			poolSize = config.ActionTree.ConnectionPoolSize
-			if parsedTimeout, err := time.ParseDuration(config.ActionTree.ConnectionTimeout); err == nil {
-				timeout = parsedTimeout
+			backoff, _ := time.ParseDuration(config.ActionTree.RetryBackoff)
+			calc.SetFailurePolicy(config.ActionTree.GameRetries, backoff, NewCircuitBreaker(config.ActionTree.MaxGameFailures))
+			if dir := config.ActionTree.ReplayDir; dir != "" {
+				minReward := math.Inf(-1)
+				if config.ActionTree.ReplayMinReward != nil {
+					minReward = *config.ActionTree.ReplayMinReward
+				}
+				if err := calc.EnableReplays(dir, minReward, config.ActionTree.ReplayGzip); err != nil {
+					info.logger().Error("Failed to enable replays", zap.Error(err))
+					return nil
+				}
 			}
 		}
+		return calc
+	default:
+		return nil
+	}
+}
 
-		calc := NewActionTreeFitnessCalculator(
-			info.ServerAddr,
-			info.OpponentType,
+// newActionTreeFitnessCalculator creates the calculator for the configured backend, nil on error
+func newActionTreeFitnessCalculator(info FitnessSetupInformation, config *cfg.Config) *ActionTreeFitnessCalculator {
+	if config != nil && config.ActionTree.Backend == "native" {
+		settings := environment.Settings{Opponent: info.OpponentType, MaxSteps: info.MaxSteps}
+		name, seed := config.ActionTree.Environment, config.ActionTree.GameSeed
+		calc, err := NewEnvironmentActionTreeFitnessCalculator(
+			func(testCase int, clientId string) (environment.GameEnvironment, error) {
+				return environment.New(name, seed+int64(testCase), settings)
+			},
 			info.Actions,
 			info.MaxSteps,
 			info.Population,
-			info.ActionTreeSelectionPercentage,
-			poolSize,
 			config.Fitness.TestCaseCount,
-			timeout,
 		)
+		if err != nil {
+			info.logger().Error("Failed to create environment fitness calculator", zap.String("environment", name), zap.Error(err))
+			return nil
+		}
+		if lc := config.ActionTree.League; lc.Enabled {
+			if err := enableLeague(calc, lc, seed, settings, info.Rand); err != nil {
+				info.logger().Error("Failed to set up league", zap.Error(err))
+				return nil
+			}
+		}
 		return calc
-	default:
+	}
+
+	// Games on the server resolve the layout per game, so catch bad declarations up front
+	if err := ValidateActionLayout(info.Actions); err != nil {
+		info.logger().Error("Invalid action layout", zap.Error(err))
 		return nil
 	}
+
+	// Extract config values with defaults
+	poolSize := 10
+	timeout := 30 * time.Second
+	if config != nil {
+		poolSize = config.ActionTree.ConnectionPoolSize
+		if parsedTimeout, err := time.ParseDuration(config.ActionTree.ConnectionTimeout); err == nil {
+			timeout = parsedTimeout
+		}
+	}
+
+	if config != nil && config.ActionTree.Multiplex {
+		batchWindow, _ := time.ParseDuration(config.ActionTree.BatchWindow)
+		return NewMultiplexActionTreeFitnessCalculator(
+			NewMultiplexClient(info.ServerAddr, timeout, batchWindow),
+			info.OpponentType,
+			info.Actions,
+			info.MaxSteps,
+			info.Population,
+			config.Fitness.TestCaseCount,
+		)
+	}
+
+	calc := NewActionTreeFitnessCalculator(
+		info.ServerAddr,
+		info.OpponentType,
+		info.Actions,
+		info.MaxSteps,
+		info.Population,
+		poolSize,
+		config.Fitness.TestCaseCount,
+		timeout,
+	)
+	return calc
+}
+
+// enableLeague switches a native Generals calculator to self-play against a league
+func enableLeague(calc *ActionTreeFitnessCalculator, lc cfg.LeagueConfig, seed int64, settings environment.Settings, r *rng.Rand) error {
+	l, err := league.New(league.Config{
+		Sampling:       lc.Sampling,
+		HallOfFameSize: lc.HallOfFameSize,
+		EliteCount:     lc.EliteCount,
+		KFactor:        lc.KFactor,
+		InitialRating:  lc.InitialRating,
+		Rand:           r,
+	})
+	if err != nil {
+		return err
+	}
+	return calc.EnableLeague(l, func(testCase int, opponent generals.Bot) (environment.GameEnvironment, error) {
+		gameSettings := settings
+		gameSettings.OpponentBot = opponent
+		return environment.New("generals", seed+int64(testCase), gameSettings)
+	}, lc.Fitness == "rating")
 }
@@ -1,6 +1,9 @@
 package fitness
 
-import "github.com/bxrne/darwin/internal/individual"
+import (
+	"github.com/bxrne/darwin/internal/individual"
+	"github.com/bxrne/darwin/internal/rng"
+)
 
 type GrammarTreeFitnessCalculator struct {
 	TestCases     []map[string]float64
@@ -19,8 +22,8 @@ func (gtreeCalculator *GrammarTreeFitnessCalculator) CalculateFitness(evolvable
 
 }
 
-func (fitnessCalc *GrammarTreeFitnessCalculator) SetupEvalFunction(evalFunction string, variableSet []string, testCaseCount int) {
-	testCases, targetResults := SetupEvalFunction(evalFunction, variableSet, testCaseCount)
+func (fitnessCalc *GrammarTreeFitnessCalculator) SetupEvalFunction(evalFunction string, variableSet []string, testCaseCount int, r *rng.Rand) {
+	testCases, targetResults := SetupEvalFunction(evalFunction, variableSet, testCaseCount, r)
 	fitnessCalc.TestCases = testCases
 	fitnessCalc.TargetResults = targetResults
 }
@@ -0,0 +1,36 @@
+package fitness
+
+import (
+	"math/rand/v2"
+
+	"github.com/bxrne/darwin/internal/generals"
+	"github.com/bxrne/darwin/internal/individual"
+)
+
+// PolicyBot plays Generals with an evolved action tree and weights, so individuals can face each other
+type PolicyBot struct {
+	executor *ActionExecutor
+	trees    *individual.ActionTreeIndividual
+	weights  *individual.WeightsIndividual
+	tracker  []bool
+}
+
+// NewPolicyBot creates a bot choosing actions with the given layout, resolved for Generals
+func NewPolicyBot(actions []individual.ActionTuple, trees *individual.ActionTreeIndividual, weights *individual.WeightsIndividual) *PolicyBot {
+	return &PolicyBot{
+		executor: NewActionExecutor(actions),
+		trees:    trees,
+		weights:  weights,
+		tracker:  make([]bool, len(actions)),
+	}
+}
+
+// Act implements generals.Bot from the bot's own fogged observation
+func (b *PolicyBot) Act(obs *generals.Observation, r *rand.Rand) generals.Action {
+	b.executor.validator.SetMountains(obs.Mountains)
+	action, err := b.executor.ExecuteActionTreesWithSoftmax(b.trees, b.weights, generals.Features(obs), generals.ValidStartPoints(obs), &b.tracker)
+	if err != nil {
+		return generals.Action{Pass: true}
+	}
+	return generals.ActionFromVector(action)
+}
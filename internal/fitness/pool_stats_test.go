@@ -0,0 +1,36 @@
+package fitness_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bxrne/darwin/internal/environment"
+	"github.com/bxrne/darwin/internal/fitness"
+	"github.com/bxrne/darwin/internal/individual"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionTreeFitnessCalculator_GIVEN_connection_pool_WHEN_pool_stats_THEN_reports_pool(t *testing.T) {
+	actions := []individual.ActionTuple{{Name: "pass", Value: 2}}
+	weights, trees := []individual.Evolvable{}, []individual.Evolvable{}
+	calc := fitness.NewActionTreeFitnessCalculator("localhost:1", "random", actions, 10, []*[]individual.Evolvable{&weights, &trees}, 4, 1, time.Second)
+	defer calc.Close()
+
+	stats := calc.PoolStats()
+
+	assert.Equal(t, "localhost:1", stats["server_addr"])
+	assert.Equal(t, 4, stats["max_connections"])
+	assert.Equal(t, 0, stats["active_count"])
+}
+
+func TestActionTreeFitnessCalculator_GIVEN_in_process_environments_WHEN_pool_stats_THEN_nil(t *testing.T) {
+	actions := []individual.ActionTuple{{Name: "direction", Value: 4}}
+	weights, trees := []individual.Evolvable{}, []individual.Evolvable{}
+	calc, err := fitness.NewEnvironmentActionTreeFitnessCalculator(func(testCase int, clientId string) (environment.GameEnvironment, error) {
+		return environment.New("gridworld", 1, environment.Settings{MaxSteps: 5})
+	}, actions, 5, []*[]individual.Evolvable{&weights, &trees}, 1)
+	require.NoError(t, err)
+
+	assert.Nil(t, calc.PoolStats())
+}
@@ -0,0 +1,67 @@
+package fitness_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/bxrne/darwin/internal/environment"
+	"github.com/bxrne/darwin/internal/fitness"
+	"github.com/bxrne/darwin/internal/individual"
+	"github.com/bxrne/darwin/internal/replay"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionTreeFitnessCalculator_GIVEN_replays_enabled_WHEN_game_played_THEN_records_decisions(t *testing.T) {
+	dir := t.TempDir()
+	actions := []individual.ActionTuple{{Name: "direction", Value: 4}}
+	weights := []individual.Evolvable{individual.NewWeightsIndividual(nil, 4, 2)}
+	trees := []individual.Evolvable{individual.NewRandomActionTreeIndividual(nil, actions, 2, []string{"+"}, []string{"goal_dx", "goal_dy"}, []string{"1"})}
+	calc, err := fitness.NewEnvironmentActionTreeFitnessCalculator(func(testCase int, clientId string) (environment.GameEnvironment, error) {
+		return environment.New("gridworld", 1, environment.Settings{MaxSteps: 10})
+	}, actions, 10, []*[]individual.Evolvable{&weights, &trees}, 1)
+	require.NoError(t, err)
+	require.NoError(t, calc.EnableReplays(dir, -1000, true))
+
+	reward := calc.CalculateTeamFitness([]individual.Evolvable{weights[0], trees[0]}, 1)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.jsonl.gz"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	r, err := replay.Read(files[0])
+	require.NoError(t, err)
+	assert.Equal(t, []string{"direction"}, r.Actions)
+	assert.Contains(t, r.Trees, "direction")
+	assert.Len(t, r.Steps, r.Header.Steps)
+	assert.Greater(t, len(r.Steps), 1)
+
+	first, decided := r.Steps[0], r.Steps[1]
+	assert.Equal(t, []int{0}, first.Action)
+	assert.Len(t, decided.Board, 8)
+	assert.NotContains(t, decided.Features, "w0", "the weights fed to the trees are not observations")
+	require.Len(t, decided.Heads, 1)
+	head := decided.Heads[0]
+	assert.Len(t, head.Outputs, 4)
+	assert.InDelta(t, 1.0, head.Probabilities[0]+head.Probabilities[1]+head.Probabilities[2]+head.Probabilities[3], 1e-9)
+	assert.Equal(t, []int{head.Chosen}, decided.Action)
+	// Fitness is the Score of the reward, so only its sign is comparable
+	assert.Equal(t, r.TotalReward > 0, reward > 0)
+}
+
+func TestActionTreeFitnessCalculator_GIVEN_game_below_threshold_WHEN_played_THEN_no_replay(t *testing.T) {
+	dir := t.TempDir()
+	actions := []individual.ActionTuple{{Name: "direction", Value: 4}}
+	weights := []individual.Evolvable{individual.NewWeightsIndividual(nil, 4, 2)}
+	trees := []individual.Evolvable{individual.NewRandomActionTreeIndividual(nil, actions, 2, []string{"+"}, []string{"goal_dx"}, []string{"1"})}
+	calc, err := fitness.NewEnvironmentActionTreeFitnessCalculator(func(testCase int, clientId string) (environment.GameEnvironment, error) {
+		return environment.New("gridworld", 1, environment.Settings{MaxSteps: 5})
+	}, actions, 5, []*[]individual.Evolvable{&weights, &trees}, 1)
+	require.NoError(t, err)
+	require.NoError(t, calc.EnableReplays(dir, 1000, false))
+
+	calc.CalculateTeamFitness([]individual.Evolvable{weights[0], trees[0]}, 1)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
@@ -2,7 +2,6 @@ package fitness
 
 import (
 	"fmt"
-	"net"
 	"time"
 
 	"go.uber.org/zap"
@@ -12,6 +11,7 @@ import (
 type ServerHealthChecker struct {
 	serverAddr string
 	timeout    time.Duration
+	logger     *zap.Logger
 }
 
 // NewServerHealthChecker creates a new health checker
@@ -22,13 +22,26 @@ func NewServerHealthChecker(serverAddr string, timeout time.Duration) *ServerHea
 	}
 }
 
+// SetLogger logs the checks to logger rather than the global logger
+func (shc *ServerHealthChecker) SetLogger(logger *zap.Logger) {
+	shc.logger = logger
+}
+
+// log is the checker's logger, or the global logger if none was set
+func (shc *ServerHealthChecker) log() *zap.Logger {
+	if shc.logger == nil {
+		return zap.L()
+	}
+	return shc.logger
+}
+
 // CheckServerHealth performs a health check on the game server
 // It sends a HEALTH message (which does not start a game) and waits for a response
 func (shc *ServerHealthChecker) CheckServerHealth() error {
-	zap.L().Info("Checking game server health", zap.String("server", shc.serverAddr))
+	shc.log().Info("Checking game server health", zap.String("server", shc.serverAddr))
 
-	// Create a temporary connection for health check
-	client := NewTCPClient(shc.serverAddr)
+	// Create a temporary connection for health check, bounding every message by the timeout
+	client := NewTCPClientWithTimeout(shc.serverAddr, shc.timeout)
 
 	// Try to connect with timeout - this verifies the server is listening
 	if err := client.Connect(); err != nil {
@@ -41,48 +54,31 @@ func (shc *ServerHealthChecker) CheckServerHealth() error {
 		return fmt.Errorf("server health check failed: failed to send health message: %w", err)
 	}
 
-	// Wait for health response with timeout
-	// Set a read timeout on the connection
-	if tcpConn, ok := client.conn.(*net.TCPConn); ok {
-		err_inner := tcpConn.SetReadDeadline(time.Now().Add(shc.timeout))
-		if err_inner != nil {
-			zap.L().Warn("Failed to set read deadline", zap.Error(err_inner))
-		}
-	}
-
 	healthResp, err := client.ReceiveHealthResponse()
 	if err != nil {
 		err_inner := client.Disconnect()
 		if err_inner != nil {
-			zap.L().Warn("Failed to disconnect", zap.Error(err_inner))
+			shc.log().Warn("Failed to disconnect", zap.Error(err_inner))
 		}
 		return fmt.Errorf("server health check failed: failed to receive health response: %w", err)
 	}
 
-	// Clear the read deadline
-	if tcpConn, ok := client.conn.(*net.TCPConn); ok {
-		err_inner := tcpConn.SetReadDeadline(time.Time{})
-		if err_inner != nil {
-			zap.L().Warn("Failed to set read deadline", zap.Error(err_inner))
-		}
-	}
-
 	// Verify the response indicates healthy status
 	if healthResp.Status != "ok" {
 		err_inner := client.Disconnect()
 		if err_inner != nil {
-			zap.L().Warn("Failed to disconnect", zap.Error(err_inner))
+			shc.log().Warn("Failed to disconnect", zap.Error(err_inner))
 		}
 		return fmt.Errorf("server health check failed: server returned non-ok status: %s", healthResp.Status)
 	}
 
 	// Disconnect cleanly
 	if err := client.Disconnect(); err != nil {
-		zap.L().Warn("Failed to disconnect health check connection", zap.Error(err))
+		shc.log().Warn("Failed to disconnect health check connection", zap.Error(err))
 		// Don't fail the health check if disconnect fails - health check was successful
 	}
 
-	zap.L().Info("Server health check passed", zap.String("server", shc.serverAddr), zap.String("status", healthResp.Status))
+	shc.log().Info("Server health check passed", zap.String("server", shc.serverAddr), zap.String("status", healthResp.Status))
 	return nil
 }
 
@@ -90,7 +86,7 @@ func (shc *ServerHealthChecker) CheckServerHealth() error {
 func (shc *ServerHealthChecker) CheckServerHealthWithRetry() error {
 	err := shc.CheckServerHealth()
 	if err != nil {
-		zap.L().Warn("Server health check failed, retrying once", zap.Error(err))
+		shc.log().Warn("Server health check failed, retrying once", zap.Error(err))
 		time.Sleep(1 * time.Second) // Brief delay before retry
 		return shc.CheckServerHealth()
 	}
@@ -26,6 +26,8 @@ const (
 	SaveReplay        MessageType = "save_replay"
 	Health            MessageType = "health"
 	HealthResponseMsg MessageType = "health_response"
+	Ping              MessageType = "ping"
+	Pong              MessageType = "pong"
 )
 
 // Message structures matching Python payloads
@@ -49,6 +51,10 @@ type HealthRequest struct {
 	Type string `json:"type"`
 }
 
+type PingRequest struct {
+	Type string `json:"type"`
+}
+
 type HealthResponse struct {
 	Type    string `json:"type"`
 	Status  string `json:"status"`
@@ -89,19 +95,36 @@ type TCPClient struct {
 	conn       net.Conn
 	reader     *bufio.Reader
 	serverAddr string
+	// readTimeout and writeTimeout bound each message; zero waits forever
+	readTimeout  time.Duration
+	writeTimeout time.Duration
 }
 
-// NewTCPClient creates a new TCP client
+// NewTCPClient creates a new TCP client without message deadlines
 func NewTCPClient(serverAddr string) *TCPClient {
 	return &TCPClient{
 		serverAddr: serverAddr,
 	}
 }
 
+// NewTCPClientWithTimeout creates a TCP client whose dial and every message read and write must
+// finish within timeout, so a hung server fails the game instead of stalling it
+func NewTCPClientWithTimeout(serverAddr string, timeout time.Duration) *TCPClient {
+	return &TCPClient{
+		serverAddr:   serverAddr,
+		readTimeout:  timeout,
+		writeTimeout: timeout,
+	}
+}
+
 // Connect establishes connection to the game server
 func (tc *TCPClient) Connect() error {
+	dialTimeout := 10 * time.Second
+	if tc.writeTimeout > 0 {
+		dialTimeout = tc.writeTimeout
+	}
 	var err error
-	tc.conn, err = net.DialTimeout("tcp", tc.serverAddr, 10*time.Second)
+	tc.conn, err = net.DialTimeout("tcp", tc.serverAddr, dialTimeout)
 	if err != nil {
 		return fmt.Errorf("failed to connect to %s: %w", tc.serverAddr, err)
 	}
@@ -135,6 +158,12 @@ func (tc *TCPClient) SendMessage(message interface{}) error {
 	// Append newline as required by server protocol
 	data = append(data, '\n')
 
+	if tc.writeTimeout > 0 {
+		if err := tc.conn.SetWriteDeadline(time.Now().Add(tc.writeTimeout)); err != nil {
+			return fmt.Errorf("failed to set write deadline: %w", err)
+		}
+	}
+
 	_, err = tc.conn.Write(data)
 	if err != nil {
 		// Check for broken pipe specifically
@@ -153,6 +182,11 @@ func (tc *TCPClient) ReceiveMessage() (map[string]interface{}, error) {
 	if tc.reader == nil {
 		return nil, fmt.Errorf("not connected to server")
 	}
+	if tc.readTimeout > 0 {
+		if err := tc.conn.SetReadDeadline(time.Now().Add(tc.readTimeout)); err != nil {
+			return nil, fmt.Errorf("failed to set read deadline: %w", err)
+		}
+	}
 
 	line, err := tc.reader.ReadString('\n')
 	if err != nil {
@@ -258,7 +292,26 @@ func (tc *TCPClient) ReceiveHealthResponse() (*HealthResponse, error) {
 	return &resp, nil
 }
 
-// SendAction sends an action to the server
+// Ping checks that the server still answers on this connection. Messages left over from the
+// previous game are skipped; an error reply, from a server without ping support, also proves it
+// is alive.
+func (tc *TCPClient) Ping() error {
+	if err := tc.SendMessage(PingRequest{Type: string(Ping)}); err != nil {
+		return fmt.Errorf("failed to send ping: %w", err)
+	}
+	for {
+		msg, err := tc.ReceiveMessage()
+		if err != nil {
+			return fmt.Errorf("failed to receive pong: %w", err)
+		}
+		msgType, _ := msg["type"].(string)
+		if MessageType(msgType) == Pong || MessageType(msgType) == Error {
+			return nil
+		}
+	}
+}
+
+// RequestReplay asks the server to store the replay of the current game
 func (tc *TCPClient) RequestReplay() error {
 	actionReq := SaveReplayRequest{
 		Type: string(SaveReplay),
@@ -289,48 +342,57 @@ func (tc *TCPClient) ReceiveObservation() (*ObservationResponse, error) {
 			continue
 		}
 
-		switch MessageType(msgType) {
-		case Observation:
-			// Debug log raw JSON message
-			// logmgr.Debug("Raw observation message",
-			// 	logmgr.Field("message", fmt.Sprintf("%+v", msg)))
-			var resp ObservationResponse
-			data, _ := json.Marshal(msg)
-			err = json.Unmarshal(data, &resp)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse observation response: %w", err)
-			}
-			// Debug log received observation
-			zap.L().Debug("Received observation",
-				zap.Float64("reward", resp.Reward),
-				zap.Bool("terminated", resp.Terminated))
-			return &resp, nil
+		resp, ok, err := observationFromMessage(MessageType(msgType), msg)
+		if ok || err != nil {
+			return resp, err
+		}
+	}
+}
 
-		case GameOver:
-			// Game over is also a valid observation response
-			var resp ObservationResponse
-			data, _ := json.Marshal(msg)
-			err = json.Unmarshal(data, &resp)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse game over response: %w", err)
-			}
-			resp.Terminated = true
-			return &resp, nil
+// observationFromMessage decodes an observation, game over or error message. ok is false for
+// other message types, which the caller skips.
+func observationFromMessage(msgType MessageType, msg map[string]interface{}) (*ObservationResponse, bool, error) {
+	switch msgType {
+	case Observation:
+		var resp ObservationResponse
+		data, _ := json.Marshal(msg)
+		err := json.Unmarshal(data, &resp)
+		if err != nil {
+			return nil, true, fmt.Errorf("failed to parse observation response: %w", err)
+		}
+		// Debug log received observation
+		zap.L().Debug("Received observation",
+			zap.Float64("reward", resp.Reward),
+			zap.Bool("terminated", resp.Terminated))
+		return &resp, true, nil
+
+	case GameOver:
+		// Game over is also a valid observation response
+		var resp ObservationResponse
+		data, _ := json.Marshal(msg)
+		err := json.Unmarshal(data, &resp)
+		if err != nil {
+			return nil, true, fmt.Errorf("failed to parse game over response: %w", err)
+		}
+		resp.Terminated = true
+		return &resp, true, nil
 
-		case Error:
-			var errResp ErrorResponse
-			data, _ := json.Marshal(msg)
-			err = json.Unmarshal(data, &errResp)
-			if err == nil {
-				return nil, fmt.Errorf("server error: %s - %s", errResp.Message, errResp.Details)
-			}
-			return nil, fmt.Errorf("server error: %v", msg)
+	case Error:
+		return nil, true, serverError(msg)
 
-		default:
-			// Ignore other message types
-			continue
-		}
+	default:
+		return nil, false, nil
+	}
+}
+
+// serverError converts an error message into an error
+func serverError(msg map[string]interface{}) error {
+	var errResp ErrorResponse
+	data, _ := json.Marshal(msg)
+	if err := json.Unmarshal(data, &errResp); err == nil {
+		return fmt.Errorf("server error: %s - %s", errResp.Message, errResp.Details)
 	}
+	return fmt.Errorf("server error: %v", msg)
 }
 
 // WaitForGameOver waits specifically for a game over message
@@ -0,0 +1,220 @@
+package fitness_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bxrne/darwin/internal/fitness"
+	"github.com/bxrne/darwin/internal/mockserver"
+	"github.com/stretchr/testify/assert"
+)
+
+// startServer runs a mock game server for the duration of the test
+func startServer(t *testing.T, scenario mockserver.Scenario) *mockserver.Server {
+	server, err := mockserver.Start(scenario)
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = server.Close() })
+	return server
+}
+
+// playGame resets a TCP environment and steps it until the game ends or maxSteps actions are sent
+func playGame(t *testing.T, pool *fitness.TCPConnectionPool, maxSteps int) ([]float64, error) {
+	env := fitness.NewTCPEnvironment(pool, "client_1", "random", 8, 8)
+	defer env.Close()
+
+	obs, err := env.Reset()
+	if err != nil {
+		return nil, err
+	}
+	assert.Len(t, obs.Grid, 8)
+
+	var rewards []float64
+	for range maxSteps {
+		obs, err = env.Step([]int{0, 1, 1, 0, 0})
+		if err != nil {
+			return rewards, err
+		}
+		rewards = append(rewards, obs.Reward)
+		if obs.Terminated || obs.Truncated {
+			break
+		}
+	}
+	return rewards, nil
+}
+
+func TestServerHealthChecker_GIVEN_healthy_server_WHEN_checked_THEN_passes(t *testing.T) {
+	server := startServer(t, mockserver.Scenario{})
+
+	err := fitness.NewServerHealthChecker(server.Addr(), time.Second).CheckServerHealth()
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), server.Stats().HealthChecks)
+	assert.Equal(t, int64(0), server.Stats().Games)
+}
+
+func TestServerHealthChecker_GIVEN_degraded_server_WHEN_checked_THEN_reports_status(t *testing.T) {
+	server := startServer(t, mockserver.Scenario{HealthStatus: "degraded"})
+
+	err := fitness.NewServerHealthChecker(server.Addr(), time.Second).CheckServerHealth()
+
+	assert.ErrorContains(t, err, "degraded")
+}
+
+func TestServerHealthChecker_GIVEN_slow_server_WHEN_checked_THEN_times_out(t *testing.T) {
+	server := startServer(t, mockserver.Scenario{Delay: 500 * time.Millisecond})
+
+	err := fitness.NewServerHealthChecker(server.Addr(), 50*time.Millisecond).CheckServerHealth()
+
+	assert.ErrorContains(t, err, "failed to receive health response")
+}
+
+func TestTCPEnvironment_GIVEN_scripted_rewards_WHEN_played_THEN_game_ends_at_termination(t *testing.T) {
+	server := startServer(t, mockserver.Scenario{Rewards: []float64{1, 2, 3, 4}, TerminateAt: 3})
+	pool := fitness.NewTCPConnectionPool(server.Addr(), 1, time.Second)
+	defer pool.Close()
+
+	rewards, err := playGame(t, pool, 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 2, 3}, rewards)
+	assert.Equal(t, int64(3), server.Stats().Actions)
+}
+
+func TestTCPEnvironment_GIVEN_truncation_WHEN_played_THEN_game_stops(t *testing.T) {
+	server := startServer(t, mockserver.Scenario{TruncateAt: 2})
+	pool := fitness.NewTCPConnectionPool(server.Addr(), 1, time.Second)
+	defer pool.Close()
+
+	rewards, err := playGame(t, pool, 10)
+
+	assert.NoError(t, err)
+	assert.Len(t, rewards, 2)
+}
+
+func TestTCPEnvironment_GIVEN_pooled_connection_WHEN_second_game_played_THEN_connection_is_reused(t *testing.T) {
+	server := startServer(t, mockserver.Scenario{TerminateAt: 1})
+	pool := fitness.NewTCPConnectionPool(server.Addr(), 1, time.Second)
+	defer pool.Close()
+
+	for range 2 {
+		_, err := playGame(t, pool, 5)
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, int64(1), server.Stats().Connections)
+	assert.Equal(t, int64(2), server.Stats().Games)
+}
+
+func TestTCPEnvironment_GIVEN_connect_rejected_WHEN_reset_THEN_returns_server_error(t *testing.T) {
+	server := startServer(t, mockserver.Scenario{ConnectError: "Server full"})
+	pool := fitness.NewTCPConnectionPool(server.Addr(), 1, time.Second)
+	defer pool.Close()
+
+	_, err := playGame(t, pool, 5)
+
+	assert.ErrorContains(t, err, "Server full")
+}
+
+func TestTCPEnvironment_GIVEN_malformed_reply_WHEN_stepped_THEN_returns_error(t *testing.T) {
+	server := startServer(t, mockserver.Scenario{Rewards: []float64{1, 1}, MalformedAt: 2})
+	pool := fitness.NewTCPConnectionPool(server.Addr(), 1, time.Second)
+	defer pool.Close()
+
+	rewards, err := playGame(t, pool, 5)
+
+	assert.ErrorContains(t, err, "failed to unmarshal message")
+	assert.Equal(t, []float64{1}, rewards)
+}
+
+func TestTCPEnvironment_GIVEN_dropped_connection_WHEN_stepped_THEN_returns_error(t *testing.T) {
+	server := startServer(t, mockserver.Scenario{DropAt: 3})
+	pool := fitness.NewTCPConnectionPool(server.Addr(), 1, time.Second)
+	defer pool.Close()
+
+	rewards, err := playGame(t, pool, 5)
+
+	assert.ErrorContains(t, err, "failed to read message")
+	assert.Len(t, rewards, 2)
+}
+
+func TestTCPClient_GIVEN_action_without_game_WHEN_sent_THEN_returns_server_error(t *testing.T) {
+	server := startServer(t, mockserver.Scenario{})
+	client := fitness.NewTCPClient(server.Addr())
+	assert.NoError(t, client.Connect())
+	defer client.Disconnect()
+
+	assert.NoError(t, client.SendAction([]int{0, 0, 0, 0, 0}))
+	_, err := client.ReceiveObservation()
+
+	assert.ErrorContains(t, err, "No active game")
+	assert.Equal(t, int64(1), server.Stats().Errors)
+}
+
+func TestTCPClient_GIVEN_replay_request_WHEN_sent_THEN_server_records_it(t *testing.T) {
+	server := startServer(t, mockserver.Scenario{})
+	client := fitness.NewTCPClient(server.Addr())
+	assert.NoError(t, client.Connect())
+
+	assert.NoError(t, client.RequestReplay())
+	// A health check on the same connection is answered after the replay request has been handled
+	assert.NoError(t, client.SendHealthCheck())
+	_, err := client.ReceiveHealthResponse()
+	assert.NoError(t, err)
+	_ = client.Disconnect()
+
+	assert.Equal(t, int64(1), server.Stats().Replays)
+}
+
+func TestTCPClient_GIVEN_hung_server_WHEN_receiving_THEN_read_deadline_fails_the_read(t *testing.T) {
+	server := startServer(t, mockserver.Scenario{Delay: 500 * time.Millisecond})
+	client := fitness.NewTCPClientWithTimeout(server.Addr(), 50*time.Millisecond)
+	assert.NoError(t, client.Connect())
+	defer client.Disconnect()
+
+	_, err := client.ConnectToGame("client_1", "random")
+
+	assert.ErrorContains(t, err, "i/o timeout")
+}
+
+func TestTCPClient_GIVEN_leftover_messages_WHEN_pinged_THEN_skips_to_pong(t *testing.T) {
+	server := startServer(t, mockserver.Scenario{})
+	client := fitness.NewTCPClientWithTimeout(server.Addr(), time.Second)
+	assert.NoError(t, client.Connect())
+	defer client.Disconnect()
+	// The connected reply and first observation are never read
+	_, err := client.ConnectToGame("client_1", "random")
+	assert.NoError(t, err)
+
+	assert.NoError(t, client.Ping())
+	assert.Equal(t, int64(1), server.Stats().Pings)
+}
+
+func TestTCPConnectionPool_GIVEN_dropped_idle_connection_WHEN_reused_THEN_ping_replaces_it(t *testing.T) {
+	server := startServer(t, mockserver.Scenario{TerminateAt: 1})
+	pool := fitness.NewTCPConnectionPool(server.Addr(), 1, time.Second)
+	defer pool.Close()
+
+	_, err := playGame(t, pool, 5)
+	assert.NoError(t, err)
+	server.DropConnections()
+	_, err = playGame(t, pool, 5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), server.Stats().Connections)
+	assert.Equal(t, int64(2), server.Stats().Games)
+}
+
+func TestTCPConnectionPool_GIVEN_failed_game_WHEN_closed_THEN_connection_is_discarded(t *testing.T) {
+	server := startServer(t, mockserver.Scenario{MalformedAt: 1})
+	pool := fitness.NewTCPConnectionPool(server.Addr(), 1, time.Second)
+	defer pool.Close()
+
+	_, err := playGame(t, pool, 5)
+	assert.Error(t, err)
+	server.SetScenario(mockserver.Scenario{TerminateAt: 1})
+	_, err = playGame(t, pool, 5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), server.Stats().Connections)
+	assert.Equal(t, int64(0), server.Stats().Pings)
+}
@@ -55,7 +55,12 @@ func (p *TCPConnectionPool) GetConnection() (*TCPClient, error) {
 
 		select {
 		case client := <-p.connections:
-			if err := p.healthCheckUnlocked(client); err != nil {
+			// Ping idle connections before reuse, without holding up other callers
+			p.mu.Unlock()
+			err := client.Ping()
+			p.mu.Lock()
+			if err != nil {
+				zap.L().Debug("Dropping dead pooled connection", zap.Error(err))
 				if err := client.Disconnect(); err != nil {
 					zap.L().Warn("Failed to disconnect client", zap.Error(err))
 				}
@@ -112,6 +117,17 @@ func (p *TCPConnectionPool) ReturnConnection(client *TCPClient) error {
 	return nil
 }
 
+// DiscardConnection closes a connection whose game failed, as it may be left mid-message
+func (p *TCPConnectionPool) DiscardConnection(client *TCPClient) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := client.Disconnect(); err != nil {
+		zap.L().Warn("Failed to disconnect client", zap.Error(err))
+	}
+	p.activeCount--
+	p.cond.Signal()
+}
+
 // ─────────────────────────────
 //
 //	Close()
@@ -160,7 +176,7 @@ func (p *TCPConnectionPool) HealthCheck() error {
 		}
 	}()
 
-	return p.healthCheckUnlocked(client)
+	return client.Ping()
 }
 
 // ─────────────────────────────
@@ -188,7 +204,7 @@ func (p *TCPConnectionPool) GetStats() map[string]interface{} {
 //
 // ─────────────────────────────
 func (p *TCPConnectionPool) createNewConnectionUnlocked() (*TCPClient, error) {
-	client := NewTCPClient(p.serverAddr)
+	client := NewTCPClientWithTimeout(p.serverAddr, p.timeout)
 
 	if err := client.Connect(); err != nil {
 		return nil, fmt.Errorf("failed to create new connection: %w", err)
@@ -0,0 +1,116 @@
+package fitness
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bxrne/darwin/internal/environment"
+	"github.com/bxrne/darwin/internal/generals"
+	"go.uber.org/zap"
+)
+
+// TCPEnvironment plays one game on the Python game server using a pooled connection
+type TCPEnvironment struct {
+	pool         *TCPConnectionPool
+	client       *TCPClient
+	clientId     string
+	opponentType string
+	rows         int
+	cols         int
+	// failed marks a connection left in an unknown state by an error
+	failed bool
+}
+
+// NewTCPEnvironment creates an environment that connects on Reset and returns its connection on Close
+func NewTCPEnvironment(pool *TCPConnectionPool, clientId string, opponentType string, rows int, cols int) *TCPEnvironment {
+	return &TCPEnvironment{pool: pool, clientId: clientId, opponentType: opponentType, rows: rows, cols: cols}
+}
+
+// Reset implements environment.GameEnvironment
+func (e *TCPEnvironment) Reset() (*environment.Observation, error) {
+	client, err := e.pool.GetConnection()
+	if err != nil {
+		return nil, fmt.Errorf("connection pool error: %w", err)
+	}
+	e.client = client
+
+	// Small delay to ensure connection is stable
+	time.Sleep(100 * time.Millisecond)
+
+	connectedResp, err := client.ConnectToGame(e.clientId, e.opponentType)
+	if err != nil {
+		e.failed = true
+		return nil, fmt.Errorf("game connection error: %w", err)
+	}
+	zap.L().Debug("Connected to game",
+		zap.String("agent_id", connectedResp.AgentID),
+		zap.String("opponent_id", connectedResp.OpponentID))
+
+	obs, err := client.ReceiveObservation()
+	if err != nil {
+		e.failed = true
+		return nil, err
+	}
+	return toObservation(obs), nil
+}
+
+// Step implements environment.GameEnvironment
+func (e *TCPEnvironment) Step(action []int) (*environment.Observation, error) {
+	if e.client == nil {
+		return nil, fmt.Errorf("step before reset")
+	}
+	if err := e.client.SendAction(action); err != nil {
+		e.failed = true
+		return nil, err
+	}
+	obs, err := e.client.ReceiveObservation()
+	if err != nil {
+		e.failed = true
+		return nil, err
+	}
+	return toObservation(obs), nil
+}
+
+// ObservationSchema implements environment.GameEnvironment
+func (e *TCPEnvironment) ObservationSchema() []string {
+	return generals.FeatureNames
+}
+
+// ActionSpace implements environment.GameEnvironment
+func (e *TCPEnvironment) ActionSpace() environment.ActionSpace {
+	return environment.GeneralsActionSpace(e.rows, e.cols)
+}
+
+// RequestReplay asks the server to store the replay of this game
+func (e *TCPEnvironment) RequestReplay() error {
+	if e.client == nil {
+		return fmt.Errorf("no game in progress")
+	}
+	return e.client.RequestReplay()
+}
+
+// Close implements environment.GameEnvironment, returning the connection to the pool, or
+// discarding it if the game failed
+func (e *TCPEnvironment) Close() error {
+	if e.client == nil {
+		return nil
+	}
+	client := e.client
+	e.client = nil
+	if e.failed {
+		e.pool.DiscardConnection(client)
+		return nil
+	}
+	return e.pool.ReturnConnection(client)
+}
+
+// toObservation converts a server message into the environment observation
+func toObservation(obs *ObservationResponse) *environment.Observation {
+	return &environment.Observation{
+		Features:   obs.Observation,
+		Reward:     obs.Reward,
+		Terminated: obs.Terminated,
+		Truncated:  obs.Truncated,
+		Grid:       obs.Info,
+	}
+}
@@ -0,0 +1,360 @@
+package fitness
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bxrne/darwin/internal/environment"
+	"github.com/bxrne/darwin/internal/generals"
+	"go.uber.org/zap"
+)
+
+// Message types only used by the multiplexed protocol
+const (
+	StepBatch MessageType = "step_batch"
+	CloseGame MessageType = "close_game"
+)
+
+// In the multiplexed protocol every message carries the game it belongs to, and the server tags
+// its replies with the same game_id
+type GameConnectRequest struct {
+	Type         string `json:"type"`
+	GameId       string `json:"game_id"`
+	ClientId     string `json:"client_id"`
+	AgentType    string `json:"agent_type"`
+	OpponentType string `json:"opponent_type"`
+}
+
+type GameStep struct {
+	GameId string      `json:"game_id"`
+	Action interface{} `json:"action"`
+}
+
+type GameActionRequest struct {
+	Type   string      `json:"type"`
+	GameId string      `json:"game_id"`
+	Action interface{} `json:"action"`
+}
+
+// StepBatchRequest steps several games at once; the server answers each game separately
+type StepBatchRequest struct {
+	Type  string     `json:"type"`
+	Steps []GameStep `json:"steps"`
+}
+
+// GameRequest is a message about a game without a payload: close_game and save_replay
+type GameRequest struct {
+	Type   string `json:"type"`
+	GameId string `json:"game_id"`
+}
+
+// MultiplexClient plays many concurrent games over one connection to the game server. Replies
+// are routed to their game by game_id, and actions queued within batchWindow of each other are
+// sent as one step_batch message. The connection is opened by the first game and reopened by the
+// next game after it breaks.
+type MultiplexClient struct {
+	serverAddr  string
+	timeout     time.Duration
+	batchWindow time.Duration
+
+	mu     sync.Mutex
+	conn   *muxConn
+	closed bool
+}
+
+// muxConn is one connection and the games playing on it
+type muxConn struct {
+	client  *TCPClient
+	writeMu sync.Mutex
+	steps   chan GameStep
+	done    chan struct{}
+
+	mu    sync.Mutex
+	games map[string]chan map[string]interface{}
+	err   error
+}
+
+// NewMultiplexClient creates a client that connects when the first game starts. timeout bounds
+// the wait for each reply.
+func NewMultiplexClient(serverAddr string, timeout time.Duration, batchWindow time.Duration) *MultiplexClient {
+	return &MultiplexClient{serverAddr: serverAddr, timeout: timeout, batchWindow: batchWindow}
+}
+
+// NewGame creates an environment playing one game on the shared connection
+func (mc *MultiplexClient) NewGame(gameId string, opponentType string, rows int, cols int) *MultiplexEnvironment {
+	return &MultiplexEnvironment{client: mc, gameId: gameId, opponentType: opponentType, rows: rows, cols: cols}
+}
+
+// Close closes the connection, failing the games still playing on it
+func (mc *MultiplexClient) Close() error {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.closed = true
+	if mc.conn == nil {
+		return nil
+	}
+	mc.conn.fail(fmt.Errorf("multiplex client is closed"))
+	mc.conn = nil
+	return nil
+}
+
+// open registers a game on the current connection, connecting first if there is none
+func (mc *MultiplexClient) open(gameId string) (*muxConn, chan map[string]interface{}, error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	if mc.closed {
+		return nil, nil, fmt.Errorf("multiplex client is closed")
+	}
+	if mc.conn == nil || mc.conn.broken() {
+		client := NewTCPClientWithTimeout(mc.serverAddr, mc.timeout)
+		// The connection may idle between games; replies are awaited per game instead
+		client.readTimeout = 0
+		if err := client.Connect(); err != nil {
+			return nil, nil, err
+		}
+		mc.conn = &muxConn{
+			client: client,
+			steps:  make(chan GameStep, 64),
+			done:   make(chan struct{}),
+			games:  make(map[string]chan map[string]interface{}),
+		}
+		go mc.conn.read()
+		go mc.conn.write(mc.batchWindow)
+	}
+
+	conn := mc.conn
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	if _, ok := conn.games[gameId]; ok {
+		return nil, nil, fmt.Errorf("game %s is already playing", gameId)
+	}
+	// Lock-step games have at most two replies in flight, e.g. an observation and game over
+	replies := make(chan map[string]interface{}, 4)
+	conn.games[gameId] = replies
+	return conn, replies, nil
+}
+
+// broken reports whether the connection has failed
+func (c *muxConn) broken() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err != nil
+}
+
+// read routes every received message to its game until the connection fails
+func (c *muxConn) read() {
+	for {
+		msg, err := c.client.ReceiveMessage()
+		if err != nil {
+			c.fail(err)
+			return
+		}
+		gameId, _ := msg["game_id"].(string)
+		c.mu.Lock()
+		replies, ok := c.games[gameId]
+		c.mu.Unlock()
+		if !ok {
+			zap.L().Debug("Dropped message for unknown game", zap.String("game_id", gameId), zap.Any("type", msg["type"]))
+			continue
+		}
+		select {
+		case replies <- msg:
+		default:
+			zap.L().Warn("Dropped message for game not reading its replies", zap.String("game_id", gameId))
+		}
+	}
+}
+
+// fail records why the connection broke and ends every game on it
+func (c *muxConn) fail(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return
+	}
+	c.err = err
+	close(c.done)
+	for gameId, replies := range c.games {
+		close(replies)
+		delete(c.games, gameId)
+	}
+	// Closing the socket rather than disconnecting the client leaves its fields to the reader
+	_ = c.client.conn.Close()
+}
+
+// write sends queued actions, batching those that arrive within window of the first
+func (c *muxConn) write(window time.Duration) {
+	for {
+		var batch []GameStep
+		select {
+		case step := <-c.steps:
+			batch = append(batch, step)
+		case <-c.done:
+			return
+		}
+
+		deadline := time.After(window)
+	collect:
+		for {
+			select {
+			case step := <-c.steps:
+				batch = append(batch, step)
+			case <-deadline:
+				break collect
+			case <-c.done:
+				return
+			}
+		}
+
+		var err error
+		if len(batch) == 1 {
+			err = c.send(GameActionRequest{Type: string(Action), GameId: batch[0].GameId, Action: batch[0].Action})
+		} else {
+			err = c.send(StepBatchRequest{Type: string(StepBatch), Steps: batch})
+		}
+		if err != nil {
+			c.fail(err)
+			return
+		}
+	}
+}
+
+// send writes one message, serialised with the other writers
+func (c *muxConn) send(message interface{}) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return c.client.SendMessage(message)
+}
+
+// close unregisters a game
+func (c *muxConn) close(gameId string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.games, gameId)
+}
+
+// MultiplexEnvironment plays one game over a MultiplexClient's shared connection
+type MultiplexEnvironment struct {
+	client       *MultiplexClient
+	conn         *muxConn
+	replies      chan map[string]interface{}
+	gameId       string
+	opponentType string
+	rows         int
+	cols         int
+}
+
+// Reset implements environment.GameEnvironment
+func (e *MultiplexEnvironment) Reset() (*environment.Observation, error) {
+	if e.conn == nil {
+		conn, replies, err := e.client.open(e.gameId)
+		if err != nil {
+			return nil, fmt.Errorf("multiplex connection error: %w", err)
+		}
+		e.conn, e.replies = conn, replies
+	}
+
+	err := e.conn.send(GameConnectRequest{
+		Type:         string(Connect),
+		GameId:       e.gameId,
+		ClientId:     e.gameId,
+		AgentType:    "human",
+		OpponentType: e.opponentType,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to send connect request: %w", err)
+	}
+	for {
+		msg, err := e.receive()
+		if err != nil {
+			return nil, fmt.Errorf("game connection error: %w", err)
+		}
+		msgType, _ := msg["type"].(string)
+		if MessageType(msgType) == Connected {
+			break
+		}
+		if MessageType(msgType) == Error {
+			return nil, fmt.Errorf("game connection error: %w", serverError(msg))
+		}
+	}
+	return e.observation()
+}
+
+// Step implements environment.GameEnvironment, queueing the action for the next batch
+func (e *MultiplexEnvironment) Step(action []int) (*environment.Observation, error) {
+	if e.conn == nil {
+		return nil, fmt.Errorf("step before reset")
+	}
+	select {
+	case e.conn.steps <- GameStep{GameId: e.gameId, Action: action}:
+	case <-e.conn.done:
+		return nil, fmt.Errorf("failed to send action: %w", e.conn.err)
+	}
+	return e.observation()
+}
+
+// observation waits for the game's next observation
+func (e *MultiplexEnvironment) observation() (*environment.Observation, error) {
+	for {
+		msg, err := e.receive()
+		if err != nil {
+			return nil, fmt.Errorf("failed to receive observation: %w", err)
+		}
+		msgType, _ := msg["type"].(string)
+		obs, ok, err := observationFromMessage(MessageType(msgType), msg)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return toObservation(obs), nil
+		}
+	}
+}
+
+// receive waits up to the client timeout for the game's next message
+func (e *MultiplexEnvironment) receive() (map[string]interface{}, error) {
+	select {
+	case msg, ok := <-e.replies:
+		if !ok {
+			e.conn.mu.Lock()
+			defer e.conn.mu.Unlock()
+			return nil, e.conn.err
+		}
+		return msg, nil
+	case <-time.After(e.client.timeout):
+		return nil, fmt.Errorf("no reply for game %s within %s", e.gameId, e.client.timeout)
+	}
+}
+
+// ObservationSchema implements environment.GameEnvironment
+func (e *MultiplexEnvironment) ObservationSchema() []string {
+	return generals.FeatureNames
+}
+
+// ActionSpace implements environment.GameEnvironment
+func (e *MultiplexEnvironment) ActionSpace() environment.ActionSpace {
+	return environment.GeneralsActionSpace(e.rows, e.cols)
+}
+
+// RequestReplay asks the server to store the replay of this game
+func (e *MultiplexEnvironment) RequestReplay() error {
+	if e.conn == nil {
+		return fmt.Errorf("no game in progress")
+	}
+	return e.conn.send(GameRequest{Type: string(SaveReplay), GameId: e.gameId})
+}
+
+// Close implements environment.GameEnvironment, ending the game on the server
+func (e *MultiplexEnvironment) Close() error {
+	if e.conn == nil {
+		return nil
+	}
+	conn := e.conn
+	e.conn = nil
+	conn.close(e.gameId)
+	if conn.broken() {
+		return nil
+	}
+	return conn.send(GameRequest{Type: string(CloseGame), GameId: e.gameId})
+}
@@ -0,0 +1,109 @@
+package fitness_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bxrne/darwin/internal/fitness"
+	"github.com/bxrne/darwin/internal/mockserver"
+	"github.com/stretchr/testify/assert"
+)
+
+// playMultiplexGame resets a game on a multiplexed client and steps it until it ends or maxSteps actions are sent
+func playMultiplexGame(client *fitness.MultiplexClient, gameId string, maxSteps int) ([]float64, error) {
+	env := client.NewGame(gameId, "random", 8, 8)
+	defer env.Close()
+
+	if _, err := env.Reset(); err != nil {
+		return nil, err
+	}
+	var rewards []float64
+	for range maxSteps {
+		obs, err := env.Step([]int{0, 1, 1, 0, 0})
+		if err != nil {
+			return rewards, err
+		}
+		rewards = append(rewards, obs.Reward)
+		if obs.Terminated || obs.Truncated {
+			break
+		}
+	}
+	return rewards, nil
+}
+
+func TestMultiplexClient_GIVEN_concurrent_games_WHEN_played_THEN_share_one_connection(t *testing.T) {
+	server := startServer(t, mockserver.Scenario{Rewards: []float64{1, 2, 3}, TerminateAt: 3})
+	client := fitness.NewMultiplexClient(server.Addr(), time.Second, 20*time.Millisecond)
+	defer client.Close()
+
+	var wg sync.WaitGroup
+	results := make([][]float64, 8)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rewards, err := playMultiplexGame(client, fmt.Sprintf("game_%d", i), 10)
+			assert.NoError(t, err)
+			results[i] = rewards
+		}()
+	}
+	wg.Wait()
+
+	for _, rewards := range results {
+		assert.Equal(t, []float64{1, 2, 3}, rewards)
+	}
+	stats := server.Stats()
+	assert.Equal(t, int64(1), stats.Connections)
+	assert.Equal(t, int64(8), stats.Games)
+	assert.Equal(t, int64(24), stats.Actions)
+	// Games stepping within the batch window of each other go out together
+	assert.Positive(t, stats.Batches)
+}
+
+func TestMultiplexClient_GIVEN_connect_rejected_WHEN_reset_THEN_returns_server_error(t *testing.T) {
+	server := startServer(t, mockserver.Scenario{ConnectError: "Server full"})
+	client := fitness.NewMultiplexClient(server.Addr(), time.Second, 0)
+	defer client.Close()
+
+	_, err := playMultiplexGame(client, "game_1", 5)
+
+	assert.ErrorContains(t, err, "Server full")
+}
+
+func TestMultiplexClient_GIVEN_dropped_connection_WHEN_next_game_starts_THEN_reconnects(t *testing.T) {
+	server := startServer(t, mockserver.Scenario{DropAt: 2})
+	client := fitness.NewMultiplexClient(server.Addr(), time.Second, 0)
+	defer client.Close()
+
+	rewards, err := playMultiplexGame(client, "game_1", 5)
+	assert.ErrorContains(t, err, "failed to read message")
+	assert.Len(t, rewards, 1)
+
+	server.SetScenario(mockserver.Scenario{TerminateAt: 2})
+	rewards, err = playMultiplexGame(client, "game_2", 5)
+	assert.NoError(t, err)
+	assert.Len(t, rewards, 2)
+	assert.Equal(t, int64(2), server.Stats().Connections)
+}
+
+func TestMultiplexClient_GIVEN_slow_server_WHEN_stepped_THEN_times_out(t *testing.T) {
+	server := startServer(t, mockserver.Scenario{Delay: 300 * time.Millisecond})
+	client := fitness.NewMultiplexClient(server.Addr(), 50*time.Millisecond, 0)
+	defer client.Close()
+
+	_, err := playMultiplexGame(client, "game_1", 5)
+
+	assert.ErrorContains(t, err, "no reply for game game_1")
+}
+
+func TestMultiplexClient_GIVEN_closed_client_WHEN_reset_THEN_returns_error(t *testing.T) {
+	server := startServer(t, mockserver.Scenario{})
+	client := fitness.NewMultiplexClient(server.Addr(), time.Second, 0)
+	assert.NoError(t, client.Close())
+
+	_, err := playMultiplexGame(client, "game_1", 5)
+
+	assert.ErrorContains(t, err, "closed")
+}
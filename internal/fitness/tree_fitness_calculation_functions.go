@@ -55,7 +55,7 @@ func CalculateTreeFitness(tree *individual.TreeNode, targetResults []float64, te
 	}
 }
 
-func SetupEvalFunction(evalFunction string, variableSet []string, testCaseCount int) ([]map[string]float64, []float64) {
+func SetupEvalFunction(evalFunction string, variableSet []string, testCaseCount int, r *rng.Rand) ([]map[string]float64, []float64) {
 	exprtkObj := exprtk.NewExprtk()
 	exprtkObj.SetExpression(evalFunction)
 
@@ -77,7 +77,7 @@ func SetupEvalFunction(evalFunction string, variableSet []string, testCaseCount
 	for i := range testCaseCount {
 		caseVars := make(map[string]float64)
 		for _, varName := range variableSet {
-			caseVars[varName] = minVal + rng.Float64()*(maxVal-minVal)
+			caseVars[varName] = minVal + r.Float64()*(maxVal-minVal)
 		}
 		for name, val := range caseVars {
 			exprtkObj.SetDoubleVariableValue(name, val)
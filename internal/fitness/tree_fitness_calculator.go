@@ -1,14 +1,17 @@
 package fitness
 
-import "github.com/bxrne/darwin/internal/individual"
+import (
+	"github.com/bxrne/darwin/internal/individual"
+	"github.com/bxrne/darwin/internal/rng"
+)
 
 type TreeFitnessCalculator struct {
 	TestCases     []map[string]float64
 	TargetResults []float64
 }
 
-func (fitnessCalc *TreeFitnessCalculator) SetupEvalFunction(evalFunction string, variableSet []string, testCaseCount int) {
-	testCases, targetResults := SetupEvalFunction(evalFunction, variableSet, testCaseCount)
+func (fitnessCalc *TreeFitnessCalculator) SetupEvalFunction(evalFunction string, variableSet []string, testCaseCount int, r *rng.Rand) {
+	testCases, targetResults := SetupEvalFunction(evalFunction, variableSet, testCaseCount, r)
 	fitnessCalc.TestCases = testCases
 	fitnessCalc.TargetResults = targetResults
 }
@@ -22,7 +22,7 @@ func TestCalculateTreeFitness_GIVEN_various_trees_WHEN_calculate_THEN_sets_expec
 		t.Run(tt.name, func(t *testing.T) {
 			fitnessCalc := &fitness.TreeFitnessCalculator{}
 			variableSet := []string{"x"}
-			fitnessCalc.SetupEvalFunction("x*2+3*2", variableSet, 1)
+			fitnessCalc.SetupEvalFunction("x*2+3*2", variableSet, 1, nil)
 			vars := make([]map[string]float64, 1)
 			vars[0] = map[string]float64{"x": 1}
 			fitnessCalc.TestCases = vars
@@ -0,0 +1,96 @@
+package generals
+
+import (
+	"fmt"
+	"math/rand/v2"
+)
+
+// Bot chooses the opponent's action from its own observation
+type Bot interface {
+	Act(obs *Observation, r *rand.Rand) Action
+}
+
+// NewBot creates a built-in opponent by the names accepted by the Python server
+func NewBot(name string) (Bot, error) {
+	switch name {
+	case "", "random":
+		return &RandomBot{IdleProbability: 0.05, SplitProbability: 0.25}, nil
+	case "expander":
+		return &ExpanderBot{}, nil
+	default:
+		return nil, fmt.Errorf("unknown opponent type %q, must be random or expander", name)
+	}
+}
+
+// RandomBot plays a uniformly random valid move, sometimes idling or splitting
+type RandomBot struct {
+	IdleProbability  float64
+	SplitProbability float64
+}
+
+// Act implements Bot
+func (b *RandomBot) Act(obs *Observation, r *rand.Rand) Action {
+	moves := ValidMoves(obs)
+	if len(moves) == 0 || r.Float64() < b.IdleProbability {
+		return Action{Pass: true}
+	}
+	a := moves[r.IntN(len(moves))]
+	a.Split = r.Float64() < b.SplitProbability
+	return a
+}
+
+// ExpanderBot captures opponent cells first, then neutral cells, using its largest
+// sufficient army; with nothing to capture it plays a random valid move
+type ExpanderBot struct{}
+
+// Act implements Bot
+func (b *ExpanderBot) Act(obs *Observation, r *rand.Rand) Action {
+	moves := ValidMoves(obs)
+	if len(moves) == 0 {
+		return Action{Pass: true}
+	}
+
+	best, bestPriority, bestArmy := -1, 0, 0
+	for i, m := range moves {
+		tr, tc := m.Row+directions[m.Direction][0], m.Col+directions[m.Direction][1]
+		army := obs.Armies[m.Row][m.Col]
+		if army-1 <= obs.Armies[tr][tc] {
+			continue
+		}
+		priority := 0
+		switch {
+		case obs.OpponentCells[tr][tc]:
+			priority = 2
+		case obs.NeutralCells[tr][tc]:
+			priority = 1
+		}
+		if priority > bestPriority || (priority == bestPriority && priority > 0 && army > bestArmy) {
+			best, bestPriority, bestArmy = i, priority, army
+		}
+	}
+	if best >= 0 {
+		return moves[best]
+	}
+	return moves[r.IntN(len(moves))]
+}
+
+// ValidMoves lists every full-army move from an owned cell with more than one army
+// onto an in-bounds, non-mountain neighbour
+func ValidMoves(obs *Observation) []Action {
+	moves := make([]Action, 0)
+	for i := range obs.Rows {
+		for j := range obs.Cols {
+			if !obs.OwnedCells[i][j] || obs.Armies[i][j] <= 1 {
+				continue
+			}
+			for dir, d := range directions {
+				ni, nj := i+d[0], j+d[1]
+				if ni < 0 || ni >= obs.Rows || nj < 0 || nj >= obs.Cols || obs.Mountains[ni][nj] {
+					continue
+				}
+				moves = append(moves, Action{Row: i, Col: j, Direction: dir})
+			}
+		}
+	}
+	return moves
+}
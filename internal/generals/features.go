@@ -0,0 +1,132 @@
+package generals
+
+import "math"
+
+// FeatureNames are the keys returned by Features
+var FeatureNames = []string{
+	"army_diff", "land_diff", "fog_count", "visible_cities_count", "visible_mountains_count",
+	"army_ratio", "land_ratio", "border_pressure", "timestep", "distance_to_enemy_general",
+	"min_city_x", "min_city_y", "enemy_general_x", "enemy_general_y", "max_owned_army_x", "max_owned_army_y",
+}
+
+// Features computes the observation dictionary of extract_features in game/src/game.py,
+// with the same keys and values; testdata/generals/features.json pins the two together.
+func Features(obs *Observation) map[string]float64 {
+	rows, cols := obs.Rows, obs.Cols
+
+	// Largest owned army, (10, 10) when nothing is owned
+	maxArmyX, maxArmyY := 10, 10
+	maxArmy := math.MinInt
+	for i := range rows {
+		for j := range cols {
+			if obs.OwnedCells[i][j] && obs.Armies[i][j] > maxArmy {
+				maxArmy = obs.Armies[i][j]
+				maxArmyX, maxArmyY = i, j
+			}
+		}
+	}
+
+	// First owned general in row-major order, (0, 0) when none is visible
+	myGeneral := [2]int{0, 0}
+	found := false
+	for i := 0; i < rows && !found; i++ {
+		for j := 0; j < cols && !found; j++ {
+			if obs.Generals[i][j] && obs.OwnedCells[i][j] {
+				myGeneral = [2]int{i, j}
+				found = true
+			}
+		}
+	}
+
+	fogCount, visibleCities, visibleMountains := 0, 0, 0
+	minCityDist := math.MaxInt
+	minCityX, minCityY := 0, 0
+	var enemyGeneral *[2]int
+	for i := range rows {
+		for j := range cols {
+			if obs.FogCells[i][j] {
+				fogCount++
+				continue
+			}
+			if obs.Cities[i][j] {
+				visibleCities++
+				if !obs.OwnedCells[i][j] {
+					if d := manhattan(myGeneral, [2]int{i, j}); d < minCityDist {
+						minCityDist = d
+						minCityX, minCityY = i, j
+					}
+				}
+			} else if obs.Mountains[i][j] {
+				visibleMountains++
+			}
+			if !obs.OwnedCells[i][j] && obs.OpponentCells[i][j] && obs.Generals[i][j] {
+				enemyGeneral = &[2]int{i, j}
+			}
+		}
+	}
+
+	// Visible owned cells touching at least one opponent cell
+	borderPressure := 0
+	for i := range rows {
+		for j := range cols {
+			if !obs.OwnedCells[i][j] || obs.FogCells[i][j] {
+				continue
+			}
+			for _, d := range directions {
+				ni, nj := i+d[0], j+d[1]
+				if ni >= 0 && ni < rows && nj >= 0 && nj < cols && obs.OpponentCells[ni][nj] {
+					borderPressure++
+					break
+				}
+			}
+		}
+	}
+
+	distanceToEnemyGeneral := rows + cols
+	enemyX, enemyY := 10, 10
+	if enemyGeneral != nil {
+		distanceToEnemyGeneral = manhattan(myGeneral, *enemyGeneral)
+		enemyX, enemyY = enemyGeneral[0], enemyGeneral[1]
+	}
+
+	return map[string]float64{
+		"army_diff":                 float64(obs.OwnedArmyCount - obs.OpponentArmyCount),
+		"land_diff":                 float64(obs.OwnedLandCount - obs.OpponentLandCount),
+		"fog_count":                 float64(fogCount),
+		"visible_cities_count":      float64(visibleCities),
+		"visible_mountains_count":   float64(visibleMountains),
+		"army_ratio":                float64(obs.OwnedArmyCount) / float64(obs.OpponentArmyCount+1),
+		"land_ratio":                float64(obs.OwnedLandCount) / float64(obs.OpponentLandCount+1),
+		"border_pressure":           float64(borderPressure),
+		"timestep":                  float64(obs.Timestep),
+		"distance_to_enemy_general": float64(distanceToEnemyGeneral),
+		"min_city_x":                float64(minCityX),
+		"min_city_y":                float64(minCityY),
+		"enemy_general_x":           float64(enemyX),
+		"enemy_general_y":           float64(enemyY),
+		"max_owned_army_x":          float64(maxArmyX),
+		"max_owned_army_y":          float64(maxArmyY),
+	}
+}
+
+// ValidStartPoints marks owned cells with at least two armies, the step "info" of the Python server
+func ValidStartPoints(obs *Observation) [][]bool {
+	mask := makeBools(obs.Rows, obs.Cols)
+	for i := range obs.Rows {
+		for j := range obs.Cols {
+			mask[i][j] = obs.OwnedCells[i][j] && obs.Armies[i][j] >= 2
+		}
+	}
+	return mask
+}
+
+func manhattan(a, b [2]int) int {
+	return abs(a[0]-b[0]) + abs(a[1]-b[1])
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
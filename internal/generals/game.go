@@ -0,0 +1,308 @@
+// Package generals is a native Go implementation of the Generals rules played by the
+// Python game server (game/src/game.py): a grid with mountains, cities and two generals,
+// army growth, moves and splits, fog of war and capture-the-general win conditions.
+// Games are fully deterministic given a seed and the agent's actions.
+package generals
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sort"
+)
+
+// Directions in the order used by the action vector: up, down, left, right
+var directions = [4][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}}
+
+const (
+	neutral = -1
+	// Agent is the player controlled by the caller, Opponent the built-in agent
+	Agent    = 0
+	Opponent = 1
+	// increment is the number of turns between growth of every owned cell
+	increment = 50
+)
+
+// Action is one player's move for a turn
+type Action struct {
+	Pass      bool
+	Row       int
+	Col       int
+	Direction int
+	Split     bool
+}
+
+// ActionFromVector converts the wire action [pass, row, col, direction, split] used by the
+// TCP protocol; as in the Python server any positive pass or split value counts as set
+func ActionFromVector(v []int) Action {
+	if len(v) < 5 {
+		return Action{Pass: true}
+	}
+	return Action{Pass: v[0] > 0, Row: v[1], Col: v[2], Direction: v[3], Split: v[4] > 0}
+}
+
+// Config holds the map and match settings, defaulting to the Python server's
+type Config struct {
+	Rows             int
+	Cols             int
+	MountainDensity  float64
+	CityDensity      float64
+	GeneralPositions [2][2]int
+	Opponent         string
+	MaxTurns         int // 0 means the game only ends by capture
+}
+
+// DefaultConfig mirrors the GridFactory used by game/src/game.py
+func DefaultConfig() Config {
+	return Config{
+		Rows:             8,
+		Cols:             8,
+		MountainDensity:  0.15,
+		CityDensity:      0.05,
+		GeneralPositions: [2][2]int{{2, 2}, {6, 6}},
+		Opponent:         "random",
+	}
+}
+
+// validate validates the Config
+func (c *Config) validate() error {
+	if c.Rows <= 1 || c.Cols <= 1 {
+		return fmt.Errorf("grid must be at least 2x2")
+	}
+	if c.MountainDensity < 0 || c.CityDensity < 0 || c.MountainDensity+c.CityDensity >= 1 {
+		return fmt.Errorf("mountain and city densities must be non-negative and sum below 1")
+	}
+	for _, p := range c.GeneralPositions {
+		if p[0] < 0 || p[0] >= c.Rows || p[1] < 0 || p[1] >= c.Cols {
+			return fmt.Errorf("general position %v is outside the grid", p)
+		}
+	}
+	if c.GeneralPositions[0] == c.GeneralPositions[1] {
+		return fmt.Errorf("generals must start on different cells")
+	}
+	return nil
+}
+
+// StepResult is the agent's view after a turn
+type StepResult struct {
+	Observation *Observation
+	Reward      float64
+	Terminated  bool
+	Truncated   bool
+}
+
+// Game is a single two-player match against a built-in opponent
+type Game struct {
+	cfg      Config
+	seed     int64
+	rng      *rand.Rand
+	opponent Bot
+
+	armies   []int
+	owner    []int
+	mountain []bool
+	city     []bool
+	general  []bool
+	generals [2]int
+
+	turn      int
+	winner    int
+	truncated bool
+	lastLand  int
+}
+
+// NewGame creates a game against the built-in opponent named in cfg; call Reset before the first Step
+func NewGame(cfg Config, seed int64) (*Game, error) {
+	opponent, err := NewBot(cfg.Opponent)
+	if err != nil {
+		return nil, err
+	}
+	return NewGameAgainst(cfg, seed, opponent)
+}
+
+// NewGameAgainst creates a game against the given opponent, ignoring cfg.Opponent
+func NewGameAgainst(cfg Config, seed int64, opponent Bot) (*Game, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return &Game{cfg: cfg, seed: seed, opponent: opponent, winner: neutral}, nil
+}
+
+// Reset regenerates the map from the seed and returns the agent's first observation.
+// Like the Python server, every reset with the same seed replays the same map and opponent.
+func (g *Game) Reset() *Observation {
+	g.rng = rand.New(rand.NewPCG(uint64(g.seed), uint64(g.seed)^0x9e3779b97f4a7c15))
+	g.generate()
+	g.turn = 0
+	g.winner = neutral
+	g.truncated = false
+	g.lastLand = g.land(Agent)
+	return g.Observe(Agent)
+}
+
+// Step plays one turn: the agent's action, the opponent's reply, then army growth.
+// The reward is the change in the agent's owned land, as with LandRewardFn.
+func (g *Game) Step(action Action) StepResult {
+	if g.Done() {
+		return StepResult{Observation: g.Observe(Agent), Terminated: g.winner != neutral, Truncated: g.truncated}
+	}
+
+	opponentAction := g.opponent.Act(g.Observe(Opponent), g.rng)
+	g.resolve([2]Action{action, opponentAction})
+
+	if g.winner == neutral {
+		g.turn++
+		g.grow()
+		if g.cfg.MaxTurns > 0 && g.turn >= g.cfg.MaxTurns {
+			g.truncated = true
+		}
+	}
+
+	land := g.land(Agent)
+	reward := float64(land - g.lastLand)
+	g.lastLand = land
+	return StepResult{
+		Observation: g.Observe(Agent),
+		Reward:      reward,
+		Terminated:  g.winner != neutral,
+		Truncated:   g.truncated,
+	}
+}
+
+// Done reports whether the game has ended by capture or truncation
+func (g *Game) Done() bool {
+	return g.winner != neutral || g.truncated
+}
+
+// Winner returns Agent or Opponent once a general has been captured, otherwise -1
+func (g *Game) Winner() int {
+	return g.winner
+}
+
+// Turn returns the number of completed turns
+func (g *Game) Turn() int {
+	return g.turn
+}
+
+// move is a validated move waiting to be applied
+type move struct {
+	player int
+	from   int
+	to     int
+	army   int
+}
+
+// resolve applies both players' moves, larger moving armies first, ties in random order
+func (g *Game) resolve(actions [2]Action) {
+	moves := make([]move, 0, 2)
+	for player, action := range actions {
+		if m, ok := g.validMove(player, action); ok {
+			moves = append(moves, m)
+		}
+	}
+	if len(moves) == 2 && moves[0].army == moves[1].army && g.rng.IntN(2) == 1 {
+		moves[0], moves[1] = moves[1], moves[0]
+	}
+	sort.SliceStable(moves, func(i, j int) bool { return moves[i].army > moves[j].army })
+
+	for _, m := range moves {
+		if g.winner != neutral {
+			return
+		}
+		// The first move may have taken the source cell or thinned its army
+		if g.owner[m.from] != m.player || g.armies[m.from] <= 1 {
+			continue
+		}
+		g.apply(m.player, m.from, m.to, min(m.army, g.armies[m.from]-1))
+	}
+}
+
+// validMove checks an action against the current state; invalid actions are ignored like passes
+func (g *Game) validMove(player int, a Action) (move, bool) {
+	if a.Pass || a.Direction < 0 || a.Direction >= len(directions) || !g.inBounds(a.Row, a.Col) {
+		return move{}, false
+	}
+	from := g.index(a.Row, a.Col)
+	if g.owner[from] != player || g.armies[from] <= 1 {
+		return move{}, false
+	}
+	toRow, toCol := a.Row+directions[a.Direction][0], a.Col+directions[a.Direction][1]
+	if !g.inBounds(toRow, toCol) {
+		return move{}, false
+	}
+	to := g.index(toRow, toCol)
+	if g.mountain[to] {
+		return move{}, false
+	}
+	army := g.armies[from] - 1
+	if a.Split {
+		army = g.armies[from] / 2
+	}
+	if army < 1 {
+		return move{}, false
+	}
+	return move{player: player, from: from, to: to, army: army}, true
+}
+
+// apply moves army from one cell to a neighbour, reinforcing or attacking it
+func (g *Game) apply(player, from, to, army int) {
+	g.armies[from] -= army
+	if g.owner[to] == player {
+		g.armies[to] += army
+		return
+	}
+	if army <= g.armies[to] {
+		g.armies[to] -= army
+		return
+	}
+
+	loser := g.owner[to]
+	g.armies[to] = army - g.armies[to]
+	g.owner[to] = player
+	if g.general[to] && loser != neutral {
+		g.capture(player, loser)
+	}
+}
+
+// capture ends the game, handing every cell of the loser to the winner
+func (g *Game) capture(winner, loser int) {
+	g.winner = winner
+	for i := range g.owner {
+		if g.owner[i] == loser {
+			g.owner[i] = winner
+		}
+	}
+}
+
+// grow adds one army to owned generals and cities every turn and to every owned cell every 50 turns
+func (g *Game) grow() {
+	for i := range g.owner {
+		if g.owner[i] == neutral {
+			continue
+		}
+		if g.general[i] || g.city[i] {
+			g.armies[i]++
+		}
+		if g.turn%increment == 0 {
+			g.armies[i]++
+		}
+	}
+}
+
+// land counts the cells owned by a player
+func (g *Game) land(player int) int {
+	count := 0
+	for _, o := range g.owner {
+		if o == player {
+			count++
+		}
+	}
+	return count
+}
+
+func (g *Game) index(row, col int) int {
+	return row*g.cfg.Cols + col
+}
+
+func (g *Game) inBounds(row, col int) bool {
+	return row >= 0 && row < g.cfg.Rows && col >= 0 && col < g.cfg.Cols
+}
@@ -0,0 +1,237 @@
+package generals
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestGame(t *testing.T, seed int64) *Game {
+	game, err := NewGame(DefaultConfig(), seed)
+	assert.NoError(t, err)
+	game.Reset()
+	return game
+}
+
+// clearBoard removes every mountain and city so moves can be tested in isolation
+func clearBoard(g *Game) {
+	for i := range g.owner {
+		g.mountain[i] = false
+		g.city[i] = false
+		if !g.general[i] {
+			g.owner[i] = neutral
+			g.armies[i] = 0
+		}
+	}
+}
+
+func TestNewGame_GIVEN_invalid_config_WHEN_created_THEN_returns_error(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.GeneralPositions = [2][2]int{{1, 1}, {1, 1}}
+
+	_, err := NewGame(cfg, 1)
+
+	assert.Error(t, err)
+}
+
+func TestReset_GIVEN_seed_WHEN_reset_THEN_generals_placed_and_connected(t *testing.T) {
+	game := newTestGame(t, 44)
+
+	assert.True(t, game.general[game.index(2, 2)])
+	assert.True(t, game.general[game.index(6, 6)])
+	assert.Equal(t, Agent, game.owner[game.index(2, 2)])
+	assert.Equal(t, Opponent, game.owner[game.index(6, 6)])
+	assert.True(t, game.connected())
+	for i := range game.city {
+		if game.city[i] {
+			assert.GreaterOrEqual(t, game.armies[i], 40)
+			assert.Less(t, game.armies[i], 50)
+		}
+	}
+}
+
+func TestStep_GIVEN_same_seed_and_actions_WHEN_played_twice_THEN_games_are_identical(t *testing.T) {
+	play := func() []map[string]float64 {
+		game := newTestGame(t, 7)
+		trace := make([]map[string]float64, 0)
+		for range 200 {
+			moves := ValidMoves(game.Observe(Agent))
+			action := Action{Pass: true}
+			if len(moves) > 0 {
+				action = moves[0]
+			}
+			result := game.Step(action)
+			trace = append(trace, Features(result.Observation))
+			if game.Done() {
+				break
+			}
+		}
+		return trace
+	}
+
+	assert.Equal(t, play(), play())
+}
+
+func TestStep_GIVEN_turns_WHEN_stepped_THEN_generals_grow_every_turn_and_land_every_50(t *testing.T) {
+	game := newTestGame(t, 1)
+	clearBoard(game)
+	general := game.generals[Agent]
+	game.owner[general+1] = Agent
+	game.armies[general+1] = 1
+
+	for range 50 {
+		game.opponent = &RandomBot{IdleProbability: 1}
+		game.Step(Action{Pass: true})
+	}
+
+	assert.Equal(t, 1+50+1, game.armies[general])
+	assert.Equal(t, 2, game.armies[general+1])
+}
+
+func TestStep_GIVEN_move_onto_neutral_WHEN_stronger_THEN_cell_captured_and_rewarded(t *testing.T) {
+	game := newTestGame(t, 1)
+	clearBoard(game)
+	game.opponent = &RandomBot{IdleProbability: 1}
+	general := game.generals[Agent]
+	game.armies[general] = 10
+
+	result := game.Step(Action{Row: 2, Col: 2, Direction: 3})
+
+	assert.Equal(t, Agent, game.owner[game.index(2, 3)])
+	assert.Equal(t, 9, game.armies[game.index(2, 3)])
+	assert.Equal(t, 2, game.armies[general]) // 1 left behind + 1 growth
+	assert.Equal(t, 1.0, result.Reward)
+}
+
+func TestStep_GIVEN_split_WHEN_moved_THEN_half_the_army_moves(t *testing.T) {
+	game := newTestGame(t, 1)
+	clearBoard(game)
+	game.opponent = &RandomBot{IdleProbability: 1}
+	game.armies[game.generals[Agent]] = 10
+
+	game.Step(Action{Row: 2, Col: 2, Direction: 1, Split: true})
+
+	assert.Equal(t, 5, game.armies[game.index(3, 2)])
+}
+
+func TestStep_GIVEN_enemy_general_captured_WHEN_stepped_THEN_agent_wins_and_takes_all_land(t *testing.T) {
+	game := newTestGame(t, 1)
+	clearBoard(game)
+	game.opponent = &RandomBot{IdleProbability: 1}
+	enemy := game.generals[Opponent]
+	game.owner[game.index(6, 5)] = Agent
+	game.armies[game.index(6, 5)] = 20
+	game.owner[game.index(0, 0)] = Opponent
+
+	result := game.Step(Action{Row: 6, Col: 5, Direction: 3})
+
+	assert.True(t, result.Terminated)
+	assert.Equal(t, Agent, game.Winner())
+	assert.Equal(t, Agent, game.owner[enemy])
+	assert.Equal(t, Agent, game.owner[game.index(0, 0)])
+	assert.Equal(t, 0, result.Observation.OpponentLandCount)
+}
+
+func TestStep_GIVEN_max_turns_WHEN_reached_THEN_truncated(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.MaxTurns = 3
+	game, err := NewGame(cfg, 1)
+	assert.NoError(t, err)
+	game.Reset()
+
+	var result StepResult
+	for range 3 {
+		result = game.Step(Action{Pass: true})
+	}
+
+	assert.True(t, result.Truncated || result.Terminated)
+	assert.True(t, game.Done())
+}
+
+func TestObserve_GIVEN_start_WHEN_observed_THEN_only_cells_near_general_visible(t *testing.T) {
+	game := newTestGame(t, 1)
+
+	obs := game.Observe(Agent)
+
+	assert.False(t, obs.FogCells[1][1])
+	assert.False(t, obs.FogCells[3][3])
+	assert.True(t, obs.FogCells[6][6])
+	assert.Equal(t, 0, obs.Armies[6][6])
+	assert.Equal(t, 1, obs.OwnedLandCount)
+	assert.Equal(t, 1, obs.OpponentLandCount)
+}
+
+func TestFeatures_GIVEN_observation_WHEN_extracted_THEN_has_python_keys(t *testing.T) {
+	game := newTestGame(t, 1)
+
+	features := Features(game.Observe(Agent))
+
+	for _, key := range FeatureNames {
+		assert.Contains(t, features, key)
+	}
+	assert.Len(t, features, len(FeatureNames))
+	assert.Equal(t, 16.0, features["distance_to_enemy_general"]) // unknown enemy: N + M
+	assert.Equal(t, 2.0, features["max_owned_army_x"])
+}
+
+// featuresFixture is a state in the shape the Python server passes to extract_features,
+// with the features it returns
+type featuresFixture struct {
+	State struct {
+		Armies            [][]int  `json:"armies"`
+		Generals          [][]bool `json:"generals"`
+		Cities            [][]bool `json:"cities"`
+		Mountains         [][]bool `json:"mountains"`
+		OwnedCells        [][]bool `json:"owned_cells"`
+		OpponentCells     [][]bool `json:"opponent_cells"`
+		FogCells          [][]bool `json:"fog_cells"`
+		OwnedLandCount    int      `json:"owned_land_count"`
+		OwnedArmyCount    int      `json:"owned_army_count"`
+		OpponentLandCount int      `json:"opponent_land_count"`
+		OpponentArmyCount int      `json:"opponent_army_count"`
+		Timestep          int      `json:"timestep"`
+	} `json:"state"`
+	Features map[string]float64 `json:"features"`
+}
+
+func TestFeatures_GIVEN_python_fixture_WHEN_extracted_THEN_matches_extract_features(t *testing.T) {
+	data, err := os.ReadFile("../../testdata/generals/features.json")
+	assert.NoError(t, err)
+	var fixture featuresFixture
+	assert.NoError(t, json.Unmarshal(data, &fixture))
+	state := fixture.State
+	obs := &Observation{
+		Rows:              len(state.Armies),
+		Cols:              len(state.Armies[0]),
+		Armies:            state.Armies,
+		Generals:          state.Generals,
+		Cities:            state.Cities,
+		Mountains:         state.Mountains,
+		OwnedCells:        state.OwnedCells,
+		OpponentCells:     state.OpponentCells,
+		FogCells:          state.FogCells,
+		OwnedLandCount:    state.OwnedLandCount,
+		OwnedArmyCount:    state.OwnedArmyCount,
+		OpponentLandCount: state.OpponentLandCount,
+		OpponentArmyCount: state.OpponentArmyCount,
+		Timestep:          state.Timestep,
+	}
+
+	features := Features(obs)
+
+	assert.Equal(t, fixture.Features, features)
+}
+
+func TestExpanderBot_GIVEN_capturable_neighbour_WHEN_act_THEN_expands(t *testing.T) {
+	game := newTestGame(t, 1)
+	clearBoard(game)
+	game.armies[game.generals[Opponent]] = 5
+
+	action := (&ExpanderBot{}).Act(game.Observe(Opponent), game.rng)
+
+	assert.False(t, action.Pass)
+	assert.Equal(t, 6, action.Row)
+	assert.Equal(t, 6, action.Col)
+}
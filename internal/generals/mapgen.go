@@ -0,0 +1,83 @@
+package generals
+
+// maxMapAttempts bounds regeneration when mountains cut the generals off from each other
+const maxMapAttempts = 100
+
+// generate lays out a uniform random map: each cell becomes a mountain or a neutral city
+// (40-49 army) with the configured densities, and the generals start with one army each.
+// Maps where the generals cannot reach each other are regenerated.
+func (g *Game) generate() {
+	size := g.cfg.Rows * g.cfg.Cols
+	g.armies = make([]int, size)
+	g.owner = make([]int, size)
+	g.mountain = make([]bool, size)
+	g.city = make([]bool, size)
+	g.general = make([]bool, size)
+	for player, p := range g.cfg.GeneralPositions {
+		g.generals[player] = g.index(p[0], p[1])
+	}
+
+	for attempt := 0; ; attempt++ {
+		for i := range size {
+			g.armies[i] = 0
+			g.owner[i] = neutral
+			g.mountain[i] = false
+			g.city[i] = false
+			g.general[i] = false
+		}
+		for i := range size {
+			if i == g.generals[Agent] || i == g.generals[Opponent] {
+				continue
+			}
+			r := g.rng.Float64()
+			switch {
+			case r < g.cfg.MountainDensity:
+				g.mountain[i] = true
+			case r < g.cfg.MountainDensity+g.cfg.CityDensity:
+				g.city[i] = true
+				g.armies[i] = 40 + g.rng.IntN(10)
+			}
+		}
+		for player, i := range g.generals {
+			g.general[i] = true
+			g.owner[i] = player
+			g.armies[i] = 1
+		}
+		// A fully walled map is impossible to fix by resampling; give up and clear the mountains
+		if g.connected() || attempt == maxMapAttempts {
+			if attempt == maxMapAttempts {
+				for i := range g.mountain {
+					g.mountain[i] = false
+				}
+			}
+			return
+		}
+	}
+}
+
+// connected reports whether a mountain-free path joins the two generals
+func (g *Game) connected() bool {
+	seen := make([]bool, len(g.owner))
+	queue := []int{g.generals[Agent]}
+	seen[g.generals[Agent]] = true
+	for len(queue) > 0 {
+		cell := queue[0]
+		queue = queue[1:]
+		if cell == g.generals[Opponent] {
+			return true
+		}
+		row, col := cell/g.cfg.Cols, cell%g.cfg.Cols
+		for _, d := range directions {
+			r, c := row+d[0], col+d[1]
+			if !g.inBounds(r, c) {
+				continue
+			}
+			next := g.index(r, c)
+			if !seen[next] && !g.mountain[next] {
+				seen[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package generals
+
+// Observation is one player's fogged view of the board, with the same fields as the
+// generals-bots observation the Python server feeds to extract_features
+type Observation struct {
+	Rows              int
+	Cols              int
+	Armies            [][]int
+	Generals          [][]bool
+	Cities            [][]bool
+	Mountains         [][]bool
+	NeutralCells      [][]bool
+	OwnedCells        [][]bool
+	OpponentCells     [][]bool
+	FogCells          [][]bool
+	StructuresInFog   [][]bool
+	OwnedLandCount    int
+	OwnedArmyCount    int
+	OpponentLandCount int
+	OpponentArmyCount int
+	Timestep          int
+}
+
+// Observe builds the view of a player. Cells within one step (including diagonals)
+// of an owned cell are visible; everything else is fog, where only the presence of
+// a mountain or city is known. Land and army totals are public.
+func (g *Game) Observe(player int) *Observation {
+	rows, cols := g.cfg.Rows, g.cfg.Cols
+	obs := &Observation{
+		Rows:            rows,
+		Cols:            cols,
+		Armies:          makeInts(rows, cols),
+		Generals:        makeBools(rows, cols),
+		Cities:          makeBools(rows, cols),
+		Mountains:       makeBools(rows, cols),
+		NeutralCells:    makeBools(rows, cols),
+		OwnedCells:      makeBools(rows, cols),
+		OpponentCells:   makeBools(rows, cols),
+		FogCells:        makeBools(rows, cols),
+		StructuresInFog: makeBools(rows, cols),
+		Timestep:        g.turn,
+	}
+
+	other := 1 - player
+	for i := range g.owner {
+		switch g.owner[i] {
+		case player:
+			obs.OwnedLandCount++
+			obs.OwnedArmyCount += g.armies[i]
+		case other:
+			obs.OpponentLandCount++
+			obs.OpponentArmyCount += g.armies[i]
+		}
+	}
+
+	for row := range rows {
+		for col := range cols {
+			i := g.index(row, col)
+			if !g.visible(player, row, col) {
+				obs.FogCells[row][col] = true
+				obs.StructuresInFog[row][col] = g.mountain[i] || g.city[i]
+				continue
+			}
+			obs.Armies[row][col] = g.armies[i]
+			obs.Generals[row][col] = g.general[i]
+			obs.Cities[row][col] = g.city[i]
+			obs.Mountains[row][col] = g.mountain[i]
+			obs.OwnedCells[row][col] = g.owner[i] == player
+			obs.OpponentCells[row][col] = g.owner[i] == other
+			obs.NeutralCells[row][col] = g.owner[i] == neutral && !g.mountain[i]
+		}
+	}
+	return obs
+}
+
+// visible reports whether a player owns the cell or one of its eight neighbours
+func (g *Game) visible(player, row, col int) bool {
+	for dr := -1; dr <= 1; dr++ {
+		for dc := -1; dc <= 1; dc++ {
+			r, c := row+dr, col+dc
+			if g.inBounds(r, c) && g.owner[g.index(r, c)] == player {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func makeInts(rows, cols int) [][]int {
+	grid := make([][]int, rows)
+	for i := range grid {
+		grid[i] = make([]int, cols)
+	}
+	return grid
+}
+
+func makeBools(rows, cols int) [][]bool {
+	grid := make([][]bool, rows)
+	for i := range grid {
+		grid[i] = make([]bool, cols)
+	}
+	return grid
+}
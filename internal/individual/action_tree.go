@@ -1,15 +1,38 @@
 package individual
 
+import (
+	"maps"
+	"slices"
+
+	"github.com/bxrne/darwin/internal/rng"
+)
+
 // ActionTreeIndividual implements an individual composed of action trees and a weights matrix for action selection
 type ActionTreeIndividual struct {
 	Trees    map[string]*Tree // action name -> action tree
 	fitness  float64
 	clientId string
+	RateGene
 }
 
+// ActionTuple declares one discrete action head: its name, the number of values it takes and
+// how its choice is constrained. Heads are chosen in order, so dependencies must come earlier.
 type ActionTuple struct {
 	Name  string `toml:"name"`
 	Value int    `toml:"value"`
+	// DependsOn names earlier heads whose chosen values are passed to this head's mask
+	DependsOn []string `toml:"depends_on"`
+	// Mask names the mask provider restricting this head's valid values, empty for none
+	Mask string `toml:"mask"`
+	// StopValue ends the action when chosen, leaving later heads at 0
+	StopValue *int `toml:"stop_value"`
+	// PenalizeConstant penalises a game in which this head's outputs never varied
+	PenalizeConstant bool `toml:"penalize_constant"`
+}
+
+// HasLayout reports whether the head declares anything beyond its name and size
+func (at ActionTuple) HasLayout() bool {
+	return len(at.DependsOn) > 0 || at.Mask != "" || at.StopValue != nil || at.PenalizeConstant
 }
 
 // Describe provides a string description of the ActionTreeIndividual
@@ -41,8 +64,9 @@ func (ati *ActionTreeIndividual) Clone() Evolvable {
 	}
 
 	return &ActionTreeIndividual{
-		Trees:   clonedTrees,
-		fitness: ati.fitness,
+		Trees:    clonedTrees,
+		fitness:  ati.fitness,
+		RateGene: ati.RateGene,
 	}
 }
 
@@ -55,16 +79,27 @@ func (ati *ActionTreeIndividual) SetClient(clientId string) {
 	ati.clientId = clientId
 }
 
-// Mutate applies mutation to the ActionTreeIndividual
+// Mutate applies mutation to the ActionTreeIndividual. Per node, every tree mutates on its own;
+// per individual, a single node of one random tree mutates with the rate as the chance.
 func (ati *ActionTreeIndividual) Mutate(rate float64, mutateInformation *MutateInformation) {
+	r := mutateInformation.rand()
+	// Trees are visited in name order so a generator always mutates the same nodes
+	names := slices.Sorted(maps.Keys(ati.Trees))
+	if mutateInformation.MutationMode == TreeMutationPerIndividual {
+		if len(ati.Trees) == 0 || r.Float64() >= rate {
+			return
+		}
+		names, rate = []string{names[r.Intn(len(names))]}, 1
+	}
 	// Mutate each tree based on the mutation rate
-	for _, tree := range ati.Trees {
+	for _, name := range names {
+		tree := ati.Trees[name]
 		tree.Mutate(rate, mutateInformation)
 		// Safety check: ensure no tree has depth 0 (Tree.Mutate should handle this, but double-check)
 		// If depth is 0, regenerate using NewRampedHalfAndHalfTree with depth 1
 		if tree.GetDepth() == 0 {
 			// Use NewRampedHalfAndHalfTree to regenerate with depth 1
-			regeneratedTree := newGrowTree(1, mutateInformation.OperandSet, mutateInformation.VariableSet, mutateInformation.TerminalSet)
+			regeneratedTree := newGrowTree(r, 1, mutateInformation.OperandSet, mutateInformation.VariableSet, mutateInformation.TerminalSet)
 			*tree = *regeneratedTree
 		}
 	}
@@ -90,7 +125,7 @@ func (ati *ActionTreeIndividual) MultiPointCrossover(i2 Evolvable, crossoverInfo
 		panic("MultiPointCrossover called with non-ActionTreeIndividual type")
 	}
 
-	for action := range ati.Trees {
+	for _, action := range slices.Sorted(maps.Keys(ati.Trees)) {
 		child1Tree, child2Tree := ati.Trees[action].MultiPointCrossover(other.Trees[action], crossoverInformation)
 		cTree1, ok := child1Tree.(*Tree)
 		if !ok {
@@ -154,12 +189,12 @@ func (ati *ActionTreeIndividual) GetMetrics() map[string]float64 {
 }
 
 // NewRandomActionTreeIndividual creates a new ActionTreeIndividual with random trees
-func NewRandomActionTreeIndividual(actions []ActionTuple, maxDepth int, operands []string, variables []string, terminals []string) *ActionTreeIndividual {
+func NewRandomActionTreeIndividual(r *rng.Rand, actions []ActionTuple, maxDepth int, operands []string, variables []string, terminals []string) *ActionTreeIndividual {
 	trees := make(map[string]*Tree)
 
 	// Create random tree for each action
 	for _, action := range actions {
-		tree := NewRandomTree(maxDepth, operands, variables, terminals)
+		tree := NewRandomTree(r, maxDepth, operands, variables, terminals)
 		trees[action.Name] = tree
 	}
 
@@ -15,8 +15,8 @@ func TestNewActionTreeIndividual_GIVEN_actions_and_trees_WHEN_created_THEN_has_c
 	variables := []string{"x", "y"}
 	terminals := []string{"1", "2"}
 
-	tree1 := individual.NewRandomTree(2, operands, variables, terminals)
-	tree2 := individual.NewRandomTree(2, operands, variables, terminals)
+	tree1 := individual.NewRandomTree(nil, 2, operands, variables, terminals)
+	tree2 := individual.NewRandomTree(nil, 2, operands, variables, terminals)
 
 	initialTrees := map[string]*individual.Tree{
 		"move_east": tree1,
@@ -44,7 +44,7 @@ func TestNewRandomActionTreeIndividual_GIVEN_parameters_WHEN_created_THEN_has_ra
 	terminals := []string{"1", "2"}
 
 	// Create ActionTreeIndividual with random trees
-	ati := individual.NewRandomActionTreeIndividual(actions, maxDepth, operands, variables, terminals)
+	ati := individual.NewRandomActionTreeIndividual(nil, actions, maxDepth, operands, variables, terminals)
 	// Verify structure
 	assert.NotNil(t, ati)
 	assert.Equal(t, 2, len(ati.Trees))
@@ -67,8 +67,8 @@ func TestNewRandomActionTreeIndividual_GIVEN_same_parameters_WHEN_created_multip
 	terminals := []string{"1", "2"}
 
 	// Create two individuals with same parameters
-	ati1 := individual.NewRandomActionTreeIndividual(actions, maxDepth, operands, variables, terminals)
-	ati2 := individual.NewRandomActionTreeIndividual(actions, maxDepth, operands, variables, terminals)
+	ati1 := individual.NewRandomActionTreeIndividual(nil, actions, maxDepth, operands, variables, terminals)
+	ati2 := individual.NewRandomActionTreeIndividual(nil, actions, maxDepth, operands, variables, terminals)
 
 	// They should have different trees (randomness)
 	tree1Desc := ati1.Trees["action1"].Describe()
@@ -86,7 +86,7 @@ func TestActionTreeIndividual_Clone_GIVEN_individual_WHEN_cloned_THEN_deep_copy(
 	terminals := []string{"1"}
 
 	// Create original
-	original := individual.NewRandomActionTreeIndividual(actions, 2, operands, variables, terminals)
+	original := individual.NewRandomActionTreeIndividual(nil, actions, 2, operands, variables, terminals)
 	original.SetFitness(42.0)
 
 	// Clone it
@@ -9,13 +9,14 @@ import (
 type BinaryIndividual struct {
 	Genome  []byte
 	Fitness float64
+	RateGene
 }
 
 // NewBinaryIndividual creates a new binary individual with random genome
-func NewBinaryIndividual(genomeSize int) *BinaryIndividual {
+func NewBinaryIndividual(r *rng.Rand, genomeSize int) *BinaryIndividual {
 	genome := make([]byte, genomeSize)
 	for i := range genome {
-		genome[i] = '0' + byte(rng.Intn(2))
+		genome[i] = '0' + byte(r.Intn(2))
 	}
 
 	b := BinaryIndividual{Genome: genome}
@@ -45,12 +46,13 @@ func (i *BinaryIndividual) Max(i2 Evolvable) Evolvable {
 }
 
 // Mutate performs mutation on the genome at specified points
-func (i *BinaryIndividual) Mutate(mutationRate float64, _ *MutateInformation) {
-	if mutationRate < rng.Float64() {
+func (i *BinaryIndividual) Mutate(mutationRate float64, mutateInformation *MutateInformation) {
+	r := mutateInformation.rand()
+	if mutationRate < r.Float64() {
 		return
 	}
 	for j := range len(i.Genome) {
-		if mutationRate > rng.Float64() {
+		if mutationRate > r.Float64() {
 			i.Genome[j] ^= 1 // Flip '0' <-> '1'
 		}
 	}
@@ -68,7 +70,7 @@ func (i *BinaryIndividual) MultiPointCrossover(i2 Evolvable, crossoverInformatio
 	newI2Genome := make([]byte, 0, len(i.Genome))
 
 	for range crossoverInformation.CrossoverPoints {
-		crossoverPointArray = append(crossoverPointArray, rng.Intn(len(i.Genome)))
+		crossoverPointArray = append(crossoverPointArray, crossoverInformation.rand().Intn(len(i.Genome)))
 	}
 	sort.Ints(crossoverPointArray)
 
@@ -102,8 +104,9 @@ func (i *BinaryIndividual) Clone() Evolvable {
 	genomeCopy := make([]byte, len(i.Genome))
 	copy(genomeCopy, i.Genome)
 	return &BinaryIndividual{
-		Genome:  genomeCopy,
-		Fitness: i.Fitness,
+		Genome:   genomeCopy,
+		Fitness:  i.Fitness,
+		RateGene: i.RateGene,
 	}
 }
 
@@ -8,7 +8,7 @@ import (
 )
 
 func TestNewBinaryIndividual_GIVEN_genome_size_WHEN_create_THEN_random_genome_and_fitness_calculated(t *testing.T) {
-	ind := individual.NewBinaryIndividual(5)
+	ind := individual.NewBinaryIndividual(nil, 5)
 
 	assert.NotNil(t, ind)
 	assert.Len(t, ind.Genome, 5)
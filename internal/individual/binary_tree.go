@@ -21,6 +21,7 @@ type Tree struct {
 	Root    *TreeNode
 	Fitness float64
 	depth   int
+	RateGene
 }
 
 // Operand represents the type of operation in the tree nodes
@@ -59,7 +60,7 @@ func applyOperator(opStr string, left, right float64, dividedByZero *bool) float
 }
 
 // newFullTree generates a tree where all non-leaf nodes are functions and all leaves are at max Depth
-func NewFullTree(depth int, operandSet []string, variableSet []string, terminalSet []string) *Tree {
+func NewFullTree(r *rng.Rand, depth int, operandSet []string, variableSet []string, terminalSet []string) *Tree {
 	functionSet := make([]Operand, 0, len(operandSet))
 	for _, prim := range operandSet {
 		functionSet = append(functionSet, Operand(prim))
@@ -67,27 +68,27 @@ func NewFullTree(depth int, operandSet []string, variableSet []string, terminalS
 	overallTerminalSet := append(terminalSet, variableSet...)
 
 	return &Tree{
-		Root:  newFullTreeNode(depth, overallTerminalSet, functionSet),
+		Root:  newFullTreeNode(r, depth, overallTerminalSet, functionSet),
 		depth: depth,
 	}
 }
 
 // newFullTreeNode generates a full tree node (functions at all non-zero Depths)
-func newFullTreeNode(depth int, terminalSet []string, functionSet []Operand) *TreeNode {
+func newFullTreeNode(r *rng.Rand, depth int, terminalSet []string, functionSet []Operand) *TreeNode {
 	if depth == 0 {
-		return &TreeNode{Value: terminalSet[rng.Intn(len(terminalSet))]}
+		return &TreeNode{Value: terminalSet[r.Intn(len(terminalSet))]}
 	}
 
-	op := functionSet[rng.Intn(len(functionSet))]
+	op := functionSet[r.Intn(len(functionSet))]
 	return &TreeNode{
 		Value: string(op),
-		Left:  newFullTreeNode(depth-1, terminalSet, functionSet),
-		Right: newFullTreeNode(depth-1, terminalSet, functionSet),
+		Left:  newFullTreeNode(r, depth-1, terminalSet, functionSet),
+		Right: newFullTreeNode(r, depth-1, terminalSet, functionSet),
 	}
 }
 
 // newGrowTree generates a tree where nodes can be functions or terminals at any Depth
-func newGrowTree(maxDepth int, operandSet []string, variableSet []string, terminalSet []string) *Tree {
+func newGrowTree(r *rng.Rand, maxDepth int, operandSet []string, variableSet []string, terminalSet []string) *Tree {
 	functionSet := make([]Operand, 0, len(operandSet))
 	for _, prim := range operandSet {
 		functionSet = append(functionSet, Operand(prim))
@@ -95,45 +96,45 @@ func newGrowTree(maxDepth int, operandSet []string, variableSet []string, termin
 	overallTerminalSet := append(terminalSet, variableSet...)
 
 	return &Tree{
-		Root: newGrowTreeNode(maxDepth, maxDepth, overallTerminalSet, functionSet),
+		Root: newGrowTreeNode(r, maxDepth, maxDepth, overallTerminalSet, functionSet),
 	}
 }
 
 // newGrowTreeNode generates a grow tree node (can choose between function and terminal)
-func newGrowTreeNode(depth int, maxDepth int, terminalSet []string, functionSet []Operand) *TreeNode {
+func newGrowTreeNode(r *rng.Rand, depth int, maxDepth int, terminalSet []string, functionSet []Operand) *TreeNode {
 	if depth == 0 {
-		return &TreeNode{Value: terminalSet[rng.Intn(len(terminalSet))]}
+		return &TreeNode{Value: terminalSet[r.Intn(len(terminalSet))]}
 	}
 
 	// At non-zero Depth, randomly choose between function and terminal
 	p := 1.0 - (float64(depth) / float64(maxDepth))
-	if rng.Float64() < p && depth != maxDepth {
+	if r.Float64() < p && depth != maxDepth {
 		// Choose terminal
-		return &TreeNode{Value: terminalSet[rng.Intn(len(terminalSet))]}
+		return &TreeNode{Value: terminalSet[r.Intn(len(terminalSet))]}
 	}
 
 	// Choose function
-	op := functionSet[rng.Intn(len(functionSet))]
+	op := functionSet[r.Intn(len(functionSet))]
 	return &TreeNode{
 		Value: string(op),
-		Left:  newGrowTreeNode(depth-1, maxDepth, terminalSet, functionSet),
-		Right: newGrowTreeNode(depth-1, maxDepth, terminalSet, functionSet),
+		Left:  newGrowTreeNode(r, depth-1, maxDepth, terminalSet, functionSet),
+		Right: newGrowTreeNode(r, depth-1, maxDepth, terminalSet, functionSet),
 	}
 }
 
 // NewRandomTree generates a random expression tree using ramped half-and-half method
-func NewRandomTree(maxDepth int, operandSet []string, variableSet []string, terminalSet []string) *Tree {
+func NewRandomTree(r *rng.Rand, maxDepth int, operandSet []string, variableSet []string, terminalSet []string) *Tree {
 	// For single tree creation, use random depth between 0 and maxDepth
 	// This maintains compatibility with existing usage
-	depth := rng.Intn(maxDepth + 1)
+	depth := r.Intn(maxDepth + 1)
 
 	// Randomly choose between grow (50%) and full (50%) methods
-	if rng.Float64() < 0.5 {
-		tree := newGrowTree(maxDepth, operandSet, variableSet, terminalSet)
+	if r.Float64() < 0.5 {
+		tree := newGrowTree(r, maxDepth, operandSet, variableSet, terminalSet)
 		tree.depth = tree.Root.CalculateMaxDepth()
 		return tree
 	}
-	return NewFullTree(depth, operandSet, variableSet, terminalSet)
+	return NewFullTree(r, depth, operandSet, variableSet, terminalSet)
 }
 
 // GetDepth returns the Depth of the tree
@@ -182,12 +183,12 @@ func (t *TreeNode) CalculateMaxDepth() int {
 
 }
 
-func (t *Tree) CalculateCrossoverPoint(otherTreeDepth int, maxDepth int) (*TreeNode, *TreeNode, bool) {
+func (t *Tree) CalculateCrossoverPoint(r *rng.Rand, otherTreeDepth int, maxDepth int) (*TreeNode, *TreeNode, bool) {
 	maxTreeDepth := t.Root.CalculateMaxDepth()
 	if maxTreeDepth <= 0 {
 		return nil, nil, false
 	}
-	treeDepth := max(rng.Intn(maxTreeDepth+1), 1)
+	treeDepth := max(r.Intn(maxTreeDepth+1), 1)
 	leftNodeSelected := true
 
 	treeNode := t.Root
@@ -199,7 +200,7 @@ func (t *Tree) CalculateCrossoverPoint(otherTreeDepth int, maxDepth int) (*TreeN
 		if ((otherTreeDepth+treeNode.CalculateMaxDepth()) <= maxDepth && i >= treeDepth) || treeNode.IsLeaf() {
 			break
 		}
-		if rng.Intn(2) == 1 && treeNode.Left != nil {
+		if r.Intn(2) == 1 && treeNode.Left != nil {
 			leftNodeSelected = true
 			prevTreeNode = treeNode
 			treeNode = treeNode.Left
@@ -215,24 +216,48 @@ func (t *Tree) CalculateCrossoverPoint(otherTreeDepth int, maxDepth int) (*TreeN
 
 }
 
-// MultiPointCrossover performs multi-point crossover between two trees
+// MultiPointCrossover recombines two trees with the crossover named by
+// crossoverInformation.CrossoverType, empty meaning TreeCrossoverRandomPath. A child breaking the
+// depth or size limit is put back to its parent.
 func (t *Tree) MultiPointCrossover(t2 Evolvable, crossoverInformation *CrossoverInformation) (Evolvable, Evolvable) {
 	tree2, ok := t2.(*Tree)
 	if !ok {
 		panic("Need Tree for Crossover")
 	}
 
+	r := crossoverInformation.rand()
+	parent1, parent2 := t.Root.cloneNode(), tree2.Root.cloneNode()
+	switch crossoverInformation.CrossoverType {
+	case TreeCrossoverKoza:
+		kozaCrossover(r, t, tree2)
+	case TreeCrossoverSizeFair:
+		sizeFairCrossover(r, t, tree2)
+	case TreeCrossoverOnePoint:
+		onePointCrossover(r, t, tree2)
+	case TreeCrossoverUniform:
+		uniformCrossover(r, t, tree2)
+	default:
+		t.randomPathCrossover(r, tree2, crossoverInformation.MaxDepth)
+	}
+	t.enforceLimits(parent1, crossoverInformation.MaxDepth, crossoverInformation.MaxSize)
+	tree2.enforceLimits(parent2, crossoverInformation.MaxDepth, crossoverInformation.MaxSize)
+
+	return t, tree2
+}
+
+// randomPathCrossover swaps the subtrees found by CalculateCrossoverPoint
+func (t *Tree) randomPathCrossover(r *rng.Rand, tree2 *Tree, maxDepth int) {
 	// Handle case where either tree has Depth 0 (no crossover possible)
 	if t.depth <= 0 || tree2.depth <= 0 {
-		return t, tree2
+		return
 	}
 
-	prevFirstTreeNode, firstTreeNode, leftFirstNodeSelected := t.CalculateCrossoverPoint(tree2.depth, crossoverInformation.MaxDepth)
-	prevSecondTreeNode, secondTreeNode, leftSecondNodeSelected := tree2.CalculateCrossoverPoint(t.depth, crossoverInformation.MaxDepth)
+	prevFirstTreeNode, firstTreeNode, leftFirstNodeSelected := t.CalculateCrossoverPoint(r, tree2.depth, maxDepth)
+	prevSecondTreeNode, secondTreeNode, leftSecondNodeSelected := tree2.CalculateCrossoverPoint(r, t.depth, maxDepth)
 
 	// Check if crossover points are valid
 	if prevFirstTreeNode == nil || prevSecondTreeNode == nil || firstTreeNode == nil || secondTreeNode == nil {
-		return t, tree2
+		return
 	}
 
 	if leftFirstNodeSelected {
@@ -248,14 +273,20 @@ func (t *Tree) MultiPointCrossover(t2 Evolvable, crossoverInformation *Crossover
 
 	t.depth = t.Root.CalculateMaxDepth()
 	tree2.depth = tree2.Root.CalculateMaxDepth()
-
-	return t, tree2
 }
 
-// Mutate mutates the tree based on the given mutation rate (interface compatibility)
+// Mutate mutates the tree based on the given mutation rate, per node or per individual as
+// mutateInformation.MutationMode says. A mutation growing the tree past MaxSize is undone.
 func (t *Tree) Mutate(rate float64, mutateInformation *MutateInformation) {
 	newSet := append(mutateInformation.TerminalSet, mutateInformation.VariableSet...)
-	t.Root = t.Root.mutateRecursive(rate, mutateInformation.OperandSet, newSet, mutateInformation.MaxDepth, 0)
+	var parent *TreeNode
+	if mutateInformation.MaxSize > 0 {
+		parent = t.Root.cloneNode()
+	}
+	newTreeMutator(rate, mutateInformation).mutate(t.Root, mutateInformation.MutationMode)
+	if parent != nil && t.Root.size() > mutateInformation.MaxSize {
+		t.Root = parent
+	}
 	// Update tree depth after mutation
 	t.depth = t.Root.CalculateMaxDepth()
 
@@ -266,7 +297,7 @@ func (t *Tree) Mutate(rate float64, mutateInformation *MutateInformation) {
 		for _, prim := range mutateInformation.OperandSet {
 			functionSet = append(functionSet, Operand(prim))
 		}
-		t.Root = newGrowTreeNode(1, 1, newSet, functionSet)
+		t.Root = newGrowTreeNode(mutateInformation.rand(), 1, 1, newSet, functionSet)
 		t.depth = 1
 	}
 }
@@ -318,7 +349,7 @@ func (tn *TreeNode) IsLeaf() bool {
 }
 
 // MutateTerminal replaces a terminal node with a different terminal from the set
-func (tn *TreeNode) MutateTerminal(terminalSet []string) {
+func (tn *TreeNode) MutateTerminal(r *rng.Rand, terminalSet []string) {
 	currentTerminal := tn.Value
 	availableTerminals := make([]string, 0, len(terminalSet))
 
@@ -330,13 +361,13 @@ func (tn *TreeNode) MutateTerminal(terminalSet []string) {
 	}
 
 	if len(availableTerminals) > 0 {
-		newTerminal := availableTerminals[rng.Intn(len(availableTerminals))]
+		newTerminal := availableTerminals[r.Intn(len(availableTerminals))]
 		tn.Value = newTerminal
 	}
 }
 
 // MutateFunction replaces a function node with a different function from the primitive set
-func (tn *TreeNode) MutateFunction(primitiveSet []string) {
+func (tn *TreeNode) MutateFunction(r *rng.Rand, primitiveSet []string) {
 	currentFunction := tn.Value
 	availableFunctions := make([]string, 0, len(primitiveSet))
 
@@ -348,7 +379,7 @@ func (tn *TreeNode) MutateFunction(primitiveSet []string) {
 	}
 
 	if len(availableFunctions) > 0 {
-		newFunction := availableFunctions[rng.Intn(len(availableFunctions))]
+		newFunction := availableFunctions[r.Intn(len(availableFunctions))]
 		tn.Value = newFunction
 	}
 }
@@ -356,7 +387,7 @@ func (tn *TreeNode) MutateFunction(primitiveSet []string) {
 // shrinkNode replaces a non-terminal node's subtree with a terminal
 // currentDepth is the depth from root to this node (0 for root)
 // Returns false if shrinking would create a depth 0 tree (single terminal)
-func (tn *TreeNode) shrinkNode(terminalSet []string, currentDepth int) bool {
+func (tn *TreeNode) shrinkNode(r *rng.Rand, terminalSet []string, currentDepth int) bool {
 	if tn == nil || tn.IsLeaf() {
 		return false // Cannot shrink a terminal node
 	}
@@ -375,7 +406,7 @@ func (tn *TreeNode) shrinkNode(terminalSet []string, currentDepth int) bool {
 	}
 
 	// Replace this node with a random terminal
-	newTerminal := terminalSet[rng.Intn(len(terminalSet))]
+	newTerminal := terminalSet[r.Intn(len(terminalSet))]
 	tn.Value = newTerminal
 	tn.Left = nil
 	tn.Right = nil
@@ -383,7 +414,7 @@ func (tn *TreeNode) shrinkNode(terminalSet []string, currentDepth int) bool {
 }
 
 // growNode replaces a terminal node with a function node and children
-func (tn *TreeNode) growNode(maxDepth int, currentDepth int, operandSet []string, terminalSet []string) bool {
+func (tn *TreeNode) growNode(r *rng.Rand, maxDepth int, currentDepth int, operandSet []string, terminalSet []string) bool {
 	if tn == nil || !tn.IsLeaf() {
 		return false // Can only grow terminal nodes
 	}
@@ -403,73 +434,15 @@ func (tn *TreeNode) growNode(maxDepth int, currentDepth int, operandSet []string
 		functionSet = append(functionSet, Operand(prim))
 	}
 
-	op := functionSet[rng.Intn(len(functionSet))]
+	op := functionSet[r.Intn(len(functionSet))]
 	tn.Value = string(op)
 
 	// Create children with remaining depth
-	tn.Left = newGrowTreeNode(remainingDepth-1, maxDepth, terminalSet, functionSet)
-	tn.Right = newGrowTreeNode(remainingDepth-1, maxDepth, terminalSet, functionSet)
+	tn.Left = newGrowTreeNode(r, remainingDepth-1, maxDepth, terminalSet, functionSet)
+	tn.Right = newGrowTreeNode(r, remainingDepth-1, maxDepth, terminalSet, functionSet)
 	return true
 }
 
-// mutateRecursive traverses the tree and gives each node a chance to mutate
-func (tn *TreeNode) mutateRecursive(rate float64, primitiveSet []string, terminalSet []string, maxDepth int, currentDepth int) *TreeNode {
-	// First, recursively mutate children (if any)
-	if tn.Left != nil {
-		tn.Left = tn.Left.mutateRecursive(rate, primitiveSet, terminalSet, maxDepth, currentDepth+1)
-	}
-	if tn.Right != nil {
-		tn.Right = tn.Right.mutateRecursive(rate, primitiveSet, terminalSet, maxDepth, currentDepth+1)
-	}
-
-	// Then, decide if this node should mutate
-	if rng.Float64() < rate {
-		// Decide mutation type: 60% value mutation, 20% shrink, 20% grow
-		mutationType := rng.Float64()
-
-		if mutationType < 0.6 {
-			// Value mutation (current behavior)
-			if tn.IsLeaf() {
-				tn.MutateTerminal(terminalSet)
-			} else {
-				tn.MutateFunction(primitiveSet)
-			}
-		} else if mutationType < 0.8 {
-			// Shrink mutation (20% probability)
-			// Only attempt if this is a non-terminal node
-			if !tn.IsLeaf() {
-				if tn.shrinkNode(terminalSet, currentDepth) {
-					// Shrink was successful, node is now a terminal
-					return tn
-				}
-			}
-			// If shrink failed, fall back to value mutation
-			if tn.IsLeaf() {
-				tn.MutateTerminal(terminalSet)
-			} else {
-				tn.MutateFunction(primitiveSet)
-			}
-		} else {
-			// Grow mutation (20% probability)
-			// Only attempt if this is a terminal node
-			if tn.IsLeaf() {
-				if tn.growNode(maxDepth, currentDepth, primitiveSet, terminalSet) {
-					// Grow was successful
-					return tn
-				}
-			}
-			// If grow failed, fall back to value mutation
-			if tn.IsLeaf() {
-				tn.MutateTerminal(terminalSet)
-			} else {
-				tn.MutateFunction(primitiveSet)
-			}
-		}
-	}
-
-	return tn
-}
-
 func (t *Tree) GetMetrics() map[string]float64 {
 	return map[string]float64{
 		"fit":   t.Fitness,
@@ -506,9 +479,10 @@ func countRec(node *TreeNode, freq map[string]int) int {
 func (t *Tree) Clone() Evolvable {
 	clonedRoot := t.Root.cloneNode()
 	return &Tree{
-		Root:    clonedRoot,
-		Fitness: t.Fitness,
-		depth:   t.depth,
+		Root:     clonedRoot,
+		Fitness:  t.Fitness,
+		depth:    t.depth,
+		RateGene: t.RateGene,
 	}
 }
 
@@ -538,9 +512,9 @@ func TreeToJSON(t *Tree) string {
 
 // NewRampedHalfAndHalfTree generates a tree with specified Depth using ramped half-and-half
 // This is useful for population initialization where specific Depths are needed
-func NewRampedHalfAndHalfTree(depth int, useGrow bool, operandSet []string, variableSet []string, terminalSet []string) *Tree {
+func NewRampedHalfAndHalfTree(r *rng.Rand, depth int, useGrow bool, operandSet []string, variableSet []string, terminalSet []string) *Tree {
 	if useGrow {
-		return newGrowTree(depth, operandSet, variableSet, terminalSet)
+		return newGrowTree(r, depth, operandSet, variableSet, terminalSet)
 	}
-	return NewFullTree(depth, operandSet, variableSet, terminalSet)
+	return NewFullTree(r, depth, operandSet, variableSet, terminalSet)
 }
@@ -12,7 +12,7 @@ func TestNewRandomTree_GIVEN_max_depth_zero_WHEN_new_random_tree_THEN_returns_le
 	primitiveSet := []string{"+", "-", "*", "/"}
 	terminalSet := []string{"1.0", "2.0", "3.0"}
 	variableSet := []string{"x", "y"}
-	tree := individual.NewRandomTree(0, primitiveSet, terminalSet, variableSet)
+	tree := individual.NewRandomTree(nil, 0, primitiveSet, terminalSet, variableSet)
 	combinedSet := append(terminalSet, variableSet...)
 	assert.NotNil(t, tree)
 	assert.NotNil(t, tree.Root)
@@ -42,7 +42,7 @@ func TestTreeNode_mutateTerminal_GIVEN_terminal_WHEN_mutate_THEN_changes_to_diff
 	node := &individual.TreeNode{Value: "x"}
 
 	originalValue := node.Value
-	node.MutateTerminal(terminalSet)
+	node.MutateTerminal(nil, terminalSet)
 
 	assert.NotEqual(t, originalValue, node.Value)
 	assert.Contains(t, terminalSet, node.Value)
@@ -53,8 +53,41 @@ func TestTreeNode_mutateFunction_GIVEN_function_WHEN_mutate_THEN_changes_to_diff
 	node := &individual.TreeNode{Value: "+"}
 
 	originalValue := node.Value
-	node.MutateFunction(primitiveSet)
+	node.MutateFunction(nil, primitiveSet)
 
 	assert.NotEqual(t, originalValue, node.Value)
 	assert.Contains(t, primitiveSet, node.Value)
 }
+
+func TestTree_Mutate_GIVEN_point_weights_only_WHEN_mutated_THEN_shape_kept(t *testing.T) {
+	tree := individual.NewRandomTree(nil, 4, []string{"+", "-"}, []string{"x"}, []string{"1", "2"})
+	nodes, _ := tree.CountNodesAndVars()
+	info := &individual.MutateInformation{
+		OperandSet:      []string{"+", "-"},
+		VariableSet:     []string{"x"},
+		TerminalSet:     []string{"1", "2"},
+		MaxDepth:        6,
+		MutationWeights: map[string]float64{individual.TreeMutationPoint: 1},
+	}
+
+	tree.Mutate(1, info)
+	after, _ := tree.CountNodesAndVars()
+
+	assert.Equal(t, nodes, after)
+}
+
+func TestTree_Mutate_GIVEN_no_weighted_type_WHEN_mutated_THEN_unchanged(t *testing.T) {
+	tree := individual.NewRandomTree(nil, 4, []string{"+", "-"}, []string{"x"}, []string{"1", "2"})
+	before := tree.Describe()
+	info := &individual.MutateInformation{
+		OperandSet:      []string{"+", "-"},
+		VariableSet:     []string{"x"},
+		TerminalSet:     []string{"1", "2"},
+		MaxDepth:        6,
+		MutationWeights: map[string]float64{"unknown": 1},
+	}
+
+	tree.Mutate(1, info)
+
+	assert.Equal(t, before, tree.Describe())
+}
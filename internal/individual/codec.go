@@ -0,0 +1,190 @@
+package individual
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// encoded is the wire form of an individual: its genome type, the genome itself and any
+// mutation rate it carries
+type encoded struct {
+	Type         string          `json:"type"`
+	Genome       json.RawMessage `json:"genome"`
+	MutationRate float64         `json:"mutation_rate,omitempty"`
+}
+
+type encodedWeights struct {
+	Rows    int       `json:"rows"`
+	Cols    int       `json:"cols"`
+	Data    []float64 `json:"data"`
+	Fitness float64   `json:"fitness"`
+	Min     float64   `json:"min"`
+	Max     float64   `json:"max"`
+}
+
+type encodedActionTree struct {
+	Trees   map[string]*TreeNode `json:"trees"`
+	Fitness float64              `json:"fitness"`
+}
+
+type encodedGrammarTree struct {
+	Genome  []int   `json:"genome"`
+	Fitness float64 `json:"fitness"`
+}
+
+// Marshal encodes an individual with its genome type so Unmarshal can rebuild it, e.g. in
+// another process
+func Marshal(evolvable Evolvable) ([]byte, error) {
+	var kind string
+	var genome any
+	switch ind := evolvable.(type) {
+	case *BinaryIndividual:
+		kind, genome = "bitstring", ind
+	case *Tree:
+		kind, genome = "tree", ind
+	case *GrammarTree:
+		kind, genome = "grammar_tree", encodedGrammarTree{Genome: ind.Genome, Fitness: ind.Fitness}
+	case *RealVectorIndividual:
+		kind, genome = "real_vector", ind
+	case *WeightsIndividual:
+		rows, cols := ind.Weights.Dims()
+		data := make([]float64, 0, rows*cols)
+		for i := range rows {
+			data = append(data, ind.Weights.RawRowView(i)...)
+		}
+		kind, genome = "weights", encodedWeights{Rows: rows, Cols: cols, Data: data, Fitness: ind.fitness, Min: ind.minVal, Max: ind.maxVal}
+	case *ActionTreeIndividual:
+		trees := make(map[string]*TreeNode, len(ind.Trees))
+		for name, tree := range ind.Trees {
+			trees[name] = tree.Root
+		}
+		kind, genome = "action_tree", encodedActionTree{Trees: trees, Fitness: ind.fitness}
+	default:
+		return nil, fmt.Errorf("cannot encode individual of type %T", evolvable)
+	}
+
+	data, err := json.Marshal(genome)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", kind, err)
+	}
+	enc := encoded{Type: kind, Genome: data}
+	if adaptive, ok := evolvable.(SelfAdaptive); ok {
+		enc.MutationRate = adaptive.MutationRate()
+	}
+	return json.Marshal(enc)
+}
+
+// Unmarshal rebuilds an individual encoded by Marshal
+func Unmarshal(data []byte) (Evolvable, error) {
+	var enc encoded
+	if err := json.Unmarshal(data, &enc); err != nil {
+		return nil, fmt.Errorf("failed to decode individual: %w", err)
+	}
+	ind, err := decodeGenome(enc)
+	if err != nil {
+		return nil, err
+	}
+	if adaptive, ok := ind.(SelfAdaptive); ok {
+		adaptive.SetMutationRate(enc.MutationRate)
+	}
+	return ind, nil
+}
+
+// decodeGenome rebuilds the genome of an encoded individual
+func decodeGenome(enc encoded) (Evolvable, error) {
+
+	var err error
+	switch enc.Type {
+	case "bitstring":
+		ind := &BinaryIndividual{}
+		err = json.Unmarshal(enc.Genome, ind)
+		return ind, err
+	case "tree":
+		ind := &Tree{}
+		if err = json.Unmarshal(enc.Genome, ind); err != nil {
+			return nil, err
+		}
+		if ind.Root == nil {
+			return nil, fmt.Errorf("tree has no root")
+		}
+		ind.depth = ind.Root.CalculateMaxDepth()
+		return ind, nil
+	case "grammar_tree":
+		var genome encodedGrammarTree
+		err = json.Unmarshal(enc.Genome, &genome)
+		return &GrammarTree{Genome: genome.Genome, Fitness: genome.Fitness}, err
+	case "real_vector":
+		ind := &RealVectorIndividual{}
+		err = json.Unmarshal(enc.Genome, ind)
+		return ind, err
+	case "weights":
+		var genome encodedWeights
+		if err = json.Unmarshal(enc.Genome, &genome); err != nil {
+			return nil, err
+		}
+		if genome.Rows*genome.Cols != len(genome.Data) || len(genome.Data) == 0 {
+			return nil, fmt.Errorf("weights of %dx%d have %d values", genome.Rows, genome.Cols, len(genome.Data))
+		}
+		return &WeightsIndividual{
+			Weights: mat.NewDense(genome.Rows, genome.Cols, genome.Data),
+			fitness: genome.Fitness,
+			minVal:  genome.Min,
+			maxVal:  genome.Max,
+		}, nil
+	case "action_tree":
+		var genome encodedActionTree
+		if err = json.Unmarshal(enc.Genome, &genome); err != nil {
+			return nil, err
+		}
+		trees := make(map[string]*Tree, len(genome.Trees))
+		for name, root := range genome.Trees {
+			if root == nil {
+				return nil, fmt.Errorf("action %s has no tree", name)
+			}
+			trees[name] = &Tree{Root: root, depth: root.CalculateMaxDepth()}
+		}
+		return &ActionTreeIndividual{Trees: trees, fitness: genome.Fitness}, nil
+	default:
+		return nil, fmt.Errorf("unknown individual type %q", enc.Type)
+	}
+}
+
+// WritePopulation writes one encoded individual per line
+func WritePopulation(w io.Writer, population []Evolvable) error {
+	writer := bufio.NewWriter(w)
+	for _, ind := range population {
+		data, err := Marshal(ind)
+		if err != nil {
+			return err
+		}
+		if _, err := writer.Write(append(data, '\n')); err != nil {
+			return fmt.Errorf("failed to write population: %w", err)
+		}
+	}
+	return writer.Flush()
+}
+
+// ReadPopulation reads individuals written by WritePopulation, skipping blank lines
+func ReadPopulation(r io.Reader) ([]Evolvable, error) {
+	var population []Evolvable
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		ind, err := Unmarshal(scanner.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		population = append(population, ind)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read population: %w", err)
+	}
+	return population, nil
+}
@@ -0,0 +1,85 @@
+package individual_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bxrne/darwin/internal/individual"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshal_GIVEN_each_genome_type_WHEN_round_tripped_THEN_genome_and_fitness_survive(t *testing.T) {
+	actions := []individual.ActionTuple{{Name: "move", Value: 2}, {Name: "turn", Value: 3}}
+	testCases := []struct {
+		name string
+		ind  individual.Evolvable
+	}{
+		{"BitString", individual.NewBinaryIndividual(nil, 16)},
+		{"Tree", individual.NewRandomTree(nil, 4, []string{"+", "*"}, []string{"x"}, []string{"1"})},
+		{"GrammarTree", individual.NewGrammarTree(nil, 10)},
+		{"RealVector", individual.NewRealVectorIndividual(nil, []individual.Bound{{Name: "a", Min: 0, Max: 1}, {Name: "b", Min: 1, Max: 5, Integer: true}})},
+		{"Weights", individual.NewWeightsIndividual(nil, 3, 4)},
+		{"ActionTree", individual.NewRandomActionTreeIndividual(nil, actions, 3, []string{"+"}, []string{"x"}, []string{"1"})},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.ind.SetFitness(0.75)
+
+			data, err := individual.Marshal(tc.ind)
+			assert.NoError(t, err)
+			decoded, err := individual.Unmarshal(data)
+
+			assert.NoError(t, err)
+			assert.IsType(t, tc.ind, decoded)
+			assert.Equal(t, 0.75, decoded.GetFitness())
+			again, err := individual.Marshal(decoded)
+			assert.NoError(t, err)
+			assert.JSONEq(t, string(data), string(again))
+		})
+	}
+}
+
+func TestMarshal_GIVEN_carried_mutation_rate_WHEN_round_tripped_THEN_rate_survives(t *testing.T) {
+	ind := individual.NewBinaryIndividual(nil, 8)
+	ind.SetMutationRate(0.07)
+
+	data, err := individual.Marshal(ind)
+	assert.NoError(t, err)
+	decoded, err := individual.Unmarshal(data)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0.07, decoded.(individual.SelfAdaptive).MutationRate())
+	assert.NotContains(t, string(mustMarshal(t, individual.NewBinaryIndividual(nil, 8))), "mutation_rate")
+}
+
+func mustMarshal(t *testing.T, ind individual.Evolvable) []byte {
+	data, err := individual.Marshal(ind)
+	assert.NoError(t, err)
+	return data
+}
+
+func TestUnmarshal_GIVEN_unknown_type_WHEN_decoded_THEN_returns_error(t *testing.T) {
+	_, err := individual.Unmarshal([]byte(`{"type": "quantum", "genome": {}}`))
+
+	assert.ErrorContains(t, err, "unknown individual type")
+}
+
+func TestWritePopulation_GIVEN_population_WHEN_read_back_THEN_same_individuals_in_order(t *testing.T) {
+	first, second := individual.NewBinaryIndividual(nil, 8), individual.NewBinaryIndividual(nil, 8)
+	first.SetFitness(0.5)
+	second.SetFitness(0.25)
+	var out strings.Builder
+
+	assert.NoError(t, individual.WritePopulation(&out, []individual.Evolvable{first, second}))
+	read, err := individual.ReadPopulation(strings.NewReader(out.String() + "\n"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []individual.Evolvable{first, second}, read)
+}
+
+func TestReadPopulation_GIVEN_bad_line_WHEN_read_THEN_reports_line(t *testing.T) {
+	_, err := individual.ReadPopulation(strings.NewReader("\n{\"type\": \"quantum\", \"genome\": {}}\n"))
+
+	assert.ErrorContains(t, err, "line 2")
+}
@@ -1,6 +1,8 @@
 // Individual package defines the Evolvable interface and related types for individuals to be evolved
 package individual
 
+import "github.com/bxrne/darwin/internal/rng"
+
 // Evolvable represents an individual that can evolve through genetic operations
 type Evolvable interface {
 	Mutate(rate float64, mutateInformation *MutateInformation)
@@ -20,11 +22,18 @@ const (
 	TreeGenome
 	GrammarTreeGenome
 	ActionTreeGenome
+	RealVectorGenome
 )
 
 type CrossoverInformation struct {
 	CrossoverPoints int
 	MaxDepth        int
+	// MaxSize caps the nodes of a tree child; zero is no cap
+	MaxSize int
+	// CrossoverType is one of TreeCrossoverTypes; empty means TreeCrossoverRandomPath
+	CrossoverType string
+	// Rand is the generator of the run; nil draws from the package generator
+	Rand *rng.Rand
 }
 
 type MutateInformation struct {
@@ -32,4 +41,32 @@ type MutateInformation struct {
 	TerminalSet []string
 	OperandSet  []string
 	MaxDepth    int
+	// MutationWeights are the relative chances of the tree mutation types in TreeMutationTypes;
+	// nil keeps the default mix
+	MutationWeights map[string]float64
+	// MutationMode is TreeMutationPerNode or TreeMutationPerIndividual; empty means per node
+	MutationMode string
+	// ConstantSigma is the standard deviation of TreeMutationConstant; zero means
+	// DefaultConstantSigma
+	ConstantSigma float64
+	// MaxSize caps the nodes of a mutated tree, undoing a mutation that exceeds it; zero is no cap
+	MaxSize int
+	// Rand is the generator of the run; nil draws from the package generator
+	Rand *rng.Rand
+}
+
+// rand is the generator to draw from, nil for the package generator
+func (ci *CrossoverInformation) rand() *rng.Rand {
+	if ci == nil {
+		return nil
+	}
+	return ci.Rand
+}
+
+// rand is the generator to draw from, nil for the package generator
+func (mi *MutateInformation) rand() *rng.Rand {
+	if mi == nil {
+		return nil
+	}
+	return mi.Rand
 }
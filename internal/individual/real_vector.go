@@ -0,0 +1,146 @@
+package individual
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bxrne/darwin/internal/rng"
+)
+
+// Bound is the inclusive range of one gene in a RealVectorIndividual
+type Bound struct {
+	Name    string
+	Min     float64
+	Max     float64
+	Integer bool
+}
+
+// RealVectorIndividual represents an individual with a bounded real-valued genome
+type RealVectorIndividual struct {
+	Genes   []float64
+	Bounds  []Bound
+	Fitness float64
+	RateGene
+}
+
+// NewRealVectorIndividual creates an individual with genes drawn uniformly within their bounds
+func NewRealVectorIndividual(r *rng.Rand, bounds []Bound) *RealVectorIndividual {
+	genes := make([]float64, len(bounds))
+	for i, b := range bounds {
+		genes[i] = b.Min + r.Float64()*(b.Max-b.Min)
+	}
+	ind := &RealVectorIndividual{Genes: genes, Bounds: bounds}
+	ind.clamp()
+	return ind
+}
+
+// Values returns the genes keyed by bound name
+func (rv *RealVectorIndividual) Values() map[string]float64 {
+	values := make(map[string]float64, len(rv.Genes))
+	for i, b := range rv.Bounds {
+		values[b.Name] = rv.Genes[i]
+	}
+	return values
+}
+
+// clamp keeps every gene inside its bound and rounds integer genes
+func (rv *RealVectorIndividual) clamp() {
+	for i, b := range rv.Bounds {
+		v := min(max(rv.Genes[i], b.Min), b.Max)
+		if b.Integer {
+			v = float64(int64(v + 0.5))
+		}
+		rv.Genes[i] = v
+	}
+}
+
+// GetFitness returns the fitness value
+func (rv *RealVectorIndividual) GetFitness() float64 {
+	return rv.Fitness
+}
+
+// SetFitness sets the fitness value
+func (rv *RealVectorIndividual) SetFitness(fitness float64) {
+	rv.Fitness = fitness
+}
+
+// Describe returns the genes as name=value pairs
+func (rv *RealVectorIndividual) Describe() string {
+	parts := make([]string, len(rv.Genes))
+	for i, b := range rv.Bounds {
+		parts[i] = fmt.Sprintf("%s=%g", b.Name, rv.Genes[i])
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, " ")
+}
+
+// Max returns the individual with higher fitness
+func (rv *RealVectorIndividual) Max(i2 Evolvable) Evolvable {
+	if rv.Fitness > i2.GetFitness() {
+		return rv
+	}
+	return i2
+}
+
+// Mutate perturbs each gene with probability rate by Gaussian noise scaled to a tenth of its range
+func (rv *RealVectorIndividual) Mutate(rate float64, mutateInformation *MutateInformation) {
+	r := mutateInformation.rand()
+	for i, b := range rv.Bounds {
+		if r.Float64() < rate {
+			rv.Genes[i] += r.NormFloat64() * (b.Max - b.Min) * 0.1
+		}
+	}
+	rv.clamp()
+}
+
+// MultiPointCrossover performs multi-point crossover with another individual
+func (rv *RealVectorIndividual) MultiPointCrossover(i2 Evolvable, crossoverInformation *CrossoverInformation) (Evolvable, Evolvable) {
+	o, ok := i2.(*RealVectorIndividual)
+	if !ok {
+		panic("MultiPointCrossover requires RealVectorIndividual")
+	}
+
+	crossoverPointArray := make([]int, 0, crossoverInformation.CrossoverPoints)
+	for range crossoverInformation.CrossoverPoints {
+		crossoverPointArray = append(crossoverPointArray, crossoverInformation.rand().Intn(len(rv.Genes)))
+	}
+	sort.Ints(crossoverPointArray)
+
+	newGenes1 := make([]float64, len(rv.Genes))
+	newGenes2 := make([]float64, len(rv.Genes))
+	swap := true
+	currentPointIndex := 0
+	for j := range rv.Genes {
+		if currentPointIndex < len(crossoverPointArray) && j >= crossoverPointArray[currentPointIndex] {
+			swap = !swap
+			currentPointIndex++
+		}
+		if swap {
+			newGenes1[j], newGenes2[j] = rv.Genes[j], o.Genes[j]
+		} else {
+			newGenes1[j], newGenes2[j] = o.Genes[j], rv.Genes[j]
+		}
+	}
+
+	return &RealVectorIndividual{Genes: newGenes1, Bounds: rv.Bounds},
+		&RealVectorIndividual{Genes: newGenes2, Bounds: rv.Bounds}
+}
+
+// Clone creates a deep copy of the individual; bounds are shared as they are never modified
+func (rv *RealVectorIndividual) Clone() Evolvable {
+	genes := make([]float64, len(rv.Genes))
+	copy(genes, rv.Genes)
+	return &RealVectorIndividual{Genes: genes, Bounds: rv.Bounds, Fitness: rv.Fitness, RateGene: rv.RateGene}
+}
+
+// GetMetrics reports fitness and every gene so hyperparameter trajectories show up in the CSV
+func (rv *RealVectorIndividual) GetMetrics() map[string]float64 {
+	metrics := map[string]float64{
+		"fit": rv.Fitness,
+	}
+	for i, b := range rv.Bounds {
+		metrics[b.Name] = rv.Genes[i]
+	}
+	return metrics
+}
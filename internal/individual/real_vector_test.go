@@ -0,0 +1,54 @@
+package individual_test
+
+import (
+	"testing"
+
+	"github.com/bxrne/darwin/internal/individual"
+	"github.com/stretchr/testify/assert"
+)
+
+var testBounds = []individual.Bound{
+	{Name: "rate", Min: 0, Max: 1},
+	{Name: "size", Min: 1, Max: 10, Integer: true},
+}
+
+func TestNewRealVectorIndividual_GIVEN_bounds_WHEN_create_THEN_genes_within_bounds(t *testing.T) {
+	ind := individual.NewRealVectorIndividual(nil, testBounds)
+
+	assert.Len(t, ind.Genes, 2)
+	assert.GreaterOrEqual(t, ind.Genes[0], 0.0)
+	assert.LessOrEqual(t, ind.Genes[0], 1.0)
+	assert.Equal(t, float64(int(ind.Genes[1])), ind.Genes[1], "integer gene should be whole")
+}
+
+func TestRealVectorIndividual_Mutate_GIVEN_full_rate_WHEN_mutate_THEN_genes_stay_in_bounds(t *testing.T) {
+	ind := individual.NewRealVectorIndividual(nil, testBounds)
+
+	for range 100 {
+		ind.Mutate(1.0, nil)
+		assert.GreaterOrEqual(t, ind.Genes[0], 0.0)
+		assert.LessOrEqual(t, ind.Genes[0], 1.0)
+		assert.GreaterOrEqual(t, ind.Genes[1], 1.0)
+		assert.LessOrEqual(t, ind.Genes[1], 10.0)
+	}
+}
+
+func TestRealVectorIndividual_MultiPointCrossover_GIVEN_parents_WHEN_crossover_THEN_genes_come_from_parents(t *testing.T) {
+	p1 := &individual.RealVectorIndividual{Genes: []float64{0.1, 2}, Bounds: testBounds}
+	p2 := &individual.RealVectorIndividual{Genes: []float64{0.9, 8}, Bounds: testBounds}
+
+	c1, c2 := p1.MultiPointCrossover(p2, &individual.CrossoverInformation{CrossoverPoints: 1})
+
+	child1 := c1.(*individual.RealVectorIndividual)
+	child2 := c2.(*individual.RealVectorIndividual)
+	for i := range child1.Genes {
+		assert.ElementsMatch(t, []float64{p1.Genes[i], p2.Genes[i]}, []float64{child1.Genes[i], child2.Genes[i]})
+	}
+}
+
+func TestRealVectorIndividual_Values_GIVEN_genes_WHEN_values_THEN_keyed_by_name(t *testing.T) {
+	ind := &individual.RealVectorIndividual{Genes: []float64{0.5, 3}, Bounds: testBounds}
+
+	assert.Equal(t, map[string]float64{"rate": 0.5, "size": 3}, ind.Values())
+	assert.Equal(t, "rate=0.5 size=3", ind.Describe())
+}
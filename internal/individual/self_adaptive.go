@@ -0,0 +1,24 @@
+package individual
+
+// SelfAdaptive is implemented by individuals carrying their own mutation rate, which is inherited
+// and varied along with the rest of the genome
+type SelfAdaptive interface {
+	MutationRate() float64
+	SetMutationRate(rate float64)
+}
+
+// RateGene is a mutation rate encoded in the genome; embedding it makes an individual
+// SelfAdaptive. Zero means the individual has no rate of its own yet.
+type RateGene struct {
+	rate float64
+}
+
+// MutationRate returns the carried rate, zero if unset
+func (g *RateGene) MutationRate() float64 {
+	return g.rate
+}
+
+// SetMutationRate replaces the carried rate
+func (g *RateGene) SetMutationRate(rate float64) {
+	g.rate = rate
+}
@@ -0,0 +1,207 @@
+package individual
+
+import (
+	"github.com/bxrne/darwin/internal/rng"
+)
+
+// Tree crossover types, set as CrossoverInformation.CrossoverType
+const (
+	// TreeCrossoverRandomPath walks a random path down each parent to a depth-limited point
+	TreeCrossoverRandomPath = "random_path"
+	// TreeCrossoverKoza swaps random subtrees, picking a function node 90% of the time and a
+	// terminal otherwise
+	TreeCrossoverKoza = "koza"
+	// TreeCrossoverSizeFair picks the first point as Koza does and the second among subtrees of
+	// similar size, so children are on average as large as their parents
+	TreeCrossoverSizeFair = "size_fair"
+	// TreeCrossoverOnePoint swaps the subtrees at one position both parents share
+	TreeCrossoverOnePoint = "one_point"
+	// TreeCrossoverUniform swaps each node the parents share with even odds: operators inside
+	// the shared region, whole subtrees at its edge
+	TreeCrossoverUniform = "uniform"
+)
+
+// TreeCrossoverTypes lists the tree crossover types
+var TreeCrossoverTypes = []string{
+	TreeCrossoverRandomPath,
+	TreeCrossoverKoza,
+	TreeCrossoverSizeFair,
+	TreeCrossoverOnePoint,
+	TreeCrossoverUniform,
+}
+
+// kozaFunctionProbability is the chance Koza crossover picks a function node over a terminal
+const kozaFunctionProbability = 0.9
+
+// point is a place in a tree holding a subtree: the root or a child link of a function node
+type point struct {
+	link **TreeNode
+	size int // nodes in the subtree
+}
+
+// points lists every point of the subtree at link in preorder, returning the subtree's size
+func points(link **TreeNode, out *[]point) int {
+	i := len(*out)
+	*out = append(*out, point{link: link})
+	size := 1
+	if node := *link; !node.IsLeaf() {
+		size += points(&node.Left, out)
+		size += points(&node.Right, out)
+	}
+	(*out)[i].size = size
+	return size
+}
+
+// swapSubtrees exchanges the subtrees at two points, copying them so the parents never share
+// nodes
+func swapSubtrees(a, b point) {
+	first, second := *a.link, *b.link
+	*a.link, *b.link = second.cloneNode(), first.cloneNode()
+}
+
+// kozaPoint picks a function node with kozaFunctionProbability, otherwise a terminal
+func kozaPoint(r *rng.Rand, all []point) point {
+	var functions, terminals []point
+	for _, p := range all {
+		if (*p.link).IsLeaf() {
+			terminals = append(terminals, p)
+		} else {
+			functions = append(functions, p)
+		}
+	}
+	if len(functions) > 0 && (len(terminals) == 0 || r.Float64() < kozaFunctionProbability) {
+		return functions[r.Intn(len(functions))]
+	}
+	return terminals[r.Intn(len(terminals))]
+}
+
+func kozaCrossover(r *rng.Rand, t1, t2 *Tree) {
+	var points1, points2 []point
+	points(&t1.Root, &points1)
+	points(&t2.Root, &points2)
+	swapSubtrees(kozaPoint(r, points1), kozaPoint(r, points2))
+}
+
+// sizeFairCrossover is Langdon's size-fair crossover. The second subtree has at most 1+2s nodes
+// for a first subtree of s nodes; it is the same size with probability 1/s, and otherwise smaller
+// or larger with odds making the expected change in size zero.
+func sizeFairCrossover(r *rng.Rand, t1, t2 *Tree) {
+	var points1, points2 []point
+	points(&t1.Root, &points1)
+	points(&t2.Root, &points2)
+	first := kozaPoint(r, points1)
+
+	var smaller, equal, larger []point
+	for _, p := range points2 {
+		switch {
+		case p.size > 1+2*first.size:
+		case p.size < first.size:
+			smaller = append(smaller, p)
+		case p.size == first.size:
+			equal = append(equal, p)
+		default:
+			larger = append(larger, p)
+		}
+	}
+
+	pEqual := 0.0
+	if len(equal) > 0 {
+		pEqual = 1 / float64(first.size)
+	}
+	pSmaller, pLarger := 0.0, 0.0
+	rest := 1 - pEqual
+	switch {
+	case len(smaller) > 0 && len(larger) > 0:
+		meanSmaller, meanLarger := meanSize(smaller), meanSize(larger)
+		s := float64(first.size)
+		pSmaller = rest * (meanLarger - s) / (meanLarger - meanSmaller)
+		pLarger = rest * (s - meanSmaller) / (meanLarger - meanSmaller)
+	case len(smaller) > 0:
+		pSmaller = rest
+	case len(larger) > 0:
+		pLarger = rest
+	default:
+		pEqual = 1
+	}
+
+	draw := r.Float64() * (pSmaller + pEqual + pLarger)
+	var class []point
+	switch {
+	case draw < pSmaller:
+		class = smaller
+	case draw < pSmaller+pEqual:
+		class = equal
+	default:
+		class = larger
+	}
+	swapSubtrees(first, class[r.Intn(len(class))])
+}
+
+func meanSize(ps []point) float64 {
+	total := 0
+	for _, p := range ps {
+		total += p.size
+	}
+	return float64(total) / float64(len(ps))
+}
+
+// commonRegion lists the pairs of points at the same position in both trees, down to where
+// either reaches a terminal
+func commonRegion(a, b **TreeNode, out *[][2]point) {
+	*out = append(*out, [2]point{{link: a}, {link: b}})
+	if (*a).IsLeaf() || (*b).IsLeaf() {
+		return
+	}
+	commonRegion(&(*a).Left, &(*b).Left, out)
+	commonRegion(&(*a).Right, &(*b).Right, out)
+}
+
+// onePointCrossover is Poli and Langdon's one-point crossover, swapping at a random position of
+// the parents' common region
+func onePointCrossover(r *rng.Rand, t1, t2 *Tree) {
+	var region [][2]point
+	commonRegion(&t1.Root, &t2.Root, &region)
+	pair := region[r.Intn(len(region))]
+	swapSubtrees(pair[0], pair[1])
+}
+
+// uniformCrossover is Poli and Langdon's uniform crossover over the parents' common region
+func uniformCrossover(r *rng.Rand, t1, t2 *Tree) {
+	var region [][2]point
+	commonRegion(&t1.Root, &t2.Root, &region)
+	for _, pair := range region {
+		if r.Float64() >= 0.5 {
+			continue
+		}
+		a, b := *pair[0].link, *pair[1].link
+		if a.IsLeaf() || b.IsLeaf() {
+			// At the edge of the region the subtrees below differ in shape, so they move whole
+			swapSubtrees(pair[0], pair[1])
+		} else {
+			a.Value, b.Value = b.Value, a.Value
+		}
+	}
+}
+
+// size counts the nodes of the subtree
+func (tn *TreeNode) size() int {
+	if tn == nil {
+		return 0
+	}
+	return 1 + tn.Left.size() + tn.Right.size()
+}
+
+// enforceLimits puts parent back as the tree's root if the tree grew deeper than maxDepth or
+// larger than maxSize nodes, or shrank to a lone terminal from a larger parent; a limit of zero
+// is no limit. The depth is recalculated either way.
+func (t *Tree) enforceLimits(parent *TreeNode, maxDepth int, maxSize int) {
+	depth := t.Root.CalculateMaxDepth()
+	tooDeep := maxDepth > 0 && depth > maxDepth
+	tooLarge := maxSize > 0 && t.Root.size() > maxSize
+	collapsed := depth == 0 && !parent.IsLeaf()
+	if tooDeep || tooLarge || collapsed {
+		t.Root = parent
+		depth = parent.CalculateMaxDepth()
+	}
+	t.depth = depth
+}
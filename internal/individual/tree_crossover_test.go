@@ -0,0 +1,128 @@
+package individual_test
+
+import (
+	"testing"
+
+	"github.com/bxrne/darwin/internal/individual"
+	"github.com/bxrne/darwin/internal/rng"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// treeSize counts the nodes of a tree
+func treeSize(t *individual.Tree) int {
+	return len(nodeValues(t.Root))
+}
+
+// crossed crosses copies of two trees as info says
+func crossed(a, b *individual.Tree, info individual.CrossoverInformation) (*individual.Tree, *individual.Tree) {
+	child1, child2 := a.Clone().MultiPointCrossover(b.Clone(), &info)
+	return child1.(*individual.Tree), child2.(*individual.Tree)
+}
+
+func TestTree_MultiPointCrossover_GIVEN_no_limits_WHEN_crossed_THEN_nodes_conserved(t *testing.T) {
+	rng.Seed(2)
+	for _, crossoverType := range individual.TreeCrossoverTypes[1:] {
+		t.Run(crossoverType, func(t *testing.T) {
+			for range 50 {
+				a := individual.NewRandomTree(nil, 4, []string{"+", "-", "*"}, []string{"x", "y"}, []string{"1"})
+				b := individual.NewRandomTree(nil, 4, []string{"+", "-", "*"}, []string{"x", "y"}, []string{"1"})
+
+				child1, child2 := crossed(a, b, individual.CrossoverInformation{CrossoverType: crossoverType})
+
+				// A child reduced to a lone terminal is put back to its parent
+				if child1.Describe() == a.Describe() || child2.Describe() == b.Describe() {
+					continue
+				}
+				assert.Equal(t, treeSize(a)+treeSize(b), treeSize(child1)+treeSize(child2))
+				assert.Positive(t, child1.GetDepth()+child2.GetDepth())
+			}
+		})
+	}
+}
+
+func TestTree_MultiPointCrossover_GIVEN_limits_WHEN_crossed_often_THEN_children_within_them(t *testing.T) {
+	rng.Seed(3)
+	info := individual.CrossoverInformation{MaxDepth: 4, MaxSize: 15}
+	for _, crossoverType := range individual.TreeCrossoverTypes {
+		t.Run(crossoverType, func(t *testing.T) {
+			info.CrossoverType = crossoverType
+			for range 100 {
+				a, b := fullTree(3), individual.NewRandomTree(nil, 4, []string{"+", "-", "*"}, []string{"x", "y"}, []string{"1"})
+				if treeSize(b) > info.MaxSize {
+					continue
+				}
+
+				child1, child2 := crossed(a, b, info)
+
+				for _, child := range []*individual.Tree{child1, child2} {
+					assert.LessOrEqual(t, child.GetDepth(), info.MaxDepth)
+					assert.Equal(t, child.Root.CalculateMaxDepth(), child.GetDepth(), "depth kept up to date")
+					assert.LessOrEqual(t, treeSize(child), info.MaxSize)
+				}
+			}
+		})
+	}
+}
+
+func TestTree_MultiPointCrossover_GIVEN_lone_terminal_parent_WHEN_koza_THEN_still_crosses(t *testing.T) {
+	rng.Seed(4)
+	leaf := &individual.Tree{Root: &individual.TreeNode{Value: "x"}}
+	info := individual.CrossoverInformation{CrossoverType: individual.TreeCrossoverKoza, MaxDepth: 5}
+
+	changed := false
+	for range 20 {
+		child1, child2 := crossed(leaf, fullTree(2), info)
+		require.NotNil(t, child2.Root)
+		changed = changed || child1.Describe() != "x"
+	}
+
+	assert.True(t, changed)
+}
+
+func TestTree_MultiPointCrossover_GIVEN_same_shape_WHEN_one_point_or_uniform_THEN_shape_and_positions_kept(t *testing.T) {
+	rng.Seed(6)
+	for _, crossoverType := range []string{individual.TreeCrossoverOnePoint, individual.TreeCrossoverUniform} {
+		t.Run(crossoverType, func(t *testing.T) {
+			for range 20 {
+				a, b := fullTree(3), fullTree(3)
+
+				child1, child2 := crossed(a, b, individual.CrossoverInformation{CrossoverType: crossoverType})
+
+				parents1, parents2 := nodeValues(a.Root), nodeValues(b.Root)
+				children1, children2 := nodeValues(child1.Root), nodeValues(child2.Root)
+				require.Len(t, children1, len(parents1))
+				require.Len(t, children2, len(parents2))
+				for i := range parents1 {
+					assert.ElementsMatch(t, []string{parents1[i], parents2[i]}, []string{children1[i], children2[i]})
+				}
+			}
+		})
+	}
+}
+
+func TestTree_MultiPointCrossover_GIVEN_small_and_large_parent_WHEN_size_fair_THEN_growth_bounded(t *testing.T) {
+	rng.Seed(7)
+	info := individual.CrossoverInformation{CrossoverType: individual.TreeCrossoverSizeFair}
+	small := fullTree(1)
+
+	for range 100 {
+		child1, _ := crossed(small, fullTree(4), info)
+
+		// Swapping out s nodes takes in at most 1+2s, and s is at most the whole small tree
+		assert.LessOrEqual(t, treeSize(child1), 1+2*treeSize(small))
+	}
+}
+
+func TestTree_Mutate_GIVEN_max_size_WHEN_growth_would_exceed_it_THEN_undone(t *testing.T) {
+	rng.Seed(9)
+	tree := fullTree(2)
+	info := mutationInfo(individual.TreeMutationGrow, individual.TreeMutationPerNode)
+	info.MaxSize = treeSize(tree)
+
+	for range 10 {
+		tree.Mutate(1, info)
+
+		assert.LessOrEqual(t, treeSize(tree), info.MaxSize)
+	}
+}
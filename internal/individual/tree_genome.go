@@ -12,13 +12,14 @@ type GrammarTree struct {
 	Genome  []int
 	Fitness float64
 	Depth   int
+	RateGene
 }
 
 // NewGrammarTree creates a new binary individual with random genome
-func NewGrammarTree(genomeSize int) *GrammarTree {
+func NewGrammarTree(r *rng.Rand, genomeSize int) *GrammarTree {
 	genome := make([]int, genomeSize)
 	for i := range genome {
-		genome[i] = rng.Intn(255)
+		genome[i] = r.Intn(255)
 	}
 
 	b := GrammarTree{Genome: genome}
@@ -75,13 +76,14 @@ func (i *GrammarTree) Max(i2 Evolvable) Evolvable {
 }
 
 // Mutate performs mutation on the genome at specified points
-func (i *GrammarTree) Mutate(mutationRate float64, _ *MutateInformation) {
-	if mutationRate < rng.Float64() {
+func (i *GrammarTree) Mutate(mutationRate float64, mutateInformation *MutateInformation) {
+	r := mutateInformation.rand()
+	if mutationRate < r.Float64() {
 		return
 	}
 	for j := range len(i.Genome) {
-		if mutationRate > rng.Float64() {
-			i.Genome[j] = rng.Intn(255) // Flip '0' <-> '1'
+		if mutationRate > r.Float64() {
+			i.Genome[j] = r.Intn(255) // Flip '0' <-> '1'
 		}
 	}
 }
@@ -98,7 +100,7 @@ func (i *GrammarTree) MultiPointCrossover(i2 Evolvable, crossoverInformation *Cr
 	newI2Genome := make([]int, 0, len(i.Genome))
 
 	for range crossoverInformation.CrossoverPoints {
-		crossoverPointArray = append(crossoverPointArray, rng.Intn(len(i.Genome)))
+		crossoverPointArray = append(crossoverPointArray, crossoverInformation.rand().Intn(len(i.Genome)))
 	}
 	sort.Ints(crossoverPointArray)
 
@@ -132,8 +134,9 @@ func (i *GrammarTree) Clone() Evolvable {
 	genomeCopy := make([]int, len(i.Genome))
 	copy(genomeCopy, i.Genome)
 	return &GrammarTree{
-		Genome:  genomeCopy,
-		Fitness: i.Fitness,
+		Genome:   genomeCopy,
+		Fitness:  i.Fitness,
+		RateGene: i.RateGene,
 	}
 }
 
@@ -0,0 +1,251 @@
+package individual
+
+import (
+	"strconv"
+
+	"github.com/bxrne/darwin/internal/rng"
+)
+
+// Tree mutation types, applied to a node chosen for mutation
+const (
+	// TreeMutationPoint replaces the node's operator or terminal with another of its kind
+	TreeMutationPoint = "point"
+	// TreeMutationShrink replaces a function node with a terminal
+	TreeMutationShrink = "shrink"
+	// TreeMutationGrow replaces a terminal with a new subtree
+	TreeMutationGrow = "grow"
+	// TreeMutationSubtree replaces the node, whatever it is, with a new random subtree
+	TreeMutationSubtree = "subtree"
+	// TreeMutationHoist replaces a function node with one of its own subtrees
+	TreeMutationHoist = "hoist"
+	// TreeMutationPermutation swaps a function node's children
+	TreeMutationPermutation = "permutation"
+	// TreeMutationConstant adds Gaussian noise to a numeric constant
+	TreeMutationConstant = "constant"
+)
+
+// TreeMutationTypes lists the tree mutation types MutationWeights can weigh
+var TreeMutationTypes = []string{
+	TreeMutationPoint,
+	TreeMutationShrink,
+	TreeMutationGrow,
+	TreeMutationSubtree,
+	TreeMutationHoist,
+	TreeMutationPermutation,
+	TreeMutationConstant,
+}
+
+// Tree mutation modes, set as MutateInformation.MutationMode
+const (
+	// TreeMutationPerNode gives every node the mutation rate as its chance to mutate, so larger
+	// trees take more mutations
+	TreeMutationPerNode = "per_node"
+	// TreeMutationPerIndividual mutates a single random node with the mutation rate as the chance,
+	// whatever the tree's size
+	TreeMutationPerIndividual = "per_individual"
+)
+
+// DefaultConstantSigma is the standard deviation of constant perturbation unless one is set
+const DefaultConstantSigma = 1.0
+
+// defaultTreeMutations is the mix used when no MutationWeights are given
+var defaultTreeMutations = map[string]float64{TreeMutationPoint: 0.6, TreeMutationShrink: 0.2, TreeMutationGrow: 0.2}
+
+// WeightedTreeMutations returns the tree mutation types weights gives a chance to, in
+// TreeMutationTypes order; nil weights are the default mix
+func WeightedTreeMutations(weights map[string]float64) []string {
+	if weights == nil {
+		weights = defaultTreeMutations
+	}
+	types := make([]string, 0, len(TreeMutationTypes))
+	for _, name := range TreeMutationTypes {
+		if weights[name] > 0 {
+			types = append(types, name)
+		}
+	}
+	return types
+}
+
+// pickTreeMutation draws a tree mutation type in proportion to weights, or "" if none has weight
+func pickTreeMutation(r *rng.Rand, weights map[string]float64) string {
+	if weights == nil {
+		weights = defaultTreeMutations
+	}
+	total := 0.0
+	for _, name := range TreeMutationTypes {
+		total += max(weights[name], 0)
+	}
+	draw := r.Float64() * total
+	cumulative := 0.0
+	picked := ""
+	for _, name := range TreeMutationTypes {
+		if weights[name] <= 0 {
+			continue
+		}
+		cumulative += weights[name]
+		picked = name
+		if draw < cumulative {
+			break
+		}
+	}
+	return picked
+}
+
+// treeMutator applies one Mutate call's settings to the nodes of a tree
+type treeMutator struct {
+	rate      float64
+	operands  []string
+	terminals []string // constants and variables
+	maxDepth  int
+	weights   map[string]float64
+	sigma     float64
+	rand      *rng.Rand
+}
+
+func newTreeMutator(rate float64, info *MutateInformation) *treeMutator {
+	sigma := info.ConstantSigma
+	if sigma <= 0 {
+		sigma = DefaultConstantSigma
+	}
+	return &treeMutator{
+		rate:      rate,
+		operands:  info.OperandSet,
+		terminals: append(append([]string{}, info.TerminalSet...), info.VariableSet...),
+		maxDepth:  info.MaxDepth,
+		weights:   info.MutationWeights,
+		sigma:     sigma,
+		rand:      info.Rand,
+	}
+}
+
+// mutate mutates the tree rooted at root in the given mode, empty meaning per node
+func (m *treeMutator) mutate(root *TreeNode, mode string) {
+	if mode == TreeMutationPerIndividual {
+		if m.rand.Float64() < m.rate {
+			nodes, depths := root.preorder(0, nil, nil)
+			i := m.rand.Intn(len(nodes))
+			m.apply(nodes[i], depths[i])
+		}
+		return
+	}
+	m.mutateRecursive(root, 0)
+}
+
+// mutateRecursive traverses the tree and gives each node a chance to mutate
+func (m *treeMutator) mutateRecursive(tn *TreeNode, currentDepth int) {
+	// First, recursively mutate children (if any)
+	if tn.Left != nil {
+		m.mutateRecursive(tn.Left, currentDepth+1)
+	}
+	if tn.Right != nil {
+		m.mutateRecursive(tn.Right, currentDepth+1)
+	}
+
+	// Then, decide if this node should mutate
+	if m.rand.Float64() < m.rate {
+		m.apply(tn, currentDepth)
+	}
+}
+
+// apply mutates tn in place with a mutation type drawn from the weights
+func (m *treeMutator) apply(tn *TreeNode, currentDepth int) {
+	applied := false
+	switch pickTreeMutation(m.rand, m.weights) {
+	case "":
+		return
+	case TreeMutationPoint:
+	case TreeMutationShrink:
+		// Only a function node can shrink
+		applied = !tn.IsLeaf() && tn.shrinkNode(m.rand, m.terminals, currentDepth)
+	case TreeMutationGrow:
+		// Only a terminal can grow
+		applied = tn.IsLeaf() && tn.growNode(m.rand, m.maxDepth, currentDepth, m.operands, m.terminals)
+	case TreeMutationSubtree:
+		applied = tn.replaceSubtree(m.rand, m.maxDepth-currentDepth, m.operands, m.terminals)
+	case TreeMutationHoist:
+		applied = tn.hoist(m.rand, currentDepth)
+	case TreeMutationPermutation:
+		applied = tn.permute()
+	case TreeMutationConstant:
+		applied = tn.perturbConstant(m.rand, m.sigma)
+	}
+	if applied {
+		return
+	}
+	// Point mutation, also the fallback when the drawn type does not apply to the node
+	if tn.IsLeaf() {
+		tn.MutateTerminal(m.rand, m.terminals)
+	} else {
+		tn.MutateFunction(m.rand, m.operands)
+	}
+}
+
+// preorder appends every node of the subtree and its depth
+func (tn *TreeNode) preorder(depth int, nodes []*TreeNode, depths []int) ([]*TreeNode, []int) {
+	nodes, depths = append(nodes, tn), append(depths, depth)
+	if tn.Left != nil {
+		nodes, depths = tn.Left.preorder(depth+1, nodes, depths)
+	}
+	if tn.Right != nil {
+		nodes, depths = tn.Right.preorder(depth+1, nodes, depths)
+	}
+	return nodes, depths
+}
+
+// replaceSubtree replaces the node with a new grow subtree at most depth deep. A function node
+// is always drawn at the root so the tree keeps a depth of at least 1.
+func (tn *TreeNode) replaceSubtree(r *rng.Rand, depth int, operandSet []string, terminalSet []string) bool {
+	if depth < 0 || len(terminalSet) == 0 {
+		return false
+	}
+	functionSet := make([]Operand, 0, len(operandSet))
+	for _, prim := range operandSet {
+		functionSet = append(functionSet, Operand(prim))
+	}
+	if depth > 0 && len(functionSet) == 0 {
+		depth = 0
+	}
+	*tn = *newGrowTreeNode(r, depth, depth, terminalSet, functionSet)
+	return true
+}
+
+// hoist replaces a function node with one of its descendants, never a lone terminal at the root
+func (tn *TreeNode) hoist(r *rng.Rand, currentDepth int) bool {
+	if tn.IsLeaf() {
+		return false
+	}
+	descendants, _ := tn.preorder(0, nil, nil)
+	candidates := make([]*TreeNode, 0, len(descendants)-1)
+	for _, d := range descendants[1:] {
+		if currentDepth > 0 || !d.IsLeaf() {
+			candidates = append(candidates, d)
+		}
+	}
+	if len(candidates) == 0 {
+		return false
+	}
+	*tn = *candidates[r.Intn(len(candidates))]
+	return true
+}
+
+// permute swaps a function node's children
+func (tn *TreeNode) permute() bool {
+	if tn.IsLeaf() {
+		return false
+	}
+	tn.Left, tn.Right = tn.Right, tn.Left
+	return true
+}
+
+// perturbConstant adds N(0, sigma²) noise to a numeric terminal; variables are left alone
+func (tn *TreeNode) perturbConstant(r *rng.Rand, sigma float64) bool {
+	if !tn.IsLeaf() {
+		return false
+	}
+	value, err := strconv.ParseFloat(tn.Value, 64)
+	if err != nil {
+		return false
+	}
+	tn.Value = strconv.FormatFloat(value+sigma*r.NormFloat64(), 'g', 6, 64)
+	return true
+}
@@ -0,0 +1,181 @@
+package individual_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/bxrne/darwin/internal/individual"
+	"github.com/bxrne/darwin/internal/rng"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mutationInfo is the MutateInformation of a small numeric problem using only mutationType
+func mutationInfo(mutationType string, mode string) *individual.MutateInformation {
+	return &individual.MutateInformation{
+		OperandSet:      []string{"+", "-", "*"},
+		VariableSet:     []string{"x", "y"},
+		TerminalSet:     []string{"1", "2"},
+		MaxDepth:        5,
+		MutationWeights: map[string]float64{mutationType: 1},
+		MutationMode:    mode,
+	}
+}
+
+// fullTree is a tree of the given depth with every leaf at the bottom
+func fullTree(depth int) *individual.Tree {
+	return individual.NewFullTree(nil, depth, []string{"+", "-", "*"}, []string{"x", "y"}, []string{"1", "2"})
+}
+
+// nodeValues lists a subtree's values in preorder
+func nodeValues(tn *individual.TreeNode) []string {
+	if tn == nil {
+		return nil
+	}
+	values := []string{tn.Value}
+	values = append(values, nodeValues(tn.Left)...)
+	return append(values, nodeValues(tn.Right)...)
+}
+
+func TestWeightedTreeMutations_GIVEN_weights_WHEN_listed_THEN_positive_types_in_order(t *testing.T) {
+	assert.Equal(t, []string{"point", "shrink", "grow"}, individual.WeightedTreeMutations(nil))
+	assert.Equal(t, []string{"hoist", "constant"}, individual.WeightedTreeMutations(map[string]float64{"constant": 1, "hoist": 2, "grow": 0}))
+}
+
+func TestTree_Mutate_GIVEN_per_individual_mode_WHEN_mutated_THEN_one_node_changes(t *testing.T) {
+	rng.Seed(5)
+	for range 20 {
+		tree := fullTree(4)
+		before := nodeValues(tree.Root)
+
+		tree.Mutate(1, mutationInfo(individual.TreeMutationPoint, individual.TreeMutationPerIndividual))
+
+		after := nodeValues(tree.Root)
+		require.Len(t, after, len(before))
+		changed := 0
+		for i := range before {
+			if before[i] != after[i] {
+				changed++
+			}
+		}
+		assert.Equal(t, 1, changed)
+	}
+}
+
+func TestTree_Mutate_GIVEN_per_individual_mode_and_zero_rate_WHEN_mutated_THEN_unchanged(t *testing.T) {
+	tree := fullTree(4)
+	before := tree.Describe()
+
+	tree.Mutate(0, mutationInfo(individual.TreeMutationSubtree, individual.TreeMutationPerIndividual))
+
+	assert.Equal(t, before, tree.Describe())
+}
+
+func TestTree_Mutate_GIVEN_per_node_mode_WHEN_mutated_THEN_every_node_changes(t *testing.T) {
+	tree := fullTree(3)
+	before := nodeValues(tree.Root)
+
+	tree.Mutate(1, mutationInfo(individual.TreeMutationPoint, individual.TreeMutationPerNode))
+
+	after := nodeValues(tree.Root)
+	for i := range before {
+		assert.NotEqual(t, before[i], after[i])
+	}
+}
+
+func TestTree_Mutate_GIVEN_permutation_WHEN_mutated_THEN_function_nodes_swap_children(t *testing.T) {
+	sum := &individual.TreeNode{Value: "+", Left: &individual.TreeNode{Value: "1"}, Right: &individual.TreeNode{Value: "x"}}
+	tree := &individual.Tree{Root: &individual.TreeNode{Value: "-", Left: sum, Right: &individual.TreeNode{Value: "y"}}}
+
+	tree.Mutate(1, mutationInfo(individual.TreeMutationPermutation, individual.TreeMutationPerNode))
+
+	assert.Equal(t, "-", tree.Root.Value, "function nodes keep their operator")
+	assert.True(t, tree.Root.Left.IsLeaf())
+	assert.Equal(t, "+", tree.Root.Right.Value)
+	assert.False(t, tree.Root.Right.IsLeaf())
+}
+
+func TestTree_Mutate_GIVEN_hoist_WHEN_mutated_THEN_smaller_and_never_a_lone_terminal(t *testing.T) {
+	rng.Seed(8)
+	for range 50 {
+		tree := fullTree(4)
+		nodes, _ := tree.CountNodesAndVars()
+
+		tree.Mutate(1, mutationInfo(individual.TreeMutationHoist, individual.TreeMutationPerIndividual))
+
+		after, _ := tree.CountNodesAndVars()
+		assert.GreaterOrEqual(t, tree.GetDepth(), 1)
+		assert.LessOrEqual(t, after, nodes)
+	}
+}
+
+func TestTree_Mutate_GIVEN_subtree_WHEN_mutated_often_THEN_max_depth_kept(t *testing.T) {
+	rng.Seed(13)
+	tree := fullTree(2)
+	info := mutationInfo(individual.TreeMutationSubtree, individual.TreeMutationPerIndividual)
+
+	for range 200 {
+		tree.Mutate(1, info)
+
+		assert.LessOrEqual(t, tree.GetDepth(), info.MaxDepth)
+		assert.GreaterOrEqual(t, tree.GetDepth(), 1)
+	}
+}
+
+func TestTree_Mutate_GIVEN_constant_WHEN_mutated_THEN_numbers_perturbed_and_the_rest_point_mutated(t *testing.T) {
+	rng.Seed(21)
+	tree := &individual.Tree{Root: &individual.TreeNode{Value: "+", Left: &individual.TreeNode{Value: "2"}, Right: &individual.TreeNode{Value: "x"}}}
+	info := mutationInfo(individual.TreeMutationConstant, individual.TreeMutationPerNode)
+	info.ConstantSigma = 0.5
+
+	tree.Mutate(1, info)
+
+	value, err := strconv.ParseFloat(tree.Root.Left.Value, 64)
+	require.NoError(t, err)
+	assert.NotEqual(t, 2.0, value)
+	assert.InDelta(t, 2, value, 3)
+	assert.NotEqual(t, "x", tree.Root.Right.Value, "a variable falls back to point mutation")
+	assert.NotEqual(t, "+", tree.Root.Value)
+}
+
+func TestTree_Mutate_GIVEN_every_type_WHEN_mutated_often_THEN_trees_stay_valid(t *testing.T) {
+	rng.Seed(34)
+	weights := make(map[string]float64, len(individual.TreeMutationTypes))
+	for _, name := range individual.TreeMutationTypes {
+		weights[name] = 1
+	}
+	for _, mode := range []string{individual.TreeMutationPerNode, individual.TreeMutationPerIndividual} {
+		tree := fullTree(3)
+		info := mutationInfo(individual.TreeMutationPoint, mode)
+		info.MutationWeights = weights
+
+		for range 100 {
+			tree.Mutate(0.3, info)
+
+			assert.LessOrEqual(t, tree.GetDepth(), info.MaxDepth, mode)
+			assert.NotPanics(t, func() { tree.Root.EvaluateTree(&map[string]float64{"x": 1, "y": 2}) }, mode)
+		}
+	}
+}
+
+func TestActionTreeIndividual_Mutate_GIVEN_per_individual_mode_WHEN_mutated_THEN_one_tree_changes(t *testing.T) {
+	rng.Seed(55)
+	actions := []individual.ActionTuple{{Name: "move", Value: 2}, {Name: "turn", Value: 3}, {Name: "wait", Value: 1}}
+	ind := individual.NewRandomActionTreeIndividual(nil, actions, 3, []string{"+", "-"}, []string{"x", "y"}, []string{"1", "2"})
+	before := make(map[string]string, len(ind.Trees))
+	for name, tree := range ind.Trees {
+		before[name] = tree.Describe()
+	}
+	info := mutationInfo(individual.TreeMutationPoint, individual.TreeMutationPerIndividual)
+	info.OperandSet = []string{"+", "-"}
+
+	ind.Mutate(1, info)
+
+	changed := 0
+	for name, tree := range ind.Trees {
+		if tree.Describe() != before[name] {
+			changed++
+		}
+	}
+	assert.Equal(t, 1, changed)
+}
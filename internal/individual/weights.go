@@ -14,15 +14,16 @@ type WeightsIndividual struct {
 	minVal   float64
 	maxVal   float64
 	clientId string
+	RateGene
 }
 
-func NewWeightsIndividual(height int, width int) *WeightsIndividual {
+func NewWeightsIndividual(r *rng.Rand, height int, width int) *WeightsIndividual {
 	Weights := mat.NewDense(height, width, nil)
 
 	minVal, maxVal := -5.0, 5.0
 	for i := range height {
 		for j := range width {
-			Weights.Set(i, j, minVal+rng.Float64()*(maxVal-minVal))
+			Weights.Set(i, j, minVal+r.Float64()*(maxVal-minVal))
 		}
 	}
 	return &WeightsIndividual{Weights: Weights, minVal: minVal, maxVal: maxVal}
@@ -67,10 +68,11 @@ func (wi WeightsIndividual) Clone() Evolvable {
 		}
 	}
 	return &WeightsIndividual{
-		Weights: clonedWeights,
-		fitness: wi.fitness,
-		minVal:  wi.minVal,
-		maxVal:  wi.maxVal,
+		Weights:  clonedWeights,
+		fitness:  wi.fitness,
+		minVal:   wi.minVal,
+		maxVal:   wi.maxVal,
+		RateGene: wi.RateGene,
 	}
 }
 
@@ -83,7 +85,7 @@ func (wi *WeightsIndividual) MultiPointCrossover(i2 Evolvable, crossoverInformat
 	crossoverPointArray := make([]int, crossoverInformation.CrossoverPoints)
 
 	for range crossoverInformation.CrossoverPoints {
-		crossoverPointArray = append(crossoverPointArray, rng.Intn(c1))
+		crossoverPointArray = append(crossoverPointArray, crossoverInformation.rand().Intn(c1))
 	}
 	sort.Ints(crossoverPointArray)
 
@@ -107,12 +109,13 @@ func (wi *WeightsIndividual) MultiPointCrossover(i2 Evolvable, crossoverInformat
 }
 
 func (wi *WeightsIndividual) Mutate(rate float64, mutateInformation *MutateInformation) {
-	r, c := wi.Weights.Dims()
-	for i := range r {
+	r := mutateInformation.rand()
+	rows, c := wi.Weights.Dims()
+	for i := range rows {
 		for j := range c {
-			if rng.Float64() < rate {
+			if r.Float64() < rate {
 				// Small modulation rather than wholly new weights
-				newVal := wi.Weights.At(i, j) + (wi.minVal+rng.Float64()*(wi.maxVal-wi.minVal))*0.3
+				newVal := wi.Weights.At(i, j) + (wi.minVal+r.Float64()*(wi.maxVal-wi.minVal))*0.3
 				wi.Weights.Set(i, j, float64(newVal))
 			}
 		}
@@ -35,7 +35,7 @@ func TestNewWeightsIndividual(t *testing.T) {
 	}
 
 	for _, tc := range tests {
-		wi := individual.NewWeightsIndividual(tc.height, tc.width)
+		wi := individual.NewWeightsIndividual(nil, tc.height, tc.width)
 
 		if wi.Weights == nil {
 			t.Fatalf("Weights should not be nil")
@@ -59,7 +59,7 @@ func TestNewWeightsIndividual(t *testing.T) {
 }
 
 func TestClone(t *testing.T) {
-	wi := individual.NewWeightsIndividual(3, 3)
+	wi := individual.NewWeightsIndividual(nil, 3, 3)
 	wi.SetFitness(12.5)
 
 	clone := wi.Clone().(*individual.WeightsIndividual)
@@ -82,8 +82,8 @@ func TestClone(t *testing.T) {
 }
 
 func TestMax(t *testing.T) {
-	wi1 := individual.NewWeightsIndividual(2, 2)
-	wi2 := individual.NewWeightsIndividual(2, 2)
+	wi1 := individual.NewWeightsIndividual(nil, 2, 2)
+	wi2 := individual.NewWeightsIndividual(nil, 2, 2)
 
 	wi1.SetFitness(10)
 	wi2.SetFitness(20)
@@ -101,7 +101,7 @@ func TestMax(t *testing.T) {
 }
 
 func TestMutate(t *testing.T) {
-	wi := individual.NewWeightsIndividual(4, 4)
+	wi := individual.NewWeightsIndividual(nil, 4, 4)
 
 	// clone original for comparison
 	before := wi.Clone().(*individual.WeightsIndividual)
@@ -115,8 +115,8 @@ func TestMutate(t *testing.T) {
 }
 
 func TestMultiPointCrossoverSmoketest(t *testing.T) {
-	parent1 := individual.NewWeightsIndividual(3, 3)
-	parent2 := individual.NewWeightsIndividual(3, 3)
+	parent1 := individual.NewWeightsIndividual(nil, 3, 3)
+	parent2 := individual.NewWeightsIndividual(nil, 3, 3)
 
 	// Force different starting matrices
 	parent1.Weights.Set(0, 0, -999)
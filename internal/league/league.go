@@ -0,0 +1,199 @@
+// Package league keeps a pool of evolved opponents for self-play: hall-of-fame champions and
+// the latest generation's elites, sampled by a configurable strategy and rated with Elo
+package league
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/bxrne/darwin/internal/individual"
+	"github.com/bxrne/darwin/internal/rng"
+)
+
+// Sampling strategies for choosing an opponent from the pool
+const (
+	// Latest plays the newest champion
+	Latest = "latest"
+	// Uniform plays any pool member with equal probability
+	Uniform = "uniform"
+	// Prioritized plays pool members in proportion to their win-rate, so strong opponents come up more often
+	Prioritized = "prioritized"
+)
+
+// Config tunes the pool and the ratings
+type Config struct {
+	Sampling       string
+	HallOfFameSize int
+	EliteCount     int
+	KFactor        float64
+	InitialRating  float64
+	// Rand draws the sampled opponents; nil is the package generator
+	Rand *rng.Rand
+}
+
+// Rating is the Elo rating and record of a player or evaluated individual
+type Rating struct {
+	Elo   float64
+	Games int
+	// Wins counts draws as half a win
+	Wins float64
+}
+
+// WinRate is the smoothed share of games won, 0.5 before any game
+func (r Rating) WinRate() float64 {
+	return (r.Wins + 1) / (float64(r.Games) + 2)
+}
+
+// Member is an individual to be frozen into the pool with the partner it plays with
+type Member struct {
+	Source  individual.Evolvable
+	Trees   *individual.ActionTreeIndividual
+	Weights *individual.WeightsIndividual
+}
+
+// Player is a frozen policy in the pool
+type Player struct {
+	Name    string
+	Trees   *individual.ActionTreeIndividual
+	Weights *individual.WeightsIndividual
+	Rating  Rating
+}
+
+// League is the opponent pool and the ratings of the individuals playing against it; it is safe
+// for concurrent use by fitness evaluations
+type League struct {
+	mu         sync.Mutex
+	config     Config
+	champions  []*Player
+	elites     []*Player
+	ratings    map[individual.Evolvable]*Rating
+	generation int
+}
+
+// New creates an empty league
+func New(config Config) (*League, error) {
+	switch config.Sampling {
+	case Latest, Uniform, Prioritized:
+	default:
+		return nil, fmt.Errorf("unknown league sampling %q, must be latest, uniform or prioritized", config.Sampling)
+	}
+	if config.HallOfFameSize <= 0 || config.EliteCount <= 0 {
+		return nil, fmt.Errorf("hall of fame size and elite count must be positive")
+	}
+	return &League{config: config, ratings: make(map[individual.Evolvable]*Rating)}, nil
+}
+
+// Pool returns the champions, oldest first, followed by the elites not already champions
+func (l *League) Pool() []*Player {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.pool()
+}
+
+func (l *League) pool() []*Player {
+	pool := append([]*Player(nil), l.champions...)
+	for _, elite := range l.elites {
+		if len(l.champions) == 0 || elite != l.champions[len(l.champions)-1] {
+			pool = append(pool, elite)
+		}
+	}
+	return pool
+}
+
+// Sample picks an opponent by the configured strategy, nil while the pool is empty
+func (l *League) Sample() *Player {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	pool := l.pool()
+	if len(pool) == 0 {
+		return nil
+	}
+
+	switch l.config.Sampling {
+	case Latest:
+		return l.champions[len(l.champions)-1]
+	case Prioritized:
+		total := 0.0
+		for _, player := range pool {
+			total += player.Rating.WinRate()
+		}
+		target := l.config.Rand.Float64() * total
+		for _, player := range pool {
+			target -= player.Rating.WinRate()
+			if target <= 0 {
+				return player
+			}
+		}
+		return pool[len(pool)-1]
+	}
+	return pool[l.config.Rand.Intn(len(pool))]
+}
+
+// Record updates the Elo ratings of an individual and its opponent after a game.
+// score is the individual's result: 1 for a win, 0 for a loss and 0.5 for a draw.
+func (l *League) Record(subject individual.Evolvable, opponent *Player, score float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	rating := l.rating(subject)
+	expected := 1 / (1 + math.Pow(10, (opponent.Rating.Elo-rating.Elo)/400))
+	delta := l.config.KFactor * (score - expected)
+
+	rating.Elo += delta
+	rating.Games++
+	rating.Wins += score
+	opponent.Rating.Elo -= delta
+	opponent.Rating.Games++
+	opponent.Rating.Wins += 1 - score
+}
+
+// Rating returns the Elo rating of an individual, the initial rating if it has not played
+func (l *League) Rating(subject individual.Evolvable) float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rating(subject).Elo
+}
+
+func (l *League) rating(subject individual.Evolvable) *Rating {
+	rating, ok := l.ratings[subject]
+	if !ok {
+		rating = &Rating{Elo: l.config.InitialRating}
+		l.ratings[subject] = rating
+	}
+	return rating
+}
+
+// EndGeneration freezes the generation's elites, best first, into the pool: they replace the
+// previous elites and the best joins the hall of fame, evicting the oldest champion when full.
+// Ratings of individuals not in alive are dropped.
+func (l *League) EndGeneration(elites []Member, alive []individual.Evolvable) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.generation++
+
+	l.elites = nil
+	for i, member := range elites[:min(len(elites), l.config.EliteCount)] {
+		l.elites = append(l.elites, &Player{
+			Name:    fmt.Sprintf("gen%d_elite%d", l.generation, i),
+			Trees:   member.Trees.Clone().(*individual.ActionTreeIndividual),
+			Weights: member.Weights.Clone().(*individual.WeightsIndividual),
+			Rating:  *l.rating(member.Source),
+		})
+	}
+	if len(l.elites) > 0 {
+		l.champions = append(l.champions, l.elites[0])
+		if len(l.champions) > l.config.HallOfFameSize {
+			l.champions = l.champions[len(l.champions)-l.config.HallOfFameSize:]
+		}
+	}
+
+	keep := make(map[individual.Evolvable]bool, len(alive))
+	for _, subject := range alive {
+		keep[subject] = true
+	}
+	for subject := range l.ratings {
+		if !keep[subject] {
+			delete(l.ratings, subject)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package league_test
+
+import (
+	"testing"
+
+	"github.com/bxrne/darwin/internal/individual"
+	"github.com/bxrne/darwin/internal/league"
+	"github.com/bxrne/darwin/internal/rng"
+	"github.com/stretchr/testify/assert"
+)
+
+func newLeague(t *testing.T, sampling string, hallOfFame int) *league.League {
+	l, err := league.New(league.Config{Sampling: sampling, HallOfFameSize: hallOfFame, EliteCount: 2, KFactor: 32, InitialRating: 1200})
+	assert.NoError(t, err)
+	return l
+}
+
+// members builds n pool candidates, best first
+func members(n int) []league.Member {
+	result := make([]league.Member, n)
+	for i := range result {
+		trees := individual.NewActionTreeIndividual(nil, map[string]*individual.Tree{})
+		result[i] = league.Member{Source: trees, Trees: trees, Weights: individual.NewWeightsIndividual(nil, 2, 2)}
+	}
+	return result
+}
+
+func TestNew_GIVEN_unknown_sampling_WHEN_created_THEN_returns_error(t *testing.T) {
+	_, err := league.New(league.Config{Sampling: "best", HallOfFameSize: 1, EliteCount: 1})
+
+	assert.ErrorContains(t, err, "prioritized")
+}
+
+func TestSample_GIVEN_empty_pool_WHEN_sampled_THEN_returns_nil(t *testing.T) {
+	assert.Nil(t, newLeague(t, league.Uniform, 3).Sample())
+}
+
+func TestRecord_GIVEN_equal_ratings_WHEN_subject_wins_THEN_ratings_move_by_half_k(t *testing.T) {
+	l := newLeague(t, league.Uniform, 3)
+	candidates := members(1)
+	l.EndGeneration(candidates, nil)
+	opponent := l.Sample()
+	subject := individual.NewWeightsIndividual(nil, 2, 2)
+
+	l.Record(subject, opponent, 1)
+
+	assert.InDelta(t, 1216, l.Rating(subject), 1e-9)
+	assert.InDelta(t, 1184, opponent.Rating.Elo, 1e-9)
+	assert.Equal(t, 1, opponent.Rating.Games)
+	assert.Equal(t, 0.0, opponent.Rating.Wins)
+}
+
+func TestEndGeneration_GIVEN_generations_WHEN_hall_of_fame_full_THEN_oldest_champion_is_evicted(t *testing.T) {
+	l := newLeague(t, league.Latest, 2)
+
+	for range 3 {
+		l.EndGeneration(members(3), nil)
+	}
+
+	names := make([]string, 0)
+	for _, player := range l.Pool() {
+		names = append(names, player.Name)
+	}
+	assert.Equal(t, []string{"gen2_elite0", "gen3_elite0", "gen3_elite1"}, names)
+	assert.Equal(t, "gen3_elite0", l.Sample().Name)
+}
+
+func TestEndGeneration_GIVEN_rated_member_WHEN_frozen_THEN_player_keeps_rating_and_dead_ratings_are_dropped(t *testing.T) {
+	l := newLeague(t, league.Uniform, 3)
+	l.EndGeneration(members(1), nil)
+	candidates := members(1)
+	dead := individual.NewWeightsIndividual(nil, 2, 2)
+	l.Record(candidates[0].Source, l.Sample(), 1)
+	l.Record(dead, l.Sample(), 1)
+
+	l.EndGeneration(candidates, []individual.Evolvable{candidates[0].Source})
+
+	assert.InDelta(t, 1216, l.Pool()[1].Rating.Elo, 1e-9)
+	assert.NotSame(t, candidates[0].Trees, l.Pool()[1].Trees)
+	assert.InDelta(t, 1216, l.Rating(candidates[0].Source), 1e-9)
+	assert.Equal(t, 1200.0, l.Rating(dead))
+}
+
+func TestSample_GIVEN_prioritized_WHEN_one_player_dominates_THEN_it_is_sampled_more_often(t *testing.T) {
+	rng.Seed(7)
+	l := newLeague(t, league.Prioritized, 3)
+	l.EndGeneration(members(2), nil)
+	pool := l.Pool()
+	strong := pool[0]
+	for range 50 {
+		l.Record(individual.NewWeightsIndividual(nil, 2, 2), strong, 0)
+		l.Record(individual.NewWeightsIndividual(nil, 2, 2), pool[1], 1)
+	}
+
+	counts := map[*league.Player]int{}
+	for range 1000 {
+		counts[l.Sample()]++
+	}
+
+	assert.Greater(t, counts[strong], 900)
+}
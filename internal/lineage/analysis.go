@@ -0,0 +1,144 @@
+package lineage
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// OperatorStat is how often an operator's children scored higher than the best of their parents
+type OperatorStat struct {
+	Operator string
+	Children int // children with a scored parent
+	Improved int // children scoring higher than every parent
+}
+
+// Rate is the fraction of children that improved on their parents
+func (s OperatorStat) Rate() float64 {
+	if s.Children == 0 {
+		return 0
+	}
+	return float64(s.Improved) / float64(s.Children)
+}
+
+// Champion returns the record with the highest fitness, the latest of any tied
+func Champion(records []Record) (Record, bool) {
+	var best Record
+	found := false
+	for _, r := range records {
+		if r.Fitness == nil {
+			continue
+		}
+		if !found || *r.Fitness >= *best.Fitness {
+			best, found = r, true
+		}
+	}
+	return best, found
+}
+
+// OperatorStats compares every child with a scored parent to the best of its parents, per
+// operator in name order. Children and parents without a fitness are left out.
+func OperatorStats(records []Record) []OperatorStat {
+	byID := index(records)
+	stats := make(map[string]*OperatorStat)
+	for _, r := range records {
+		if r.Fitness == nil || len(r.Parents) == 0 {
+			continue
+		}
+		bestParent, scored := 0.0, false
+		for _, id := range r.Parents {
+			if parent, ok := byID[id]; ok && parent.Fitness != nil && (!scored || *parent.Fitness > bestParent) {
+				bestParent, scored = *parent.Fitness, true
+			}
+		}
+		if !scored {
+			continue
+		}
+		stat, ok := stats[r.Operator]
+		if !ok {
+			stat = &OperatorStat{Operator: r.Operator}
+			stats[r.Operator] = stat
+		}
+		stat.Children++
+		if *r.Fitness > bestParent {
+			stat.Improved++
+		}
+	}
+
+	out := make([]OperatorStat, 0, len(stats))
+	for _, stat := range stats {
+		out = append(out, *stat)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Operator < out[j].Operator })
+	return out
+}
+
+// WriteStats writes a table of operator success rates
+func WriteStats(w io.Writer, stats []OperatorStat) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-12s %10s %10s %8s\n", "operator", "children", "improved", "rate")
+	for _, s := range stats {
+		fmt.Fprintf(&b, "%-12s %10d %10d %7.1f%%\n", s.Operator, s.Children, s.Improved, 100*s.Rate())
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteDOT writes the ancestry of individual id as a Graphviz digraph, edges running from parent
+// to child and labelled with the operator that bred the child
+func WriteDOT(w io.Writer, records []Record, id uint64) error {
+	byID := index(records)
+	if _, ok := byID[id]; !ok {
+		return fmt.Errorf("individual %d is not in the lineage", id)
+	}
+
+	// Walk back from id, visiting each ancestor once
+	seen := map[uint64]bool{id: true}
+	queue := []uint64{id}
+	var ancestry []Record
+	for len(queue) > 0 {
+		r := byID[queue[0]]
+		queue = queue[1:]
+		ancestry = append(ancestry, r)
+		for _, parent := range r.Parents {
+			if _, ok := byID[parent]; ok && !seen[parent] {
+				seen[parent] = true
+				queue = append(queue, parent)
+			}
+		}
+	}
+	sort.Slice(ancestry, func(i, j int) bool { return ancestry[i].ID < ancestry[j].ID })
+
+	var b strings.Builder
+	b.WriteString("digraph lineage {\n\trankdir=TB;\n\tnode [shape=box];\n")
+	for _, r := range ancestry {
+		fitness := "unscored"
+		if r.Fitness != nil {
+			fitness = fmt.Sprintf("%.4g", *r.Fitness)
+		}
+		style := ""
+		if r.ID == id {
+			style = ", style=bold"
+		}
+		fmt.Fprintf(&b, "\tn%d [label=\"#%d gen %d\\n%s\\nfitness %s\"%s];\n", r.ID, r.ID, r.Generation, r.Operator, fitness, style)
+	}
+	for _, r := range ancestry {
+		for _, parent := range r.Parents {
+			if seen[parent] {
+				fmt.Fprintf(&b, "\tn%d -> n%d [label=\"%s\"];\n", parent, r.ID, r.Operator)
+			}
+		}
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func index(records []Record) map[uint64]Record {
+	byID := make(map[uint64]Record, len(records))
+	for _, r := range records {
+		byID[r.ID] = r
+	}
+	return byID
+}
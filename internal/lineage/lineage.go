@@ -0,0 +1,209 @@
+// Package lineage gives every individual of a run an ID and records which individuals it was bred
+// from and by which operator. A lineage file is JSONL, one Record per individual, written once
+// the individual has been scored; parents always come before their children.
+package lineage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/bxrne/darwin/internal/individual"
+)
+
+// Operators recorded in lineage files
+const (
+	// OpInit marks the initial population, which has no parents
+	OpInit = "init"
+	// OpElitism marks an elite carried over unchanged; its parent is its previous self
+	OpElitism = "elitism"
+	// OpCrossover marks a child of two parents recombined, then mutated
+	OpCrossover = "crossover"
+	// OpMutation marks a mutated copy of one parent
+	OpMutation = "mutation"
+	// OpInject marks an individual injected through the control API
+	OpInject = "inject"
+)
+
+// Record is one individual of a lineage file. Fitness is null if it was not finite.
+type Record struct {
+	ID         uint64   `json:"id"`
+	Generation int      `json:"generation"`
+	Operator   string   `json:"operator"`
+	Mutation   string   `json:"mutation,omitempty"` // tree mutation type, if one was chosen for the child
+	Parents    []uint64 `json:"parents,omitempty"`
+	Fitness    *float64 `json:"fitness"`
+}
+
+// Birth is an individual bred from parents of the population it replaces
+type Birth struct {
+	Child        individual.Evolvable
+	Operator     string
+	MutationType string
+	Parents      []individual.Evolvable
+}
+
+// Tracker assigns IDs to the individuals of the populations it is told about and writes their
+// records. Individuals are told apart by pointer, so only individuals of pointer types are tracked.
+type Tracker struct {
+	mu      sync.Mutex
+	out     *bufio.Writer
+	closer  io.Closer
+	next    uint64
+	ids     map[individual.Evolvable]uint64
+	pending []pending
+}
+
+// pending is a record waiting for its individual's fitness
+type pending struct {
+	ind    individual.Evolvable
+	record Record
+}
+
+// NewTracker writes records to w
+func NewTracker(w io.Writer) *Tracker {
+	return &Tracker{out: bufio.NewWriter(w), next: 1, ids: make(map[individual.Evolvable]uint64)}
+}
+
+// Create writes records to a new file at path
+func Create(path string) (*Tracker, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create lineage file: %w", err)
+	}
+	t := NewTracker(file)
+	t.closer = file
+	return t, nil
+}
+
+// ID returns the ID of a tracked individual
+func (t *Tracker) ID(ind individual.Evolvable) (uint64, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	id, ok := t.ids[ind]
+	return id, ok
+}
+
+// Seed tracks individuals with no parents, such as the initial population
+func (t *Tracker) Seed(generation int, operator string, individuals []individual.Evolvable) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, ind := range individuals {
+		t.born(ind, generation, operator, nil)
+	}
+}
+
+// Breed replaces the tracked population old with births of generation; an elite carried over is
+// a birth with itself as child and parent. Parents outside old are left out of the record.
+func (t *Tracker) Breed(generation int, old []individual.Evolvable, births []Birth) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	parents := make([][]uint64, len(births))
+	for i, birth := range births {
+		for _, parent := range birth.Parents {
+			if id, ok := t.ids[parent]; ok && !slices.Contains(parents[i], id) {
+				parents[i] = append(parents[i], id)
+			}
+		}
+	}
+	t.forget(old)
+	for i, birth := range births {
+		if record := t.born(birth.Child, generation, birth.Operator, parents[i]); record != nil {
+			record.Mutation = birth.MutationType
+		}
+	}
+}
+
+// Replace stops tracking removed and tracks added as parentless individuals of operator
+func (t *Tracker) Replace(generation int, operator string, removed, added []individual.Evolvable) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.forget(removed)
+	for _, ind := range added {
+		t.born(ind, generation, operator, nil)
+	}
+}
+
+// Flush writes the records of the individuals tracked since the last flush with their fitness
+// as it stands, so call it once they have been scored
+func (t *Tracker) Flush() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	encoder := json.NewEncoder(t.out)
+	for _, p := range t.pending {
+		if f := p.ind.GetFitness(); !math.IsNaN(f) && !math.IsInf(f, 0) {
+			p.record.Fitness = &f
+		}
+		if err := encoder.Encode(p.record); err != nil {
+			return fmt.Errorf("failed to write lineage: %w", err)
+		}
+	}
+	t.pending = t.pending[:0]
+	if err := t.out.Flush(); err != nil {
+		return fmt.Errorf("failed to write lineage: %w", err)
+	}
+	return nil
+}
+
+// Close flushes the records still pending and closes the file opened by Create
+func (t *Tracker) Close() error {
+	err := t.Flush()
+	if t.closer != nil {
+		if closeErr := t.closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// born tracks ind under a new ID, returning its pending record or nil if ind is not trackable
+func (t *Tracker) born(ind individual.Evolvable, generation int, operator string, parents []uint64) *Record {
+	if !trackable(ind) {
+		return nil
+	}
+	record := Record{ID: t.next, Generation: generation, Operator: operator, Parents: parents}
+	t.next++
+	t.ids[ind] = record.ID
+	t.pending = append(t.pending, pending{ind: ind, record: record})
+	return &t.pending[len(t.pending)-1].record
+}
+
+func (t *Tracker) forget(individuals []individual.Evolvable) {
+	for _, ind := range individuals {
+		if trackable(ind) {
+			delete(t.ids, ind)
+		}
+	}
+}
+
+// trackable reports whether ind can key the ID map; values of other types may not be comparable
+func trackable(ind individual.Evolvable) bool {
+	return ind != nil && reflect.TypeOf(ind).Kind() == reflect.Pointer
+}
+
+// Read parses a lineage file
+func Read(r io.Reader) ([]Record, error) {
+	var records []Record
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("invalid lineage record on line %d: %w", line, err)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read lineage: %w", err)
+	}
+	return records, nil
+}
@@ -0,0 +1,130 @@
+package lineage_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bxrne/darwin/internal/individual"
+	"github.com/bxrne/darwin/internal/lineage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scored(fitness float64) individual.Evolvable {
+	ind := individual.NewBinaryIndividual(nil, 4)
+	ind.SetFitness(fitness)
+	return ind
+}
+
+func fitness(f float64) *float64 {
+	return &f
+}
+
+func TestTracker_GIVEN_bred_generation_WHEN_flushed_THEN_records_parents_and_operators(t *testing.T) {
+	var out strings.Builder
+	tracker := lineage.NewTracker(&out)
+	a, b := scored(1), scored(2)
+	tracker.Seed(0, lineage.OpInit, []individual.Evolvable{a, b})
+	require.NoError(t, tracker.Flush())
+
+	child, mutant := scored(3), scored(0.5)
+	tracker.Breed(1, []individual.Evolvable{a, b}, []lineage.Birth{
+		{Child: b, Operator: lineage.OpElitism, Parents: []individual.Evolvable{b}},
+		{Child: child, Operator: lineage.OpCrossover, Parents: []individual.Evolvable{a, b}},
+		{Child: mutant, Operator: lineage.OpMutation, Parents: []individual.Evolvable{a, a}},
+	})
+	require.NoError(t, tracker.Close())
+
+	records, err := lineage.Read(strings.NewReader(out.String()))
+	require.NoError(t, err)
+	assert.Equal(t, []lineage.Record{
+		{ID: 1, Generation: 0, Operator: lineage.OpInit, Fitness: fitness(1)},
+		{ID: 2, Generation: 0, Operator: lineage.OpInit, Fitness: fitness(2)},
+		{ID: 3, Generation: 1, Operator: lineage.OpElitism, Parents: []uint64{2}, Fitness: fitness(2)},
+		{ID: 4, Generation: 1, Operator: lineage.OpCrossover, Parents: []uint64{1, 2}, Fitness: fitness(3)},
+		{ID: 5, Generation: 1, Operator: lineage.OpMutation, Parents: []uint64{1}, Fitness: fitness(0.5)},
+	}, records)
+	_, tracked := tracker.ID(a)
+	assert.False(t, tracked, "a was not carried over")
+	id, _ := tracker.ID(b)
+	assert.Equal(t, uint64(3), id)
+}
+
+func TestTracker_GIVEN_replaced_individuals_WHEN_flushed_THEN_added_have_no_parents(t *testing.T) {
+	var out strings.Builder
+	tracker := lineage.NewTracker(&out)
+	old, injected := scored(1), scored(5)
+	tracker.Seed(0, lineage.OpInit, []individual.Evolvable{old})
+
+	tracker.Replace(2, lineage.OpInject, []individual.Evolvable{old}, []individual.Evolvable{injected})
+	require.NoError(t, tracker.Flush())
+
+	_, tracked := tracker.ID(old)
+	assert.False(t, tracked)
+	assert.Contains(t, out.String(), `{"id":2,"generation":2,"operator":"inject","fitness":5}`)
+}
+
+func TestRead_GIVEN_invalid_line_WHEN_read_THEN_returns_error(t *testing.T) {
+	_, err := lineage.Read(strings.NewReader("{\"id\":1}\nnot json\n"))
+
+	assert.ErrorContains(t, err, "line 2")
+}
+
+// family is two initial individuals, an elite copy and a crossover and a mutation child
+func family() []lineage.Record {
+	return []lineage.Record{
+		{ID: 1, Operator: lineage.OpInit, Fitness: fitness(1)},
+		{ID: 2, Operator: lineage.OpInit, Fitness: fitness(2)},
+		{ID: 3, Generation: 1, Operator: lineage.OpElitism, Parents: []uint64{2}, Fitness: fitness(2)},
+		{ID: 4, Generation: 1, Operator: lineage.OpCrossover, Parents: []uint64{1, 2}, Fitness: fitness(3)},
+		{ID: 5, Generation: 1, Operator: lineage.OpMutation, Parents: []uint64{1}, Fitness: fitness(0.5)},
+		{ID: 6, Generation: 2, Operator: lineage.OpMutation, Parents: []uint64{4}, Fitness: nil},
+	}
+}
+
+func TestChampion_GIVEN_records_WHEN_found_THEN_highest_scored(t *testing.T) {
+	champion, ok := lineage.Champion(family())
+
+	assert.True(t, ok)
+	assert.Equal(t, uint64(4), champion.ID)
+	_, ok = lineage.Champion(nil)
+	assert.False(t, ok)
+}
+
+func TestOperatorStats_GIVEN_records_WHEN_computed_THEN_rates_against_best_parent(t *testing.T) {
+	stats := lineage.OperatorStats(family())
+
+	assert.Equal(t, []lineage.OperatorStat{
+		{Operator: lineage.OpCrossover, Children: 1, Improved: 1},
+		{Operator: lineage.OpElitism, Children: 1, Improved: 0},
+		{Operator: lineage.OpMutation, Children: 1, Improved: 0},
+	}, stats)
+	assert.Equal(t, 1.0, stats[0].Rate())
+	assert.Equal(t, 0.0, lineage.OperatorStat{}.Rate())
+}
+
+func TestWriteDOT_GIVEN_individual_WHEN_written_THEN_only_its_ancestry(t *testing.T) {
+	var out strings.Builder
+
+	require.NoError(t, lineage.WriteDOT(&out, family(), 4))
+
+	dot := out.String()
+	assert.True(t, strings.HasPrefix(dot, "digraph lineage {"))
+	assert.Contains(t, dot, `n4 [label="#4 gen 1\ncrossover\nfitness 3", style=bold];`)
+	assert.Contains(t, dot, `n1 -> n4 [label="crossover"];`)
+	assert.Contains(t, dot, `n2 -> n4 [label="crossover"];`)
+	assert.NotContains(t, dot, "n3")
+	assert.NotContains(t, dot, "n5")
+}
+
+func TestWriteDOT_GIVEN_unknown_individual_WHEN_written_THEN_returns_error(t *testing.T) {
+	assert.Error(t, lineage.WriteDOT(&strings.Builder{}, family(), 42))
+}
+
+func TestWriteStats_GIVEN_stats_WHEN_written_THEN_table_of_rates(t *testing.T) {
+	var out strings.Builder
+
+	require.NoError(t, lineage.WriteStats(&out, lineage.OperatorStats(family())))
+
+	assert.Contains(t, out.String(), "crossover             1          1   100.0%")
+}
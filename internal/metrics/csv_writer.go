@@ -2,144 +2,122 @@ package metrics
 
 import (
 	"encoding/csv"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"sort"
+	"strings"
 	"sync"
 )
 
-// CSVWriter supports dynamic schema expansion and rewriting
+// csvBaseColumns lead every row, before the metrics
+var csvBaseColumns = []string{"generation", "duration_ns", "population_size", "timestamp"}
+
+// CSVWriter appends a row per generation without keeping rows in memory. The first row fixes the
+// header line; metrics first seen later become new columns at the end, so later rows are longer
+// than the header line and the full header is kept in the sidecar file HeaderPath(path).
 type CSVWriter struct {
-	file        *os.File
-	mu          sync.Mutex
-	initialized bool
+	mu     sync.Mutex
+	file   *os.File
+	writer *csv.Writer
+	path   string
+	keys   []string // metric columns in file order
+	known  map[string]bool
+	rows   int
+	closed bool
+}
 
-	header []string   // dynamic metric keys
-	rows   [][]string // stored rows
-	path   string     // path to CSV file
+// HeaderPath is the sidecar file holding the full header of a CSV whose schema grew
+func HeaderPath(path string) string {
+	return path + ".header"
 }
 
-// NewCSVWriter creates a new dynamic CSV writer
+// NewCSVWriter creates an append-only CSV writer, replacing any existing file
 func NewCSVWriter(filename string) (*CSVWriter, error) {
 	file, err := os.Create(filename)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create CSV file %s: %w", filename, err)
 	}
+	// A sidecar left by an earlier run would describe the wrong columns
+	if err := os.Remove(HeaderPath(filename)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		file.Close()
+		return nil, fmt.Errorf("failed to remove stale CSV header: %w", err)
+	}
 
 	return &CSVWriter{
-		file:        file,
-		path:        filename,
-		header:      nil,
-		rows:        make([][]string, 0),
-		initialized: true,
+		file:   file,
+		writer: csv.NewWriter(file),
+		path:   filename,
+		known:  make(map[string]bool),
 	}, nil
 }
 
-// WriteMetrics writes a generation row and updates schema if needed
+// WriteMetrics appends a generation row, extending the schema with any new metrics
 func (csvw *CSVWriter) WriteMetrics(metrics GenerationMetrics) error {
-	if !csvw.initialized {
-		return fmt.Errorf("CSV writer not initialized")
-	}
-
 	csvw.mu.Lock()
 	defer csvw.mu.Unlock()
+	if csvw.closed {
+		return fmt.Errorf("CSV writer is closed")
+	}
 
-	// ---- Detect new metric keys ----
-	newKeysFound := false
+	var newKeys []string
 	for key := range metrics.Metrics {
-		if !contains(csvw.header, key) {
-			csvw.header = append(csvw.header, key)
-			newKeysFound = true
+		if !csvw.known[key] {
+			newKeys = append(newKeys, key)
 		}
 	}
+	sort.Strings(newKeys)
+	for _, key := range newKeys {
+		csvw.known[key] = true
+		csvw.keys = append(csvw.keys, key)
+	}
 
-	// Always sort for stable ordering
-	sort.Strings(csvw.header)
-
-	// ---- If schema expanded, rewrite whole file ----
-	if newKeysFound {
-		if err := csvw.rewriteCSV(); err != nil {
-			return err
+	if csvw.rows == 0 {
+		if err := csvw.writer.Write(csvw.header()); err != nil {
+			return fmt.Errorf("failed to write header: %w", err)
+		}
+	} else if len(newKeys) > 0 {
+		if err := os.WriteFile(HeaderPath(csvw.path), []byte(strings.Join(csvw.header(), ",")+"\n"), 0o644); err != nil {
+			return fmt.Errorf("failed to write CSV header: %w", err)
 		}
 	}
 
-	// ---- Build new row ----
 	row := []string{
 		fmt.Sprintf("%d", metrics.Generation),
 		fmt.Sprintf("%d", metrics.Duration.Nanoseconds()),
 		fmt.Sprintf("%d", metrics.PopulationSize),
 		metrics.Timestamp.Format("2006-01-02T15:04:05.000Z"),
 	}
-
-	// Add metric values in header order
-	for _, key := range csvw.header {
+	for _, key := range csvw.keys {
 		if v, ok := metrics.Metrics[key]; ok {
 			row = append(row, fmt.Sprintf("%f", v))
 		} else {
 			row = append(row, "0") // zero-fill missing metrics
 		}
 	}
-
-	// Save row
-	csvw.rows = append(csvw.rows, row)
-
-	// Rewrite CSV every time (cheap for <100k rows)
-	return csvw.rewriteCSV()
-}
-
-// rewriteCSV rebuilds the file from header and rows
-func (csvw *CSVWriter) rewriteCSV() error {
-	// Close current file
-	csvw.file.Close()
-
-	// Recreate fresh file
-	f, err := os.Create(csvw.path)
-	if err != nil {
-		return fmt.Errorf("failed to recreate CSV file: %w", err)
-	}
-	csvw.file = f
-
-	writer := csv.NewWriter(csvw.file)
-
-	// Build full header
-	fullHeader := append([]string{
-		"generation",
-		"duration_ns",
-		"population_size",
-		"timestamp",
-	}, csvw.header...)
-
-	// Write header
-	if err := writer.Write(fullHeader); err != nil {
-		return fmt.Errorf("failed to write header: %w", err)
-	}
-
-	// Write rows
-	for _, row := range csvw.rows {
-		if err := writer.Write(row); err != nil {
-			return fmt.Errorf("failed to write row: %w", err)
-		}
+	if err := csvw.writer.Write(row); err != nil {
+		return fmt.Errorf("failed to write row: %w", err)
 	}
+	csvw.rows++
+	csvw.writer.Flush()
+	return csvw.writer.Error()
+}
 
-	writer.Flush()
-	return writer.Error()
+// header is the full header: the base columns and every metric seen so far
+func (csvw *CSVWriter) header() []string {
+	return append(append([]string(nil), csvBaseColumns...), csvw.keys...)
 }
 
 // Close the file
 func (csvw *CSVWriter) Close() error {
 	csvw.mu.Lock()
 	defer csvw.mu.Unlock()
-
-	if !csvw.initialized {
+	if csvw.closed {
 		return nil
 	}
-
-	if err := csvw.file.Close(); err != nil {
-		return err
-	}
-
-	csvw.initialized = false
-	return nil
+	csvw.closed = true
+	return csvw.file.Close()
 }
 
 // MetricsHandler is a callback that writes metrics
@@ -151,22 +129,5 @@ func CreateCSVHandler(filename string) (MetricsHandler, error) {
 	if err != nil {
 		return nil, err
 	}
-
-	return func(metrics GenerationMetrics) {
-		if err := csvWriter.WriteMetrics(metrics); err != nil {
-			fmt.Printf("Warning: failed to write metrics: %v\n", err)
-		}
-	}, nil
+	return CreateSinkHandler(csvWriter), nil
 }
-
-// ---- Helper ----
-
-func contains(list []string, val string) bool {
-	for _, v := range list {
-		if v == val {
-			return true
-		}
-	}
-	return false
-}
-
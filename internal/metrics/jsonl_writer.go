@@ -0,0 +1,111 @@
+package metrics
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sync"
+	"time"
+)
+
+// jsonlRun is the first line of a JSONL metrics file
+type jsonlRun struct {
+	Type    string    `json:"type"`
+	Started time.Time `json:"started"`
+	Run     RunInfo   `json:"run,omitempty"`
+}
+
+// Record is the JSON form of GenerationMetrics, as written to JSONL files. NaN and infinite
+// metrics, which JSON cannot hold, are null.
+type Record struct {
+	Type            string              `json:"type"`
+	Generation      int                 `json:"generation"`
+	DurationNs      int64               `json:"duration_ns"`
+	PopulationSize  int                 `json:"population_size"`
+	Timestamp       time.Time           `json:"timestamp"`
+	BestDescription string              `json:"best_description"`
+	Metrics         map[string]*float64 `json:"metrics"`
+}
+
+// JSONLWriter appends one JSON object per generation after a line describing the run
+type JSONLWriter struct {
+	mu     sync.Mutex
+	file   *os.File
+	writer *bufio.Writer
+	closed bool
+}
+
+// NewJSONLWriter creates a JSON Lines writer, replacing any existing file, and records run
+func NewJSONLWriter(filename string, run RunInfo) (*JSONLWriter, error) {
+	file, err := os.Create(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JSONL file %s: %w", filename, err)
+	}
+	jw := &JSONLWriter{file: file, writer: bufio.NewWriter(file)}
+	if err := jw.writeLine(jsonlRun{Type: "run", Started: time.Now(), Run: run}); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return jw, nil
+}
+
+// WriteMetrics appends a generation line
+func (jw *JSONLWriter) WriteMetrics(metrics GenerationMetrics) error {
+	jw.mu.Lock()
+	defer jw.mu.Unlock()
+	if jw.closed {
+		return fmt.Errorf("JSONL writer is closed")
+	}
+	return jw.writeLine(NewRecord(metrics))
+}
+
+// NewRecord converts a generation's metrics to their JSON form
+func NewRecord(metrics GenerationMetrics) Record {
+	return Record{
+		Type:            "generation",
+		Generation:      metrics.Generation,
+		DurationNs:      metrics.Duration.Nanoseconds(),
+		PopulationSize:  metrics.PopulationSize,
+		Timestamp:       metrics.Timestamp,
+		BestDescription: metrics.BestDescription,
+		Metrics:         finite(metrics.Metrics),
+	}
+}
+
+// finite maps NaN and infinite metrics to null
+func finite(metrics map[string]float64) map[string]*float64 {
+	values := make(map[string]*float64, len(metrics))
+	for key, v := range metrics {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			values[key] = nil
+			continue
+		}
+		values[key] = &v
+	}
+	return values
+}
+
+// writeLine encodes a line and flushes it so readers following the file see whole generations
+func (jw *JSONLWriter) writeLine(line any) error {
+	data, err := json.Marshal(line)
+	if err != nil {
+		return fmt.Errorf("failed to encode metrics: %w", err)
+	}
+	if _, err := jw.writer.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	return jw.writer.Flush()
+}
+
+// Close the file
+func (jw *JSONLWriter) Close() error {
+	jw.mu.Lock()
+	defer jw.mu.Unlock()
+	if jw.closed {
+		return nil
+	}
+	jw.closed = true
+	return jw.file.Close()
+}
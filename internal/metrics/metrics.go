@@ -8,6 +8,7 @@ type GenerationMetrics struct {
 	Duration        time.Duration
 	BestDescription string
 	PopulationSize  int
+	Evaluations     int // individuals scored this generation
 	Metrics         map[string]float64
 	Timestamp       time.Time
 }
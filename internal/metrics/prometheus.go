@@ -0,0 +1,212 @@
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// PrometheusContentType is the media type of the Prometheus text exposition format
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// PrometheusExporter serves the latest generation's metrics at /metrics in the Prometheus text
+// format
+type PrometheusExporter struct {
+	poolStats func() map[string]interface{}
+
+	mu          sync.Mutex
+	latest      *GenerationMetrics
+	evaluations int
+
+	listener net.Listener
+	server   *http.Server
+}
+
+// NewPrometheusExporter creates an exporter. poolStats, if not nil, is read on every scrape for the
+// fields of TCPConnectionPool.GetStats.
+func NewPrometheusExporter(poolStats func() map[string]interface{}) *PrometheusExporter {
+	return &PrometheusExporter{poolStats: poolStats}
+}
+
+// Listen serves /metrics on addr, e.g. "127.0.0.1:9464" or "127.0.0.1:0"
+func (pe *PrometheusExporter) Listen(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", pe)
+	pe.listener = listener
+	pe.server = &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := pe.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			zap.L().Error("Prometheus endpoint stopped", zap.Error(err))
+		}
+	}()
+	zap.L().Info("Prometheus endpoint listening", zap.String("url", "http://"+pe.Addr()+"/metrics"))
+	return nil
+}
+
+// Addr is the address /metrics is served on
+func (pe *PrometheusExporter) Addr() string {
+	return pe.listener.Addr().String()
+}
+
+// Close stops serving
+func (pe *PrometheusExporter) Close() error {
+	if pe.server == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return pe.server.Shutdown(ctx)
+}
+
+// Follow observes every generation received from a MetricsStreamer subscription until it closes
+func (pe *PrometheusExporter) Follow(subscription <-chan GenerationMetrics) {
+	for m := range subscription {
+		pe.Observe(m)
+	}
+}
+
+// Observe makes m the generation reported
+func (pe *PrometheusExporter) Observe(m GenerationMetrics) {
+	pe.mu.Lock()
+	defer pe.mu.Unlock()
+	pe.latest = &m
+	pe.evaluations += m.Evaluations
+}
+
+// ServeHTTP implements http.Handler
+func (pe *PrometheusExporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", PrometheusContentType)
+	if err := pe.Write(w); err != nil {
+		zap.L().Warn("Failed to write Prometheus metrics", zap.Error(err))
+	}
+}
+
+// Write writes the current metrics in the Prometheus text format
+func (pe *PrometheusExporter) Write(w io.Writer) error {
+	pe.mu.Lock()
+	latest, evaluations := pe.latest, pe.evaluations
+	pe.mu.Unlock()
+
+	out := bufio.NewWriter(w)
+	family(out, "darwin_evaluations_total", "counter", "Individuals scored since the run started")
+	sample(out, "darwin_evaluations_total", nil, float64(evaluations))
+	if latest != nil {
+		family(out, "darwin_generation", "gauge", "Last completed generation")
+		sample(out, "darwin_generation", nil, float64(latest.Generation))
+		family(out, "darwin_generation_duration_seconds", "gauge", "Wall time of the last generation")
+		sample(out, "darwin_generation_duration_seconds", nil, latest.Duration.Seconds())
+		family(out, "darwin_evaluations_per_second", "gauge", "Individuals scored per second in the last generation")
+		sample(out, "darwin_evaluations_per_second", nil, evaluationRate(latest))
+		family(out, "darwin_population_size", "gauge", "Individuals in the population")
+		sample(out, "darwin_population_size", nil, float64(latest.PopulationSize))
+		writePopulationMetrics(out, latest.Metrics)
+	}
+	if pe.poolStats != nil {
+		if stats := pe.poolStats(); stats != nil {
+			writePoolStats(out, stats)
+		}
+	}
+	return out.Flush()
+}
+
+// evaluationRate is the last generation's evaluations per second
+func evaluationRate(m *GenerationMetrics) float64 {
+	if m.Duration <= 0 {
+		return 0
+	}
+	return float64(m.Evaluations) / m.Duration.Seconds()
+}
+
+// writePopulationMetrics reports each population metric, splitting the engine's min_, avg_ and
+// max_ prefixes into a stat label
+func writePopulationMetrics(out *bufio.Writer, metrics map[string]float64) {
+	keys := make([]string, 0, len(metrics))
+	for key := range metrics {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	family(out, "darwin_population_metric", "gauge", "Population statistics of each individual metric")
+	for _, key := range keys {
+		labels := [][2]string{{"metric", key}}
+		for _, stat := range []string{"min", "avg", "max"} {
+			if name, ok := strings.CutPrefix(key, stat+"_"); ok {
+				labels = [][2]string{{"metric", name}, {"stat", stat}}
+				break
+			}
+		}
+		sample(out, "darwin_population_metric", labels, metrics[key])
+	}
+}
+
+// writePoolStats reports the numeric fields of TCPConnectionPool.GetStats
+func writePoolStats(out *bufio.Writer, stats map[string]interface{}) {
+	server := [][2]string{{"server", fmt.Sprint(stats["server_addr"])}}
+	if active, ok := number(stats["active_count"]); ok {
+		family(out, "darwin_connection_pool_active", "gauge", "Connections open to the game server")
+		sample(out, "darwin_connection_pool_active", server, active)
+	}
+	if available, ok := number(stats["available"]); ok {
+		family(out, "darwin_connection_pool_available", "gauge", "Idle connections ready for a game")
+		sample(out, "darwin_connection_pool_available", server, available)
+	}
+	if maxConnections, ok := number(stats["max_connections"]); ok {
+		family(out, "darwin_connection_pool_max", "gauge", "Most connections the pool opens")
+		sample(out, "darwin_connection_pool_max", server, maxConnections)
+	}
+	if created, ok := number(stats["total_created"]); ok {
+		family(out, "darwin_connection_pool_created_total", "counter", "Connections opened since the run started")
+		sample(out, "darwin_connection_pool_created_total", server, created)
+	}
+}
+
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+func family(out *bufio.Writer, name, kind, help string) {
+	fmt.Fprintf(out, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func sample(out *bufio.Writer, name string, labels [][2]string, value float64) {
+	out.WriteString(name)
+	if len(labels) > 0 {
+		out.WriteByte('{')
+		for i, label := range labels {
+			if i > 0 {
+				out.WriteByte(',')
+			}
+			fmt.Fprintf(out, "%s=\"%s\"", label[0], labelEscaper.Replace(label[1]))
+		}
+		out.WriteByte('}')
+	}
+	out.WriteByte(' ')
+	// FormatFloat spells non-finite values NaN, +Inf and -Inf, as the format expects
+	out.WriteString(strconv.FormatFloat(value, 'g', -1, 64))
+	out.WriteByte('\n')
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scrape(t *testing.T, pe *PrometheusExporter) string {
+	t.Helper()
+	var out strings.Builder
+	require.NoError(t, pe.Write(&out))
+	return out.String()
+}
+
+func TestPrometheusExporter_GIVEN_no_generation_WHEN_scraped_THEN_reports_zero_evaluations_only(t *testing.T) {
+	body := scrape(t, NewPrometheusExporter(nil))
+
+	assert.Contains(t, body, "# TYPE darwin_evaluations_total counter\ndarwin_evaluations_total 0\n")
+	assert.NotContains(t, body, "darwin_generation")
+}
+
+func TestPrometheusExporter_GIVEN_generations_WHEN_scraped_THEN_reports_latest_and_totals(t *testing.T) {
+	pe := NewPrometheusExporter(nil)
+	first := generation(1, map[string]float64{"max_fit": 0.5})
+	first.Evaluations = 20
+	second := generation(2, map[string]float64{"min_fit": 0.25, "avg_fit": 0.5, "max_fit": 0.75, "avg_depth": 3, "x": math.NaN()})
+	second.Evaluations = 10
+	second.Duration = 500 * time.Millisecond
+	pe.Observe(first)
+	pe.Observe(second)
+
+	body := scrape(t, pe)
+
+	assert.Contains(t, body, "darwin_evaluations_total 30\n")
+	assert.Contains(t, body, "# TYPE darwin_generation gauge\ndarwin_generation 2\n")
+	assert.Contains(t, body, "darwin_generation_duration_seconds 0.5\n")
+	assert.Contains(t, body, "darwin_evaluations_per_second 20\n")
+	assert.Contains(t, body, `darwin_population_metric{metric="fit",stat="min"} 0.25`+"\n")
+	assert.Contains(t, body, `darwin_population_metric{metric="fit",stat="max"} 0.75`+"\n")
+	assert.Contains(t, body, `darwin_population_metric{metric="depth",stat="avg"} 3`+"\n")
+	assert.Contains(t, body, `darwin_population_metric{metric="x"} NaN`+"\n")
+}
+
+func TestPrometheusExporter_GIVEN_pool_stats_WHEN_scraped_THEN_reports_connections(t *testing.T) {
+	pe := NewPrometheusExporter(func() map[string]interface{} {
+		return map[string]interface{}{
+			"server_addr":     "localhost:5000",
+			"max_connections": 8,
+			"active_count":    5,
+			"available":       2,
+			"total_created":   6,
+			"closed":          false,
+		}
+	})
+
+	body := scrape(t, pe)
+
+	assert.Contains(t, body, `darwin_connection_pool_active{server="localhost:5000"} 5`+"\n")
+	assert.Contains(t, body, `darwin_connection_pool_available{server="localhost:5000"} 2`+"\n")
+	assert.Contains(t, body, `darwin_connection_pool_max{server="localhost:5000"} 8`+"\n")
+	assert.Contains(t, body, "# TYPE darwin_connection_pool_created_total counter\n")
+}
+
+func TestPrometheusExporter_GIVEN_label_with_quotes_WHEN_scraped_THEN_escaped(t *testing.T) {
+	pe := NewPrometheusExporter(nil)
+	pe.Observe(generation(1, map[string]float64{`a"b\c`: 1}))
+
+	assert.Contains(t, scrape(t, pe), `darwin_population_metric{metric="a\"b\\c"} 1`)
+}
+
+func TestPrometheusExporter_GIVEN_listening_WHEN_followed_stream_scraped_THEN_served_over_http(t *testing.T) {
+	pe := NewPrometheusExporter(nil)
+	require.NoError(t, pe.Listen("127.0.0.1:0"))
+	defer pe.Close()
+	subscription := make(chan GenerationMetrics, 1)
+	subscription <- generation(7, map[string]float64{"max_fit": 1})
+	close(subscription)
+	pe.Follow(subscription)
+
+	resp, err := http.Get("http://" + pe.Addr() + "/metrics")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, PrometheusContentType, resp.Header.Get("Content-Type"))
+	assert.Contains(t, string(body), "darwin_generation 7\n")
+}
@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.uber.org/zap"
+)
+
+// Sink persists generation metrics as they arrive
+type Sink interface {
+	WriteMetrics(metrics GenerationMetrics) error
+	Close() error
+}
+
+// RunInfo describes a run, e.g. its seed and genome, for sinks that record it
+type RunInfo map[string]any
+
+// SinkFactory opens a sink writing to path
+type SinkFactory func(path string, run RunInfo) (Sink, error)
+
+// sinkFactories maps the sink types usable in [[metrics.sinks]] to their factories
+var sinkFactories = map[string]SinkFactory{
+	"csv": func(path string, run RunInfo) (Sink, error) {
+		return NewCSVWriter(path)
+	},
+	"jsonl": func(path string, run RunInfo) (Sink, error) {
+		return NewJSONLWriter(path, run)
+	},
+}
+
+// RegisterSink makes a sink type available to the metrics config under name
+func RegisterSink(name string, factory SinkFactory) {
+	sinkFactories[name] = factory
+}
+
+// SinkNames returns the registered sink types in sorted order
+func SinkNames() []string {
+	names := make([]string, 0, len(sinkFactories))
+	for name := range sinkFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewSink opens a sink of a registered type
+func NewSink(name, path string, run RunInfo) (Sink, error) {
+	factory, ok := sinkFactories[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown metrics sink %q, available: %v", name, SinkNames())
+	}
+	return factory(path, run)
+}
+
+// MultiSink writes every generation to each of its sinks
+type MultiSink []Sink
+
+// WriteMetrics implements Sink, writing to every sink even if one fails
+func (ms MultiSink) WriteMetrics(metrics GenerationMetrics) error {
+	var errs []error
+	for _, sink := range ms {
+		errs = append(errs, sink.WriteMetrics(metrics))
+	}
+	return errors.Join(errs...)
+}
+
+// Close implements Sink, closing every sink
+func (ms MultiSink) Close() error {
+	var errs []error
+	for _, sink := range ms {
+		errs = append(errs, sink.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// CreateSinkHandler creates a callback writing to sink, logging failed writes
+func CreateSinkHandler(sink Sink) MetricsHandler {
+	return func(metrics GenerationMetrics) {
+		if err := sink.WriteMetrics(metrics); err != nil {
+			zap.L().Warn("Failed to write metrics", zap.Int("generation", metrics.Generation), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package metrics
+
+import (
+	"bufio"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func generation(n int, values map[string]float64) GenerationMetrics {
+	return GenerationMetrics{
+		Generation:      n,
+		Duration:        time.Millisecond,
+		BestDescription: "(x + 1)",
+		PopulationSize:  10,
+		Metrics:         values,
+		Timestamp:       time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func readLines(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func TestCSVWriter_GIVEN_stable_schema_WHEN_written_THEN_appends_rows_without_sidecar(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.csv")
+	writer, err := NewCSVWriter(path)
+	require.NoError(t, err)
+
+	require.NoError(t, writer.WriteMetrics(generation(1, map[string]float64{"max_fit": 1, "avg_fit": 0.5})))
+	// Rows are on disk before the writer closes
+	assert.Len(t, readLines(t, path), 2)
+	require.NoError(t, writer.WriteMetrics(generation(2, map[string]float64{"max_fit": 2})))
+	require.NoError(t, writer.Close())
+
+	lines := readLines(t, path)
+	assert.Equal(t, "generation,duration_ns,population_size,timestamp,avg_fit,max_fit", lines[0])
+	assert.Equal(t, "2,1000000,10,2025-01-01T00:00:00.000Z,0,2.000000", lines[2])
+	assert.NoFileExists(t, HeaderPath(path))
+}
+
+func TestCSVWriter_GIVEN_new_metric_WHEN_written_THEN_appends_column_and_writes_sidecar(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.csv")
+	require.NoError(t, os.WriteFile(HeaderPath(path), []byte("stale\n"), 0o644))
+	writer, err := NewCSVWriter(path)
+	require.NoError(t, err)
+	assert.NoFileExists(t, HeaderPath(path), "a sidecar from an earlier run is removed")
+
+	require.NoError(t, writer.WriteMetrics(generation(1, map[string]float64{"max_fit": 1})))
+	require.NoError(t, writer.WriteMetrics(generation(2, map[string]float64{"max_fit": 2, "diversity": 0.5})))
+	require.NoError(t, writer.Close())
+
+	lines := readLines(t, path)
+	assert.Equal(t, "generation,duration_ns,population_size,timestamp,max_fit", lines[0], "the header line is never rewritten")
+	assert.Equal(t, "2,1000000,10,2025-01-01T00:00:00.000Z,2.000000,0.500000", lines[2])
+	assert.Equal(t, []string{"generation,duration_ns,population_size,timestamp,max_fit,diversity"}, readLines(t, HeaderPath(path)))
+}
+
+func TestCSVWriter_GIVEN_closed_writer_WHEN_written_THEN_returns_error(t *testing.T) {
+	writer, err := NewCSVWriter(filepath.Join(t.TempDir(), "metrics.csv"))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	assert.Error(t, writer.WriteMetrics(generation(1, nil)))
+	assert.NoError(t, writer.Close())
+}
+
+func TestJSONLWriter_GIVEN_generations_WHEN_written_THEN_records_run_and_everything(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.jsonl")
+	writer, err := NewJSONLWriter(path, RunInfo{"seed": 42, "genome": "tree"})
+	require.NoError(t, err)
+
+	require.NoError(t, writer.WriteMetrics(generation(1, map[string]float64{"max_fit": 1.5, "min_fit": math.Inf(-1)})))
+	require.NoError(t, writer.Close())
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	var lines []map[string]any
+	for scanner.Scan() {
+		var line map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	require.Len(t, lines, 2)
+	assert.Equal(t, "run", lines[0]["type"])
+	assert.Equal(t, map[string]any{"seed": 42.0, "genome": "tree"}, lines[0]["run"])
+	assert.Equal(t, "generation", lines[1]["type"])
+	assert.Equal(t, "(x + 1)", lines[1]["best_description"])
+	assert.Equal(t, map[string]any{"max_fit": 1.5, "min_fit": nil}, lines[1]["metrics"])
+}
+
+func TestNewSink_GIVEN_unknown_type_WHEN_opened_THEN_lists_available(t *testing.T) {
+	_, err := NewSink("parquet", "metrics.parquet", nil)
+
+	assert.ErrorContains(t, err, "csv jsonl")
+}
+
+// recordingSink counts writes and closes, optionally failing
+type recordingSink struct {
+	writes, closes int
+	err            error
+}
+
+func (rs *recordingSink) WriteMetrics(metrics GenerationMetrics) error {
+	rs.writes++
+	return rs.err
+}
+
+func (rs *recordingSink) Close() error {
+	rs.closes++
+	return nil
+}
+
+func TestMultiSink_GIVEN_failing_sink_WHEN_written_THEN_still_writes_the_others(t *testing.T) {
+	failing, healthy := &recordingSink{err: assert.AnError}, &recordingSink{}
+	sinks := MultiSink{failing, healthy}
+
+	assert.ErrorIs(t, sinks.WriteMetrics(generation(1, nil)), assert.AnError)
+	assert.NoError(t, sinks.Close())
+
+	assert.Equal(t, 1, healthy.writes)
+	assert.Equal(t, 1, healthy.closes)
+	assert.Equal(t, 1, failing.closes)
+}
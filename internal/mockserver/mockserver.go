@@ -0,0 +1,389 @@
+// Package mockserver is an in-process stand-in for the Python game server. It speaks the same
+// newline-delimited JSON protocol and plays scripted games, so the TCP fitness path can be tested
+// without Python. It is also the reference server for the multiplexed protocol, where every
+// message carries a game_id and one connection plays many games.
+package mockserver
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bxrne/darwin/internal/generals"
+)
+
+// Scenario scripts how the server answers. Action numbers count the actions of one game from 1;
+// a zero action number disables the behaviour.
+type Scenario struct {
+	// Rewards[n-1] is the reward for action n; later actions earn nothing
+	Rewards []float64
+	// TerminateAt ends the game as terminated after this action
+	TerminateAt int
+	// TruncateAt ends the game as truncated after this action
+	TruncateAt int
+	// MalformedAt answers this action with a line that is not JSON
+	MalformedAt int
+	// DropAt closes the connection instead of answering this action
+	DropAt int
+	// Delay is slept before every reply
+	Delay time.Duration
+	// ConnectError rejects connect requests with this error message
+	ConnectError string
+	// HealthStatus is reported to health checks, "ok" when empty
+	HealthStatus string
+	// Features overrides observation features; defaults to zeroed Generals features
+	Features map[string]float64
+	// Grid is the info grid sent with step observations; defaults to every 8x8 cell movable
+	Grid [][]bool
+}
+
+// Stats counts what the server has handled
+type Stats struct {
+	Connections  int64
+	Games        int64
+	Actions      int64
+	Replays      int64
+	HealthChecks int64
+	Errors       int64
+	// Batches counts step_batch messages, whose actions are also counted in Actions
+	Batches int64
+	Pings   int64
+}
+
+// Server is a scripted game server listening on a local port
+type Server struct {
+	listener net.Listener
+	mu       sync.Mutex
+	scenario Scenario
+	conns    map[net.Conn]struct{}
+	wg       sync.WaitGroup
+
+	connections  atomic.Int64
+	games        atomic.Int64
+	actions      atomic.Int64
+	replays      atomic.Int64
+	healthChecks atomic.Int64
+	errors       atomic.Int64
+	batches      atomic.Int64
+	pings        atomic.Int64
+}
+
+// Start listens on a free localhost port and serves the scenario until Close
+func Start(scenario Scenario) (*Server, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{listener: listener, scenario: scenario, conns: make(map[net.Conn]struct{})}
+	s.wg.Add(1)
+	go s.accept()
+	return s, nil
+}
+
+// Addr is the host:port clients should dial
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// SetScenario replaces the scenario for connections accepted from now on
+func (s *Server) SetScenario(scenario Scenario) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scenario = scenario
+}
+
+// Stats returns the counters so far
+func (s *Server) Stats() Stats {
+	return Stats{
+		Connections:  s.connections.Load(),
+		Games:        s.games.Load(),
+		Actions:      s.actions.Load(),
+		Replays:      s.replays.Load(),
+		HealthChecks: s.healthChecks.Load(),
+		Errors:       s.errors.Load(),
+		Batches:      s.batches.Load(),
+		Pings:        s.pings.Load(),
+	}
+}
+
+// DropConnections closes every open connection, as a restarting server would
+func (s *Server) DropConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+}
+
+// Close stops accepting, drops open connections and waits for their handlers
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	s.mu.Lock()
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	return err
+}
+
+func (s *Server) accept() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		scenario := s.scenario
+		s.mu.Unlock()
+		s.connections.Add(1)
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer s.forget(conn)
+			(&session{server: s, conn: conn, scenario: scenario, games: make(map[string]*game)}).serve()
+		}()
+	}
+}
+
+func (s *Server) forget(conn net.Conn) {
+	s.mu.Lock()
+	delete(s.conns, conn)
+	s.mu.Unlock()
+	_ = conn.Close()
+}
+
+// session is one client connection. Messages without a game_id play the connection's single
+// legacy game; multiplexed messages address their own game and get replies tagged with its id.
+type session struct {
+	server   *Server
+	conn     net.Conn
+	scenario Scenario
+	games    map[string]*game
+}
+
+// game is the state of one game on a connection
+type game struct {
+	id      string
+	playing bool
+	step    int
+}
+
+// message is the part of a client message the server routes on
+type message struct {
+	Type   string `json:"type"`
+	GameId string `json:"game_id"`
+	Steps  []struct {
+		GameId string `json:"game_id"`
+	} `json:"steps"`
+}
+
+func (ss *session) serve() {
+	scanner := bufio.NewScanner(ss.conn)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var msg message
+		if err := json.Unmarshal(line, &msg); err != nil {
+			ss.server.errors.Add(1)
+			if !ss.send(errorMessage("Processing error", err.Error()), "") {
+				return
+			}
+			continue
+		}
+		if !ss.handle(msg) {
+			return
+		}
+	}
+}
+
+// game returns the game with the given id, creating it on first use
+func (ss *session) game(id string) *game {
+	g, ok := ss.games[id]
+	if !ok {
+		g = &game{id: id}
+		ss.games[id] = g
+	}
+	return g
+}
+
+// handle answers one message and reports whether the connection stays open
+func (ss *session) handle(msg message) bool {
+	g := ss.game(msg.GameId)
+	switch msg.Type {
+	case "connect":
+		if ss.scenario.ConnectError != "" {
+			ss.server.errors.Add(1)
+			return ss.send(errorMessage(ss.scenario.ConnectError, "scripted connect failure"), g.id)
+		}
+		ss.server.games.Add(1)
+		g.playing, g.step = true, 0
+		return ss.send(map[string]any{
+			"type":        "connected",
+			"agent_id":    "agent",
+			"opponent_id": "mock",
+			"message":     "Connected to mock server",
+		}, g.id) && ss.send(ss.observation(g, 0, false, false, ss.mountains()), g.id)
+
+	case "reset":
+		if !g.playing {
+			return ss.send(errorMessage("No active game", "Send CONNECT first"), g.id)
+		}
+		g.step = 0
+		return ss.send(ss.observation(g, 0, false, false, ss.mountains()), g.id)
+
+	case "action":
+		return ss.act(g)
+
+	case "step_batch":
+		ss.server.batches.Add(1)
+		for _, step := range msg.Steps {
+			if !ss.act(ss.game(step.GameId)) {
+				return false
+			}
+		}
+		return true
+
+	case "close_game":
+		delete(ss.games, g.id)
+		return true
+
+	case "save_replay":
+		ss.server.replays.Add(1)
+		return true
+
+	case "ping":
+		ss.server.pings.Add(1)
+		return ss.send(map[string]any{"type": "pong"}, "")
+
+	case "health":
+		ss.server.healthChecks.Add(1)
+		status := ss.scenario.HealthStatus
+		if status == "" {
+			status = "ok"
+		}
+		ss.send(map[string]any{"type": "health_response", "status": status, "message": "Mock server"}, "")
+		// The Python server closes the connection after answering a health check
+		return false
+
+	default:
+		ss.server.errors.Add(1)
+		return ss.send(errorMessage("Unknown message type", "Type: "+msg.Type), g.id)
+	}
+}
+
+// act answers an action in a game according to the scenario
+func (ss *session) act(g *game) bool {
+	if !g.playing {
+		ss.server.errors.Add(1)
+		return ss.send(errorMessage("No active game", "Send CONNECT first"), g.id)
+	}
+	ss.server.actions.Add(1)
+	g.step++
+
+	sc := ss.scenario
+	switch g.step {
+	case sc.DropAt:
+		return false
+	case sc.MalformedAt:
+		ss.sleep()
+		_, err := ss.conn.Write([]byte("{\"type\": \"observation\", \"observation\": \n"))
+		return err == nil
+	}
+
+	reward := 0.0
+	if g.step <= len(sc.Rewards) {
+		reward = sc.Rewards[g.step-1]
+	}
+	terminated := g.step == sc.TerminateAt
+	truncated := !terminated && g.step == sc.TruncateAt
+	if !ss.send(ss.observation(g, reward, terminated, truncated, ss.grid()), g.id) {
+		return false
+	}
+	if terminated || truncated {
+		g.playing = false
+		return ss.send(map[string]any{
+			"type":          "game_over",
+			"winner":        nil,
+			"final_rewards": map[string]float64{"agent": reward},
+			"reason":        "Game completed",
+		}, g.id)
+	}
+	return true
+}
+
+func (ss *session) observation(g *game, reward float64, terminated, truncated bool, info [][]bool) map[string]any {
+	features := ss.scenario.Features
+	if features == nil {
+		features = make(map[string]float64, len(generals.FeatureNames))
+		for _, name := range generals.FeatureNames {
+			features[name] = 0
+		}
+		features["timestep"] = float64(g.step)
+	}
+	return map[string]any{
+		"type":        "observation",
+		"observation": features,
+		"reward":      reward,
+		"terminated":  terminated,
+		"truncated":   truncated,
+		"info":        info,
+	}
+}
+
+// mountains is the info sent on connect and reset: an 8x8 map without mountains
+func (ss *session) mountains() [][]bool {
+	return filledGrid(false)
+}
+
+func (ss *session) grid() [][]bool {
+	if ss.scenario.Grid != nil {
+		return ss.scenario.Grid
+	}
+	return filledGrid(true)
+}
+
+func filledGrid(value bool) [][]bool {
+	grid := make([][]bool, 8)
+	for i := range grid {
+		grid[i] = make([]bool, 8)
+		for j := range grid[i] {
+			grid[i][j] = value
+		}
+	}
+	return grid
+}
+
+func (ss *session) sleep() {
+	if ss.scenario.Delay > 0 {
+		time.Sleep(ss.scenario.Delay)
+	}
+}
+
+// send writes one message, tagged with gameId when it is set, after the scripted delay and
+// reports success
+func (ss *session) send(message map[string]any, gameId string) bool {
+	ss.sleep()
+	if gameId != "" {
+		message["game_id"] = gameId
+	}
+	data, err := json.Marshal(message)
+	if err != nil {
+		return false
+	}
+	_, err = ss.conn.Write(append(data, '\n'))
+	return err == nil
+}
+
+func errorMessage(message, details string) map[string]any {
+	return map[string]any{"type": "error", "message": message, "details": details}
+}
@@ -0,0 +1,121 @@
+package plugin
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/bxrne/darwin/internal/adaptive"
+	"github.com/bxrne/darwin/internal/individual"
+)
+
+// AdaptationBuilder creates a rate adaptation controller; a nil controller keeps the rates fixed
+type AdaptationBuilder func(ctx *Context) (adaptive.Controller, error)
+
+// Adaptations holds the rate adaptation strategies
+var Adaptations = NewRegistry[AdaptationBuilder]("rate adaptation")
+
+// RegisterAdaptation registers a strategy selectable with [operators.adaptation] type = name
+func RegisterAdaptation(name string, builder AdaptationBuilder) { Adaptations.Register(name, builder) }
+
+// rateBounds keeps an adapted mutation rate within bounds
+type rateBounds struct {
+	MinRate float64 `toml:"min_rate"`
+	MaxRate float64 `toml:"max_rate"`
+}
+
+var defaultRateBounds = rateBounds{MinRate: 0.001, MaxRate: 1}
+
+type oneFifthOptions struct {
+	rateBounds
+	Factor float64 `toml:"factor"`
+}
+
+type diversityOptions struct {
+	rateBounds
+	Factor float64 `toml:"factor"`
+	Metric string  `toml:"metric"`
+	Target float64 `toml:"target"`
+}
+
+type probabilityMatchingOptions struct {
+	MinProbability float64 `toml:"min_probability"`
+	AdaptationRate float64 `toml:"adaptation_rate"`
+}
+
+type banditOptions struct {
+	Exploration float64 `toml:"exploration"`
+}
+
+type selfAdaptiveOptions struct {
+	rateBounds
+	Tau float64 `toml:"tau"`
+}
+
+// defaultDiversityMetrics is the diversity metric each genome's rate follows unless one is set
+var defaultDiversityMetrics = map[string]string{
+	"bitstring":    "diversity_hamming",
+	"tree":         "diversity_unique_phenotypes",
+	"action_tree":  "diversity_unique_phenotypes",
+	"grammar_tree": "diversity_codon_entropy",
+}
+
+func init() {
+	RegisterAdaptation("fixed", func(ctx *Context) (adaptive.Controller, error) {
+		return nil, nil
+	})
+	RegisterAdaptation("one_fifth", func(ctx *Context) (adaptive.Controller, error) {
+		opts := oneFifthOptions{rateBounds: defaultRateBounds, Factor: 1.22}
+		if err := ctx.Decode(&opts); err != nil {
+			return nil, err
+		}
+		return adaptive.NewOneFifth(opts.Factor, opts.MinRate, opts.MaxRate)
+	})
+	RegisterAdaptation("diversity", func(ctx *Context) (adaptive.Controller, error) {
+		opts := diversityOptions{rateBounds: defaultRateBounds, Factor: 1.1, Metric: defaultDiversityMetrics[ctx.Config.GenomeName()]}
+		if opts.Metric == "" {
+			opts.Metric = "diversity_fitness_entropy"
+		}
+		if err := ctx.Decode(&opts); err != nil {
+			return nil, err
+		}
+		return adaptive.NewDiversityTarget(opts.Metric, opts.Target, opts.Factor, opts.MinRate, opts.MaxRate)
+	})
+	RegisterAdaptation("probability_matching", func(ctx *Context) (adaptive.Controller, error) {
+		opts := probabilityMatchingOptions{MinProbability: 0.05, AdaptationRate: 0.3}
+		if err := ctx.Decode(&opts); err != nil {
+			return nil, err
+		}
+		if err := requireTrees(ctx); err != nil {
+			return nil, err
+		}
+		return adaptive.NewProbabilityMatching(individual.WeightedTreeMutations(ctx.Config.Tree.MutationWeights), opts.MinProbability, opts.AdaptationRate)
+	})
+	RegisterAdaptation("bandit", func(ctx *Context) (adaptive.Controller, error) {
+		opts := banditOptions{Exploration: math.Sqrt2}
+		if err := ctx.Decode(&opts); err != nil {
+			return nil, err
+		}
+		if err := requireTrees(ctx); err != nil {
+			return nil, err
+		}
+		return adaptive.NewBandit(individual.WeightedTreeMutations(ctx.Config.Tree.MutationWeights), opts.Exploration)
+	})
+	RegisterAdaptation("self_adaptive", func(ctx *Context) (adaptive.Controller, error) {
+		opts := selfAdaptiveOptions{rateBounds: defaultRateBounds, Tau: 0.2}
+		if err := ctx.Decode(&opts); err != nil {
+			return nil, err
+		}
+		return adaptive.NewSelfAdaptive(opts.Tau, opts.MinRate, opts.MaxRate)
+	})
+}
+
+// requireTrees rejects genomes whose mutation has no tree mutation types to choose between. The
+// types to choose between are those [tree_individual] mutation_weights gives a chance to.
+func requireTrees(ctx *Context) error {
+	switch genome := ctx.Config.GenomeName(); genome {
+	case "tree", "action_tree":
+		return nil
+	default:
+		return fmt.Errorf("choosing tree mutation types needs a tree or action_tree genome, not %q", genome)
+	}
+}
@@ -0,0 +1,108 @@
+package plugin
+
+import (
+	"fmt"
+
+	"github.com/bxrne/darwin/internal/evolution"
+	"github.com/bxrne/darwin/internal/fitness"
+	"github.com/bxrne/darwin/internal/individual"
+	"github.com/bxrne/darwin/internal/population"
+	"github.com/bxrne/darwin/internal/rng"
+	"github.com/bxrne/darwin/internal/selection"
+)
+
+// Built-in components keep the legacy config sections working under their registered names
+func init() {
+	RegisterGenome("bitstring", legacyGenome(individual.BitStringGenome))
+	RegisterGenome("tree", legacyGenome(individual.TreeGenome))
+	RegisterGenome("grammar_tree", legacyGenome(individual.GrammarTreeGenome))
+	RegisterGenome("action_tree", legacyGenome(individual.ActionTreeGenome))
+
+	RegisterFitness("bitstring", legacyFitness)
+	RegisterFitness("tree", legacyFitness)
+	RegisterFitness("grammar_tree", legacyFitness)
+	RegisterFitness("action_tree", legacyFitness)
+
+	RegisterSelector("tournament", func(ctx *Context) (selection.Selector, error) {
+		size, err := selectionSize(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return selection.NewTournamentSelector(size), nil
+	})
+	RegisterSelector("roulette", func(ctx *Context) (selection.Selector, error) {
+		size, err := selectionSize(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return selection.NewRouletteSelector(size), nil
+	})
+
+	RegisterMutation("native", func(ctx *Context) (evolution.MutationOperator, error) {
+		return evolution.NativeMutation, nil
+	})
+	RegisterCrossover("native", func(ctx *Context) (evolution.CrossoverOperator, error) {
+		return evolution.NativeCrossover, nil
+	})
+}
+
+// legacyGenome builds one of the original genome types from its own config section
+func legacyGenome(genomeType individual.GenomeType) GenomeBuilder {
+	return func(ctx *Context) (*Genome, error) {
+		config := ctx.Config
+		factory := population.NewIndividualFactory(config)
+		return &Genome{
+			Type: genomeType,
+			New: func(r *rng.Rand) individual.Evolvable {
+				return factory.CreateIndividual(genomeType, r)
+			},
+			CrossoverInformation: individual.CrossoverInformation{
+				CrossoverPoints: config.Evolution.CrossoverPointCount,
+				MaxDepth:        config.Tree.MaxDepth,
+				MaxSize:         config.Tree.MaxSize,
+				CrossoverType:   config.Tree.CrossoverType,
+			},
+			MutateInformation: individual.MutateInformation{
+				OperandSet:      config.Tree.OperandSet,
+				TerminalSet:     config.Tree.TerminalSet,
+				VariableSet:     config.Tree.VariableSet,
+				MaxDepth:        config.Tree.MaxDepth,
+				MutationWeights: config.Tree.MutationWeights,
+				MutationMode:    config.Tree.MutationMode,
+				ConstantSigma:   config.Tree.ConstantSigma,
+				MaxSize:         config.Tree.MaxSize,
+			},
+		}, nil
+	}
+}
+
+// legacyFitness builds the fitness calculator matching a legacy genome type
+func legacyFitness(ctx *Context, genome *Genome, pop population.Population) (fitness.FitnessCalculator, error) {
+	config := ctx.Config
+	grammar := individual.CreateGrammar(config.Tree.TerminalSet, config.Tree.VariableSet, config.Tree.OperandSet)
+	info := fitness.GenerateFitnessInfoFromConfig(config, genome.Type, grammar, pop.GetPopulations())
+	info.Rand = ctx.Rand
+	info.Logger = ctx.Logger
+	calc := fitness.FitnessCalculatorFactoryWithConfig(info, config)
+	if calc == nil {
+		return nil, fmt.Errorf("no fitness calculator for genome %q", config.GenomeName())
+	}
+	return calc, nil
+}
+
+// selectionOptions lets a selector sub-section override evolution.selection_size
+type selectionOptions struct {
+	Size int `toml:"size"`
+}
+
+// selectionSize reads the selection size, preferring the selector's own options
+func selectionSize(ctx *Context) (int, error) {
+	var opts selectionOptions
+	if err := ctx.Decode(&opts); err != nil {
+		return 0, err
+	}
+	if opts.Size > 0 {
+		return opts.Size, nil
+	}
+	return ctx.Config.Evolution.SelectionSize, nil
+}
@@ -0,0 +1,193 @@
+// Package plugin holds name-based registries for genome types, fitness calculators,
+// selectors, variation operators and rate adaptations. A new representation registers its builders
+// from an init function in its own package and is then selectable from TOML by name.
+package plugin
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/bxrne/darwin/internal/adaptive"
+	"github.com/bxrne/darwin/internal/cfg"
+	"github.com/bxrne/darwin/internal/evolution"
+	"github.com/bxrne/darwin/internal/fitness"
+	"github.com/bxrne/darwin/internal/individual"
+	"github.com/bxrne/darwin/internal/population"
+	"github.com/bxrne/darwin/internal/rng"
+	"github.com/bxrne/darwin/internal/selection"
+	"go.uber.org/zap"
+)
+
+// Context is handed to every builder: the full resolved config, the
+// component's own options sub-section and the run's generator
+type Context struct {
+	Config  *cfg.Config
+	Options map[string]any
+	// Rand is the generator of the run; components draw from it rather than the package generator
+	Rand *rng.Rand
+	// Logger is the logger of the run; components log through it rather than the global logger
+	Logger *zap.Logger
+}
+
+// Decode decodes the component's options into v. Unknown keys are an error so
+// typos in a sub-section are not silently ignored.
+func (c *Context) Decode(v any) error {
+	if len(c.Options) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(c.Options); err != nil {
+		return fmt.Errorf("failed to encode options: %w", err)
+	}
+	md, err := toml.Decode(buf.String(), v)
+	if err != nil {
+		return fmt.Errorf("failed to decode options: %w", err)
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, len(undecoded))
+		for i, key := range undecoded {
+			keys[i] = key.String()
+		}
+		sort.Strings(keys)
+		return fmt.Errorf("unknown options: %s", strings.Join(keys, ", "))
+	}
+	return nil
+}
+
+// Genome describes how to create and vary one representation
+type Genome struct {
+	// Type selects the population layout; anything but ActionTreeGenome uses a generic population
+	Type individual.GenomeType
+	// New creates a random individual, drawing from r
+	New                  func(r *rng.Rand) individual.Evolvable
+	CrossoverInformation individual.CrossoverInformation
+	MutateInformation    individual.MutateInformation
+}
+
+// GenomeBuilder creates a genome description from config
+type GenomeBuilder func(ctx *Context) (*Genome, error)
+
+// FitnessBuilder creates a fitness calculator for an already built population
+type FitnessBuilder func(ctx *Context, genome *Genome, pop population.Population) (fitness.FitnessCalculator, error)
+
+// SelectorBuilder creates a parent selector
+type SelectorBuilder func(ctx *Context) (selection.Selector, error)
+
+// MutationBuilder creates a mutation operator
+type MutationBuilder func(ctx *Context) (evolution.MutationOperator, error)
+
+// CrossoverBuilder creates a crossover operator
+type CrossoverBuilder func(ctx *Context) (evolution.CrossoverOperator, error)
+
+var (
+	Genomes    = NewRegistry[GenomeBuilder]("genome")
+	Fitnesses  = NewRegistry[FitnessBuilder]("fitness")
+	Selectors  = NewRegistry[SelectorBuilder]("selector")
+	Mutations  = NewRegistry[MutationBuilder]("mutation operator")
+	Crossovers = NewRegistry[CrossoverBuilder]("crossover operator")
+)
+
+// RegisterGenome registers a genome type selectable with [genome] type = name
+func RegisterGenome(name string, builder GenomeBuilder) { Genomes.Register(name, builder) }
+
+// RegisterFitness registers a fitness calculator selectable with [fitness] type = name
+func RegisterFitness(name string, builder FitnessBuilder) { Fitnesses.Register(name, builder) }
+
+// RegisterSelector registers a selector selectable with [evolution] selection_type = name
+func RegisterSelector(name string, builder SelectorBuilder) { Selectors.Register(name, builder) }
+
+// RegisterMutation registers a mutation operator selectable with [operators.mutation] type = name
+func RegisterMutation(name string, builder MutationBuilder) { Mutations.Register(name, builder) }
+
+// RegisterCrossover registers a crossover operator selectable with [operators.crossover] type = name
+func RegisterCrossover(name string, builder CrossoverBuilder) { Crossovers.Register(name, builder) }
+
+// Components are the resolved building blocks of one run
+type Components struct {
+	Genome     *Genome
+	Population population.Population
+	Fitness    fitness.FitnessCalculator
+	Selector   selection.Selector
+	Mutation   evolution.MutationOperator
+	Crossover  evolution.CrossoverOperator
+	Adaptation adaptive.Controller // nil for fixed rates
+}
+
+// Build looks up every configured component by name and builds it, drawing the initial
+// population and anything else random from r and logging to logger
+func Build(config *cfg.Config, r *rng.Rand, logger *zap.Logger) (*Components, error) {
+	genomeName := config.GenomeName()
+	if genomeName == "" {
+		return nil, fmt.Errorf("no genome selected, set [genome] type or enable an individual type")
+	}
+	genomeBuilder, err := Genomes.Lookup(genomeName)
+	if err != nil {
+		return nil, err
+	}
+	genome, err := genomeBuilder(&Context{Config: config, Options: config.Genome.Options, Rand: r, Logger: logger})
+	if err != nil {
+		return nil, fmt.Errorf("genome %s: %w", genomeName, err)
+	}
+
+	popInfo := population.NewPopulationInfo(config, genome.Type)
+	pop := population.NewPopulationBuilder().BuildPopulation(&popInfo, genome.New, r)
+
+	fitnessName := config.FitnessName()
+	fitnessBuilder, err := Fitnesses.Lookup(fitnessName)
+	if err != nil {
+		return nil, err
+	}
+	calculator, err := fitnessBuilder(&Context{Config: config, Options: config.Fitness.Options, Rand: r, Logger: logger}, genome, pop)
+	if err != nil {
+		return nil, fmt.Errorf("fitness %s: %w", fitnessName, err)
+	}
+
+	selectorBuilder, err := Selectors.Lookup(config.Evolution.SelectionType)
+	if err != nil {
+		return nil, err
+	}
+	selector, err := selectorBuilder(&Context{Config: config, Options: config.Evolution.SelectionOptions, Rand: r, Logger: logger})
+	if err != nil {
+		return nil, fmt.Errorf("selector %s: %w", config.Evolution.SelectionType, err)
+	}
+
+	mutationBuilder, err := Mutations.Lookup(config.Operators.Mutation.Type)
+	if err != nil {
+		return nil, err
+	}
+	mutation, err := mutationBuilder(&Context{Config: config, Options: config.Operators.Mutation.Options, Rand: r, Logger: logger})
+	if err != nil {
+		return nil, fmt.Errorf("mutation operator %s: %w", config.Operators.Mutation.Type, err)
+	}
+
+	crossoverBuilder, err := Crossovers.Lookup(config.Operators.Crossover.Type)
+	if err != nil {
+		return nil, err
+	}
+	crossover, err := crossoverBuilder(&Context{Config: config, Options: config.Operators.Crossover.Options, Rand: r, Logger: logger})
+	if err != nil {
+		return nil, fmt.Errorf("crossover operator %s: %w", config.Operators.Crossover.Type, err)
+	}
+
+	adaptationBuilder, err := Adaptations.Lookup(config.Operators.Adaptation.Type)
+	if err != nil {
+		return nil, err
+	}
+	adaptation, err := adaptationBuilder(&Context{Config: config, Options: config.Operators.Adaptation.Options, Rand: r, Logger: logger})
+	if err != nil {
+		return nil, fmt.Errorf("rate adaptation %s: %w", config.Operators.Adaptation.Type, err)
+	}
+
+	return &Components{
+		Genome:     genome,
+		Population: pop,
+		Fitness:    calculator,
+		Selector:   selector,
+		Mutation:   mutation,
+		Crossover:  crossover,
+		Adaptation: adaptation,
+	}, nil
+}
@@ -0,0 +1,195 @@
+package plugin_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/bxrne/darwin/internal/adaptive"
+	"github.com/bxrne/darwin/internal/cfg"
+	"github.com/bxrne/darwin/internal/fitness"
+	"github.com/bxrne/darwin/internal/individual"
+	"github.com/bxrne/darwin/internal/plugin"
+	"github.com/bxrne/darwin/internal/population"
+	"github.com/bxrne/darwin/internal/rng"
+	"github.com/bxrne/darwin/internal/selection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// counterOptions is the [genome.options] sub-section of the test genome
+type counterOptions struct {
+	Length int `toml:"length"`
+}
+
+// lengthFitness scores a bitstring by its length, proving the custom genome options reached the factory
+type lengthFitness struct{}
+
+func (lengthFitness) CalculateFitness(e individual.Evolvable) {
+	e.SetFitness(float64(len(e.(*individual.BinaryIndividual).Genome)))
+}
+
+func init() {
+	plugin.RegisterGenome("test_counter", func(ctx *plugin.Context) (*plugin.Genome, error) {
+		var opts counterOptions
+		if err := ctx.Decode(&opts); err != nil {
+			return nil, err
+		}
+		return &plugin.Genome{New: func(r *rng.Rand) individual.Evolvable { return individual.NewBinaryIndividual(r, opts.Length) }}, nil
+	})
+	plugin.RegisterFitness("test_counter", func(ctx *plugin.Context, genome *plugin.Genome, pop population.Population) (fitness.FitnessCalculator, error) {
+		return lengthFitness{}, nil
+	})
+}
+
+func loadBitstringConfig(t *testing.T) *cfg.Config {
+	config, err := cfg.LoadConfig("../../config/default.toml")
+	assert.NoError(t, err)
+	config.BitString.Enabled = true
+	config.ActionTree.Enabled = false
+	return config
+}
+
+func TestRegistry_Lookup_GIVEN_unknown_name_WHEN_lookup_THEN_error_lists_registered(t *testing.T) {
+	r := plugin.NewRegistry[int]("thing")
+	r.Register("b", 2)
+	r.Register("a", 1)
+
+	_, err := r.Lookup("c")
+
+	assert.ErrorContains(t, err, `unknown thing "c", registered: a, b`)
+}
+
+func TestRegistry_Register_GIVEN_duplicate_WHEN_register_THEN_panics(t *testing.T) {
+	r := plugin.NewRegistry[int]("thing")
+	r.Register("a", 1)
+
+	assert.Panics(t, func() { r.Register("a", 2) })
+}
+
+func TestContext_Decode_GIVEN_unknown_key_WHEN_decode_THEN_returns_error(t *testing.T) {
+	ctx := &plugin.Context{Options: map[string]any{"length": int64(4), "lenght": int64(5)}}
+	var opts counterOptions
+
+	err := ctx.Decode(&opts)
+
+	assert.ErrorContains(t, err, "lenght")
+}
+
+func TestBuild_GIVEN_legacy_bitstring_config_WHEN_build_THEN_uses_builtin_components(t *testing.T) {
+	config := loadBitstringConfig(t)
+
+	components, err := plugin.Build(config, rng.New(1), zap.NewNop())
+
+	assert.NoError(t, err)
+	assert.Equal(t, individual.BitStringGenome, components.Genome.Type)
+	assert.Equal(t, config.Evolution.PopulationSize, components.Population.Count())
+	assert.IsType(t, &fitness.BinaryFitnessCalculator{}, components.Fitness)
+	assert.IsType(t, &selection.TournamentSelector{}, components.Selector)
+	assert.NotNil(t, components.Mutation)
+	assert.NotNil(t, components.Crossover)
+}
+
+func TestBuild_GIVEN_registered_genome_type_WHEN_build_THEN_options_decoded_on_demand(t *testing.T) {
+	config := loadBitstringConfig(t)
+	config.Genome = cfg.ComponentConfig{Type: "test_counter", Options: map[string]any{"length": int64(7)}}
+
+	components, err := plugin.Build(config, rng.New(1), zap.NewNop())
+
+	assert.NoError(t, err)
+	ind := components.Population.Get(0)
+	components.Fitness.CalculateFitness(ind)
+	assert.Equal(t, 7.0, ind.GetFitness())
+}
+
+func TestBuild_GIVEN_unknown_selector_WHEN_build_THEN_returns_error(t *testing.T) {
+	config := loadBitstringConfig(t)
+	config.Evolution.SelectionType = "lottery"
+
+	_, err := plugin.Build(config, rng.New(1), zap.NewNop())
+
+	assert.ErrorContains(t, err, fmt.Sprintf(`unknown selector "lottery", registered: %s`, "roulette, tournament"))
+}
+
+func TestBuild_GIVEN_fixed_adaptation_WHEN_build_THEN_no_controller(t *testing.T) {
+	config := loadBitstringConfig(t)
+
+	components, err := plugin.Build(config, rng.New(1), zap.NewNop())
+
+	assert.NoError(t, err)
+	assert.Nil(t, components.Adaptation)
+}
+
+func TestBuild_GIVEN_one_fifth_adaptation_WHEN_build_THEN_bounds_decoded(t *testing.T) {
+	config := loadBitstringConfig(t)
+	config.Operators.Adaptation = cfg.ComponentConfig{Type: "one_fifth", Options: map[string]any{"factor": 2.0, "min_rate": 0.05, "max_rate": 0.3}}
+
+	components, err := plugin.Build(config, rng.New(1), zap.NewNop())
+
+	require.NoError(t, err)
+	require.IsType(t, &adaptive.OneFifth{}, components.Adaptation)
+	controller := components.Adaptation
+	controller.Rates(adaptive.Rates{Mutation: 0.01})
+	assert.Equal(t, 0.05, controller.Rates(adaptive.Rates{Mutation: 0.01}).Mutation, "clamped to min_rate")
+	controller.Rates(adaptive.Rates{Mutation: 0.2})
+	controller.Observe(adaptive.Outcome{Children: []adaptive.Child{{Improved: true}}})
+	assert.Equal(t, 0.3, controller.Rates(adaptive.Rates{Mutation: 0.2}).Mutation, "clamped to max_rate")
+}
+
+func TestBuild_GIVEN_bad_adaptation_options_WHEN_build_THEN_returns_error(t *testing.T) {
+	testCases := []struct {
+		name       string
+		adaptation cfg.ComponentConfig
+		want       string
+	}{
+		{"UnknownOption", cfg.ComponentConfig{Type: "self_adaptive", Options: map[string]any{"sigma": 0.1}}, "sigma"},
+		{"MissingTarget", cfg.ComponentConfig{Type: "diversity"}, "target"},
+		{"BanditOnBitstrings", cfg.ComponentConfig{Type: "bandit"}, "tree or action_tree"},
+		{"UnknownStrategy", cfg.ComponentConfig{Type: "annealing"}, `unknown rate adaptation "annealing"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := loadBitstringConfig(t)
+			config.Operators.Adaptation = tc.adaptation
+
+			_, err := plugin.Build(config, rng.New(1), zap.NewNop())
+
+			assert.ErrorContains(t, err, tc.want)
+		})
+	}
+}
+
+func TestBuild_GIVEN_tree_variation_settings_WHEN_build_THEN_passed_to_genome_and_adaptation(t *testing.T) {
+	config := loadBitstringConfig(t)
+	config.BitString.Enabled = false
+	config.Tree.Enabled = true
+	config.Tree.VariableSet = []string{"x", "y"} // the variables of fitness.target_function
+	config.Tree.MutationWeights = map[string]float64{"point": 1, "hoist": 1}
+	config.Tree.MutationMode = individual.TreeMutationPerIndividual
+	config.Tree.CrossoverType = individual.TreeCrossoverSizeFair
+	config.Tree.MaxSize = 63
+	config.Operators.Adaptation = cfg.ComponentConfig{Type: "bandit"}
+
+	components, err := plugin.Build(config, rng.New(1), zap.NewNop())
+
+	require.NoError(t, err)
+	assert.Equal(t, individual.TreeCrossoverSizeFair, components.Genome.CrossoverInformation.CrossoverType)
+	assert.Equal(t, 63, components.Genome.CrossoverInformation.MaxSize)
+	assert.Equal(t, 63, components.Genome.MutateInformation.MaxSize)
+	assert.Equal(t, config.Tree.MutationWeights, components.Genome.MutateInformation.MutationWeights)
+	assert.Equal(t, individual.TreeMutationPerIndividual, components.Genome.MutateInformation.MutationMode)
+	assert.ElementsMatch(t, []string{"mutation_p_point", "mutation_p_hoist"}, keys(components.Adaptation.(adaptive.Reporter).Report()))
+
+	config.Tree.MutationWeights = map[string]float64{"point": 1}
+	_, err = plugin.Build(config, rng.New(1), zap.NewNop())
+	assert.ErrorContains(t, err, "at least two mutation types")
+}
+
+func keys(m map[string]float64) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
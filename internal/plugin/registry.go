@@ -0,0 +1,58 @@
+package plugin
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Registry maps names to component builders of one kind
+type Registry[T any] struct {
+	mu      sync.RWMutex
+	kind    string
+	entries map[string]T
+}
+
+// NewRegistry creates an empty registry; kind is used in error messages
+func NewRegistry[T any](kind string) *Registry[T] {
+	return &Registry[T]{kind: kind, entries: make(map[string]T)}
+}
+
+// Register adds a builder under name. Registering an empty or duplicate name panics,
+// as it is a programming error in an init function.
+func (r *Registry[T]) Register(name string, builder T) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if name == "" {
+		panic(fmt.Sprintf("plugin: empty %s name", r.kind))
+	}
+	if _, exists := r.entries[name]; exists {
+		panic(fmt.Sprintf("plugin: %s %q registered twice", r.kind, name))
+	}
+	r.entries[name] = builder
+}
+
+// Lookup returns the builder registered under name
+func (r *Registry[T]) Lookup(name string) (T, error) {
+	r.mu.RLock()
+	builder, ok := r.entries[name]
+	r.mu.RUnlock()
+	if !ok {
+		var zero T
+		return zero, fmt.Errorf("unknown %s %q, registered: %s", r.kind, name, strings.Join(r.Names(), ", "))
+	}
+	return builder, nil
+}
+
+// Names returns the registered names in sorted order
+func (r *Registry[T]) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.entries))
+	for name := range r.entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,247 @@
+package population
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/bxrne/darwin/internal/fitness"
+	"github.com/bxrne/darwin/internal/individual"
+	"github.com/bxrne/darwin/internal/rng"
+)
+
+// Coevolving is implemented by populations made of several species. Each generation the engine
+// breeds every species listed by Breeding in turn, after Activate points Get, Count,
+// SetPopulation and GetPopulation at it.
+type Coevolving interface {
+	Breeding() []int
+	Activate(species int)
+}
+
+// CoevolutionConfig picks how two cooperating species are bred and scored
+type CoevolutionConfig struct {
+	// Mode is alternate, breeding one species at a time, or simultaneous, breeding both
+	Mode string
+	// SwitchStep is the number of generations between switches in alternate mode
+	SwitchStep int
+	// Collaborators picks the partners an individual is scored with: best, random or top
+	Collaborators string
+	// CollaboratorCount is the number of random partners
+	CollaboratorCount int
+	// CollaboratorPercentage is the share of the other species used as top partners
+	CollaboratorPercentage float64
+	// Credit combines the scores with each partner into fitness: max or mean
+	Credit string
+	// Active is the species bred first in alternate mode
+	Active int
+}
+
+// withDefaults fills the strategies left empty: alternate breeding, top collaborators and max credit
+func (cc CoevolutionConfig) withDefaults() CoevolutionConfig {
+	if cc.Mode == "" {
+		cc.Mode = "alternate"
+	}
+	if cc.Collaborators == "" {
+		cc.Collaborators = "top"
+	}
+	if cc.CollaboratorCount <= 0 {
+		cc.CollaboratorCount = 1
+	}
+	if cc.CollaboratorPercentage <= 0 {
+		cc.CollaboratorPercentage = 0.1
+	}
+	if cc.Credit == "" {
+		cc.Credit = "max"
+	}
+	return cc
+}
+
+// validate checks the strategy names
+func (cc *CoevolutionConfig) validate() error {
+	if cc.Mode != "alternate" && cc.Mode != "simultaneous" {
+		return fmt.Errorf("unknown co-evolution mode %q, must be alternate or simultaneous", cc.Mode)
+	}
+	if cc.Collaborators != "best" && cc.Collaborators != "random" && cc.Collaborators != "top" {
+		return fmt.Errorf("unknown collaborator selection %q, must be best, random or top", cc.Collaborators)
+	}
+	if cc.Credit != "max" && cc.Credit != "mean" {
+		return fmt.Errorf("unknown credit assignment %q, must be max or mean", cc.Credit)
+	}
+	if cc.Mode == "alternate" && cc.SwitchStep <= 0 {
+		return fmt.Errorf("switch step must be positive in alternate mode")
+	}
+	return nil
+}
+
+// CoevolutionPopulation evolves two species whose individuals are only scored in teams of one
+// member of each, in species order. It works for any pair of genome types.
+type CoevolutionPopulation struct {
+	species [2][]individual.Evolvable
+	config  CoevolutionConfig
+	active  int
+	rand    *rng.Rand
+}
+
+// NewCoevolutionPopulation creates the two species of the given sizes, drawing the individuals
+// and any random collaborators from r
+func NewCoevolutionPopulation(sizes [2]int, creators [2]func(*rng.Rand) individual.Evolvable, config CoevolutionConfig, r *rng.Rand) (*CoevolutionPopulation, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	cp := &CoevolutionPopulation{config: config, active: config.Active, rand: r}
+	for s := range cp.species {
+		cp.species[s] = make([]individual.Evolvable, sizes[s])
+		for i := range sizes[s] {
+			cp.species[s][i] = creators[s](r)
+		}
+	}
+	return cp, nil
+}
+
+// Breeding implements Coevolving
+func (cp *CoevolutionPopulation) Breeding() []int {
+	if cp.config.Mode == "simultaneous" {
+		return []int{0, 1}
+	}
+	return []int{cp.active}
+}
+
+// Activate implements Coevolving
+func (cp *CoevolutionPopulation) Activate(species int) {
+	cp.active = species
+}
+
+func (cp *CoevolutionPopulation) Get(index int) individual.Evolvable {
+	return cp.species[cp.active][index]
+}
+
+func (cp *CoevolutionPopulation) Count() int {
+	return len(cp.species[cp.active])
+}
+
+// Update switches the bred species every SwitchStep generations in alternate mode
+func (cp *CoevolutionPopulation) Update(generation int) {
+	if cp.config.Mode == "alternate" && generation%cp.config.SwitchStep == 0 {
+		cp.active = 1 - cp.active
+	}
+}
+
+func (cp *CoevolutionPopulation) SetPopulation(population []individual.Evolvable) {
+	cp.species[cp.active] = population
+}
+
+func (cp *CoevolutionPopulation) GetPopulation() []individual.Evolvable {
+	return cp.species[cp.active]
+}
+
+// GetPopulations returns both species, in team order
+func (cp *CoevolutionPopulation) GetPopulations() []*[]individual.Evolvable {
+	return []*[]individual.Evolvable{&cp.species[0], &cp.species[1]}
+}
+
+// CalculateFitnesses scores the bred species. A fitness.TeamFitnessCalculator scores each
+// individual with partners from the other species, combined by the credit assignment; any other
+// calculator scores individuals on their own.
+func (cp *CoevolutionPopulation) CalculateFitnesses(fitnessCalc fitness.FitnessCalculator) {
+	teamCalc, isTeam := fitnessCalc.(fitness.TeamFitnessCalculator)
+	workers := runtime.NumCPU()
+	if parallelCalc, ok := fitnessCalc.(fitness.ParallelFitnessCalculator); ok {
+		workers = max(workers, parallelCalc.Parallelism())
+	}
+	for _, s := range cp.Breeding() {
+		individuals := cp.species[s]
+		if !isTeam {
+			parallel(len(individuals), workers, func(i int) {
+				fitnessCalc.CalculateFitness(individuals[i])
+			})
+			continue
+		}
+
+		shared := cp.collaborators(1 - s)
+		// Random partners are drawn up front so a seed gives every individual the same ones
+		var drawn [][]individual.Evolvable
+		if cp.config.Collaborators == "random" {
+			drawn = make([][]individual.Evolvable, len(individuals))
+			for i := range drawn {
+				drawn[i] = cp.randomCollaborators(1 - s)
+			}
+		}
+		parallel(len(individuals), workers, func(i int) {
+			partners := shared
+			if drawn != nil {
+				partners = drawn[i]
+			}
+			scores := make([]float64, len(partners))
+			for j, partner := range partners {
+				team := make([]individual.Evolvable, 2)
+				team[s], team[1-s] = individuals[i], partner
+				scores[j] = teamCalc.CalculateTeamFitness(team, s)
+			}
+			individuals[i].SetFitness(cp.credit(scores))
+		})
+	}
+}
+
+// collaborators returns the partners shared by every individual: the best or the top share of a species
+func (cp *CoevolutionPopulation) collaborators(species int) []individual.Evolvable {
+	ranked := append([]individual.Evolvable(nil), cp.species[species]...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].GetFitness() > ranked[j].GetFitness()
+	})
+	count := 1
+	if cp.config.Collaborators == "top" {
+		count = int(float64(len(ranked))*cp.config.CollaboratorPercentage + 0.5)
+	}
+	return ranked[:min(max(count, 1), len(ranked))]
+}
+
+// randomCollaborators draws distinct partners from a species
+func (cp *CoevolutionPopulation) randomCollaborators(species int) []individual.Evolvable {
+	pool := append([]individual.Evolvable(nil), cp.species[species]...)
+	count := min(max(cp.config.CollaboratorCount, 1), len(pool))
+	for i := range count {
+		j := i + cp.rand.Intn(len(pool)-i)
+		pool[i], pool[j] = pool[j], pool[i]
+	}
+	return pool[:count]
+}
+
+// credit combines an individual's team scores into its fitness
+func (cp *CoevolutionPopulation) credit(scores []float64) float64 {
+	if len(scores) == 0 {
+		return 0.0
+	}
+	if cp.config.Credit == "mean" {
+		sum := 0.0
+		for _, score := range scores {
+			sum += score
+		}
+		return sum / float64(len(scores))
+	}
+	best := scores[0]
+	for _, score := range scores[1:] {
+		best = max(best, score)
+	}
+	return best
+}
+
+// parallel runs fn for every index on up to workers goroutines, each taking a contiguous chunk
+func parallel(n int, workers int, fn func(i int)) {
+	if n == 0 {
+		return
+	}
+	var wg sync.WaitGroup
+	chunkSize := (n + max(workers, 1) - 1) / max(workers, 1)
+	for start := 0; start < n; start += chunkSize {
+		end := min(start+chunkSize, n)
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				fn(i)
+			}
+		}(start, end)
+	}
+	wg.Wait()
+}
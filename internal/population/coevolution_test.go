@@ -0,0 +1,125 @@
+package population_test
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/bxrne/darwin/internal/individual"
+	"github.com/bxrne/darwin/internal/population"
+	"github.com/bxrne/darwin/internal/rng"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockTeamCalc scores a team by the fitness of the subject's partner, counting the teams played
+type mockTeamCalc struct {
+	teams atomic.Int64
+}
+
+func (f *mockTeamCalc) CalculateFitness(e individual.Evolvable) {
+	e.SetFitness(-1.0)
+}
+
+func (f *mockTeamCalc) CalculateTeamFitness(team []individual.Evolvable, subject int) float64 {
+	f.teams.Add(1)
+	return team[1-subject].GetFitness()
+}
+
+// newCoevolution pairs 4 bitstrings with 5 trees whose fitnesses are 1 to 5
+func newCoevolution(t *testing.T, config population.CoevolutionConfig) *population.CoevolutionPopulation {
+	pop, err := population.NewCoevolutionPopulation([2]int{4, 5}, [2]func(*rng.Rand) individual.Evolvable{
+		func(r *rng.Rand) individual.Evolvable { return individual.NewBinaryIndividual(r, 8) },
+		func(r *rng.Rand) individual.Evolvable {
+			return individual.NewRandomTree(r, 2, []string{"+"}, []string{"x"}, []string{"1"})
+		},
+	}, config, rng.New(1))
+	assert.NoError(t, err)
+
+	pop.Activate(1)
+	for i, tree := range pop.GetPopulation() {
+		tree.SetFitness(float64(i + 1))
+	}
+	pop.Activate(config.Active)
+	return pop
+}
+
+func TestCoevolutionPopulation_GIVEN_collaborator_strategies_WHEN_scored_THEN_partners_and_credit_apply(t *testing.T) {
+	testCases := []struct {
+		name          string
+		collaborators string
+		count         int
+		percentage    float64
+		credit        string
+		teams         int64
+		fitness       float64
+	}{
+		{"BestMax", "best", 0, 0, "max", 4, 5},
+		{"TopMean", "top", 0, 0.4, "mean", 8, 4.5},
+		{"RandomMax", "random", 5, 0, "max", 20, 5},
+		{"RandomMean", "random", 5, 0, "mean", 20, 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pop := newCoevolution(t, population.CoevolutionConfig{
+				Mode:                   "alternate",
+				SwitchStep:             1,
+				Collaborators:          tc.collaborators,
+				CollaboratorCount:      tc.count,
+				CollaboratorPercentage: tc.percentage,
+				Credit:                 tc.credit,
+			})
+			calc := &mockTeamCalc{}
+
+			pop.CalculateFitnesses(calc)
+
+			assert.Equal(t, tc.teams, calc.teams.Load())
+			for _, ind := range pop.GetPopulation() {
+				assert.Equal(t, tc.fitness, ind.GetFitness())
+			}
+		})
+	}
+}
+
+func TestCoevolutionPopulation_GIVEN_simultaneous_mode_WHEN_scored_THEN_both_species_are_bred_and_scored(t *testing.T) {
+	pop := newCoevolution(t, population.CoevolutionConfig{Mode: "simultaneous", Collaborators: "best", Credit: "max"})
+	calc := &mockTeamCalc{}
+
+	pop.CalculateFitnesses(calc)
+
+	assert.Equal(t, []int{0, 1}, pop.Breeding())
+	assert.Equal(t, int64(9), calc.teams.Load())
+	populations := pop.GetPopulations()
+	assert.Len(t, *populations[0], 4)
+	assert.Len(t, *populations[1], 5)
+}
+
+func TestCoevolutionPopulation_GIVEN_alternate_mode_WHEN_updated_THEN_switches_every_step(t *testing.T) {
+	pop := newCoevolution(t, population.CoevolutionConfig{Mode: "alternate", SwitchStep: 2, Collaborators: "best", Credit: "max", Active: 1})
+
+	assert.Equal(t, []int{1}, pop.Breeding())
+	assert.Equal(t, 5, pop.Count())
+	pop.Update(1)
+	assert.Equal(t, []int{1}, pop.Breeding())
+	pop.Update(2)
+	assert.Equal(t, []int{0}, pop.Breeding())
+	assert.Equal(t, 4, pop.Count())
+}
+
+func TestCoevolutionPopulation_GIVEN_single_calculator_WHEN_scored_THEN_individuals_are_scored_alone(t *testing.T) {
+	pop := newCoevolution(t, population.CoevolutionConfig{Mode: "alternate", SwitchStep: 1, Collaborators: "best", Credit: "max"})
+
+	pop.CalculateFitnesses(&mockFitnessCalc{})
+
+	for _, ind := range pop.GetPopulation() {
+		assert.Equal(t, 42.0, ind.GetFitness())
+	}
+}
+
+func TestNewCoevolutionPopulation_GIVEN_unknown_strategy_WHEN_created_THEN_returns_error(t *testing.T) {
+	_, err := population.NewCoevolutionPopulation([2]int{1, 1}, [2]func(*rng.Rand) individual.Evolvable{
+		func(r *rng.Rand) individual.Evolvable { return individual.NewBinaryIndividual(r, 8) },
+		func(r *rng.Rand) individual.Evolvable { return individual.NewBinaryIndividual(r, 8) },
+	}, population.CoevolutionConfig{Mode: "alternate", SwitchStep: 1, Collaborators: "worst", Credit: "max"}, nil)
+
+	assert.ErrorContains(t, err, "collaborator selection")
+}
@@ -3,6 +3,7 @@ package population
 import (
 	"github.com/bxrne/darwin/internal/fitness"
 	"github.com/bxrne/darwin/internal/individual"
+	"github.com/bxrne/darwin/internal/rng"
 )
 
 type GenericPopulation struct {
@@ -10,9 +11,14 @@ type GenericPopulation struct {
 	count      int
 }
 
-func newGenericPopulation(populationCount int) *GenericPopulation {
-	return &GenericPopulation{
-		count: populationCount}
+// NewGenericPopulation creates a single population of size individuals drawn from creator with r,
+// for a genome of any type
+func NewGenericPopulation(size int, creator func(*rng.Rand) individual.Evolvable, r *rng.Rand) *GenericPopulation {
+	population := make([]individual.Evolvable, size)
+	for i := range population {
+		population[i] = creator(r)
+	}
+	return &GenericPopulation{population: population, count: size}
 }
 
 func (gp *GenericPopulation) Get(index int) individual.Evolvable {
@@ -38,7 +44,15 @@ func (gp *GenericPopulation) GetPopulations() []*[]individual.Evolvable {
 	return nil
 }
 
+// CalculateFitnesses scores individuals one at a time, or concurrently for a
+// fitness.ParallelFitnessCalculator
 func (gp *GenericPopulation) CalculateFitnesses(fitnessCalc fitness.FitnessCalculator) {
+	if parallelCalc, ok := fitnessCalc.(fitness.ParallelFitnessCalculator); ok {
+		parallel(len(gp.population), parallelCalc.Parallelism(), func(i int) {
+			fitnessCalc.CalculateFitness(gp.population[i])
+		})
+		return
+	}
 	for _, ind := range gp.population {
 		fitnessCalc.CalculateFitness(ind)
 	}
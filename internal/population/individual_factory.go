@@ -5,6 +5,7 @@ import (
 
 	"github.com/bxrne/darwin/internal/cfg"
 	"github.com/bxrne/darwin/internal/individual"
+	"github.com/bxrne/darwin/internal/rng"
 )
 
 // IndividualFactory creates individuals based on genome type
@@ -20,17 +21,17 @@ func NewIndividualFactory(config *cfg.Config) *IndividualFactory {
 	}
 }
 
-// CreateIndividual creates an individual of the specified type
-func (f *IndividualFactory) CreateIndividual(populationType individual.GenomeType) individual.Evolvable {
+// CreateIndividual creates an individual of the specified type, drawing from r
+func (f *IndividualFactory) CreateIndividual(populationType individual.GenomeType, r *rng.Rand) individual.Evolvable {
 	switch populationType {
 	case individual.BitStringGenome:
-		return individual.NewBinaryIndividual(f.config.BitString.GenomeSize)
+		return individual.NewBinaryIndividual(r, f.config.BitString.GenomeSize)
 	case individual.TreeGenome:
-		return f.createRampedHalfAndHalfTree()
+		return f.createRampedHalfAndHalfTree(r)
 	case individual.GrammarTreeGenome:
-		return individual.NewGrammarTree(f.config.GrammarTree.GenomeSize)
+		return individual.NewGrammarTree(r, f.config.GrammarTree.GenomeSize)
 	case individual.ActionTreeGenome:
-		return f.createActionTreeIndividual()
+		return f.createActionTreeIndividual(r)
 	default:
 		fmt.Printf("Unknown genome type: %v\n", populationType)
 		return nil
@@ -38,7 +39,7 @@ func (f *IndividualFactory) CreateIndividual(populationType individual.GenomeTyp
 }
 
 // createRampedHalfAndHalfTree creates a tree using ramped half-and-half initialization
-func (f *IndividualFactory) createRampedHalfAndHalfTree() *individual.Tree {
+func (f *IndividualFactory) createRampedHalfAndHalfTree(r *rng.Rand) *individual.Tree {
 	popSize := f.config.Evolution.PopulationSize
 	index := f.getNextTreeCounter()
 	initialDepth := f.config.Tree.InitalDepth
@@ -84,11 +85,11 @@ func (f *IndividualFactory) createRampedHalfAndHalfTree() *individual.Tree {
 	localIndex := index - groupStart
 	useGrow := localIndex < groupCount/2
 
-	return individual.NewRampedHalfAndHalfTree(depth, useGrow, f.config.Tree.OperandSet, f.config.Tree.VariableSet, f.config.Tree.TerminalSet)
+	return individual.NewRampedHalfAndHalfTree(r, depth, useGrow, f.config.Tree.OperandSet, f.config.Tree.VariableSet, f.config.Tree.TerminalSet)
 }
 
 // createActionTreeIndividual creates an action tree individual with trees for each action
-func (f *IndividualFactory) createActionTreeIndividual() *individual.ActionTreeIndividual {
+func (f *IndividualFactory) createActionTreeIndividual(r *rng.Rand) *individual.ActionTreeIndividual {
 	// Create random trees for each action using ramped half-and-half
 	// All trees in an individual use the same depth and method
 	// Depth 0 is disallowed, so we distribute from depth 1 to initialDepth
@@ -142,7 +143,7 @@ func (f *IndividualFactory) createActionTreeIndividual() *individual.ActionTreeI
 	}
 	variableSet = append(variableSet, f.config.Tree.VariableSet...)
 	for _, action := range f.config.ActionTree.Actions {
-		tree := individual.NewRampedHalfAndHalfTree(depth, useGrow, f.config.Tree.OperandSet, variableSet, f.config.Tree.TerminalSet)
+		tree := individual.NewRampedHalfAndHalfTree(r, depth, useGrow, f.config.Tree.OperandSet, variableSet, f.config.Tree.TerminalSet)
 		initialTrees[action.Name] = tree
 	}
 	result := individual.NewActionTreeIndividual(f.config.ActionTree.Actions, initialTrees)
@@ -1,12 +1,10 @@
 package population
 
 import (
-	"runtime"
-	"sync"
-
 	"github.com/bxrne/darwin/internal/cfg"
 	"github.com/bxrne/darwin/internal/fitness"
 	"github.com/bxrne/darwin/internal/individual"
+	"github.com/bxrne/darwin/internal/rng"
 )
 
 type Population interface {
@@ -27,6 +25,7 @@ type PopulationInfo struct {
 	trainWeightsFirst    bool
 	GenomeType           individual.GenomeType
 	SwitchPopulationStep int
+	coevolution          CoevolutionConfig
 }
 
 func NewPopulationInfo(config *cfg.Config, genomeType individual.GenomeType) PopulationInfo {
@@ -34,8 +33,9 @@ func NewPopulationInfo(config *cfg.Config, genomeType individual.GenomeType) Pop
 	var weightsCount, numColumns int
 	var trainWeightsFirst bool
 	var switchStep int
+	var coevolution CoevolutionConfig
 
-	if config.ActionTree.Enabled {
+	if genomeType == individual.ActionTreeGenome {
 		maxValue = config.ActionTree.Actions[0].Value // start with first value
 		for _, a := range config.ActionTree.Actions[1:] {
 			if a.Value > maxValue {
@@ -46,6 +46,13 @@ func NewPopulationInfo(config *cfg.Config, genomeType individual.GenomeType) Pop
 		numColumns = config.ActionTree.WeightsColumnCount
 		trainWeightsFirst = config.ActionTree.TrainWeightsFirst
 		switchStep = config.ActionTree.SwitchTrainingTargetStep
+		coevolution = CoevolutionConfig{
+			Mode:                   config.ActionTree.Coevolution.Mode,
+			Collaborators:          config.ActionTree.Coevolution.Collaborators,
+			CollaboratorCount:      config.ActionTree.Coevolution.CollaboratorCount,
+			CollaboratorPercentage: config.ActionTree.FitnessSelectionPercentage,
+			Credit:                 config.ActionTree.Coevolution.Credit,
+		}
 	} else {
 		// Default values for non-ActionTree individual types
 		maxValue = 0
@@ -63,6 +70,7 @@ func NewPopulationInfo(config *cfg.Config, genomeType individual.GenomeType) Pop
 		SwitchPopulationStep: switchStep,
 		maxNumInputs:         maxValue,
 		GenomeType:           genomeType,
+		coevolution:          coevolution,
 	}
 }
 
@@ -74,34 +82,28 @@ func NewPopulationBuilder() *PopulationBuilder {
 	return &PopulationBuilder{}
 }
 
-// BuildPopulation creates a population of binary individuals
-func (pb *PopulationBuilder) BuildPopulation(popInfo *PopulationInfo, creator func() individual.Evolvable) Population {
-	var wg sync.WaitGroup
-	numWorkers := runtime.NumCPU()
-
-	chunkSize := (popInfo.Size + numWorkers - 1) / numWorkers
+// BuildPopulation creates a population with creator, drawing everything from r. Individuals are
+// created one after another so a seed always gives the same population.
+func (pb *PopulationBuilder) BuildPopulation(popInfo *PopulationInfo, creator func(*rng.Rand) individual.Evolvable, r *rng.Rand) Population {
 	switch popInfo.GenomeType {
 	case individual.ActionTreeGenome:
-		return NewActionTreeAndWeightsPopulation(popInfo, creator)
-	default:
-		population := make([]individual.Evolvable, popInfo.Size)
-		for i := range numWorkers {
-			start := i * chunkSize
-			end := start + chunkSize
-			end = min(end, popInfo.Size)
-
-			wg.Add(1)
-			go func(start, end int) {
-				defer wg.Done()
-				for j := start; j < end; j++ {
-					population[j] = creator()
-				}
-			}(start, end) // chunk to use
+		// Weights come first in each team, trees second
+		config := popInfo.coevolution.withDefaults()
+		config.SwitchStep = max(popInfo.SwitchPopulationStep, 1)
+		config.Active = 1
+		if popInfo.trainWeightsFirst {
+			config.Active = 0
 		}
-
-		wg.Wait()
-		newPop := newGenericPopulation(popInfo.Size)
-		newPop.SetPopulation(population)
-		return newPop
+		newWeights := func(r *rng.Rand) individual.Evolvable {
+			return individual.NewWeightsIndividual(r, popInfo.maxNumInputs, popInfo.numColumns)
+		}
+		pop, err := NewCoevolutionPopulation([2]int{popInfo.weightsCount, popInfo.Size}, [2]func(*rng.Rand) individual.Evolvable{newWeights, creator}, config, r)
+		if err != nil {
+			// The config has already been validated
+			panic(err)
+		}
+		return pop
+	default:
+		return NewGenericPopulation(popInfo.Size, creator, r)
 	}
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/bxrne/darwin/internal/individual"
 	"github.com/bxrne/darwin/internal/population"
+	"github.com/bxrne/darwin/internal/rng"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -19,7 +20,7 @@ func (f *mockFitnessCalc) CalculateFitness(e individual.Evolvable) {
 	(e).SetFitness(42.0)
 }
 
-func (f *mockFitnessCalc) SetupEvalFunction(expr string, vars []string, c int) {}
+func (f *mockFitnessCalc) SetupEvalFunction(expr string, vars []string, c int, r *rng.Rand) {}
 
 // --- PARAMETERIZED TEST --------------------------------------------------------
 
@@ -28,41 +29,41 @@ func TestPopulationBuilder_BuildPopulation_GIVEN_various_genome_types_WHEN_build
 		name       string
 		size       int
 		genomeType individual.GenomeType
-		initFunc   func() individual.Evolvable
+		initFunc   func(*rng.Rand) individual.Evolvable
 	}{
 		{
 			name:       "GenericGenome small",
 			size:       5,
 			genomeType: individual.BitStringGenome,
-			initFunc:   func() individual.Evolvable { return individual.NewBinaryIndividual(5) },
+			initFunc:   func(r *rng.Rand) individual.Evolvable { return individual.NewBinaryIndividual(r, 5) },
 		},
 		{
 			name:       "GenericGenome large",
 			size:       200,
 			genomeType: individual.TreeGenome,
-			initFunc: func() individual.Evolvable {
-				return individual.NewRandomTree(5, []string{"+", "-", "*"}, []string{"a"}, []string{"1.0"})
+			initFunc: func(r *rng.Rand) individual.Evolvable {
+				return individual.NewRandomTree(r, 5, []string{"+", "-", "*"}, []string{"a"}, []string{"1.0"})
 			},
 		},
 		{
 			name:       "ActionTree genome type triggers ActionTreePopulation",
 			size:       10,
 			genomeType: individual.ActionTreeGenome,
-			initFunc: func() individual.Evolvable {
+			initFunc: func(r *rng.Rand) individual.Evolvable {
 				return individual.NewActionTreeIndividual(
 					[]individual.ActionTuple{{Name: "move", Value: 2}, {Name: "jump", Value: 3}, {Name: "turn", Value: 4}}, // actions
 					map[string]*individual.Tree{ // initialTrees
-						"move": individual.NewRandomTree(3,
+						"move": individual.NewRandomTree(r, 3,
 							[]string{"+", "-", "*"},
 							[]string{"x", "y"},
 							[]string{"1", "2"},
 						),
-						"jump": individual.NewRandomTree(3,
+						"jump": individual.NewRandomTree(r, 3,
 							[]string{"+", "*"},
 							[]string{"x"},
 							[]string{"1"},
 						),
-						"turn": individual.NewRandomTree(3,
+						"turn": individual.NewRandomTree(r, 3,
 							[]string{"-"},
 							[]string{"x"},
 							[]string{"1"},
@@ -82,7 +83,7 @@ func TestPopulationBuilder_BuildPopulation_GIVEN_various_genome_types_WHEN_build
 
 			fit := &mockFitnessCalc{}
 			popInfo := &population.PopulationInfo{Size: tt.size, GenomeType: tt.genomeType}
-			pop := pb.BuildPopulation(popInfo, tt.initFunc)
+			pop := pb.BuildPopulation(popInfo, tt.initFunc, rng.New(1))
 			pop.CalculateFitnesses(fit)
 			// -- Validate population exists --
 			assert.NotNil(t, pop, "Returned population should not be nil")
@@ -91,8 +92,8 @@ func TestPopulationBuilder_BuildPopulation_GIVEN_various_genome_types_WHEN_build
 
 			// -- Validate type correctness --
 			if tt.genomeType == individual.ActionTreeGenome {
-				_, ok := pop.(*population.ActionTreeAndWeightsPopulation)
-				assert.True(t, ok, "Expected ActionTreeAndWeightsPopulation for ActionTreeGenome")
+				_, ok := pop.(*population.CoevolutionPopulation)
+				assert.True(t, ok, "Expected CoevolutionPopulation for ActionTreeGenome")
 				return // Other checks don't apply to action-tree path
 			} else {
 				_, ok := pop.(*population.GenericPopulation)
@@ -111,3 +112,22 @@ func TestPopulationBuilder_BuildPopulation_GIVEN_various_genome_types_WHEN_build
 		})
 	}
 }
+
+func TestPopulationBuilder_BuildPopulation_GIVEN_same_seed_WHEN_built_twice_THEN_same_population(t *testing.T) {
+	newTree := func(r *rng.Rand) individual.Evolvable {
+		return individual.NewRandomTree(r, 4, []string{"+", "-", "*"}, []string{"x"}, []string{"1"})
+	}
+	popInfo := &population.PopulationInfo{Size: 50, GenomeType: individual.TreeGenome}
+	describe := func(pop population.Population) []string {
+		descriptions := make([]string, pop.Count())
+		for i, ind := range pop.GetPopulation() {
+			descriptions[i] = ind.Describe()
+		}
+		return descriptions
+	}
+
+	first := population.NewPopulationBuilder().BuildPopulation(popInfo, newTree, rng.New(3))
+	second := population.NewPopulationBuilder().BuildPopulation(popInfo, newTree, rng.New(3))
+
+	assert.Equal(t, describe(first), describe(second))
+}
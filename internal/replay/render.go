@@ -0,0 +1,76 @@
+package replay
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Render draws step index of a replay as text: the board, the observed features and, for each
+// action head, the softmax over its values with masked values dashed out and the choice marked
+func Render(w io.Writer, r *Replay, index int) error {
+	if index < 0 || index >= len(r.Steps) {
+		return fmt.Errorf("step %d outside replay of %d steps", index, len(r.Steps))
+	}
+	step := r.Steps[index]
+	total := 0.0
+	for _, s := range r.Steps[:index+1] {
+		total += s.Reward
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s  step %d/%d  reward %.2f  cumulative %.2f", r.Game, index+1, len(r.Steps), step.Reward, total)
+	switch {
+	case step.Terminated:
+		b.WriteString("  [terminated]")
+	case step.Truncated:
+		b.WriteString("  [truncated]")
+	}
+	b.WriteString("\n\n")
+
+	for _, row := range step.Board {
+		fmt.Fprintf(&b, "  %s\n", row)
+	}
+	if len(step.Board) > 0 {
+		b.WriteString("\n")
+	}
+
+	names := make([]string, 0, len(step.Features))
+	for name := range step.Features {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	b.WriteString("Features:\n")
+	for i, name := range names {
+		fmt.Fprintf(&b, "  %-24s %10.3f", name, step.Features[name])
+		if i%2 == 1 || i == len(names)-1 {
+			b.WriteString("\n")
+		}
+	}
+
+	if len(step.Heads) > 0 {
+		b.WriteString("\nDecision:\n")
+	}
+	for _, head := range step.Heads {
+		fmt.Fprintf(&b, "  %-12s ->%3d |", head.Name, head.Chosen)
+		for value, p := range head.Probabilities {
+			marker := " "
+			if value == head.Chosen {
+				marker = "*"
+			}
+			if value < len(head.Mask) && head.Mask[value] == 0 {
+				fmt.Fprintf(&b, " %s  -  ", marker)
+				continue
+			}
+			fmt.Fprintf(&b, " %s%.2f ", marker, p)
+		}
+		b.WriteString("\n")
+	}
+	if len(step.Action) > 0 {
+		fmt.Fprintf(&b, "\nAction: %v\n", step.Action)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,135 @@
+// Package replay records what an action-tree agent saw and decided in each step of a game and
+// reads the recordings back. A replay is JSONL: a header line followed by one line per step,
+// gzipped when the file name ends in .gz.
+package replay
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Head is one action head's decision: the tree output for each value, their softmax, the mask
+// the head's mask provider applied and the value chosen
+type Head struct {
+	Name          string    `json:"name"`
+	Outputs       []float64 `json:"outputs"`
+	Probabilities []float64 `json:"probabilities"`
+	Mask          []float64 `json:"mask,omitempty"`
+	Chosen        int       `json:"chosen"`
+}
+
+// Step is an observation and the action the agent chose in response. The final step of a game
+// has no action.
+type Step struct {
+	Step       int                `json:"step"`
+	Features   map[string]float64 `json:"features"`
+	Board      []string           `json:"board,omitempty"`
+	Reward     float64            `json:"reward"`
+	Terminated bool               `json:"terminated,omitempty"`
+	Truncated  bool               `json:"truncated,omitempty"`
+	Heads      []Head             `json:"heads,omitempty"`
+	Action     []int              `json:"action,omitempty"`
+}
+
+// Header describes the game and the agent that played it
+type Header struct {
+	Game        string            `json:"game"`
+	Started     time.Time         `json:"started"`
+	Actions     []string          `json:"actions"`
+	Trees       map[string]string `json:"trees"`
+	TotalReward float64           `json:"total_reward"`
+	Steps       int               `json:"steps"`
+}
+
+// Replay is a recorded game
+type Replay struct {
+	Header
+	Steps []Step
+}
+
+// New starts an empty replay of a game with the given action heads and their trees
+func New(game string, actions []string, trees map[string]string) *Replay {
+	return &Replay{Header: Header{Game: game, Started: time.Now(), Actions: actions, Trees: trees}}
+}
+
+// Add appends a step
+func (r *Replay) Add(step Step) {
+	r.Steps = append(r.Steps, step)
+}
+
+// Write saves the replay to path, gzipped if path ends in .gz
+func Write(path string, r *Replay) (err error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create replay: %w", err)
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	var w io.Writer = file
+	if strings.HasSuffix(path, ".gz") {
+		gz := gzip.NewWriter(file)
+		defer func() {
+			if closeErr := gz.Close(); err == nil {
+				err = closeErr
+			}
+		}()
+		w = gz
+	}
+	buffered := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buffered)
+	header := r.Header
+	header.Steps = len(r.Steps)
+	if err := encoder.Encode(header); err != nil {
+		return fmt.Errorf("failed to write replay header: %w", err)
+	}
+	for _, step := range r.Steps {
+		if err := encoder.Encode(step); err != nil {
+			return fmt.Errorf("failed to write replay step %d: %w", step.Step, err)
+		}
+	}
+	return buffered.Flush()
+}
+
+// Read loads a replay written by Write
+func Read(path string) (*Replay, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open replay: %w", err)
+	}
+	defer file.Close()
+
+	var rd io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress replay: %w", err)
+		}
+		defer gz.Close()
+		rd = gz
+	}
+	decoder := json.NewDecoder(rd)
+	r := &Replay{}
+	if err := decoder.Decode(&r.Header); err != nil {
+		return nil, fmt.Errorf("failed to read replay header: %w", err)
+	}
+	for {
+		var step Step
+		if err := decoder.Decode(&step); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read replay step %d: %w", len(r.Steps), err)
+		}
+		r.Steps = append(r.Steps, step)
+	}
+	return r, nil
+}
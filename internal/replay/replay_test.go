@@ -0,0 +1,79 @@
+package replay_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bxrne/darwin/internal/replay"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleReplay() *replay.Replay {
+	r := replay.New("client_1", []string{"pass", "direction"}, map[string]string{"pass": "(x + 1)", "direction": "x"})
+	r.Add(replay.Step{Step: 0, Features: map[string]float64{"x": 1}, Board: []string{"A.", ".G"}, Action: []int{0, 0}})
+	r.Add(replay.Step{
+		Step:     1,
+		Features: map[string]float64{"x": 2},
+		Board:    []string{".A", ".G"},
+		Reward:   -0.1,
+		Heads: []replay.Head{
+			{Name: "pass", Outputs: []float64{1, 0}, Probabilities: []float64{0.73, 0.27}, Chosen: 0},
+			{Name: "direction", Outputs: []float64{3, 2, 1, 0}, Probabilities: []float64{0.64, 0.24, 0.09, 0.03}, Mask: []float64{0, 1, 1, 0}, Chosen: 1},
+		},
+		Action: []int{0, 1},
+	})
+	r.Add(replay.Step{Step: 2, Features: map[string]float64{"x": 3}, Reward: 10, Terminated: true})
+	r.TotalReward = 9.9
+	return r
+}
+
+func TestWrite_GIVEN_replay_WHEN_read_back_THEN_round_trips(t *testing.T) {
+	for _, name := range []string{"game.jsonl", "game.jsonl.gz"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			written := sampleReplay()
+
+			require.NoError(t, replay.Write(path, written))
+			read, err := replay.Read(path)
+
+			require.NoError(t, err)
+			assert.Equal(t, written.Steps, read.Steps)
+			assert.Equal(t, written.Trees, read.Trees)
+			assert.Equal(t, 9.9, read.TotalReward)
+			assert.Equal(t, 3, read.Header.Steps)
+		})
+	}
+}
+
+func TestRead_GIVEN_missing_file_WHEN_read_THEN_returns_error(t *testing.T) {
+	_, err := replay.Read(filepath.Join(t.TempDir(), "missing.jsonl"))
+
+	assert.ErrorContains(t, err, "failed to open replay")
+}
+
+func TestRender_GIVEN_decided_step_WHEN_rendered_THEN_shows_board_and_masked_choice(t *testing.T) {
+	var out strings.Builder
+
+	require.NoError(t, replay.Render(&out, sampleReplay(), 1))
+
+	frame := out.String()
+	assert.Contains(t, frame, "client_1  step 2/3")
+	assert.Contains(t, frame, ".A\n")
+	assert.Contains(t, frame, "direction    ->  1 |    -   *0.24   0.09     -")
+	assert.Contains(t, frame, "Action: [0 1]")
+}
+
+func TestRender_GIVEN_final_step_WHEN_rendered_THEN_marks_the_end(t *testing.T) {
+	var out strings.Builder
+
+	require.NoError(t, replay.Render(&out, sampleReplay(), 2))
+
+	assert.Contains(t, out.String(), "cumulative 9.90  [terminated]")
+	assert.NotContains(t, out.String(), "Decision")
+}
+
+func TestRender_GIVEN_step_out_of_range_WHEN_rendered_THEN_returns_error(t *testing.T) {
+	assert.Error(t, replay.Render(&strings.Builder{}, sampleReplay(), 3))
+}
@@ -5,35 +5,82 @@ import (
 	"sync"
 )
 
-var (
-	mu   sync.Mutex
-	rng  *rand.Rand
-	seed int64 = 42 // Default seed for reproducibility
-)
+// Rand is a generator with a stream of its own. A run draws everything from its Rand so runs in
+// one process neither share nor disturb each other's sequences. A nil *Rand draws from the
+// package generator that Seed sets.
+type Rand struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+}
 
-func init() {
-	// Default seed for reproducibility
-	Seed(42)
+// New returns a generator seeded with s
+func New(s int64) *Rand {
+	return &Rand{rng: rand.New(rand.NewPCG(uint64(s), uint64(s)))}
 }
 
+// Default seed for reproducibility
+var global = New(42)
+
 // Seed sets the random seed for reproducible results
 func Seed(s int64) {
-	mu.Lock()
-	defer mu.Unlock()
-	seed = s
-	rng = rand.New(rand.NewPCG(uint64(seed), uint64(seed)))
+	seeded := New(s)
+	global.mu.Lock()
+	defer global.mu.Unlock()
+	global.rng = seeded.rng
 }
 
 // Intn returns a random int in [0,n)
 func Intn(n int) int {
-	mu.Lock()
-	defer mu.Unlock()
-	return rng.IntN(n)
+	return global.Intn(n)
 }
 
 // Float64 returns a random float64 in [0.0,1.0)
 func Float64() float64 {
-	mu.Lock()
-	defer mu.Unlock()
-	return rng.Float64()
+	return global.Float64()
+}
+
+// NormFloat64 returns a normally distributed float64 with mean 0 and standard deviation 1
+func NormFloat64() float64 {
+	return global.NormFloat64()
+}
+
+// or is r, or the package generator if r is nil
+func (r *Rand) or() *Rand {
+	if r == nil {
+		return global
+	}
+	return r
+}
+
+// Intn returns a random int in [0,n)
+func (r *Rand) Intn(n int) int {
+	r = r.or()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rng.IntN(n)
+}
+
+// Float64 returns a random float64 in [0.0,1.0)
+func (r *Rand) Float64() float64 {
+	r = r.or()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rng.Float64()
+}
+
+// NormFloat64 returns a normally distributed float64 with mean 0 and standard deviation 1
+func (r *Rand) NormFloat64() float64 {
+	r = r.or()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rng.NormFloat64()
+}
+
+// Split returns a new generator seeded from r's stream. Work done concurrently stays
+// reproducible if each task is given a generator split from r in a fixed order.
+func (r *Rand) Split() *Rand {
+	r = r.or()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &Rand{rng: rand.New(rand.NewPCG(r.rng.Uint64(), r.rng.Uint64()))}
 }
@@ -38,3 +38,35 @@ func TestReproducibility_GIVEN_same_seed_WHEN_generate_values_THEN_same_sequence
 	assert.Equal(t, val1, val2)
 	assert.Equal(t, float1, float2)
 }
+
+func TestRand_GIVEN_same_seed_WHEN_drawn_alongside_package_generator_THEN_sequences_independent(t *testing.T) {
+	first := rng.New(5)
+	expected := []int{first.Intn(1000), first.Intn(1000)}
+
+	second := rng.New(5)
+	got := []int{second.Intn(1000)}
+	rng.Seed(123)
+	rng.Intn(1000)
+	got = append(got, second.Intn(1000))
+
+	assert.Equal(t, expected, got)
+}
+
+func TestRand_Split_GIVEN_same_seed_WHEN_split_THEN_same_children(t *testing.T) {
+	a, b := rng.New(9), rng.New(9)
+
+	childA, childB := a.Split(), b.Split()
+
+	assert.Equal(t, childA.Float64(), childB.Float64())
+	assert.Equal(t, a.Float64(), b.Float64())
+}
+
+func TestRand_GIVEN_nil_WHEN_drawn_THEN_uses_package_generator(t *testing.T) {
+	var r *rng.Rand
+	rng.Seed(11)
+	expected := rng.Intn(1000)
+
+	rng.Seed(11)
+
+	assert.Equal(t, expected, r.Intn(1000))
+}
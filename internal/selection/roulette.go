@@ -7,9 +7,16 @@ import (
 	"github.com/bxrne/darwin/internal/rng"
 )
 
-// Selector defines the interface for selection strategies
+// Selector defines the interface for selection strategies. Select draws from r, the generator of
+// the caller; nil is the package generator.
 type Selector interface {
-	Select(population []individual.Evolvable) individual.Evolvable
+	Select(population []individual.Evolvable, r *rng.Rand) individual.Evolvable
+}
+
+// Resizable is implemented by selectors whose sample size can change between generations
+type Resizable interface {
+	Size() int
+	SetSize(size int)
 }
 
 // RouletteSelector implements roulette wheel selection
@@ -22,19 +29,29 @@ func NewRouletteSelector(sampleSize int) *RouletteSelector {
 	return &RouletteSelector{SampleSize: sampleSize}
 }
 
+// Size implements Resizable
+func (rs *RouletteSelector) Size() int {
+	return rs.SampleSize
+}
+
+// SetSize implements Resizable
+func (rs *RouletteSelector) SetSize(size int) {
+	rs.SampleSize = size
+}
+
 // Select performs roulette wheel selection
-func (rs *RouletteSelector) Select(population []individual.Evolvable) individual.Evolvable {
+func (rs *RouletteSelector) Select(population []individual.Evolvable, r *rng.Rand) individual.Evolvable {
 	rouletteTable := make([]individual.Evolvable, 0, rs.SampleSize)
 	total := 0.0
 
 	for range rs.SampleSize {
-		randIndex := rng.Intn(len(population))
+		randIndex := r.Intn(len(population))
 		rouletteTable = append(rouletteTable, population[randIndex])
 		total += math.Abs(population[randIndex].GetFitness())
 	}
 
 	runningTotal := 0.0
-	randomValue := rng.Float64() * total
+	randomValue := r.Float64() * total
 	for i := range rs.SampleSize {
 		runningTotal += math.Abs(rouletteTable[i].GetFitness())
 		if runningTotal > randomValue {
@@ -17,7 +17,7 @@ func TestRouletteSelector_Select_GIVEN_population_WHEN_select_THEN_returns_indiv
 
 	selector := selection.NewRouletteSelector(2)
 
-	selected := selector.Select(pop)
+	selected := selector.Select(pop, nil)
 
 	assert.NotNil(t, selected)
 	assert.Contains(t, pop, selected)
@@ -31,7 +31,7 @@ func TestRouletteSelector_Select_GIVEN_sample_size_WHEN_select_THEN_uses_sample_
 
 	selector := selection.NewRouletteSelector(1) // Sample size 1
 
-	selected := selector.Select(pop)
+	selected := selector.Select(pop, nil)
 
 	assert.NotNil(t, selected)
 }
@@ -45,7 +45,7 @@ func TestTournamentSelector_Select_GIVEN_population_WHEN_select_THEN_returns_bes
 
 	selector := selection.NewTournamentSelector(2)
 
-	selected := selector.Select(pop)
+	selected := selector.Select(pop, nil)
 
 	assert.NotNil(t, selected)
 	// Should be one of the individuals
@@ -60,7 +60,7 @@ func TestTournamentSelector_Select_GIVEN_tournament_size_WHEN_select_THEN_uses_t
 
 	selector := selection.NewTournamentSelector(1) // Tournament size 1
 
-	selected := selector.Select(pop)
+	selected := selector.Select(pop, nil)
 
 	assert.NotNil(t, selected)
 }
@@ -73,8 +73,16 @@ func TestTournamentSelector_Select_GIVEN_tournament_size_one_WHEN_select_THEN_re
 
 	selector := selection.NewTournamentSelector(1)
 
-	selected := selector.Select(pop)
+	selected := selector.Select(pop, nil)
 
 	assert.NotNil(t, selected)
 	assert.Contains(t, pop, selected)
 }
+
+func TestSelectors_GIVEN_resizable_WHEN_resized_THEN_size_reported(t *testing.T) {
+	for _, selector := range []selection.Resizable{selection.NewTournamentSelector(3), selection.NewRouletteSelector(3)} {
+		selector.SetSize(5)
+
+		assert.Equal(t, 5, selector.Size())
+	}
+}
@@ -15,11 +15,21 @@ func NewTournamentSelector(tournamentSize int) *TournamentSelector {
 	return &TournamentSelector{TournamentSize: tournamentSize}
 }
 
+// Size implements Resizable
+func (ts *TournamentSelector) Size() int {
+	return ts.TournamentSize
+}
+
+// SetSize implements Resizable
+func (ts *TournamentSelector) SetSize(size int) {
+	ts.TournamentSize = size
+}
+
 // Select performs tournament selection
-func (ts *TournamentSelector) Select(population []individual.Evolvable) individual.Evolvable {
+func (ts *TournamentSelector) Select(population []individual.Evolvable, r *rng.Rand) individual.Evolvable {
 	tournamentPop := make([]individual.Evolvable, 0, ts.TournamentSize)
 	for range ts.TournamentSize {
-		randIndex := rng.Intn(len(population))
+		randIndex := r.Intn(len(population))
 		tournamentPop = append(tournamentPop, population[randIndex])
 	}
 
@@ -0,0 +1,275 @@
+// Package tui draws a live terminal view of a run and turns keystrokes into engine commands. It
+// only writes ANSI escape sequences; putting the terminal into cbreak mode is up to the caller.
+package tui
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bxrne/darwin/internal/evolution"
+	"github.com/bxrne/darwin/internal/fitness"
+	"github.com/bxrne/darwin/internal/metrics"
+)
+
+const (
+	clearScreen = "\033[H\033[2J"
+	// MutationStep is how much each + or - keystroke moves the mutation rate
+	MutationStep = 0.05
+	// sparkWidth is how many recent generations the fitness sparklines show
+	sparkWidth = 60
+)
+
+// Keys lists the keystrokes understood, as shown in the footer
+const Keys = "p pause  r resume  s stop and save  + / - mutation rate"
+
+// Monitor holds what the terminal view shows
+type Monitor struct {
+	generations int
+	commands    chan evolution.EvolutionCommand
+	changed     chan struct{}
+	started     time.Time
+
+	mu           sync.Mutex
+	latest       *metrics.GenerationMetrics
+	best         []float64
+	average      []float64
+	mutationRate float64
+	stopping     bool
+	status       string
+	poolStats    func() map[string]interface{}
+}
+
+// New creates a monitor of a run of generations starting at mutationRate
+func New(generations int, mutationRate float64) *Monitor {
+	return &Monitor{
+		generations:  generations,
+		commands:     make(chan evolution.EvolutionCommand, 16),
+		changed:      make(chan struct{}, 1),
+		started:      time.Now(),
+		mutationRate: mutationRate,
+		status:       "running",
+	}
+}
+
+// Commands returns the engine commands sent by keystrokes
+func (m *Monitor) Commands() <-chan evolution.EvolutionCommand {
+	return m.commands
+}
+
+// Attach shows the connection pool of calculator, if it has one
+func (m *Monitor) Attach(calculator fitness.FitnessCalculator) {
+	reporter, ok := calculator.(fitness.PoolStatsReporter)
+	if !ok {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.poolStats = reporter.PoolStats
+}
+
+// Observe records a finished generation
+func (m *Monitor) Observe(g metrics.GenerationMetrics) {
+	m.mu.Lock()
+	m.latest = &g
+	m.best = append(m.best, g.Metrics["max_fit"])
+	m.average = append(m.average, g.Metrics["avg_fit"])
+	m.mu.Unlock()
+	m.redraw()
+}
+
+// StopRequested reports whether the run was stopped to be saved
+func (m *Monitor) StopRequested() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.stopping
+}
+
+// Key handles a keystroke, queueing the engine command it stands for. Unknown keys are ignored.
+func (m *Monitor) Key(key byte) {
+	m.mu.Lock()
+	if m.stopping {
+		m.mu.Unlock()
+		return
+	}
+	var cmd evolution.EvolutionCommand
+	switch key {
+	case 'p':
+		cmd, m.status = evolution.EvolutionCommand{Type: evolution.CmdPause}, "paused after this generation"
+	case 'r':
+		cmd, m.status = evolution.EvolutionCommand{Type: evolution.CmdResume}, "running"
+	case 's':
+		cmd, m.stopping, m.status = evolution.EvolutionCommand{Type: evolution.CmdStop}, true, "stopping after this generation, then saving"
+	case '+', '=':
+		m.mutationRate = math.Min(m.mutationRate+MutationStep, 1)
+		cmd = evolution.EvolutionCommand{Type: evolution.CmdSetMutationRate, MutationRate: m.mutationRate}
+	case '-', '_':
+		m.mutationRate = math.Max(m.mutationRate-MutationStep, 0)
+		cmd = evolution.EvolutionCommand{Type: evolution.CmdSetMutationRate, MutationRate: m.mutationRate}
+	default:
+		m.mu.Unlock()
+		return
+	}
+	m.mu.Unlock()
+
+	select {
+	case m.commands <- cmd:
+	default:
+		m.setStatus("too many keystrokes queued, ignored")
+	}
+	m.redraw()
+}
+
+func (m *Monitor) setStatus(status string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.status = status
+}
+
+// redraw asks Run for a new frame without waiting for it
+func (m *Monitor) redraw() {
+	select {
+	case m.changed <- struct{}{}:
+	default:
+	}
+}
+
+// Run reads keystrokes from in and draws to out whenever something changes, and at least every
+// refresh, until ctx is done
+func (m *Monitor) Run(ctx context.Context, in io.Reader, out io.Writer, refresh time.Duration) {
+	go func() {
+		reader := bufio.NewReader(in)
+		for {
+			key, err := reader.ReadByte()
+			if err != nil {
+				return
+			}
+			m.Key(key)
+		}
+	}()
+
+	ticker := time.NewTicker(refresh)
+	defer ticker.Stop()
+	for {
+		_ = m.Render(out)
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.changed:
+		case <-ticker.C:
+		}
+	}
+}
+
+// Render draws one frame
+func (m *Monitor) Render(out io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+	b.WriteString(clearScreen)
+	generation := 0
+	if m.latest != nil {
+		generation = m.latest.Generation
+	}
+	fmt.Fprintf(&b, "darwin  %s  elapsed %s  mutation rate %.2f\n\n", m.status, time.Since(m.started).Round(time.Second), m.mutationRate)
+	fmt.Fprintf(&b, "Generation %d/%d  %s\n", generation, m.generations, ProgressBar(generation, m.generations, 40))
+	if m.latest != nil {
+		fmt.Fprintf(&b, "Last generation %s, %d evaluations\n", m.latest.Duration.Round(time.Millisecond), m.latest.Evaluations)
+	}
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "Best fitness    %s %s\n", Sparkline(m.best, sparkWidth), last(m.best))
+	fmt.Fprintf(&b, "Average fitness %s %s\n\n", Sparkline(m.average, sparkWidth), last(m.average))
+
+	b.WriteString("Best individual\n")
+	if m.latest != nil {
+		b.WriteString(indent(m.latest.BestDescription))
+	} else {
+		b.WriteString("  waiting for the first generation\n")
+	}
+	if m.poolStats != nil {
+		if stats := m.poolStats(); stats != nil {
+			b.WriteString("\nConnection pool\n  ")
+			b.WriteString(formatPoolStats(stats))
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString("\n" + Keys + "\n")
+
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+// Sparkline draws values as a row of block characters scaled between their minimum and maximum;
+// non-finite values are gaps
+func Sparkline(values []float64, width int) string {
+	const blocks = "▁▂▃▄▅▆▇█"
+	levels := []rune(blocks)
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+		lo, hi = math.Min(lo, v), math.Max(hi, v)
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		switch {
+		case math.IsNaN(v) || math.IsInf(v, 0):
+			b.WriteRune(' ')
+		case hi == lo:
+			b.WriteRune(levels[len(levels)/2])
+		default:
+			b.WriteRune(levels[int((v-lo)/(hi-lo)*float64(len(levels)-1)+0.5)])
+		}
+	}
+	return b.String()
+}
+
+// ProgressBar draws done out of total as a bar width characters wide
+func ProgressBar(done, total, width int) string {
+	filled := 0
+	if total > 0 {
+		filled = min(done*width/total, width)
+	}
+	return "[" + strings.Repeat("#", filled) + strings.Repeat(".", width-filled) + "]"
+}
+
+func last(values []float64) string {
+	if len(values) == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.4f", values[len(values)-1])
+}
+
+func indent(text string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		b.WriteString("  " + line + "\n")
+	}
+	return b.String()
+}
+
+// formatPoolStats lists the fields of TCPConnectionPool.GetStats in name order
+func formatPoolStats(stats map[string]interface{}) string {
+	keys := make([]string, 0, len(stats))
+	for key := range stats {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	fields := make([]string, len(keys))
+	for i, key := range keys {
+		fields[i] = fmt.Sprintf("%s=%v", key, stats[key])
+	}
+	return strings.Join(fields, "  ")
+}
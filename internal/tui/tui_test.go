@@ -0,0 +1,120 @@
+package tui_test
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bxrne/darwin/internal/evolution"
+	"github.com/bxrne/darwin/internal/metrics"
+	"github.com/bxrne/darwin/internal/tui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func receive(t *testing.T, m *tui.Monitor) evolution.EvolutionCommand {
+	t.Helper()
+	select {
+	case cmd := <-m.Commands():
+		return cmd
+	case <-time.After(time.Second):
+		t.Fatal("no command sent")
+	}
+	return evolution.EvolutionCommand{}
+}
+
+func TestSparkline_GIVEN_rising_values_WHEN_drawn_THEN_lowest_to_highest_block(t *testing.T) {
+	assert.Equal(t, "▁▅█", tui.Sparkline([]float64{0, 0.6, 1}, 10))
+}
+
+func TestSparkline_GIVEN_more_values_than_width_WHEN_drawn_THEN_keeps_latest(t *testing.T) {
+	assert.Equal(t, "▁█", tui.Sparkline([]float64{5, 0, 1}, 2))
+}
+
+func TestSparkline_GIVEN_flat_and_missing_values_WHEN_drawn_THEN_mid_blocks_and_gaps(t *testing.T) {
+	assert.Equal(t, "▅ ▅", tui.Sparkline([]float64{2, math.Inf(-1), 2}, 10))
+}
+
+func TestProgressBar_GIVEN_half_done_WHEN_drawn_THEN_half_filled(t *testing.T) {
+	assert.Equal(t, "[##..]", tui.ProgressBar(5, 10, 4))
+	assert.Equal(t, "[####]", tui.ProgressBar(12, 10, 4))
+}
+
+func TestMonitor_GIVEN_keys_WHEN_pressed_THEN_sends_engine_commands(t *testing.T) {
+	m := tui.New(10, 0.3)
+
+	m.Key('p')
+	assert.Equal(t, evolution.CmdPause, receive(t, m).Type)
+	m.Key('r')
+	assert.Equal(t, evolution.CmdResume, receive(t, m).Type)
+	m.Key('+')
+	bumped := receive(t, m)
+	assert.Equal(t, evolution.CmdSetMutationRate, bumped.Type)
+	assert.InDelta(t, 0.35, bumped.MutationRate, 1e-9)
+	m.Key('x')
+	m.Key('s')
+	assert.Equal(t, evolution.CmdStop, receive(t, m).Type)
+	assert.True(t, m.StopRequested())
+}
+
+func TestMonitor_GIVEN_rate_at_bounds_WHEN_bumped_THEN_clamped(t *testing.T) {
+	m := tui.New(10, 0.98)
+
+	m.Key('+')
+	assert.Equal(t, 1.0, receive(t, m).MutationRate)
+
+	low := tui.New(10, 0.02)
+	low.Key('-')
+	assert.Equal(t, 0.0, receive(t, low).MutationRate)
+}
+
+func TestMonitor_GIVEN_stopping_WHEN_more_keys_THEN_ignored(t *testing.T) {
+	m := tui.New(10, 0.3)
+	m.Key('s')
+	receive(t, m)
+
+	m.Key('p')
+
+	select {
+	case cmd := <-m.Commands():
+		t.Fatalf("unexpected command %v", cmd)
+	default:
+	}
+}
+
+func TestMonitor_GIVEN_generation_WHEN_rendered_THEN_shows_progress_fitness_and_best(t *testing.T) {
+	m := tui.New(4, 0.3)
+	m.Observe(metrics.GenerationMetrics{Generation: 1, Metrics: map[string]float64{"max_fit": 0.5, "avg_fit": 0.25}, BestDescription: "(x + 1)"})
+	m.Observe(metrics.GenerationMetrics{Generation: 2, Evaluations: 4, Duration: time.Second, Metrics: map[string]float64{"max_fit": 0.75, "avg_fit": 0.5}, BestDescription: "(x * y)"})
+
+	var out strings.Builder
+	require.NoError(t, m.Render(&out))
+
+	frame := out.String()
+	assert.Contains(t, frame, "Generation 2/4")
+	assert.Contains(t, frame, "1s, 4 evaluations")
+	assert.Contains(t, frame, "Best fitness    ▁█ 0.7500")
+	assert.Contains(t, frame, "  (x * y)\n")
+	assert.Contains(t, frame, tui.Keys)
+	assert.NotContains(t, frame, "Connection pool")
+}
+
+func TestMonitor_GIVEN_input_WHEN_run_THEN_draws_and_keys_become_commands(t *testing.T) {
+	m := tui.New(4, 0.3)
+	ctx, cancel := context.WithCancel(context.Background())
+	var out strings.Builder
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		m.Run(ctx, strings.NewReader("p"), &out, time.Hour)
+	}()
+	cmd := receive(t, m)
+	cancel()
+	<-done
+
+	assert.Equal(t, evolution.CmdPause, cmd.Type)
+	assert.Contains(t, out.String(), "Generation 0/4")
+}
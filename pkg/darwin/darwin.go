@@ -0,0 +1,245 @@
+// Package darwin is the public library API for building and running evolutions
+// programmatically, without TOML config files or the global zap logger.
+//
+//	engine, err := darwin.New(
+//		darwin.WithFactory(func(r *darwin.Rand) darwin.Evolvable { return darwin.NewBitString(r, 64) }),
+//		darwin.WithFitnessFunc(darwin.OneMax),
+//		darwin.WithGenerations(50),
+//		darwin.OnGeneration(func(m darwin.GenerationMetrics) { fmt.Println(m.Generation) }),
+//	)
+//	result, err := engine.Run(ctx)
+package darwin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bxrne/darwin/internal/evolution"
+	"github.com/bxrne/darwin/internal/fitness"
+	"github.com/bxrne/darwin/internal/individual"
+	"github.com/bxrne/darwin/internal/metrics"
+	"github.com/bxrne/darwin/internal/population"
+	"github.com/bxrne/darwin/internal/rng"
+	"github.com/bxrne/darwin/internal/selection"
+	"go.uber.org/zap"
+)
+
+// Evolvable is the interface every individual implements
+type Evolvable = individual.Evolvable
+
+// MutateInformation is passed to Evolvable.Mutate
+type MutateInformation = individual.MutateInformation
+
+// CrossoverInformation is passed to Evolvable.MultiPointCrossover
+type CrossoverInformation = individual.CrossoverInformation
+
+// FitnessCalculator assigns a fitness to an individual via SetFitness
+type FitnessCalculator = fitness.FitnessCalculator
+
+// Aborter is implemented by a FitnessCalculator that can give up on a run; once Err returns
+// non-nil the run stops after the current generation and reports the error
+type Aborter = fitness.Aborter
+
+// Selector picks a parent from the population
+type Selector = selection.Selector
+
+// Rand is a random number generator. Each run of an Engine owns one, seeded by WithSeed, and
+// hands it to the factory and to Mutate and MultiPointCrossover through the Rand field of their
+// information. A nil *Rand draws from a process-wide one.
+type Rand = rng.Rand
+
+// NewRand returns a generator seeded with seed
+func NewRand(seed int64) *Rand {
+	return rng.New(seed)
+}
+
+// GenerationMetrics is reported once per generation
+type GenerationMetrics = metrics.GenerationMetrics
+
+// BitString is the built-in binary genome
+type BitString = individual.BinaryIndividual
+
+// RealVector is the built-in bounded real-valued genome
+type RealVector = individual.RealVectorIndividual
+
+// Bound is the range of one RealVector gene
+type Bound = individual.Bound
+
+// NewBitString creates a random bitstring of the given length
+func NewBitString(r *Rand, size int) *BitString {
+	return individual.NewBinaryIndividual(r, size)
+}
+
+// NewRealVector creates a random real-valued vector within bounds
+func NewRealVector(r *Rand, bounds []Bound) *RealVector {
+	return individual.NewRealVectorIndividual(r, bounds)
+}
+
+// FitnessFunc adapts a plain function into a FitnessCalculator
+type FitnessFunc func(Evolvable) float64
+
+// CalculateFitness implements FitnessCalculator
+func (fn FitnessFunc) CalculateFitness(evolvable Evolvable) {
+	evolvable.SetFitness(fn(evolvable))
+}
+
+// OneMax scores a BitString by its fraction of ones
+func OneMax(evolvable Evolvable) float64 {
+	calc := fitness.BinaryFitnessCalculator{}
+	calc.CalculateFitness(evolvable)
+	return evolvable.GetFitness()
+}
+
+// Result is the outcome of a run
+type Result struct {
+	Population  []Evolvable
+	Best        Evolvable
+	Generations int
+	// Err is why the run stopped before its last generation, nil if it completed
+	Err error
+}
+
+// Engine runs an evolution configured through Options
+type Engine struct {
+	populationSize int
+	generations    int
+	crossoverRate  float64
+	mutationRate   float64
+	elitism        float64
+	seed           int64
+	factory        func(r *Rand) Evolvable
+	fitness        FitnessCalculator
+	selector       Selector
+	crossoverInfo  CrossoverInformation
+	mutateInfo     MutateInformation
+	logger         *zap.Logger
+	callbacks      []func(GenerationMetrics)
+}
+
+// New creates an engine. A factory and a fitness calculator are required;
+// everything else has defaults (100 individuals, 50 generations, tournament of 3).
+func New(opts ...Option) (*Engine, error) {
+	e := &Engine{
+		populationSize: 100,
+		generations:    50,
+		crossoverRate:  0.9,
+		mutationRate:   0.05,
+		elitism:        0.1,
+		seed:           42,
+		selector:       selection.NewTournamentSelector(3),
+		crossoverInfo:  CrossoverInformation{CrossoverPoints: 1},
+		logger:         zap.NewNop(),
+	}
+	for _, opt := range opts {
+		if err := opt(e); err != nil {
+			return nil, err
+		}
+	}
+	if e.factory == nil {
+		return nil, fmt.Errorf("an individual factory is required, use WithFactory")
+	}
+	if e.fitness == nil {
+		return nil, fmt.Errorf("a fitness calculator is required, use WithFitness or WithFitnessFunc")
+	}
+	return e, nil
+}
+
+// Run evolves to completion, invoking OnGeneration callbacks along the way
+func (e *Engine) Run(ctx context.Context) (*Result, error) {
+	metricsOut, results := e.Start(ctx)
+	for m := range metricsOut {
+		for _, callback := range e.callbacks {
+			callback(m)
+		}
+	}
+	result := <-results
+	if result == nil {
+		return nil, ctx.Err()
+	}
+	if result.Err != nil {
+		return nil, result.Err
+	}
+	return result, nil
+}
+
+// Start runs the evolution in the background. Metrics arrive on the first channel,
+// which is closed when the run ends; the result (nil if cancelled) is then sent on the second,
+// with Err set if an Aborter fitness calculator stopped the run early.
+// Callbacks registered with OnGeneration are not invoked; consume the channel instead.
+func (e *Engine) Start(ctx context.Context) (<-chan GenerationMetrics, <-chan *Result) {
+	metricsOut := make(chan GenerationMetrics, e.generations)
+	results := make(chan *Result, 1)
+
+	r := rng.New(e.seed)
+	pop := population.NewGenericPopulation(e.populationSize, e.factory, r)
+
+	metricsChan := make(chan GenerationMetrics, e.generations)
+	cmdChan := make(chan evolution.EvolutionCommand, e.generations)
+	engine := evolution.NewEvolutionEngine(pop, e.selector, metricsChan, cmdChan, e.fitness, e.crossoverInfo, e.mutateInfo, e.logger)
+	engine.SetRand(r)
+	engine.Start(ctx)
+
+	for gen := 1; gen <= e.generations; gen++ {
+		cmdChan <- evolution.EvolutionCommand{
+			Type:            evolution.CmdStartGeneration,
+			Generation:      gen,
+			CrossoverPoints: e.crossoverInfo.CrossoverPoints,
+			CrossoverRate:   e.crossoverRate,
+			MutationRate:    e.mutationRate,
+			ElitismPct:      e.elitism,
+		}
+	}
+	close(cmdChan)
+
+	go func() {
+		defer close(results)
+		done := make(chan struct{})
+		go func() {
+			engine.Wait()
+			close(done)
+		}()
+
+		completed := 0
+		forward := func(m GenerationMetrics) {
+			completed = m.Generation
+			metricsOut <- m
+		}
+	loop:
+		for {
+			select {
+			case m := <-metricsChan:
+				forward(m)
+			case <-done:
+				// Drain anything sent before the engine stopped
+				for {
+					select {
+					case m := <-metricsChan:
+						forward(m)
+					default:
+						break loop
+					}
+				}
+			}
+		}
+		close(metricsOut)
+
+		if ctx.Err() != nil {
+			return
+		}
+		final := engine.GetPopulation()
+		results <- &Result{Population: final, Best: best(final), Generations: completed, Err: engine.Err()}
+	}()
+
+	return metricsOut, results
+}
+
+// best returns the fittest individual
+func best(pop []Evolvable) Evolvable {
+	var top Evolvable
+	for _, ind := range pop {
+		if top == nil || ind.GetFitness() > top.GetFitness() {
+			top = ind
+		}
+	}
+	return top
+}
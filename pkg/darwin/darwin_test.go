@@ -0,0 +1,225 @@
+package darwin_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bxrne/darwin/internal/rng"
+	"github.com/bxrne/darwin/pkg/darwin"
+	"github.com/stretchr/testify/assert"
+)
+
+// counter is a custom genome defined outside the module's internal packages
+type counter struct {
+	value   int
+	fitness float64
+}
+
+func (c *counter) Mutate(rate float64, info *darwin.MutateInformation) {
+	if info.Rand.Float64() < rate {
+		c.value += info.Rand.Intn(3) - 1
+	}
+}
+
+func (c *counter) MultiPointCrossover(other darwin.Evolvable, _ *darwin.CrossoverInformation) (darwin.Evolvable, darwin.Evolvable) {
+	o := other.(*counter)
+	return &counter{value: c.value}, &counter{value: o.value}
+}
+
+func (c *counter) Max(other darwin.Evolvable) darwin.Evolvable {
+	if other.GetFitness() > c.fitness {
+		return other
+	}
+	return c
+}
+
+func (c *counter) GetFitness() float64        { return c.fitness }
+func (c *counter) SetFitness(fitness float64) { c.fitness = fitness }
+func (c *counter) Clone() darwin.Evolvable    { return &counter{value: c.value, fitness: c.fitness} }
+func (c *counter) Describe() string           { return fmt.Sprintf("%d", c.value) }
+func (c *counter) GetMetrics() map[string]float64 {
+	return map[string]float64{"fit": c.fitness, "value": float64(c.value)}
+}
+
+// firstSelector always picks the first individual; the engine selects concurrently
+type firstSelector struct{ calls atomic.Int64 }
+
+func (f *firstSelector) Select(pop []darwin.Evolvable, _ *darwin.Rand) darwin.Evolvable {
+	f.calls.Add(1)
+	return pop[0]
+}
+
+func TestNew_GIVEN_no_factory_WHEN_new_THEN_returns_error(t *testing.T) {
+	_, err := darwin.New(darwin.WithFitnessFunc(darwin.OneMax))
+
+	assert.Error(t, err)
+}
+
+func TestNew_GIVEN_invalid_rate_WHEN_new_THEN_returns_error(t *testing.T) {
+	_, err := darwin.New(
+		darwin.WithFactory(func(r *darwin.Rand) darwin.Evolvable { return darwin.NewBitString(r, 8) }),
+		darwin.WithFitnessFunc(darwin.OneMax),
+		darwin.WithRates(1.5, 0.1),
+	)
+
+	assert.Error(t, err)
+}
+
+func TestRun_GIVEN_bitstring_onemax_WHEN_run_THEN_callbacks_receive_every_generation(t *testing.T) {
+	var generations []int
+	engine, err := darwin.New(
+		darwin.WithFactory(func(r *darwin.Rand) darwin.Evolvable { return darwin.NewBitString(r, 16) }),
+		darwin.WithFitnessFunc(darwin.OneMax),
+		darwin.WithPopulationSize(20),
+		darwin.WithGenerations(5),
+		darwin.OnGeneration(func(m darwin.GenerationMetrics) { generations = append(generations, m.Generation) }),
+	)
+	assert.NoError(t, err)
+
+	result, err := engine.Run(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, generations)
+	assert.Equal(t, 5, result.Generations)
+	assert.Len(t, result.Population, 20)
+	assert.Greater(t, result.Best.GetFitness(), 0.0)
+}
+
+func TestStart_GIVEN_custom_genome_and_selector_WHEN_started_THEN_metrics_stream_on_channel(t *testing.T) {
+	selector := &firstSelector{}
+	engine, err := darwin.New(
+		darwin.WithFactory(func(*darwin.Rand) darwin.Evolvable { return &counter{} }),
+		darwin.WithFitnessFunc(func(e darwin.Evolvable) float64 { return float64(e.(*counter).value) }),
+		darwin.WithSelector(selector),
+		darwin.WithPopulationSize(10),
+		darwin.WithGenerations(3),
+	)
+	assert.NoError(t, err)
+
+	metricsOut, results := engine.Start(context.Background())
+	count := 0
+	for m := range metricsOut {
+		count++
+		assert.Contains(t, m.Metrics, "avg_value")
+	}
+	result := <-results
+
+	assert.Equal(t, 3, count)
+	assert.NotNil(t, result)
+	assert.Greater(t, selector.calls.Load(), int64(0))
+	assert.IsType(t, &counter{}, result.Best)
+}
+
+func TestRun_GIVEN_cancelled_context_WHEN_run_THEN_returns_context_error(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	engine, err := darwin.New(
+		darwin.WithFactory(func(r *darwin.Rand) darwin.Evolvable { return darwin.NewBitString(r, 8) }),
+		darwin.WithFitnessFunc(darwin.OneMax),
+	)
+	assert.NoError(t, err)
+
+	_, err = engine.Run(ctx)
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+// abortingFitness scores OneMax until told to give up on the run
+type abortingFitness struct{ calls atomic.Int64 }
+
+func (a *abortingFitness) CalculateFitness(evolvable darwin.Evolvable) {
+	a.calls.Add(1)
+	evolvable.SetFitness(darwin.OneMax(evolvable))
+}
+
+// Err gives up once a couple of generations have been scored
+func (a *abortingFitness) Err() error {
+	if a.calls.Load() > 20 {
+		return errors.New("game server unreachable")
+	}
+	return nil
+}
+
+func TestRun_GIVEN_fitness_aborts_WHEN_run_THEN_returns_abort_error(t *testing.T) {
+	engine, err := darwin.New(
+		darwin.WithFactory(func(r *darwin.Rand) darwin.Evolvable { return darwin.NewBitString(r, 8) }),
+		darwin.WithFitness(&abortingFitness{}),
+		darwin.WithPopulationSize(10),
+		darwin.WithGenerations(5),
+	)
+	assert.NoError(t, err)
+
+	result, err := engine.Run(context.Background())
+
+	assert.ErrorContains(t, err, "game server unreachable")
+	assert.Nil(t, result)
+}
+
+func TestStart_GIVEN_fitness_aborts_WHEN_started_THEN_result_carries_abort_error(t *testing.T) {
+	engine, err := darwin.New(
+		darwin.WithFactory(func(r *darwin.Rand) darwin.Evolvable { return darwin.NewBitString(r, 8) }),
+		darwin.WithFitness(&abortingFitness{}),
+		darwin.WithPopulationSize(10),
+		darwin.WithGenerations(5),
+	)
+	assert.NoError(t, err)
+
+	metricsOut, results := engine.Start(context.Background())
+	for range metricsOut {
+	}
+	result := <-results
+
+	assert.ErrorContains(t, result.Err, "game server unreachable")
+	assert.Less(t, result.Generations, 5)
+}
+
+// describeRun runs a small OneMax evolution with seed and describes its final population
+func describeRun(t *testing.T, seed int64) []string {
+	engine, err := darwin.New(
+		darwin.WithFactory(func(r *darwin.Rand) darwin.Evolvable { return darwin.NewBitString(r, 16) }),
+		darwin.WithFitnessFunc(darwin.OneMax),
+		darwin.WithPopulationSize(20),
+		darwin.WithGenerations(5),
+		darwin.WithSeed(seed),
+	)
+	assert.NoError(t, err)
+	result, err := engine.Run(context.Background())
+	assert.NoError(t, err)
+
+	described := make([]string, 0, len(result.Population))
+	for _, ind := range result.Population {
+		described = append(described, ind.Describe())
+	}
+	return described
+}
+
+func TestRun_GIVEN_same_seed_WHEN_run_alongside_other_engines_THEN_same_population(t *testing.T) {
+	expected := describeRun(t, 7)
+
+	var wg sync.WaitGroup
+	concurrent := make([][]string, 4)
+	for i := range concurrent {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			concurrent[i] = describeRun(t, 7)
+		}()
+	}
+	wg.Wait()
+
+	for _, got := range concurrent {
+		assert.Equal(t, expected, got)
+	}
+}
+
+func TestRun_GIVEN_seeded_package_generator_WHEN_run_THEN_package_generator_untouched(t *testing.T) {
+	rng.Seed(3)
+	describeRun(t, 7)
+	got := rng.Float64()
+
+	assert.Equal(t, rng.New(3).Float64(), got)
+}
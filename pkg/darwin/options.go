@@ -0,0 +1,174 @@
+package darwin
+
+import (
+	"fmt"
+
+	"github.com/bxrne/darwin/internal/selection"
+	"go.uber.org/zap"
+)
+
+// Option configures an Engine
+type Option func(*Engine) error
+
+// WithPopulationSize sets the number of individuals per generation
+func WithPopulationSize(size int) Option {
+	return func(e *Engine) error {
+		if size <= 0 {
+			return fmt.Errorf("population size must be greater than 0")
+		}
+		e.populationSize = size
+		return nil
+	}
+}
+
+// WithGenerations sets how many generations Run performs
+func WithGenerations(generations int) Option {
+	return func(e *Engine) error {
+		if generations <= 0 {
+			return fmt.Errorf("generations must be greater than 0")
+		}
+		e.generations = generations
+		return nil
+	}
+}
+
+// WithFactory sets the function creating the initial individuals. Any type
+// implementing Evolvable can be used, including ones defined outside this module.
+// The factory draws from the run's generator r so a seed always gives the same population.
+func WithFactory(factory func(r *Rand) Evolvable) Option {
+	return func(e *Engine) error {
+		if factory == nil {
+			return fmt.Errorf("factory must not be nil")
+		}
+		e.factory = factory
+		return nil
+	}
+}
+
+// WithFitness sets the fitness calculator
+func WithFitness(calculator FitnessCalculator) Option {
+	return func(e *Engine) error {
+		if calculator == nil {
+			return fmt.Errorf("fitness calculator must not be nil")
+		}
+		e.fitness = calculator
+		return nil
+	}
+}
+
+// WithFitnessFunc sets a plain function as the fitness calculator
+func WithFitnessFunc(fn FitnessFunc) Option {
+	return func(e *Engine) error {
+		if fn == nil {
+			return fmt.Errorf("fitness function must not be nil")
+		}
+		e.fitness = fn
+		return nil
+	}
+}
+
+// WithSelector sets a custom parent selection strategy
+func WithSelector(selector Selector) Option {
+	return func(e *Engine) error {
+		if selector == nil {
+			return fmt.Errorf("selector must not be nil")
+		}
+		e.selector = selector
+		return nil
+	}
+}
+
+// WithTournamentSelection uses tournament selection of the given size
+func WithTournamentSelection(size int) Option {
+	return func(e *Engine) error {
+		if size <= 0 {
+			return fmt.Errorf("tournament size must be greater than 0")
+		}
+		e.selector = selection.NewTournamentSelector(size)
+		return nil
+	}
+}
+
+// WithRouletteSelection uses roulette selection over samples of the given size
+func WithRouletteSelection(size int) Option {
+	return func(e *Engine) error {
+		if size <= 0 {
+			return fmt.Errorf("roulette sample size must be greater than 0")
+		}
+		e.selector = selection.NewRouletteSelector(size)
+		return nil
+	}
+}
+
+// WithRates sets the crossover and mutation rates, both in [0,1]
+func WithRates(crossoverRate, mutationRate float64) Option {
+	return func(e *Engine) error {
+		if crossoverRate < 0 || crossoverRate > 1 {
+			return fmt.Errorf("crossover rate must be between 0 and 1")
+		}
+		if mutationRate < 0 || mutationRate > 1 {
+			return fmt.Errorf("mutation rate must be between 0 and 1")
+		}
+		e.crossoverRate = crossoverRate
+		e.mutationRate = mutationRate
+		return nil
+	}
+}
+
+// WithElitism sets the fraction of the population copied unchanged into the next generation
+func WithElitism(percentage float64) Option {
+	return func(e *Engine) error {
+		if percentage <= 0 || percentage > 1 {
+			return fmt.Errorf("elitism percentage must be greater than 0 and at most 1")
+		}
+		e.elitism = percentage
+		return nil
+	}
+}
+
+// WithCrossoverInformation sets the information passed to MultiPointCrossover
+func WithCrossoverInformation(info CrossoverInformation) Option {
+	return func(e *Engine) error {
+		e.crossoverInfo = info
+		return nil
+	}
+}
+
+// WithMutateInformation sets the information passed to Mutate
+func WithMutateInformation(info MutateInformation) Option {
+	return func(e *Engine) error {
+		e.mutateInfo = info
+		return nil
+	}
+}
+
+// WithSeed seeds the generator each Run creates for itself. Runs never share a generator,
+// so engines running at once in one process each reproduce their own seed.
+func WithSeed(seed int64) Option {
+	return func(e *Engine) error {
+		e.seed = seed
+		return nil
+	}
+}
+
+// WithLogger sets the logger used by the engine. The default discards all output;
+// the global zap logger is never used or replaced.
+func WithLogger(logger *zap.Logger) Option {
+	return func(e *Engine) error {
+		if logger == nil {
+			return fmt.Errorf("logger must not be nil")
+		}
+		e.logger = logger
+		return nil
+	}
+}
+
+// OnGeneration registers a callback invoked with every generation's metrics, in order
+func OnGeneration(callback func(GenerationMetrics)) Option {
+	return func(e *Engine) error {
+		if callback != nil {
+			e.callbacks = append(e.callbacks, callback)
+		}
+		return nil
+	}
+}
@@ -1,7 +1,15 @@
+import os
+
 import pandas as pd
 import matplotlib.pyplot as plt
-# Load the CSV
-df = pd.read_csv("../test_small_argmax.csv")
+# Load the CSV; metrics added mid-run lengthen later rows and the full header moves to a sidecar
+csv_path = "../test_small_argmax.csv"
+if os.path.exists(csv_path + ".header"):
+    with open(csv_path + ".header") as f:
+        columns = f.read().strip().split(",")
+    df = pd.read_csv(csv_path, header=None, skiprows=1, names=columns)
+else:
+    df = pd.read_csv(csv_path)
 
 # Split into two dataframes:
 # Trees → depth > 0